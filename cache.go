@@ -0,0 +1,106 @@
+package gqlt
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SetResponseCache enables an on-disk cache of query responses in dir. Execute
+// returns a cached response for an identical request (same endpoint, document,
+// operation name and variables) made within ttl, without contacting the server.
+// Only queries are cached; mutations and subscriptions always go to the server,
+// and responses with errors are never stored. The client's headers and
+// credentials are part of the key (hashed, never written to disk), so clients
+// with different credentials can share a directory without seeing each other's
+// responses. An empty dir or a ttl of 0 disables the cache.
+//
+// Example:
+//
+//	client.SetResponseCache(filepath.Join(os.TempDir(), "gqlt-cache"), 30*time.Second)
+func (c *Client) SetResponseCache(dir string, ttl time.Duration) {
+	if dir == "" || ttl <= 0 {
+		c.cacheDir, c.cacheTTL = "", 0
+		return
+	}
+	c.cacheDir, c.cacheTTL = dir, ttl
+}
+
+// cachePath returns the cache file for a request, or "" if the request can't be
+// cached because caching is disabled or the operation is not a query
+func (c *Client) cachePath(query string, variables map[string]interface{}, operationName string) string {
+	if c.cacheDir == "" {
+		return ""
+	}
+	info, err := DetectOperationType(query, operationName)
+	if err != nil || info.Type != OperationTypeQuery {
+		return ""
+	}
+
+	// Maps marshal with sorted keys, so equal variables always hash the same
+	key, err := json.Marshal(struct {
+		Endpoint      string                 `json:"endpoint"`
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+		Headers       map[string]string      `json:"headers"`
+		Credentials   []string               `json:"credentials"`
+	}{c.endpoint, query, operationName, variables, c.headers, c.cacheCredentials()})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(key)
+	return filepath.Join(c.cacheDir, hex.EncodeToString(sum[:])+".json")
+}
+
+// cacheCredentials lists the credentials the client authenticates with, so
+// responses are only served to clients authenticating the same way
+func (c *Client) cacheCredentials() []string {
+	var credentials []string
+	if c.basicAuth != nil {
+		credentials = append(credentials, "basic", c.basicAuth.username, c.basicAuth.password)
+	}
+	if c.oauth2 != nil {
+		credentials = append(credentials, "oauth2", c.oauth2.TokenURL, c.oauth2.ClientID, c.oauth2.ClientSecret)
+		credentials = append(credentials, c.oauth2.Scopes...)
+	}
+	if c.sigV4 != nil {
+		credentials = append(credentials, "sigv4", c.sigV4.region, c.sigV4.service,
+			c.sigV4.creds.AccessKeyID, c.sigV4.creds.SecretAccessKey, c.sigV4.creds.SessionToken)
+	}
+	return credentials
+}
+
+// readCache returns the cached response body at path if it is younger than the TTL
+func (c *Client) readCache(path string) ([]byte, bool) {
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) >= c.cacheTTL {
+		return nil, false
+	}
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	return body, true
+}
+
+// writeCache stores a response body at path. The cache is best effort, so
+// failures are ignored; the file is renamed into place so readers never see a
+// partial body.
+func (c *Client) writeCache(path string, body []byte) {
+	if err := os.MkdirAll(c.cacheDir, 0700); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(c.cacheDir, ".response-*")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(body)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil || os.Rename(tmp.Name(), path) != nil {
+		os.Remove(tmp.Name())
+	}
+}
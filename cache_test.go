@@ -0,0 +1,189 @@
+package gqlt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestClient_ResponseCache(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"user": {"name": "Ada"}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	client.SetResponseCache(t.TempDir(), time.Minute)
+
+	query := `query GetUser($id: ID!) { user(id: $id) { name } }`
+	first, err := client.Execute(query, map[string]interface{}{"id": "1"}, "")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if first.Cached {
+		t.Error("Expected the first response to come from the server")
+	}
+
+	second, err := client.Execute(query, map[string]interface{}{"id": "1"}, "")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected the second query to hit the cache, got %d requests", requests)
+	}
+	if !second.Cached {
+		t.Error("Expected the second response to be marked as cached")
+	}
+	if data, ok := second.Data.(map[string]interface{}); !ok || data["user"] == nil {
+		t.Errorf("Expected the cached data, got %v", second.Data)
+	}
+
+	// Different variables are a different request
+	if _, err := client.Execute(query, map[string]interface{}{"id": "2"}, ""); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected a request for new variables, got %d requests", requests)
+	}
+}
+
+func TestClient_ResponseCacheCredentials(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"me": {"name": "Ada"}}}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	newClient := func(headers map[string]string) *Client {
+		client := NewClient(server.URL, headers)
+		client.SetResponseCache(dir, time.Minute)
+		return client
+	}
+	execute := func(client *Client) *Response {
+		t.Helper()
+		response, err := client.Execute(`query { me { name } }`, nil, "")
+		if err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		return response
+	}
+
+	execute(newClient(map[string]string{"Authorization": "Bearer a"}))
+
+	// Clients sharing the directory with other credentials go to the server
+	if execute(newClient(map[string]string{"Authorization": "Bearer b"})).Cached {
+		t.Error("Expected a different token not to be served the cached response")
+	}
+	basic := newClient(nil)
+	basic.SetAuth("ada", "secret")
+	if execute(basic).Cached {
+		t.Error("Expected basic auth not to be served the cached response")
+	}
+	other := newClient(nil)
+	other.SetAuth("grace", "secret")
+	if execute(other).Cached {
+		t.Error("Expected another user not to be served the cached response")
+	}
+	if requests != 4 {
+		t.Errorf("Expected 4 requests, got %d", requests)
+	}
+
+	// The same credentials are served from the cache
+	if !execute(newClient(map[string]string{"Authorization": "Bearer a"})).Cached {
+		t.Error("Expected the same token to be served the cached response")
+	}
+}
+
+func TestClient_ResponseCacheSkipsMutations(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"data": {"createUser": {"id": "1"}}}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := NewClient(server.URL, nil)
+	client.SetResponseCache(dir, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		result, err := client.Execute(`mutation { createUser { id } }`, nil, "")
+		if err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		if result.Cached {
+			t.Error("Expected a mutation never to be served from the cache")
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Expected every mutation to reach the server, got %d requests", requests)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected nothing to be cached for mutations, got %d files", len(entries))
+	}
+}
+
+func TestClient_ResponseCacheExpiry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"data": {"now": "later"}}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := NewClient(server.URL, nil)
+	client.SetResponseCache(dir, time.Minute)
+
+	if _, err := client.Execute(`{ now }`, nil, ""); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	// Age the cached entry past the TTL
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("Expected one cached response, got %d (%v)", len(entries), err)
+	}
+	old := time.Now().Add(-2 * time.Minute)
+	if err := os.Chtimes(filepath.Join(dir, entries[0].Name()), old, old); err != nil {
+		t.Fatalf("Failed to age cache entry: %v", err)
+	}
+
+	result, err := client.Execute(`{ now }`, nil, "")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if result.Cached || requests != 2 {
+		t.Errorf("Expected an expired entry to be refetched, got cached=%v after %d requests", result.Cached, requests)
+	}
+}
+
+func TestClient_ResponseCacheSkipsErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"data": null, "errors": [{"message": "boom"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	client.SetResponseCache(t.TempDir(), time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Execute(`{ boom }`, nil, ""); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Expected responses with errors not to be cached, got %d requests", requests)
+	}
+}
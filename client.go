@@ -2,26 +2,102 @@ package gqlt
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/http/cookiejar"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/oauth2/clientcredentials"
+	"golang.org/x/time/rate"
 )
 
 // Client represents a GraphQL client that can execute queries, mutations, and subscriptions
 // against a GraphQL endpoint. It handles authentication, headers, and HTTP communication.
 type Client struct {
-	endpoint   string
-	headers    map[string]string
-	httpClient *http.Client
+	endpoint     string
+	headers      map[string]string
+	httpClient   *http.Client
+	basicAuth    *basicAuthTransport
+	tlsConfig    *tls.Config
+	proxyURL     *url.URL
+	compression  bool
+	contentType  string
+	logger       func(RequestLog)
+	transport    *TransportOptions
+	roundTripper http.RoundTripper
+	oauth2       *clientcredentials.Config
+	sigV4        *sigV4Transport
+	maxFileSize  int64
+	maxPages     int
+	maxResponse  int64
+	cacheDir     string
+	cacheTTL     time.Duration
+	limiter      *rate.Limiter
+
+	retryAttempts int
+	retryMaxWait  time.Duration
+
+	clientName         string
+	clientVersion      string
+	clientHeaderPrefix string
+	requestIDHeader    string
+
+	subscriptionProtocol SubscriptionProtocol
+	connectionParams     map[string]interface{}
+	reconnectAttempts    int
+	reconnectDelay       time.Duration
+	lifecycleEvents      bool
+	subscriptionBuffer   int
+	subscriptionReplay   io.Writer
+}
+
+// SubscriptionProtocol selects the transport Subscribe uses
+type SubscriptionProtocol string
+
+const (
+	// SubscriptionProtocolAuto tries WebSocket first and falls back to graphql-sse for HTTP endpoints
+	SubscriptionProtocolAuto SubscriptionProtocol = ""
+	// SubscriptionProtocolSSE uses the graphql-sse protocol in distinct connections mode
+	SubscriptionProtocolSSE SubscriptionProtocol = "graphql-sse"
+	// SubscriptionProtocolGraphQLTransportWS uses WebSocket with the graphql-transport-ws subprotocol
+	SubscriptionProtocolGraphQLTransportWS SubscriptionProtocol = "graphql-transport-ws"
+	// SubscriptionProtocolGraphQLWS uses WebSocket with the legacy graphql-ws subprotocol
+	SubscriptionProtocolGraphQLWS SubscriptionProtocol = "graphql-ws"
+)
+
+// RequestLog describes a completed HTTP round trip, as passed to the logger set with SetLogger
+type RequestLog struct {
+	Method     string
+	URL        string
+	Headers    map[string]string // Request headers, with credentials redacted
+	BodySize   int               // Size of the request body in bytes, as sent
+	StatusCode int               // Zero if no response was received
+	Duration   time.Duration
+	Error      error // Set if the request failed or the response couldn't be read
 }
 
+// redactedHeaders are request headers whose values are hidden from the logger
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "X-API-Key"}
+
+// CompressionThreshold is the request body size in bytes from which Execute gzips
+// the request when compression is enabled
+const CompressionThreshold = 1024
+
 // Response represents a GraphQL response containing data, errors, and extensions.
 // The Data field contains the actual response data, Errors contains any GraphQL errors,
 // and Extensions contains additional metadata from the server.
@@ -29,10 +105,72 @@ type Response struct {
 	Data       interface{}            `json:"data"`
 	Errors     []interface{}          `json:"errors,omitempty"`
 	Extensions map[string]interface{} `json:"extensions,omitempty"`
+
+	// DurationMs is the round-trip time of the request in milliseconds, from sending
+	// the request until the response body has been read. It is not serialized.
+	DurationMs int64 `json:"-"`
+
+	// RequestID is the ID sent with the request when the client has a request ID
+	// header (see SetRequestIDHeader). It is not serialized.
+	RequestID string `json:"-"`
+
+	// Upload describes the files sent by ExecuteWithFiles. It is nil for other
+	// requests and is not serialized.
+	Upload *UploadStats `json:"-"`
+
+	// HasNext is set on the responses delivered by ExecuteIncremental while more
+	// incremental results are expected
+	HasNext bool `json:"hasNext,omitempty"`
+
+	// Cached reports whether the response was served from the response cache
+	// (see SetResponseCache) instead of the server. It is not serialized.
+	Cached bool `json:"-"`
+}
+
+// UploadStats describes the files sent with a multipart upload request
+type UploadStats struct {
+	Count      int   // Number of files uploaded
+	TotalBytes int64 // Combined size of the files, excluding multipart framing
+}
+
+// ErrFileTooLarge is wrapped by ExecuteWithFiles errors for files larger than the
+// limit set with SetMaxFileSize
+var ErrFileTooLarge = errors.New("file exceeds the maximum upload size")
+
+// TransportOptions tunes how the HTTP transport pools keep-alive connections
+type TransportOptions struct {
+	// MaxIdleConnsPerHost is the number of idle connections kept open per host
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept before it is closed
+	IdleConnTimeout time.Duration
+}
+
+// DefaultTransportOptions returns the pooling settings of the transport shared by clients
+func DefaultTransportOptions() TransportOptions {
+	return TransportOptions{
+		MaxIdleConnsPerHost: 16,
+		IdleConnTimeout:     90 * time.Second,
+	}
+}
+
+// sharedTransport is used by every client without custom TLS, proxy or transport
+// settings, so connections to the same host are reused across clients
+var sharedTransport = newTransport(DefaultTransportOptions())
+
+// newTransport clones the default transport, which honors the proxy environment
+// variables, with the given pooling settings
+func newTransport(opts TransportOptions) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+	transport.IdleConnTimeout = opts.IdleConnTimeout
+	return transport
 }
 
 // NewClient creates a new GraphQL client for the specified endpoint.
 // The headers parameter can be nil or contain additional HTTP headers to send with requests.
+// Cookies set by the server are retained and sent with subsequent requests made by the
+// same client, so a login mutation and later queries share the session. Clients share
+// a pooled transport, so connections to the same host are kept alive and reused.
 //
 // Example:
 //
@@ -40,10 +178,12 @@ type Response struct {
 //	    "Authorization": "Bearer token",
 //	})
 func NewClient(endpoint string, headers map[string]string) *Client {
+	// cookiejar.New only fails for invalid options
+	jar, _ := cookiejar.New(nil)
 	return &Client{
 		endpoint:   endpoint,
 		headers:    headers,
-		httpClient: &http.Client{},
+		httpClient: &http.Client{Jar: jar, Transport: sharedTransport},
 	}
 }
 
@@ -54,22 +194,361 @@ func NewClient(endpoint string, headers map[string]string) *Client {
 //
 //	client.SetAuth("username", "password")
 func (c *Client) SetAuth(username, password string) {
-	c.httpClient = &http.Client{
-		Transport: &basicAuthTransport{
-			username: username,
-			password: password,
-		},
+	c.basicAuth = &basicAuthTransport{
+		username: username,
+		password: password,
+	}
+	c.updateTransport()
+}
+
+// SetTLSConfig sets the TLS configuration used for HTTPS requests, replacing any
+// configuration set by SetClientCertificate or SetRootCA.
+//
+// Example:
+//
+//	client.SetTLSConfig(&tls.Config{MinVersion: tls.VersionTLS13})
+func (c *Client) SetTLSConfig(config *tls.Config) {
+	c.tlsConfig = config
+	c.updateTransport()
+}
+
+// SetClientCertificate loads a PEM encoded certificate and private key and presents
+// them to the server for mutual TLS authentication.
+//
+// Example:
+//
+//	if err := client.SetClientCertificate("client.crt", "client.key"); err != nil {
+//	    log.Fatal(err)
+//	}
+func (c *Client) SetClientCertificate(certFile, keyFile string) error {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load client certificate: %w", err)
+	}
+
+	config := c.clonedTLSConfig()
+	config.Certificates = append(config.Certificates, cert)
+	c.SetTLSConfig(config)
+	return nil
+}
+
+// SetRootCA loads PEM encoded CA certificates from a file and uses them, instead of
+// the system roots, to verify the server's certificate.
+//
+// Example:
+//
+//	if err := client.SetRootCA("internal-ca.pem"); err != nil {
+//	    log.Fatal(err)
+//	}
+func (c *Client) SetRootCA(caFile string) error {
+	data, err := os.ReadFile(caFile)
+	if err != nil {
+		return fmt.Errorf("failed to read CA file: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return fmt.Errorf("no certificates found in CA file: %s", caFile)
+	}
+
+	config := c.clonedTLSConfig()
+	config.RootCAs = pool
+	c.SetTLSConfig(config)
+	return nil
+}
+
+// SetInsecureSkipVerify disables verification of the server's certificate chain and
+// host name when insecure is true. This makes HTTPS connections vulnerable to
+// interception and should only be used for testing.
+//
+// Example:
+//
+//	client.SetInsecureSkipVerify(true)
+func (c *Client) SetInsecureSkipVerify(insecure bool) {
+	config := c.clonedTLSConfig()
+	config.InsecureSkipVerify = insecure
+	c.SetTLSConfig(config)
+}
+
+// SetProxy routes requests through the given HTTP proxy. An empty proxy URL restores
+// the default, which honors the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+//
+// Example:
+//
+//	if err := client.SetProxy("http://proxy.example.com:3128"); err != nil {
+//	    log.Fatal(err)
+//	}
+func (c *Client) SetProxy(proxyURL string) error {
+	if proxyURL == "" {
+		c.proxyURL = nil
+		c.updateTransport()
+		return nil
+	}
+
+	parsed, err := url.Parse(proxyURL)
+	if err != nil {
+		return fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return fmt.Errorf("invalid proxy URL: %s", proxyURL)
+	}
+
+	c.proxyURL = parsed
+	c.updateTransport()
+	return nil
+}
+
+// clonedTLSConfig returns a copy of the current TLS configuration, or an empty one
+func (c *Client) clonedTLSConfig() *tls.Config {
+	if c.tlsConfig == nil {
+		return &tls.Config{}
+	}
+	return c.tlsConfig.Clone()
+}
+
+// SetMaxFileSize limits the size of each file sent by ExecuteWithFiles. A request
+// with a larger file fails with ErrFileTooLarge before anything is sent. A limit
+// of 0 (the default) allows files of any size.
+//
+// Example:
+//
+//	client.SetMaxFileSize(10 << 20) // 10 MiB
+func (c *Client) SetMaxFileSize(maxBytes int64) {
+	c.maxFileSize = maxBytes
+}
+
+// SetRateLimit limits the client to requestsPerSecond HTTP requests, smoothing out
+// bursts: Execute, ExecuteStream and ExecuteWithFiles block until the request may
+// be sent (or their context is done). Responses served from the response cache
+// don't count. A rate of 0 (the default) removes the limit.
+//
+// Example:
+//
+//	client.SetRateLimit(5) // at most 5 requests per second
+func (c *Client) SetRateLimit(requestsPerSecond float64) {
+	if requestsPerSecond <= 0 {
+		c.limiter = nil
+		return
+	}
+	c.limiter = rate.NewLimiter(rate.Limit(requestsPerSecond), 1)
+}
+
+// SetTransportOptions gives the client its own transport with the given connection
+// pooling settings instead of the transport shared by all clients.
+//
+// Example:
+//
+//	client.SetTransportOptions(gqlt.TransportOptions{
+//	    MaxIdleConnsPerHost: 64,
+//	    IdleConnTimeout:     5 * time.Minute,
+//	})
+func (c *Client) SetTransportOptions(opts TransportOptions) {
+	c.transport = &opts
+	c.updateTransport()
+}
+
+// SetTransport sends the client's HTTP requests through the given RoundTripper,
+// e.g. one that calls an http.Handler directly so tests need no server. While it
+// is set, the TLS, proxy and pooling settings are ignored, since they configure
+// the client's own transport; basic authentication still applies. Pass nil to
+// restore the client's own transport.
+//
+// Example:
+//
+//	type handlerTransport struct{ handler http.Handler }
+//
+//	func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+//	    rec := httptest.NewRecorder()
+//	    t.handler.ServeHTTP(rec, req)
+//	    return rec.Result(), nil
+//	}
+//
+//	client.SetTransport(handlerTransport{handler})
+func (c *Client) SetTransport(transport http.RoundTripper) {
+	c.roundTripper = transport
+	c.updateTransport()
+}
+
+// updateTransport rebuilds the HTTP transport from the TLS, proxy, pooling and authentication settings
+func (c *Client) updateTransport() {
+	var transport http.RoundTripper = sharedTransport
+	if c.roundTripper != nil {
+		transport = c.roundTripper
+	} else if c.tlsConfig != nil || c.proxyURL != nil || c.transport != nil {
+		opts := DefaultTransportOptions()
+		if c.transport != nil {
+			opts = *c.transport
+		}
+		// The default transport's proxy is http.ProxyFromEnvironment
+		base := newTransport(opts)
+		base.TLSClientConfig = c.tlsConfig
+		if c.proxyURL != nil {
+			base.Proxy = http.ProxyURL(c.proxyURL)
+		}
+		transport = base
+	}
+	// Signing comes last, so the signature covers the final headers
+	if c.sigV4 != nil {
+		c.sigV4.base = transport
+		transport = c.sigV4
 	}
+	if c.oauth2 != nil {
+		transport = c.oauth2Transport(transport)
+	}
+	if c.basicAuth != nil {
+		c.basicAuth.base = transport
+		transport = c.basicAuth
+	}
+	c.httpClient.Transport = transport
+}
+
+// SetCookies adds cookies for the client's endpoint to its cookie jar.
+// They are sent with all subsequent requests, along with any cookies the server sets.
+// Cookies are ignored if the endpoint is not a valid URL.
+//
+// Example:
+//
+//	client.SetCookies([]*http.Cookie{
+//	    {Name: "session", Value: "abc123"},
+//	})
+func (c *Client) SetCookies(cookies []*http.Cookie) {
+	endpointURL, err := url.Parse(c.endpoint)
+	if err != nil {
+		return
+	}
+	c.httpClient.Jar.SetCookies(endpointURL, cookies)
+}
+
+// SetCompression enables gzip compression. Requests ask for gzipped responses, and
+// JSON request bodies of at least CompressionThreshold bytes are sent gzipped.
+// Responses are decompressed transparently; servers that ignore the encoding and
+// reply with plain JSON still work.
+//
+// Example:
+//
+//	client.SetCompression(true)
+func (c *Client) SetCompression(enabled bool) {
+	c.compression = enabled
+}
+
+// Content types of the request body, selected with SetContentType
+const (
+	// ContentTypeJSON sends the query, variables and operation name as a JSON object
+	ContentTypeJSON = "application/json"
+	// ContentTypeGraphQL sends the query alone as the raw request body
+	ContentTypeGraphQL = "application/graphql"
+)
+
+// SetContentType selects how operations are sent: as a JSON object
+// (ContentTypeJSON, the default) or, for servers that accept it, as the bare
+// query string (ContentTypeGraphQL). The latter can't carry variables or an
+// operation name, so executing an operation with either fails. File uploads are
+// always sent as multipart/form-data.
+//
+// Example:
+//
+//	if err := client.SetContentType(gqlt.ContentTypeGraphQL); err != nil {
+//	    log.Fatal(err)
+//	}
+func (c *Client) SetContentType(contentType string) error {
+	switch contentType {
+	case ContentTypeJSON, ContentTypeGraphQL:
+		c.contentType = contentType
+		return nil
+	default:
+		return fmt.Errorf("unsupported content type: %s", contentType)
+	}
+}
+
+// SetLogger registers a function that is called after every Execute and
+// ExecuteWithFiles round trip, including failed ones. Pass nil to disable logging.
+//
+// Example:
+//
+//	client.SetLogger(func(info gqlt.RequestLog) {
+//	    log.Printf("%s %s -> %d in %v", info.Method, info.URL, info.StatusCode, info.Duration)
+//	})
+func (c *Client) SetLogger(logger func(RequestLog)) {
+	c.logger = logger
+}
+
+// SetSubscriptionProtocol selects the protocol used by Subscribe. By default
+// (SubscriptionProtocolAuto) WebSocket is tried first, using whichever subprotocol the
+// server chooses, falling back to graphql-sse. Pinning a WebSocket subprotocol disables
+// the fallback.
+//
+// Example:
+//
+//	if err := client.SetSubscriptionProtocol(gqlt.SubscriptionProtocolSSE); err != nil {
+//	    log.Fatal(err)
+//	}
+func (c *Client) SetSubscriptionProtocol(protocol SubscriptionProtocol) error {
+	switch protocol {
+	case SubscriptionProtocolAuto, SubscriptionProtocolSSE, SubscriptionProtocolGraphQLTransportWS, SubscriptionProtocolGraphQLWS:
+		c.subscriptionProtocol = protocol
+		return nil
+	default:
+		return fmt.Errorf("unsupported subscription protocol: %s", protocol)
+	}
+}
+
+// SetConnectionParams sets parameters sent in the connection_init message of WebSocket
+// subscriptions. Many servers expect auth tokens there rather than in HTTP headers.
+//
+// Example:
+//
+//	client.SetConnectionParams(map[string]interface{}{
+//	    "authToken": "secret",
+//	})
+func (c *Client) SetConnectionParams(params map[string]interface{}) {
+	c.connectionParams = params
+}
+
+// SetReconnect makes WebSocket subscriptions re-dial and re-subscribe when the connection
+// drops unexpectedly, trying up to maxAttempts times with delay between attempts. After a
+// successful reconnect a SubscriptionMessage with Reconnected set is sent. Subscriptions
+// that complete normally or whose context is cancelled are not reconnected. A maxAttempts
+// of zero disables reconnection, which is the default.
+//
+// Example:
+//
+//	client.SetReconnect(5, 2*time.Second)
+func (c *Client) SetReconnect(maxAttempts int, delay time.Duration) {
+	c.reconnectAttempts = maxAttempts
+	c.reconnectDelay = delay
+}
+
+// SetLifecycleEvents makes WebSocket subscriptions deliver keep-alive, complete and
+// error events as messages with the corresponding Type, so callers can observe that a
+// quiet connection is still alive. By default only data messages are delivered.
+// See SubscriptionClient.SetLifecycleEvents.
+//
+// Example:
+//
+//	client.SetLifecycleEvents(true)
+//	for msg := range messages {
+//	    if msg.Type == gqlt.SubscriptionMessageTypeKeepAlive {
+//	        continue
+//	    }
+//	}
+func (c *Client) SetLifecycleEvents(enabled bool) {
+	c.lifecycleEvents = enabled
 }
 
 // SetHeaders sets additional HTTP headers for the client.
 // These headers will be sent with all subsequent requests.
+// Values containing "{{" are templates evaluated for each request:
+// {{now}} is the current time (RFC 3339), {{uuid}} a random UUID and
+// {{hmac message key}} the hex-encoded HMAC-SHA256 of message, where
+// {{.Body}} is the request body. Other values are sent unchanged.
 //
 // Example:
 //
 //	client.SetHeaders(map[string]string{
 //	    "Authorization": "Bearer token",
 //	    "X-Custom-Header": "value",
+//	    "X-Timestamp": "{{now}}",
+//	    "X-Signature": `{{hmac .Body "secret"}}`,
 //	})
 func (c *Client) SetHeaders(headers map[string]string) {
 	if c.headers == nil {
@@ -80,6 +559,63 @@ func (c *Client) SetHeaders(headers map[string]string) {
 	}
 }
 
+// DefaultClientHeaderPrefix is the prefix of the headers sent by SetClientName, as
+// used by Apollo Studio to attribute traffic to clients
+const DefaultClientHeaderPrefix = "apollographql-client"
+
+// SetClientName identifies the client to the server by sending the
+// apollographql-client-name and apollographql-client-version headers with every
+// request, including subscriptions. An empty version defaults to gqlt's Version.
+//
+// Example:
+//
+//	client.SetClientName("billing-dashboard", "1.4.0")
+func (c *Client) SetClientName(name, version string) {
+	if version == "" {
+		version = Version()
+	}
+	c.removeClientHeaders()
+	c.clientName, c.clientVersion = name, version
+	c.SetHeaders(c.clientHeaders())
+}
+
+// SetClientHeaderPrefix changes the prefix of the headers sent by SetClientName
+// (DefaultClientHeaderPrefix by default), for servers that expect other names.
+// The headers become prefix + "-name" and prefix + "-version".
+//
+// Example:
+//
+//	client.SetClientHeaderPrefix("x-client")
+func (c *Client) SetClientHeaderPrefix(prefix string) {
+	c.removeClientHeaders()
+	c.clientHeaderPrefix = prefix
+	if c.clientName != "" {
+		c.SetHeaders(c.clientHeaders())
+	}
+}
+
+// clientHeaders returns the headers identifying the client set by SetClientName
+func (c *Client) clientHeaders() map[string]string {
+	prefix := c.clientHeaderPrefix
+	if prefix == "" {
+		prefix = DefaultClientHeaderPrefix
+	}
+	return map[string]string{
+		prefix + "-name":    c.clientName,
+		prefix + "-version": c.clientVersion,
+	}
+}
+
+// removeClientHeaders drops the headers sent for a previous SetClientName call
+func (c *Client) removeClientHeaders() {
+	if c.clientName == "" {
+		return
+	}
+	for name := range c.clientHeaders() {
+		delete(c.headers, name)
+	}
+}
+
 // Execute executes a GraphQL query, mutation, or subscription against the configured endpoint.
 // The query parameter contains the GraphQL operation string, variables contains any variables
 // to be passed to the operation, and operationName specifies which operation to execute
@@ -93,63 +629,180 @@ func (c *Client) SetHeaders(headers map[string]string) {
 //	    "GetUser",
 //	)
 func (c *Client) Execute(query string, variables map[string]interface{}, operationName string) (*Response, error) {
-	// Build GraphQL request payload
-	payload := map[string]interface{}{
-		"query": query,
-	}
+	return c.ExecuteContext(context.Background(), query, variables, operationName)
+}
 
-	if operationName != "" {
-		payload["operationName"] = operationName
+// ExecuteContext works like Execute but aborts the request when the context is
+// cancelled or its deadline expires.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//	response, err := client.ExecuteContext(ctx, `query { users { name } }`, nil, "")
+func (c *Client) ExecuteContext(ctx context.Context, query string, variables map[string]interface{}, operationName string) (*Response, error) {
+	// Serve repeated queries from the response cache if one is configured
+	cachePath := c.cachePath(query, variables, operationName)
+	if cachePath != "" {
+		if body, ok := c.readCache(cachePath); ok {
+			var result Response
+			if err := json.Unmarshal(body, &result); err == nil {
+				result.Cached = true
+				return &result, nil
+			}
+		}
 	}
 
-	if len(variables) > 0 {
-		payload["variables"] = variables
+	req, bodySize, err := c.newRequest(ctx, query, variables, operationName)
+	if err != nil {
+		return nil, err
 	}
 
-	// Convert to JSON
-	jsonData, err := json.Marshal(payload)
+	// Execute request
+	body, duration, err := c.roundTrip(req, bodySize)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal GraphQL request: %w", err)
+		return nil, err
 	}
 
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(context.Background(), "POST", c.endpoint, bytes.NewBuffer(jsonData))
+	// Parse JSON response
+	var result Response
+	err = json.Unmarshal(body, &result)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+		return nil, fmt.Errorf("failed to parse GraphQL response: %w", err)
 	}
+	result.DurationMs = duration.Milliseconds()
+	result.RequestID = c.requestID(req)
 
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	for k, v := range c.headers {
-		req.Header.Set(k, v)
+	if cachePath != "" && len(result.Errors) == 0 {
+		c.writeCache(cachePath, body)
 	}
 
-	// Execute request
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute GraphQL request: %w", err)
-	}
-	defer resp.Body.Close()
+	return &result, nil
+}
 
-	// Read response
-	body, err := io.ReadAll(resp.Body)
+// ExecuteStream works like Execute but writes the response to w as it is read,
+// instead of decoding it into memory first. The output is the same compact JSON
+// document the JSON formatter writes for a Response, except that object keys in
+// data keep the order the server sent them in. Only the errors and extensions are
+// held in memory; they are written after data and also returned in the Response,
+// whose Data is nil.
+//
+// If the response turns out to be malformed part way through, whatever was read
+// up to that point has already been written to w.
+//
+// Example:
+//
+//	response, err := client.ExecuteStream(`query { logs { line } }`, nil, "", os.Stdout)
+//	if err == nil && len(response.Errors) > 0 {
+//	    os.Exit(2)
+//	}
+func (c *Client) ExecuteStream(query string, variables map[string]interface{}, operationName string, w io.Writer) (*Response, error) {
+	return c.ExecuteStreamContext(context.Background(), query, variables, operationName, w)
+}
+
+// ExecuteStreamContext works like ExecuteStream but aborts the request when the
+// context is cancelled or its deadline expires.
+func (c *Client) ExecuteStreamContext(ctx context.Context, query string, variables map[string]interface{}, operationName string, w io.Writer) (*Response, error) {
+	req, bodySize, err := c.newRequest(ctx, query, variables, operationName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
 
-	// Parse JSON response
 	var result Response
-	err = json.Unmarshal(body, &result)
+	duration, err := c.send(req, func() int { return bodySize }, func(body io.Reader, header http.Header) error {
+		// Incremental responses are merged before they are written
+		if boundary, ok := multipartBoundary(header); ok {
+			if err := readIncremental(body, boundary, &result, nil); err != nil {
+				return err
+			}
+			err := json.NewEncoder(w).Encode(&result)
+			result.Data = nil
+			return err
+		}
+		return streamResponse(body, w, &result)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse GraphQL response: %w", err)
+		return nil, err
 	}
+	result.DurationMs = duration.Milliseconds()
+	result.RequestID = c.requestID(req)
 
 	return &result, nil
 }
 
+// newRequest builds the POST request for a GraphQL operation, with a JSON body or
+// the bare query as selected with SetContentType, gzipping the body when
+// compression is enabled and the body is large enough. It also returns the size
+// of the body as sent.
+func (c *Client) newRequest(ctx context.Context, query string, variables map[string]interface{}, operationName string) (*http.Request, int, error) {
+	contentType := ContentTypeJSON
+	var body []byte
+	var err error
+	if c.contentType == ContentTypeGraphQL {
+		if len(variables) > 0 || operationName != "" {
+			return nil, 0, fmt.Errorf("%s requests can't carry variables or an operation name", ContentTypeGraphQL)
+		}
+		contentType = ContentTypeGraphQL
+		body = []byte(query)
+	} else {
+		// Build GraphQL request payload
+		payload := map[string]interface{}{
+			"query": query,
+		}
+
+		if operationName != "" {
+			payload["operationName"] = operationName
+		}
+
+		if len(variables) > 0 {
+			payload["variables"] = variables
+		}
+
+		// Convert to JSON
+		body, err = json.Marshal(payload)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to marshal GraphQL request: %w", err)
+		}
+	}
+
+	// Compress large request bodies
+	requestBody := body
+	compressBody := c.compression && len(body) >= CompressionThreshold
+	if compressBody {
+		body, err = gzipBytes(body)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to compress GraphQL request: %w", err)
+		}
+	}
+
+	// Create HTTP request
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	// Set headers
+	req.Header.Set("Content-Type", contentType)
+	if compressBody {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	c.setAcceptEncoding(req)
+	if err := c.setHeaders(req, requestBody); err != nil {
+		return nil, 0, err
+	}
+	c.setRequestID(req)
+
+	return req, len(body), nil
+}
+
 // ExecuteWithFiles executes a GraphQL operation with file uploads using multipart/form-data.
 // This method is used for GraphQL operations that require file uploads, such as mutations
 // with Upload scalar types. The files parameter maps field names to file paths.
+// All files are opened before the request is sent, so a missing file (or one larger
+// than the SetMaxFileSize limit) fails the whole request; their contents are then
+// streamed into the request body as it is sent (with chunked transfer encoding), so
+// large files are never held in memory. The number and combined size of the files
+// are reported in the response's Upload.
 //
 // Example:
 //
@@ -160,10 +813,12 @@ func (c *Client) Execute(query string, variables map[string]interface{}, operati
 //	    map[string]string{"file": "/path/to/file.jpg"},
 //	)
 func (c *Client) ExecuteWithFiles(query string, variables map[string]interface{}, operationName string, files map[string]string) (*Response, error) {
-	// Create multipart form data
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
+	return c.ExecuteWithFilesContext(context.Background(), query, variables, operationName, files)
+}
 
+// ExecuteWithFilesContext works like ExecuteWithFiles but aborts the request when the
+// context is cancelled or its deadline expires.
+func (c *Client) ExecuteWithFilesContext(ctx context.Context, query string, variables map[string]interface{}, operationName string, files map[string]string) (*Response, error) {
 	// Add GraphQL operation fields
 	operations := map[string]interface{}{
 		"query": query,
@@ -180,98 +835,318 @@ func (c *Client) ExecuteWithFiles(query string, variables map[string]interface{}
 		return nil, fmt.Errorf("failed to marshal operations: %w", err)
 	}
 
+	// Create file mapping JSON
+	fileMap := make(map[string][]string)
+	for name := range files {
+		fileMap[name] = []string{"variables." + name}
+	}
+	mapJSON, err := json.Marshal(fileMap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal file map: %w", err)
+	}
+
+	// Open every file before sending anything, so a missing file aborts the whole request
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	opened := make([]*os.File, 0, len(names))
+	closeFiles := func() {
+		for _, file := range opened {
+			file.Close()
+		}
+	}
+	stats := &UploadStats{}
+	for _, name := range names {
+		file, err := os.Open(files[name])
+		if err != nil {
+			closeFiles()
+			return nil, fmt.Errorf("failed to open file %s for upload %q: %w", files[name], name, err)
+		}
+		opened = append(opened, file)
+
+		info, err := file.Stat()
+		if err != nil {
+			closeFiles()
+			return nil, fmt.Errorf("failed to stat file %s for upload %q: %w", files[name], name, err)
+		}
+		if c.maxFileSize > 0 && info.Size() > c.maxFileSize {
+			closeFiles()
+			return nil, fmt.Errorf("%w: %s is %d bytes (limit %d)", ErrFileTooLarge, files[name], info.Size(), c.maxFileSize)
+		}
+		stats.Count++
+		stats.TotalBytes += info.Size()
+	}
+
+	// Stream the multipart body while the HTTP client sends it, so files are
+	// never held in memory as a whole
+	pr, pw := io.Pipe()
+	// Closing the reader once the request is done unblocks the writer (and closes
+	// the files) if the body wasn't read to the end, e.g. when sending failed
+	defer pr.Close()
+	body := &countingReader{reader: pr}
+	writer := multipart.NewWriter(pw)
+	go func() {
+		defer closeFiles()
+		pw.CloseWithError(writeUploadBody(writer, operationsJSON, mapJSON, names, opened))
+	}()
+
+	// Create HTTP request
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	// Set headers
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	c.setAcceptEncoding(req)
+	if err := c.setHeaders(req, operationsJSON); err != nil {
+		return nil, err
+	}
+	c.setRequestID(req)
+
+	// Execute request
+	var responseBody []byte
+	duration, err := c.send(req, body.count, func(r io.Reader, _ http.Header) error {
+		var err error
+		responseBody, err = io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse JSON response
+	var result Response
+	err = json.Unmarshal(responseBody, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GraphQL response: %w", err)
+	}
+	result.DurationMs = duration.Milliseconds()
+	result.RequestID = c.requestID(req)
+	result.Upload = stats
+
+	return &result, nil
+}
+
+// writeUploadBody writes the operations, map and file parts of a multipart upload
+// request, in the order of names
+func writeUploadBody(writer *multipart.Writer, operationsJSON, mapJSON []byte, names []string, files []*os.File) error {
 	// Add operations field
 	operationsField, err := writer.CreateFormField("operations")
 	if err != nil {
-		return nil, fmt.Errorf("failed to create operations field: %w", err)
+		return fmt.Errorf("failed to create operations field: %w", err)
 	}
-	_, err = operationsField.Write(operationsJSON)
-	if err != nil {
-		return nil, fmt.Errorf("failed to write operations: %w", err)
+	if _, err := operationsField.Write(operationsJSON); err != nil {
+		return fmt.Errorf("failed to write operations: %w", err)
 	}
 
 	// Add map field for file mappings
 	if len(files) > 0 {
 		mapField, err := writer.CreateFormField("map")
 		if err != nil {
-			return nil, fmt.Errorf("failed to create map field: %w", err)
+			return fmt.Errorf("failed to create map field: %w", err)
 		}
-
-		// Create file mapping JSON
-		fileMap := make(map[string][]string)
-		for name := range files {
-			fileMap[name] = []string{"variables." + name}
+		if _, err := mapField.Write(mapJSON); err != nil {
+			return fmt.Errorf("failed to write file map: %w", err)
 		}
+	}
 
-		mapJSON, err := json.Marshal(fileMap)
+	// Add files
+	for i, file := range files {
+		part, err := writer.CreateFormFile(names[i], filepath.Base(file.Name()))
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal file map: %w", err)
+			return fmt.Errorf("failed to create form file for %s: %w", names[i], err)
+		}
+		if _, err := io.Copy(part, file); err != nil {
+			return fmt.Errorf("failed to copy file %s: %w", file.Name(), err)
 		}
+	}
 
-		_, err = mapField.Write(mapJSON)
+	// Close the writer
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close multipart writer: %w", err)
+	}
+	return nil
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	reader io.Reader
+	n      int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	atomic.AddInt64(&r.n, int64(n))
+	return n, err
+}
+
+func (r *countingReader) count() int {
+	return int(atomic.LoadInt64(&r.n))
+}
+
+// roundTrip sends a request and reads the response body, reporting the round trip to the logger.
+// The parts of an incremental (multipart/mixed) response are merged into a single JSON response.
+func (c *Client) roundTrip(req *http.Request, bodySize int) ([]byte, time.Duration, error) {
+	var body []byte
+	duration, err := c.send(req, func() int { return bodySize }, func(r io.Reader, header http.Header) error {
+		if boundary, ok := multipartBoundary(header); ok {
+			var result Response
+			if err := readIncremental(r, boundary, &result, nil); err != nil {
+				return err
+			}
+			var err error
+			body, err = json.Marshal(&result)
+			return err
+		}
+
+		var err error
+		body, err = io.ReadAll(r)
 		if err != nil {
-			return nil, fmt.Errorf("failed to write file map: %w", err)
+			return fmt.Errorf("failed to read response body: %w", err)
 		}
+		return nil
+	})
+	return body, duration, err
+}
 
-		// Add files
-		for name, path := range files {
-			file, err := os.Open(path)
-			if err != nil {
-				return nil, fmt.Errorf("failed to open file %s: %w", path, err)
-			}
-			defer file.Close()
+// send sends a request and passes the (decompressed) response body and headers to
+// handle, reporting the round trip to the logger once the body has been handled.
+// The request body size is only asked for then, so streamed bodies can be counted.
+// Responses asking the client to back off are retried as configured by SetRetry;
+// each attempt is logged, and the returned duration is that of the last one.
+func (c *Client) send(req *http.Request, bodySize func() int, handle func(body io.Reader, header http.Header) error) (time.Duration, error) {
+	for attempt := 0; ; attempt++ {
+		canRetry := attempt < c.retryAttempts && (req.Body == nil || req.GetBody != nil)
+		duration, wait, err := c.sendOnce(req, bodySize, handle, attempt, canRetry)
+		if wait < 0 {
+			return duration, err
+		}
 
-			part, err := writer.CreateFormFile(name, filepath.Base(path))
-			if err != nil {
-				return nil, fmt.Errorf("failed to create form file for %s: %w", name, err)
-			}
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return duration, fmt.Errorf("retry wait aborted: %w", req.Context().Err())
+		case <-timer.C:
+		}
 
-			_, err = io.Copy(part, file)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
 			if err != nil {
-				return nil, fmt.Errorf("failed to copy file %s: %w", path, err)
+				return duration, fmt.Errorf("failed to rewind request body for retry: %w", err)
 			}
+			req.Body = body
 		}
 	}
+}
 
-	// Close the writer
-	err = writer.Close()
-	if err != nil {
-		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
+// sendOnce makes a single attempt at a request for send. If canRetry is set and
+// the server answers with a retryable status, the response is discarded and the
+// time to wait before the next attempt is returned; otherwise the wait is negative.
+func (c *Client) sendOnce(req *http.Request, bodySize func() int, handle func(body io.Reader, header http.Header) error, attempt int, canRetry bool) (time.Duration, time.Duration, error) {
+	info := RequestLog{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Headers: redactHeaders(req.Header),
 	}
 
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(context.Background(), "POST", c.endpoint, &buf)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	// Wait for the rate limiter; the wait doesn't count towards the round trip
+	if c.limiter != nil {
+		if err := c.limiter.Wait(req.Context()); err != nil {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return 0, -1, fmt.Errorf("rate limit wait aborted: %w", err)
+		}
 	}
 
-	// Set headers
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-	for k, v := range c.headers {
-		req.Header.Set(k, v)
+	wait := time.Duration(-1)
+	start := time.Now()
+	err := func() error {
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to execute GraphQL request: %w", err)
+		}
+		defer resp.Body.Close()
+		info.StatusCode = resp.StatusCode
+
+		if canRetry && isRetryableStatus(resp.StatusCode) {
+			wait = c.retryDelay(resp.Header.Get("Retry-After"), attempt, time.Now())
+			io.Copy(io.Discard, resp.Body)
+			return fmt.Errorf("server responded with %s, retrying in %v", resp.Status, wait)
+		}
+
+		body, err := responseBody(resp)
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %w", err)
+		}
+		defer body.Close()
+		return handle(c.limitResponse(body), resp.Header)
+	}()
+	duration := time.Since(start)
+
+	if c.logger != nil {
+		info.BodySize = bodySize()
+		info.Duration = duration
+		info.Error = err
+		c.logger(info)
 	}
 
-	// Execute request
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute GraphQL request: %w", err)
+	return duration, wait, err
+}
+
+// redactHeaders flattens request headers into a map, hiding credentials
+func redactHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for k := range header {
+		headers[k] = header.Get(k)
+	}
+	for _, k := range redactedHeaders {
+		if header.Get(k) != "" {
+			headers[http.CanonicalHeaderKey(k)] = "[REDACTED]"
+		}
 	}
-	defer resp.Body.Close()
+	return headers
+}
 
-	// Read response
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+// setAcceptEncoding asks for a gzipped response when compression is enabled
+func (c *Client) setAcceptEncoding(req *http.Request) {
+	if c.compression {
+		req.Header.Set("Accept-Encoding", "gzip")
 	}
+}
 
-	// Parse JSON response
-	var result Response
-	err = json.Unmarshal(body, &result)
+// responseBody returns a reader for a response body, decompressing it if the server gzipped it
+func responseBody(resp *http.Response) (io.ReadCloser, error) {
+	// The transport only decompresses responses when it requested gzip itself
+	if resp.Uncompressed || !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return resp.Body, nil
+	}
+
+	reader, err := gzip.NewReader(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse GraphQL response: %w", err)
+		return nil, fmt.Errorf("failed to decompress response: %w", err)
 	}
+	return reader, nil
+}
 
-	return &result, nil
+// gzipBytes compresses data with gzip
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
 // Introspect performs GraphQL introspection to get the schema
@@ -371,7 +1246,7 @@ func (c *Client) Introspect() (*Response, error) {
 	`
 
 	// Try introspection query first
-	result, err := c.Execute(introspectionQuery, nil, "IntrospectionQuery")
+	result, err := c.Execute(introspectionQuery, nil, "")
 
 	// If introspection query worked, return it
 	if err == nil && result.Data != nil {
@@ -398,8 +1273,35 @@ func (c *Client) Introspect() (*Response, error) {
 	}, nil
 }
 
+// SupportsIntrospection probes whether the endpoint answers introspection queries.
+// It returns false without an error if the server answers the probe with GraphQL
+// errors instead of the schema, as production servers with introspection disabled
+// do, and an error if the probe couldn't be sent or the response has neither.
+//
+// Example:
+//
+//	ok, err := client.SupportsIntrospection()
+//	if err == nil && !ok {
+//	    log.Println("introspection is disabled; load the schema from a file")
+//	}
+func (c *Client) SupportsIntrospection() (bool, error) {
+	result, err := c.Execute(`query IntrospectionProbe { __schema { queryType { name } } }`, nil, "")
+	if err != nil {
+		return false, err
+	}
+	if data, ok := result.Data.(map[string]interface{}); ok && data["__schema"] != nil {
+		return true, nil
+	}
+	if len(result.Errors) > 0 {
+		return false, nil
+	}
+	return false, fmt.Errorf("introspection probe returned neither a schema nor errors")
+}
+
 // Subscribe establishes a GraphQL subscription over WebSocket and returns channels for messages and errors.
-// The subscription runs until the context is cancelled, an error occurs, or the server closes the connection.
+// HTTP endpoints fall back to graphql-sse if the WebSocket connection fails; use SetSubscriptionProtocol
+// to select a protocol explicitly. The subscription runs until the context is cancelled, an error occurs,
+// or the server closes the connection.
 //
 // Example:
 //
@@ -417,26 +1319,38 @@ func (c *Client) Introspect() (*Response, error) {
 //	    fmt.Printf("Received: %+v\n", msg)
 //	}
 func (c *Client) Subscribe(ctx context.Context, query string, variables map[string]interface{}, operationName string) (<-chan *SubscriptionMessage, <-chan error, error) {
-	// Try WebSocket first (Hot Chocolate default), then fall back to SSE
-	if strings.HasPrefix(c.endpoint, "ws://") || strings.HasPrefix(c.endpoint, "wss://") {
-		// Explicit WebSocket URL
-		subClient := NewSubscriptionClient(c.endpoint, c.headers)
+	messages, errs, err := c.subscribe(ctx, query, variables, operationName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.subscriptionBuffer > 0 || c.subscriptionReplay != nil {
+		messages, errs = bufferSubscription(ctx, messages, errs, c.subscriptionBuffer, c.subscriptionReplay)
+	}
+	return messages, errs, nil
+}
 
-		// Connect to WebSocket
-		if err := subClient.Connect(ctx); err != nil {
-			return nil, nil, fmt.Errorf("failed to connect for subscription: %w", err)
+// subscribe starts a subscription over the selected protocol
+func (c *Client) subscribe(ctx context.Context, query string, variables map[string]interface{}, operationName string) (<-chan *SubscriptionMessage, <-chan error, error) {
+	isHTTP := strings.HasPrefix(c.endpoint, "http://") || strings.HasPrefix(c.endpoint, "https://")
+
+	// Use graphql-sse directly if it was selected
+	if c.subscriptionProtocol == SubscriptionProtocolSSE {
+		if !isHTTP {
+			return nil, nil, fmt.Errorf("graphql-sse requires an http or https endpoint: %s", c.endpoint)
 		}
+		sseClient := c.newSSESubscriptionClient()
+		return sseClient.Subscribe(ctx, query, variables, operationName)
+	}
 
-		// Subscribe
-		messages, errs, err := subClient.Subscribe(ctx, query, variables, operationName)
+	// Try WebSocket first (Hot Chocolate default), then fall back to SSE
+	if strings.HasPrefix(c.endpoint, "ws://") || strings.HasPrefix(c.endpoint, "wss://") {
+		// Explicit WebSocket URL
+		messages, errs, err := c.subscribeWebSocket(ctx, c.endpoint, query, variables, operationName)
 		if err != nil {
-			subClient.Close()
-			return nil, nil, err
+			return nil, nil, fmt.Errorf("failed to connect for subscription: %w", err)
 		}
-
-		// Return channels
 		return messages, errs, nil
-	} else if strings.HasPrefix(c.endpoint, "http://") || strings.HasPrefix(c.endpoint, "https://") {
+	} else if isHTTP {
 		// HTTP/HTTPS endpoint - try WebSocket first, then SSE
 
 		// Convert HTTP to WebSocket URL
@@ -447,38 +1361,138 @@ func (c *Client) Subscribe(ctx context.Context, query string, variables map[stri
 			wsURL = "wss://" + strings.TrimPrefix(c.endpoint, "https://")
 		}
 
-		subClient := NewSubscriptionClient(wsURL, c.headers)
-
 		// Try to connect to WebSocket
-		if err := subClient.Connect(ctx); err != nil {
+		messages, errs, err := c.subscribeWebSocket(ctx, wsURL, query, variables, operationName)
+		if err != nil {
+			// Don't fall back if a WebSocket subprotocol was pinned
+			if c.subscriptionProtocol != SubscriptionProtocolAuto {
+				return nil, nil, fmt.Errorf("failed to connect for subscription: %w", err)
+			}
 			// WebSocket failed, fall back to SSE
-			sseClient := NewSSESubscriptionClient(c.endpoint, c.headers)
+			sseClient := c.newSSESubscriptionClient()
 			return sseClient.Subscribe(ctx, query, variables, operationName)
 		}
+		return messages, errs, nil
+	}
+
+	return nil, nil, fmt.Errorf("unsupported endpoint scheme: %s", c.endpoint)
+}
+
+// newSSESubscriptionClient returns an SSE subscription client for the endpoint that
+// sends its requests with the client's HTTP client, so they get the same TLS,
+// proxy and authentication settings as queries
+func (c *Client) newSSESubscriptionClient() *SSESubscriptionClient {
+	sseClient := NewSSESubscriptionClient(c.endpoint, c.headers)
+	sseClient.client = c.httpClient
+	return sseClient
+}
+
+// subscribeWebSocket connects to a WebSocket endpoint and starts a subscription,
+// reconnecting on dropped connections if SetReconnect was used
+func (c *Client) subscribeWebSocket(ctx context.Context, wsURL string, query string, variables map[string]interface{}, operationName string) (<-chan *SubscriptionMessage, <-chan error, error) {
+	subscribe := func() (*SubscriptionClient, <-chan *SubscriptionMessage, <-chan error, error) {
+		subClient := NewSubscriptionClient(wsURL, c.headers)
+		subClient.httpClient = c.httpClient
+		subClient.SetProtocol(c.subscriptionProtocol)
+		subClient.SetConnectionParams(c.connectionParams)
+		subClient.SetLifecycleEvents(c.lifecycleEvents)
+
+		// Connect to WebSocket
+		if err := subClient.Connect(ctx); err != nil {
+			return nil, nil, nil, err
+		}
 
-		// WebSocket connected successfully
+		// Subscribe
 		messages, errs, err := subClient.Subscribe(ctx, query, variables, operationName)
 		if err != nil {
 			subClient.Close()
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
+		return subClient, messages, errs, nil
+	}
 
-		// Return channels
+	subClient, messages, errs, err := subscribe()
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.reconnectAttempts <= 0 {
 		return messages, errs, nil
 	}
 
-	return nil, nil, fmt.Errorf("unsupported endpoint scheme: %s", c.endpoint)
+	// Forward messages, re-subscribing whenever the connection drops
+	out := make(chan *SubscriptionMessage, 10)
+	outErrs := make(chan error, 1)
+	go func() {
+		defer close(out)
+		defer close(outErrs)
+
+		for {
+			for msg := range messages {
+				select {
+				case out <- msg:
+				case <-ctx.Done():
+					// The caller has stopped reading
+					subClient.Close()
+					return
+				}
+			}
+			err := <-errs
+			subClient.Close()
+
+			// Only dropped connections are retried
+			if err == nil || ctx.Err() != nil || !errors.Is(err, ErrConnectionLost) {
+				if err != nil && ctx.Err() == nil {
+					outErrs <- err
+				}
+				return
+			}
+
+			subClient, messages, errs, err = c.resubscribe(ctx, subscribe, err)
+			if err != nil {
+				if ctx.Err() == nil {
+					outErrs <- err
+				}
+				return
+			}
+			out <- &SubscriptionMessage{Type: SubscriptionMessageTypeReconnected, Reconnected: true}
+		}
+	}()
+
+	return out, outErrs, nil
+}
+
+// resubscribe retries a subscription up to the configured number of attempts
+func (c *Client) resubscribe(ctx context.Context, subscribe func() (*SubscriptionClient, <-chan *SubscriptionMessage, <-chan error, error), cause error) (*SubscriptionClient, <-chan *SubscriptionMessage, <-chan error, error) {
+	lastErr := cause
+	for attempt := 1; attempt <= c.reconnectAttempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			return nil, nil, nil, ctx.Err()
+		case <-time.After(c.reconnectDelay):
+		}
+
+		subClient, messages, errs, err := subscribe()
+		if err == nil {
+			return subClient, messages, errs, nil
+		}
+		lastErr = err
+	}
+	return nil, nil, nil, fmt.Errorf("failed to reconnect after %d attempts: %w", c.reconnectAttempts, lastErr)
 }
 
 // basicAuthTransport implements HTTP transport with basic authentication
 type basicAuthTransport struct {
 	username string
 	password string
+	base     http.RoundTripper // nil means http.DefaultTransport
 }
 
 func (t *basicAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	auth := t.username + ":" + t.password
 	encoded := base64.StdEncoding.EncodeToString([]byte(auth))
 	req.Header.Set("Authorization", "Basic "+encoded)
+	if t.base != nil {
+		return t.base.RoundTrip(req)
+	}
 	return http.DefaultTransport.RoundTrip(req)
 }
@@ -1,12 +1,30 @@
 package gqlt
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestNewClient(t *testing.T) {
@@ -125,7 +143,7 @@ func TestExecute(t *testing.T) {
 	client := NewClient(server.URL, nil)
 
 	// Execute query
-	query := `query { user { id name }`
+	query := `query { user { id name } }`
 	variables := map[string]interface{}{
 		"id": "123",
 	}
@@ -681,6 +699,68 @@ func TestClient_Introspect(t *testing.T) {
 	})
 }
 
+func TestClient_Introspect_RequestsDeprecationInfo(t *testing.T) {
+	var sentQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&request)
+		sentQuery, _ = request["query"].(string)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"__schema": map[string]interface{}{
+					"types": []interface{}{},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	if _, err := client.Introspect(); err != nil {
+		t.Fatalf("Introspect failed: %v", err)
+	}
+
+	// Deprecation reporting relies on these being present in the introspection result
+	for _, expected := range []string{"fields(includeDeprecated: true)", "enumValues(includeDeprecated: true)", "isDeprecated", "deprecationReason"} {
+		if !strings.Contains(sentQuery, expected) {
+			t.Errorf("Expected introspection query to contain %q", expected)
+		}
+	}
+}
+
+func TestClient_SupportsIntrospection(t *testing.T) {
+	tests := []struct {
+		name      string
+		response  string
+		supported bool
+		wantErr   bool
+	}{
+		{"enabled", `{"data":{"__schema":{"queryType":{"name":"Query"}}}}`, true, false},
+		{"disabled", `{"errors":[{"message":"GraphQL introspection is not allowed by Apollo Server"}]}`, false, false},
+		{"not graphql", `{"data":null}`, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			supported, err := NewClient(server.URL, nil).SupportsIntrospection()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SupportsIntrospection error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if supported != tt.supported {
+				t.Errorf("Expected supported %v, got %v", tt.supported, supported)
+			}
+		})
+	}
+}
+
 func TestClient_ErrorScenarios(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -843,6 +923,351 @@ func TestClient_ExecuteWithFilesEdgeCases(t *testing.T) {
 	})
 }
 
+func TestClient_ExecuteWithFilesStreaming(t *testing.T) {
+	// Several files of a few megabytes each with distinct contents
+	dir := t.TempDir()
+	const fileSize = 4 << 20
+	files := make(map[string]string)
+	for i, name := range []string{"first", "second", "third", "fourth"} {
+		path := filepath.Join(dir, name+".bin")
+		if err := os.WriteFile(path, bytes.Repeat([]byte{byte('a' + i)}, fileSize), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+		files[name] = path
+	}
+
+	type part struct {
+		filename string
+		size     int
+		uniform  bool
+	}
+	received := make(map[string]part)
+	var peakHeap uint64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Read the parts as a stream, sampling the live heap along the way
+		reader, err := r.MultipartReader()
+		if err != nil {
+			t.Errorf("Expected multipart request: %v", err)
+			return
+		}
+		buf := make([]byte, 64<<10)
+		for {
+			p, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Errorf("Failed to read part: %v", err)
+				return
+			}
+			info := part{filename: p.FileName(), uniform: true}
+			var first byte
+			for {
+				n, err := p.Read(buf)
+				for _, b := range buf[:n] {
+					if info.size == 0 {
+						first = b
+					}
+					info.uniform = info.uniform && b == first
+					info.size++
+				}
+				if info.size%(1<<20) < n {
+					runtime.GC()
+					var stats runtime.MemStats
+					runtime.ReadMemStats(&stats)
+					if stats.HeapAlloc > peakHeap {
+						peakHeap = stats.HeapAlloc
+					}
+				}
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Errorf("Failed to read part %s: %v", p.FormName(), err)
+					return
+				}
+			}
+			received[p.FormName()] = info
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"upload": true}}`))
+	}))
+	defer server.Close()
+
+	runtime.GC()
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	var logged RequestLog
+	client := NewClient(server.URL, nil)
+	client.SetLogger(func(info RequestLog) { logged = info })
+	if _, err := client.ExecuteWithFiles(`mutation ($first: Upload!, $second: Upload!, $third: Upload!, $fourth: Upload!) { upload }`, nil, "", files); err != nil {
+		t.Fatalf("ExecuteWithFiles failed: %v", err)
+	}
+
+	for name, path := range files {
+		got, ok := received[name]
+		if !ok || got.filename != filepath.Base(path) || got.size != fileSize || !got.uniform {
+			t.Errorf("Expected part %s with %d bytes of %s, got %+v (present: %v)", name, fileSize, filepath.Base(path), got, ok)
+		}
+	}
+	if _, ok := received["operations"]; !ok {
+		t.Error("Expected operations part")
+	}
+	if logged.BodySize < len(files)*fileSize {
+		t.Errorf("Expected the streamed body size to be logged, got %d", logged.BodySize)
+	}
+
+	// Buffering the request would keep all files live on the heap at once
+	total := int64(len(files) * fileSize)
+	if growth := int64(peakHeap) - int64(before.HeapAlloc); growth > total/2 {
+		t.Errorf("Expected heap growth below %d bytes while uploading, peaked at %d", total/2, growth)
+	}
+}
+
+func TestClient_ExecuteWithFilesMissingFile(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"data": {"upload": true}}`))
+	}))
+	defer server.Close()
+
+	existing := filepath.Join(t.TempDir(), "a.txt")
+	if err := os.WriteFile(existing, []byte("a"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+
+	client := NewClient(server.URL, nil)
+	_, err := client.ExecuteWithFiles(`mutation { upload }`, nil, "", map[string]string{"a": existing, "b": missing})
+	if err == nil || !strings.Contains(err.Error(), missing) || !strings.Contains(err.Error(), `upload "b"`) {
+		t.Errorf("Expected error naming the missing file, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no request to be sent, got %d", requests)
+	}
+}
+
+func TestClient_ExecuteWithFilesUnreachable(t *testing.T) {
+	// Reserve a port nothing listens on
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	endpoint := "http://" + listener.Addr().String()
+	listener.Close()
+
+	path := filepath.Join(t.TempDir(), "upload.bin")
+	if err := os.WriteFile(path, bytes.Repeat([]byte("x"), 1<<20), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	openFiles := func() int {
+		entries, err := os.ReadDir("/proc/self/fd")
+		if err != nil {
+			return -1
+		}
+		return len(entries)
+	}
+	goroutines, files := runtime.NumGoroutine(), openFiles()
+
+	client := NewClient(endpoint, nil)
+	for i := 0; i < 5; i++ {
+		if _, err := client.ExecuteWithFiles(`mutation { upload }`, nil, "", map[string]string{"file": path}); err == nil {
+			t.Fatal("Expected an error for an unreachable endpoint")
+		}
+	}
+
+	// The body writers give up and close their files once the requests fail
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > goroutines || openFiles() > files {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected goroutines and open files to return to %d and %d, got %d and %d",
+				goroutines, files, runtime.NumGoroutine(), openFiles())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestClient_ExecuteWithFilesMaxFileSize(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"data": {"upload": true}}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	small := filepath.Join(dir, "small.txt")
+	large := filepath.Join(dir, "large.txt")
+	if err := os.WriteFile(small, []byte("12345"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.WriteFile(large, bytes.Repeat([]byte("x"), 100), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	client := NewClient(server.URL, nil)
+	client.SetMaxFileSize(50)
+	_, err := client.ExecuteWithFiles(`mutation { upload }`, nil, "", map[string]string{"a": small, "b": large})
+	if !errors.Is(err, ErrFileTooLarge) {
+		t.Fatalf("Expected ErrFileTooLarge, got %v", err)
+	}
+	if !strings.Contains(err.Error(), large) {
+		t.Errorf("Expected error to name the large file, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no request to be sent, got %d", requests)
+	}
+
+	// Files at or under the limit go through
+	client.SetMaxFileSize(100)
+	if _, err := client.ExecuteWithFiles(`mutation { upload }`, nil, "", map[string]string{"a": small, "b": large}); err != nil {
+		t.Fatalf("Expected files within the limit to upload, got %v", err)
+	}
+}
+
+func TestClient_ExecuteWithFilesUploadStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"upload": true}}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	files := map[string]string{}
+	for name, size := range map[string]int{"a": 10, "b": 250, "c": 0} {
+		path := filepath.Join(dir, name+".bin")
+		if err := os.WriteFile(path, bytes.Repeat([]byte("x"), size), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		files[name] = path
+	}
+
+	client := NewClient(server.URL, nil)
+	result, err := client.ExecuteWithFiles(`mutation { upload }`, nil, "", files)
+	if err != nil {
+		t.Fatalf("ExecuteWithFiles failed: %v", err)
+	}
+	if result.Upload == nil {
+		t.Fatal("Expected upload stats in the response")
+	}
+	if result.Upload.Count != 3 || result.Upload.TotalBytes != 260 {
+		t.Errorf("Expected 3 files and 260 bytes, got %d files and %d bytes", result.Upload.Count, result.Upload.TotalBytes)
+	}
+
+	// Regular requests carry no upload stats
+	result, err = client.Execute(`query { upload }`, nil, "")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if result.Upload != nil {
+		t.Errorf("Expected no upload stats for a JSON request, got %+v", result.Upload)
+	}
+}
+
+func TestClient_SetRateLimit(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"data": {"ok": true}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	client.SetRateLimit(20)
+
+	// The first request goes out immediately, the other four wait 50ms each
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := client.Execute(`{ ok }`, nil, ""); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+	}
+	elapsed := time.Since(start)
+	if elapsed < 190*time.Millisecond {
+		t.Errorf("Expected 5 requests at 20/s to take at least 200ms, took %v", elapsed)
+	}
+	if requests != 5 {
+		t.Errorf("Expected 5 requests, got %d", requests)
+	}
+
+	// A rate of 0 removes the limit
+	client.SetRateLimit(0)
+	start = time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := client.Execute(`{ ok }`, nil, ""); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("Expected unlimited requests to be fast, took %v", elapsed)
+	}
+}
+
+func TestClient_SetRateLimitContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"ok": true}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	client.SetRateLimit(0.5)
+	if _, err := client.Execute(`{ ok }`, nil, ""); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	// The next token is two seconds away, past the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.ExecuteContext(ctx, `{ ok }`, nil, "")
+	if err == nil || !strings.Contains(err.Error(), "rate limit") {
+		t.Errorf("Expected the rate limit wait to be aborted, got %v", err)
+	}
+}
+
+func TestClient_SetClientName(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.Write([]byte(`{"data": {"ok": true}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	client.SetClientName("billing-dashboard", "1.4.0")
+	if _, err := client.Execute(`{ ok }`, nil, ""); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if got := received.Get("apollographql-client-name"); got != "billing-dashboard" {
+		t.Errorf("Expected client name header, got %q", got)
+	}
+	if got := received.Get("apollographql-client-version"); got != "1.4.0" {
+		t.Errorf("Expected client version header, got %q", got)
+	}
+
+	// The version defaults to gqlt's own
+	client.SetClientName("billing-dashboard", "")
+	if _, err := client.Execute(`{ ok }`, nil, ""); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if got := received.Get("apollographql-client-version"); got != Version() {
+		t.Errorf("Expected version %q, got %q", Version(), got)
+	}
+
+	// A custom prefix replaces the default headers
+	client.SetClientHeaderPrefix("x-client")
+	if _, err := client.Execute(`{ ok }`, nil, ""); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if got := received.Get("x-client-name"); got != "billing-dashboard" {
+		t.Errorf("Expected prefixed client name header, got %q", got)
+	}
+	if got := received.Get("apollographql-client-name"); got != "" {
+		t.Errorf("Expected the default header to be dropped, got %q", got)
+	}
+}
+
 func TestClient_Coverage(t *testing.T) {
 	// Additional tests to increase coverage
 
@@ -917,3 +1342,659 @@ func TestClient_Coverage(t *testing.T) {
 		}
 	})
 }
+
+func TestClient_ExecuteContext_Cancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"hello": "world"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.ExecuteContext(ctx, `query { hello }`, nil, ""); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	response, err := client.ExecuteContext(context.Background(), `query { hello }`, nil, "")
+	if err != nil {
+		t.Fatalf("ExecuteContext failed: %v", err)
+	}
+	if response.Data == nil {
+		t.Error("Expected response data")
+	}
+}
+
+func TestClient_CookieJar(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(readBody(t, r), "login") {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc123"})
+			w.Write([]byte(`{"data": {"login": true}}`))
+			return
+		}
+		if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "abc123" {
+			w.Write([]byte(`{"errors": [{"message": "not logged in"}]}`))
+			return
+		}
+		// Basic auth set via SetAuth must still be sent alongside the cookie
+		if username, _, ok := r.BasicAuth(); r.Header.Get("X-Require-Auth") == "true" && (!ok || username != "user") {
+			w.Write([]byte(`{"errors": [{"message": "missing basic auth"}]}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"me": "user"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, map[string]string{"X-Require-Auth": "true"})
+	client.SetAuth("user", "pass")
+
+	if _, err := client.Execute(`mutation { login }`, nil, ""); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+
+	response, err := client.Execute(`query { me }`, nil, "")
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(response.Errors) > 0 {
+		t.Errorf("Expected session cookie and auth to be sent, got errors: %v", response.Errors)
+	}
+
+	// A different client does not share the session
+	other := NewClient(server.URL, nil)
+	response, err = other.Execute(`query { me }`, nil, "")
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(response.Errors) == 0 {
+		t.Error("Expected a new client not to have the session cookie")
+	}
+}
+
+func TestClient_SetCookies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		cookie, err := r.Cookie("session")
+		if err != nil {
+			w.Write([]byte(`{"errors": [{"message": "missing cookie"}]}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]string{"session": cookie.Value}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	client.SetCookies([]*http.Cookie{{Name: "session", Value: "preset"}})
+
+	response, err := client.Execute(`query { session }`, nil, "")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	data, _ := response.Data.(map[string]interface{})
+	if data["session"] != "preset" {
+		t.Errorf("Expected preset session cookie to be sent, got %v (errors: %v)", response.Data, response.Errors)
+	}
+}
+
+// readBody returns the request body as a string
+func readBody(t *testing.T, r *http.Request) string {
+	t.Helper()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		t.Fatalf("Failed to read request body: %v", err)
+	}
+	return string(body)
+}
+
+// writeCertificatePEM writes a DER encoded certificate to a PEM file and returns its path
+func writeCertificatePEM(t *testing.T, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cert.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		t.Fatalf("Failed to write certificate: %v", err)
+	}
+	return path
+}
+
+// newClientCertificate creates a self-signed client certificate and returns the
+// certificate, its PEM file and the PEM file of its private key
+func newClientCertificate(t *testing.T) (*x509.Certificate, string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "gqlt-test-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+	keyFile := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+
+	return cert, writeCertificatePEM(t, der), keyFile
+}
+
+func TestClient_SetRootCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"hello": "world"}}`))
+	}))
+	defer server.Close()
+
+	// The test server's certificate is not trusted by default
+	client := NewClient(server.URL, nil)
+	if _, err := client.Execute(`query { hello }`, nil, ""); err == nil {
+		t.Fatal("Expected handshake to fail without the custom CA")
+	}
+
+	if err := client.SetRootCA(writeCertificatePEM(t, server.Certificate().Raw)); err != nil {
+		t.Fatalf("SetRootCA failed: %v", err)
+	}
+	if _, err := client.Execute(`query { hello }`, nil, ""); err != nil {
+		t.Errorf("Expected handshake to succeed with the custom CA: %v", err)
+	}
+}
+
+func TestClient_SetClientCertificate(t *testing.T) {
+	clientCert, certFile, keyFile := newClientCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if username, _, ok := r.BasicAuth(); !ok || username != "user" {
+			w.Write([]byte(`{"errors": [{"message": "missing basic auth"}]}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"hello": "world"}}`))
+	}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	if err := client.SetRootCA(writeCertificatePEM(t, server.Certificate().Raw)); err != nil {
+		t.Fatalf("SetRootCA failed: %v", err)
+	}
+	if _, err := client.Execute(`query { hello }`, nil, ""); err == nil {
+		t.Fatal("Expected handshake to fail without a client certificate")
+	}
+
+	if err := client.SetClientCertificate(certFile, keyFile); err != nil {
+		t.Fatalf("SetClientCertificate failed: %v", err)
+	}
+	// Basic auth must compose with the TLS configuration, in either order
+	client.SetAuth("user", "pass")
+
+	response, err := client.Execute(`query { hello }`, nil, "")
+	if err != nil {
+		t.Fatalf("Expected mutual TLS handshake to succeed: %v", err)
+	}
+	if len(response.Errors) > 0 {
+		t.Errorf("Expected basic auth to be sent over mutual TLS, got errors: %v", response.Errors)
+	}
+}
+
+func TestClient_TLSConfigErrors(t *testing.T) {
+	client := NewClient("https://api.example.com/graphql", nil)
+
+	missing := filepath.Join(t.TempDir(), "missing.pem")
+	if err := client.SetRootCA(missing); err == nil {
+		t.Error("Expected error for missing CA file")
+	}
+	if err := client.SetClientCertificate(missing, missing); err == nil {
+		t.Error("Expected error for missing certificate files")
+	}
+
+	notPEM := filepath.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(notPEM, []byte("not a certificate"), 0644)
+	if err := client.SetRootCA(notPEM); err == nil {
+		t.Error("Expected error for CA file without certificates")
+	}
+}
+
+func TestClient_SetInsecureSkipVerify(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"hello": "world"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	if _, err := client.Execute(`query { hello }`, nil, ""); err == nil {
+		t.Fatal("Expected self-signed certificate to be rejected")
+	}
+
+	client.SetInsecureSkipVerify(true)
+	if _, err := client.Execute(`query { hello }`, nil, ""); err != nil {
+		t.Errorf("Expected request to succeed with verification disabled: %v", err)
+	}
+
+	client.SetInsecureSkipVerify(false)
+	if _, err := client.Execute(`query { hello }`, nil, ""); err == nil {
+		t.Error("Expected verification to be enabled again")
+	}
+}
+
+func TestClient_SetProxy(t *testing.T) {
+	// The proxy answers on behalf of the target, which doesn't exist
+	var proxiedURL string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedURL = r.URL.String()
+		if username, _, ok := r.BasicAuth(); !ok || username != "user" {
+			w.Write([]byte(`{"errors": [{"message": "missing basic auth"}]}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"hello": "proxy"}}`))
+	}))
+	defer proxy.Close()
+
+	endpoint := "http://graphql.invalid/graphql"
+	client := NewClient(endpoint, nil)
+	client.SetAuth("user", "pass")
+	if err := client.SetProxy(proxy.URL); err != nil {
+		t.Fatalf("SetProxy failed: %v", err)
+	}
+
+	response, err := client.Execute(`query { hello }`, nil, "")
+	if err != nil {
+		t.Fatalf("Execute through proxy failed: %v", err)
+	}
+	if proxiedURL != endpoint {
+		t.Errorf("Expected proxy to receive request for %s, got %q", endpoint, proxiedURL)
+	}
+	if len(response.Errors) > 0 {
+		t.Errorf("Expected basic auth to be sent through the proxy, got errors: %v", response.Errors)
+	}
+
+	// Clearing the proxy sends requests directly again
+	if err := client.SetProxy(""); err != nil {
+		t.Fatalf("SetProxy failed: %v", err)
+	}
+	if _, err := client.Execute(`query { hello }`, nil, ""); err == nil {
+		t.Error("Expected direct request to an invalid host to fail")
+	}
+}
+
+func TestClient_SetProxy_Invalid(t *testing.T) {
+	client := NewClient("https://api.example.com/graphql", nil)
+	for _, proxyURL := range []string{"://bad", "proxy.example.com"} {
+		if err := client.SetProxy(proxyURL); err == nil {
+			t.Errorf("Expected error for proxy URL %q", proxyURL)
+		}
+	}
+}
+
+func TestClient_Compression_Response(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		body := []byte(`{"data": {"hello": "world"}}`)
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			w.Write(body)
+			return
+		}
+		compressed, err := gzipBytes(body)
+		if err != nil {
+			t.Errorf("Failed to compress response: %v", err)
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(compressed)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	client.SetCompression(true)
+
+	response, err := client.Execute(`query { hello }`, nil, "")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	data, _ := response.Data.(map[string]interface{})
+	if data["hello"] != "world" {
+		t.Errorf("Expected decompressed response data, got %v", response.Data)
+	}
+}
+
+func TestClient_Compression_Request(t *testing.T) {
+	var contentEncodings []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentEncodings = append(contentEncodings, r.Header.Get("Content-Encoding"))
+
+		var reader io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gzipReader, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("Failed to read gzipped request: %v", err)
+				return
+			}
+			reader = gzipReader
+		}
+		var payload map[string]interface{}
+		if err := json.NewDecoder(reader).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+			return
+		}
+
+		// The server ignores Accept-Encoding and replies with plain JSON
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"length": len(payload["query"].(string))},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	client.SetCompression(true)
+
+	largeQuery := "query { hello }" + strings.Repeat(" ", CompressionThreshold)
+	response, err := client.Execute(largeQuery, nil, "")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	data, _ := response.Data.(map[string]interface{})
+	if data["length"] != float64(len(largeQuery)) {
+		t.Errorf("Expected server to receive the full query, got %v", response.Data)
+	}
+
+	// Small bodies are sent uncompressed
+	if _, err := client.Execute(`query { hello }`, nil, ""); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if len(contentEncodings) != 2 || contentEncodings[0] != "gzip" || contentEncodings[1] != "" {
+		t.Errorf("Expected only the large request to be gzipped, got %q", contentEncodings)
+	}
+}
+
+func TestClient_SetContentType(t *testing.T) {
+	var contentType, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"hello":"world"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	if _, err := client.Execute(`query Hello { hello }`, map[string]interface{}{"x": 1}, "Hello"); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if contentType != ContentTypeJSON || body != `{"operationName":"Hello","query":"query Hello { hello }","variables":{"x":1}}` {
+		t.Errorf("Unexpected JSON request %q with body %s", contentType, body)
+	}
+
+	if err := client.SetContentType(ContentTypeGraphQL); err != nil {
+		t.Fatalf("SetContentType failed: %v", err)
+	}
+	response, err := client.Execute(`{ hello }`, nil, "")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if contentType != ContentTypeGraphQL || body != `{ hello }` {
+		t.Errorf("Unexpected GraphQL request %q with body %s", contentType, body)
+	}
+	if response.Data == nil {
+		t.Errorf("Expected data, got %+v", response)
+	}
+
+	// The raw query has no room for variables or an operation name
+	if _, err := client.Execute(`query Hello { hello }`, nil, "Hello"); err == nil {
+		t.Error("Expected an error for an operation name")
+	}
+	if _, err := client.Execute(`query($x: Int) { hello }`, map[string]interface{}{"x": 1}, ""); err == nil {
+		t.Error("Expected an error for variables")
+	}
+
+	if err := client.SetContentType("text/plain"); err == nil {
+		t.Error("Expected an error for an unsupported content type")
+	}
+}
+
+func TestClient_Execute_DurationMs(t *testing.T) {
+	delay := 20 * time.Millisecond
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"hello": "world"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+
+	response, err := client.Execute(`query { hello }`, nil, "")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if response.DurationMs < delay.Milliseconds() {
+		t.Errorf("Expected DurationMs of at least %d, got %d", delay.Milliseconds(), response.DurationMs)
+	}
+
+	response, err = client.ExecuteWithFiles(`query { hello }`, nil, "", nil)
+	if err != nil {
+		t.Fatalf("ExecuteWithFiles failed: %v", err)
+	}
+	if response.DurationMs < delay.Milliseconds() {
+		t.Errorf("Expected DurationMs of at least %d, got %d", delay.Milliseconds(), response.DurationMs)
+	}
+
+	// The timing is not part of the serialized response
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if strings.Contains(string(data), "DurationMs") || strings.Contains(string(data), "duration") {
+		t.Errorf("Expected duration to be omitted from JSON, got %s", data)
+	}
+}
+
+func TestClient_SetLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(readBody(t, r), "fail") {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("internal error"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"hello": "world"}}`))
+	}))
+	defer server.Close()
+
+	var logs []RequestLog
+	client := NewClient(server.URL, map[string]string{"Authorization": "Bearer secret"})
+	client.SetLogger(func(info RequestLog) {
+		logs = append(logs, info)
+	})
+
+	if _, err := client.Execute(`query { hello }`, nil, ""); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(logs) != 1 {
+		t.Fatalf("Expected logger to be called once, got %d", len(logs))
+	}
+	info := logs[0]
+	if info.Method != "POST" || info.URL != server.URL || info.StatusCode != http.StatusOK || info.Error != nil {
+		t.Errorf("Unexpected request log: %+v", info)
+	}
+	if info.BodySize == 0 {
+		t.Error("Expected request body size to be logged")
+	}
+	if info.Headers["Authorization"] != "[REDACTED]" {
+		t.Errorf("Expected Authorization header to be redacted, got %q", info.Headers["Authorization"])
+	}
+	if info.Headers["Content-Type"] != "application/json" {
+		t.Errorf("Expected Content-Type header to be logged, got %q", info.Headers["Content-Type"])
+	}
+
+	// Failed calls are logged too
+	if _, err := client.ExecuteWithFiles(`query { fail }`, nil, "", nil); err == nil {
+		t.Fatal("Expected error for non-JSON response")
+	}
+	if len(logs) != 2 || logs[1].StatusCode != http.StatusInternalServerError {
+		t.Fatalf("Expected second call to be logged with status 500, got %+v", logs)
+	}
+
+	server.Close()
+	if _, err := client.Execute(`query { hello }`, nil, ""); err == nil {
+		t.Fatal("Expected error for closed server")
+	}
+	if len(logs) != 3 || logs[2].StatusCode != 0 || logs[2].Error == nil {
+		t.Errorf("Expected failed round trip to be logged with an error, got %+v", logs[len(logs)-1])
+	}
+}
+
+// newConnCountingServer starts a GraphQL test server that counts the connections
+// opened to it
+func newConnCountingServer(t testing.TB) (*httptest.Server, *int64) {
+	var conns int64
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"ok": true}}`))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt64(&conns, 1)
+		}
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+	return server, &conns
+}
+
+func TestClientReusesConnections(t *testing.T) {
+	server, conns := newConnCountingServer(t)
+
+	client := NewClient(server.URL, nil)
+	for i := 0; i < 5; i++ {
+		if _, err := client.Execute(`{ ok }`, nil, ""); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+	}
+	// A second client to the same endpoint shares the pooled transport
+	if _, err := NewClient(server.URL, nil).Execute(`{ ok }`, nil, ""); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if got := atomic.LoadInt64(conns); got != 1 {
+		t.Errorf("Expected sequential requests to share 1 connection, got %d", got)
+	}
+
+	// Custom transport options give the client its own pool
+	tuned := NewClient(server.URL, nil)
+	tuned.SetTransportOptions(TransportOptions{MaxIdleConnsPerHost: 4, IdleConnTimeout: time.Minute})
+	for i := 0; i < 3; i++ {
+		if _, err := tuned.Execute(`{ ok }`, nil, ""); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+	}
+	if got := atomic.LoadInt64(conns); got != 2 {
+		t.Errorf("Expected the tuned client to open 1 connection of its own, got %d in total", got)
+	}
+	transport, ok := tuned.httpClient.Transport.(*http.Transport)
+	if !ok || transport.MaxIdleConnsPerHost != 4 || transport.IdleConnTimeout != time.Minute {
+		t.Errorf("Expected transport options to be applied, got %+v", tuned.httpClient.Transport)
+	}
+}
+
+func BenchmarkClientSequentialExecute(b *testing.B) {
+	server, conns := newConnCountingServer(b)
+	client := NewClient(server.URL, nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.Execute(`{ ok }`, nil, ""); err != nil {
+			b.Fatalf("Execute failed: %v", err)
+		}
+	}
+	b.ReportMetric(float64(atomic.LoadInt64(conns)), "conns")
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestClientSetTransport(t *testing.T) {
+	var requests []*http.Request
+	canned := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"data":{"user":{"id":"1","name":"Ada"}},"extensions":{"cost":3}}`)),
+			Request:    req,
+		}, nil
+	})
+
+	// The endpoint doesn't resolve, so only the custom transport can answer
+	client := NewClient("http://in-memory.invalid/graphql", nil)
+	client.SetAuth("user", "secret")
+	client.SetTransport(canned)
+
+	response, err := client.Execute(`query GetUser { user { id name } }`, map[string]interface{}{"id": "1"}, "GetUser")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	user := response.Data.(map[string]interface{})["user"].(map[string]interface{})
+	if user["name"] != "Ada" {
+		t.Errorf("Expected the canned user, got %v", user)
+	}
+	if response.Extensions["cost"] != float64(3) {
+		t.Errorf("Expected the canned extensions, got %v", response.Extensions)
+	}
+
+	if len(requests) != 1 {
+		t.Fatalf("Expected 1 request through the transport, got %d", len(requests))
+	}
+	if requests[0].URL.String() != "http://in-memory.invalid/graphql" {
+		t.Errorf("Unexpected request URL: %s", requests[0].URL)
+	}
+	if username, password, ok := requests[0].BasicAuth(); !ok || username != "user" || password != "secret" {
+		t.Errorf("Expected basic auth to apply on top of the transport, got %q/%q", username, password)
+	}
+
+	// Transport errors are returned from Execute
+	client.SetTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	}))
+	if _, err := client.Execute(`{ ok }`, nil, ""); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("Expected the transport error, got %v", err)
+	}
+
+	// nil restores the client's own transport
+	client.SetTransport(nil)
+	if _, ok := client.httpClient.Transport.(*basicAuthTransport); !ok || client.basicAuth.base != sharedTransport {
+		t.Errorf("Expected the shared transport to be restored, got %+v", client.httpClient.Transport)
+	}
+}
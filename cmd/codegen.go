@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kluzzebass/gqlt"
+	"github.com/spf13/cobra"
+)
+
+var codegenCmd = &cobra.Command{
+	Use:   "codegen",
+	Short: "Generate code from a GraphQL schema",
+	Long: `Generate code from a GraphQL schema, such as type declarations for client
+applications. The schema is read from --schema-file, or the cached schema of the
+active configuration.`,
+}
+
+var codegenTypeScriptCmd = &cobra.Command{
+	Use:   "typescript",
+	Short: "Generate TypeScript types from the schema",
+	Long: `Generate TypeScript declarations for the types of the schema: interfaces for
+object and input types, string unions for enums and union types for unions.
+Nullable fields are optional and also accept null. The declarations are written
+to --out, or stdout.`,
+	Example: `gqlt codegen typescript --schema-file schema.json --out types.ts
+gqlt codegen typescript > src/graphql/types.ts`,
+	Args: cobra.NoArgs,
+	RunE: codegenTypeScript,
+}
+
+var codegenGoCmd = &cobra.Command{
+	Use:   "go",
+	Short: "Generate Go types from the schema",
+	Long: `Generate Go declarations for the types of the schema: structs with json tags
+for object and input types and string types with a constant per value for enums.
+Nullable fields are pointers and lists are slices. The gofmt'ed file is written
+to --out, or stdout.`,
+	Example: `gqlt codegen go --schema-file schema.json --package models --out models/types.go
+gqlt codegen go --package api > internal/api/types.go`,
+	Args: cobra.NoArgs,
+	RunE: codegenGo,
+}
+
+var (
+	codegenSchemaFile string
+	codegenOut        string
+	codegenPackage    string
+)
+
+func init() {
+	rootCmd.AddCommand(codegenCmd)
+	codegenCmd.AddCommand(codegenTypeScriptCmd)
+	codegenCmd.AddCommand(codegenGoCmd)
+
+	codegenCmd.PersistentFlags().StringVar(&codegenSchemaFile, "schema-file", "", "Schema file (JSON or SDL) to generate from (default is the cached schema of the active configuration)")
+	codegenCmd.PersistentFlags().StringVar(&codegenOut, "out", "", "Write the generated code to this file instead of stdout")
+	codegenGoCmd.Flags().StringVar(&codegenPackage, "package", gqlt.DefaultGoPackage, "Package name of the generated file")
+}
+
+func codegenTypeScript(cmd *cobra.Command, args []string) error {
+	return runCodegen(cmd, "TypeScript", func(analyzer *gqlt.Analyzer) (string, error) {
+		return gqlt.GenerateTypeScript(analyzer)
+	})
+}
+
+func codegenGo(cmd *cobra.Command, args []string) error {
+	return runCodegen(cmd, "Go", func(analyzer *gqlt.Analyzer) (string, error) {
+		return gqlt.GenerateGo(analyzer, codegenPackage)
+	})
+}
+
+// runCodegen loads the schema, generates code from it and writes the code to
+// --out or stdout
+func runCodegen(cmd *cobra.Command, language string, generate func(*gqlt.Analyzer) (string, error)) error {
+	quietMode := cmd.Flag("quiet").Value.String() == "true"
+	formatter := setupFormatter(cmd)
+
+	schemaPath := codegenSchemaFile
+	if schemaPath == "" {
+		cfg, err := gqlt.Load(configDir)
+		if err != nil {
+			formatter.FormatStructuredError(err, gqlt.ErrorCodeConfigLoad, quietMode)
+			return err
+		}
+		activeName, _, err := cfg.ResolveActive(configName)
+		if err != nil {
+			formatter.FormatStructuredError(err, gqlt.ErrorCodeConfigLoad, quietMode)
+			return err
+		}
+		if configDir != "" {
+			schemaPath = gqlt.GetSchemaPathForConfigInDir(activeName, configDir)
+		} else {
+			schemaPath = gqlt.GetSchemaPathForConfig(activeName)
+		}
+	}
+
+	analyzer, err := gqlt.LoadAnalyzerFromFile(schemaPath)
+	if err != nil {
+		err = fmt.Errorf("failed to load schema: %w", err)
+		formatter.FormatStructuredError(err, gqlt.ErrorCodeSchemaLoad, quietMode)
+		return err
+	}
+
+	code, err := generate(analyzer)
+	if err != nil {
+		err = fmt.Errorf("failed to generate %s: %w", language, err)
+		formatter.FormatStructuredError(err, gqlt.ErrorCodeSchemaLoad, quietMode)
+		return err
+	}
+
+	if codegenOut == "" {
+		fmt.Fprint(cmd.OutOrStdout(), code)
+		return nil
+	}
+	if err := os.WriteFile(codegenOut, []byte(code), 0644); err != nil {
+		err = fmt.Errorf("failed to write %s: %w", codegenOut, err)
+		formatter.FormatStructuredError(err, gqlt.ErrorCodeSystemError, quietMode)
+		return err
+	}
+	return nil
+}
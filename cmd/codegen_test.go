@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kluzzebass/gqlt"
+)
+
+func resetCodegenFlags() {
+	codegenSchemaFile = ""
+	codegenOut = ""
+	codegenPackage = gqlt.DefaultGoPackage
+}
+
+func TestCodegenTypeScriptCommand(t *testing.T) {
+	dir := t.TempDir()
+	schemaPath := filepath.Join(dir, "schema.graphqls")
+	sdl := `type Query {
+  user: User
+}
+
+type User {
+  id: ID!
+  role: Role
+}
+
+enum Role {
+  ADMIN
+  USER
+}`
+	if err := os.WriteFile(schemaPath, []byte(sdl), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	resetCodegenFlags()
+	defer resetCodegenFlags()
+
+	out := filepath.Join(dir, "types.ts")
+	cmd := createFullTestCommand()
+	if _, err := executeCommandWithOutput(cmd, []string{"codegen", "typescript", "--schema-file", schemaPath, "--out", out}); err != nil {
+		t.Fatalf("codegen typescript failed: %v", err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("Expected the types to be written: %v", err)
+	}
+	for _, want := range []string{"export interface User {", "  role?: Role | null;", `export type Role = "ADMIN" | "USER";`} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("Expected %q in the output, got:\n%s", want, got)
+		}
+	}
+
+	// Without --out the types go to stdout
+	resetCodegenFlags()
+	var stdout bytes.Buffer
+	cmd = createFullTestCommand()
+	cmd.SetOut(&stdout)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"codegen", "typescript", "--schema-file", schemaPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("codegen typescript failed: %v", err)
+	}
+	if stdout.String() != string(got) {
+		t.Errorf("Expected the same output on stdout, got:\n%s", stdout.String())
+	}
+
+	resetCodegenFlags()
+	cmd = createFullTestCommand()
+	if _, err := executeCommandWithOutput(cmd, []string{"codegen", "typescript", "--schema-file", filepath.Join(dir, "missing.json")}); err == nil {
+		t.Error("Expected codegen typescript to fail for a missing schema")
+	}
+}
+
+func TestCodegenGoCommand(t *testing.T) {
+	dir := t.TempDir()
+	schemaPath := filepath.Join(dir, "schema.graphqls")
+	sdl := `type Query {
+  user: User
+}
+
+type User {
+  id: ID!
+  name: String
+}`
+	if err := os.WriteFile(schemaPath, []byte(sdl), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	resetCodegenFlags()
+	defer resetCodegenFlags()
+
+	out := filepath.Join(dir, "types.go")
+	cmd := createFullTestCommand()
+	if _, err := executeCommandWithOutput(cmd, []string{"codegen", "go", "--schema-file", schemaPath, "--package", "api", "--out", out}); err != nil {
+		t.Fatalf("codegen go failed: %v", err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("Expected the types to be written: %v", err)
+	}
+	for _, want := range []string{"package api", "type User struct {", "Name *string `json:\"name\"`"} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("Expected %q in the output, got:\n%s", want, got)
+		}
+	}
+
+	resetCodegenFlags()
+	cmd = createFullTestCommand()
+	if _, err := executeCommandWithOutput(cmd, []string{"codegen", "go", "--schema-file", schemaPath, "--package", "not-valid"}); err == nil {
+		t.Error("Expected codegen go to fail for an invalid package name")
+	}
+}
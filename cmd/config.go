@@ -1,7 +1,16 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/kluzzebass/gqlt"
 	"github.com/spf13/cobra"
@@ -13,6 +22,7 @@ func setupFormatter(cmd *cobra.Command) gqlt.Formatter {
 	formatter := gqlt.NewFormatter(outputFormat)
 	formatter.SetOutput(cmd.OutOrStdout())
 	formatter.SetErrorOutput(cmd.ErrOrStderr())
+	setCompact(cmd, formatter, cmd.OutOrStdout())
 	return formatter
 }
 
@@ -22,6 +32,11 @@ var configCmd = &cobra.Command{
 	Long: `Manage gqlt configuration files with support for multiple named configurations.
 This allows you to store different settings for different environments (production, staging, local, etc.).
 
+The active configuration is chosen by --use-config, then the GQLT_CONFIG environment
+variable, then the current configuration. A GQLT_CONFIG naming a configuration that
+doesn't exist is an error. GQLT_ENDPOINT overrides the endpoint of the active
+configuration; an explicit --url still takes precedence.
+
 AI-FRIENDLY FEATURES:
 - Structured output with --format json|table|yaml
 - Machine-readable error codes
@@ -53,6 +68,10 @@ gqlt config set myapi auth.api_key "api-key"
 # Clone configuration
 gqlt config clone production staging
 
+# Share a configuration without credentials
+gqlt config export production --no-secrets --out-file production.json
+gqlt config import production.json
+
 # Structured output for AI agents
 gqlt config list --format json --quiet
 gqlt config show --format yaml`,
@@ -65,16 +84,22 @@ func init() {
 var configShowCmd = &cobra.Command{
 	Use:   "show [name]",
 	Short: "Show current or named configuration",
-	Long:  "Show the current configuration or a specific named configuration.",
-	Args:  cobra.MaximumNArgs(1),
-	RunE:  configShow,
+	Long: `Show the current configuration or a specific named configuration.
+Secrets (auth credentials and credential headers) are masked unless --reveal is given.`,
+	Example: `gqlt config show production
+gqlt config show production --reveal`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: configShow,
 }
 
 var configListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all configurations",
-	Long:  "List all available configurations with their current status.",
-	RunE:  configList,
+	Long: `List all available configurations with their current status.
+Secrets (auth credentials and credential headers) are masked unless --reveal is given.`,
+	Example: `gqlt config list
+gqlt config list --reveal`,
+	RunE: configList,
 }
 
 var configCreateCmd = &cobra.Command{
@@ -104,6 +129,20 @@ var configUseCmd = &cobra.Command{
 	RunE:  configUse,
 }
 
+var configCurrentCmd = &cobra.Command{
+	Use:   "current",
+	Short: "Print the active configuration name and endpoint",
+	Long: `Print the name and endpoint of the active configuration.
+
+The active configuration is resolved the same way as for other commands
+(--use-config, then GQLT_CONFIG, then the current selection). With --quiet
+only the name is printed, which is handy for shell prompts.`,
+	Example: `gqlt config current
+gqlt config current --quiet`,
+	Args: cobra.NoArgs,
+	RunE: configCurrent,
+}
+
 var configSetCmd = &cobra.Command{
 	Use:   "set <name> <key> <value>",
 	Short: "Set a configuration value",
@@ -118,13 +157,26 @@ Available keys:
   auth.username               - Username for basic authentication
   auth.password               - Password for basic authentication
   auth.api_key                - API key for authentication
+  oauth2.token_url            - OAuth2 token endpoint for the client credentials flow
+  oauth2.client_id            - OAuth2 client ID
+  oauth2.client_secret        - OAuth2 client secret
+  oauth2.scopes               - OAuth2 scopes to request (comma or space separated)
+  base                        - Name of a configuration to inherit endpoint, headers and auth from
+  defaults.query              - Query used by 'gqlt run' when --query and --query-file are not given
+  defaults.operation          - Operation name used with defaults.query when --operation is not given
+  defaults.variables          - JSON object of variables used with defaults.query when --vars and --vars-file are not given
+  schema.refresh              - Age (e.g. 24h) after which 'gqlt run' re-introspects the saved schema
   defaults.out                - Default output mode (json|pretty|raw)
 
 Authentication precedence:
   1. Basic auth (auth.username + auth.password)
   2. Bearer token (auth.token)
   3. API key (auth.api_key)
-  4. Custom headers (headers.Authorization, headers.X-API-Key)`,
+  4. Custom headers (headers.Authorization, headers.X-API-Key)
+
+With oauth2.token_url set and no credentials given on the command line,
+'gqlt run' fetches a bearer token with the OAuth2 client credentials flow and
+fetches a new one when it expires.`,
 	Example: `# Basic configuration
 gqlt config set production endpoint https://api.example.com/graphql
 gqlt config set production defaults.out pretty
@@ -135,6 +187,18 @@ gqlt config set production auth.username "admin"
 gqlt config set production auth.password "secret"
 gqlt config set production auth.api_key "api-key-123"
 
+# OAuth2 client credentials
+gqlt config set production oauth2.token_url https://auth.example.com/oauth/token
+gqlt config set production oauth2.client_id "my-client"
+gqlt config set production oauth2.client_secret "my-secret"
+gqlt config set production oauth2.scopes "read:users,write:users"
+
+# Inherit shared settings from another configuration
+gqlt config set staging base common
+
+# Keep the saved schema at most a day old
+gqlt config set production schema.refresh 24h
+
 # Custom headers
 gqlt config set production headers.X-Custom "custom-value"
 gqlt config set production headers.Authorization "Bearer manual-token"
@@ -144,11 +208,16 @@ gqlt config set production headers.X-API-Key "manual-api-key"`,
 }
 
 var configInitCmd = &cobra.Command{
-	Use:     "init",
-	Short:   "Initialize configuration file",
-	Long:    "Create a new configuration file with default settings.",
-	Example: `gqlt config init`,
-	RunE:    configInit,
+	Use:   "init",
+	Short: "Initialize configuration file",
+	Long: `Create a new configuration file with default settings.
+
+With --interactive, prompt for a configuration name, endpoint and auth method
+(token, basic, api-key or none) and write the populated configuration. When
+stdin is not a terminal, the prompts are skipped and defaults are written.`,
+	Example: `gqlt config init
+gqlt config init --interactive`,
+	RunE: configInit,
 }
 
 var configValidateCmd = &cobra.Command{
@@ -166,16 +235,79 @@ var configCloneCmd = &cobra.Command{
 	RunE:  configClone,
 }
 
+var configExportCmd = &cobra.Command{
+	Use:   "export <name>",
+	Short: "Export a configuration for sharing",
+	Long: `Export a single named configuration as JSON to stdout or a file.
+Use --no-secrets to strip auth credentials and credential headers before sharing.`,
+	Example: `gqlt config export production
+gqlt config export production --no-secrets --out-file production.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: configExport,
+}
+
+var configImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import a configuration from a file",
+	Long: `Import a configuration written by 'gqlt config export' into the configuration file.
+Fails if a configuration with the same name exists, unless --force is given.`,
+	Example: `gqlt config import production.json
+gqlt config import production.json --name prod-copy
+gqlt config import production.json --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: configImport,
+}
+
+var configPingCmd = &cobra.Command{
+	Use:   "ping [name]",
+	Short: "Check that configured endpoints are reachable",
+	Long: `Send a minimal { __typename } query to the endpoint of the named configuration,
+or of every configuration with an endpoint if no name is given, with the
+configuration's headers and credentials, and report whether each one answered
+and how long it took.
+
+Fails if any endpoint is unreachable.`,
+	Example: `gqlt config ping
+gqlt config ping production --timeout 2s
+gqlt config ping --format table`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: configPing,
+}
+
+var (
+	configShowReveal      bool
+	configListReveal      bool
+	configExportNoSecrets bool
+	configExportOutFile   string
+	configImportName      string
+	configImportForce     bool
+	configInitInteractive bool
+	configPingTimeout     time.Duration
+)
+
 func init() {
 	configCmd.AddCommand(configShowCmd)
 	configCmd.AddCommand(configListCmd)
 	configCmd.AddCommand(configCreateCmd)
 	configCmd.AddCommand(configDeleteCmd)
 	configCmd.AddCommand(configUseCmd)
+	configCmd.AddCommand(configCurrentCmd)
 	configCmd.AddCommand(configSetCmd)
 	configCmd.AddCommand(configInitCmd)
 	configCmd.AddCommand(configValidateCmd)
 	configCmd.AddCommand(configCloneCmd)
+	configCmd.AddCommand(configExportCmd)
+	configCmd.AddCommand(configImportCmd)
+	configCmd.AddCommand(configPingCmd)
+
+	configShowCmd.Flags().BoolVar(&configShowReveal, "reveal", false, "show secrets in full instead of masking them")
+	configListCmd.Flags().BoolVar(&configListReveal, "reveal", false, "show secrets in full instead of masking them")
+	configExportCmd.Flags().BoolVar(&configExportNoSecrets, "no-secrets", false, "strip auth credentials and credential headers")
+	configExportCmd.Flags().StringVar(&configExportOutFile, "out-file", "", "write the export to a file instead of stdout")
+	configImportCmd.Flags().StringVar(&configImportName, "name", "", "import under this name instead of the exported name")
+	configImportCmd.Flags().BoolVar(&configImportForce, "force", false, "overwrite an existing configuration with the same name")
+	configInitCmd.Flags().BoolVarP(&configInitInteractive, "interactive", "i", false, "prompt for name, endpoint and auth method")
+	configPingCmd.Flags().DurationVar(&configPingTimeout, "timeout", 5*time.Second, "how long to wait for each endpoint")
 }
 
 func configShow(cmd *cobra.Command, args []string) error {
@@ -202,6 +334,10 @@ func configShow(cmd *cobra.Command, args []string) error {
 		return formatter.FormatStructuredError(fmt.Errorf("configuration '%s' does not exist", name), "CONFIG_NOT_FOUND", quietMode)
 	}
 
+	if !configShowReveal {
+		entry = maskConfigEntry(entry)
+	}
+
 	quietMode := cmd.Flag("quiet").Value.String() == "true"
 	formatter := setupFormatter(cmd)
 	return formatter.FormatStructured(entry, quietMode)
@@ -215,9 +351,17 @@ func configList(cmd *cobra.Command, args []string) error {
 		return formatter.FormatStructuredError(err, "CONFIG_LOAD_ERROR", quietMode)
 	}
 
+	configs := cfg.Configs
+	if !configListReveal {
+		configs = make(map[string]gqlt.ConfigEntry, len(cfg.Configs))
+		for name, entry := range cfg.Configs {
+			configs[name] = maskConfigEntry(entry)
+		}
+	}
+
 	quietMode := cmd.Flag("quiet").Value.String() == "true"
 	formatter := setupFormatter(cmd)
-	return formatter.FormatStructured(cfg.Configs, quietMode)
+	return formatter.FormatStructured(configs, quietMode)
 }
 
 func configCreate(cmd *cobra.Command, args []string) error {
@@ -289,6 +433,26 @@ func configUse(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func configCurrent(cmd *cobra.Command, args []string) error {
+	quietMode := cmd.Flag("quiet").Value.String() == "true"
+	formatter := setupFormatter(cmd)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return formatter.FormatStructuredError(err, "CONFIG_LOAD_ERROR", quietMode)
+	}
+
+	name, entry, err := cfg.ResolveActive(configName)
+	if err != nil {
+		formatter.FormatStructuredError(err, "CONFIG_LOAD_ERROR", quietMode)
+		return err
+	}
+	if quietMode {
+		return formatter.FormatStructured(name, quietMode)
+	}
+	return formatter.FormatStructured(map[string]string{"name": name, "endpoint": entry.Endpoint}, quietMode)
+}
+
 func configSet(cmd *cobra.Command, args []string) error {
 	cfg, err := loadConfig()
 	if err != nil {
@@ -300,6 +464,9 @@ func configSet(cmd *cobra.Command, args []string) error {
 	value := args[2]
 
 	if err := cfg.SetValue(name, key, value); err != nil {
+		quietMode := cmd.Flag("quiet").Value.String() == "true"
+		formatter := setupFormatter(cmd)
+		formatter.FormatStructuredError(err, gqlt.ErrorCodeConfigValidate, quietMode)
 		return err
 	}
 
@@ -314,6 +481,12 @@ func configSet(cmd *cobra.Command, args []string) error {
 func configInit(cmd *cobra.Command, args []string) error {
 	cfg := gqlt.GetDefaultConfig()
 
+	if configInitInteractive && isInteractive(cmd.InOrStdin()) {
+		if err := runInitWizard(cfg, cmd.InOrStdin(), cmd.OutOrStdout()); err != nil {
+			return err
+		}
+	}
+
 	if err := cfg.Save(configDir); err != nil {
 		return fmt.Errorf("failed to create config file: %w", err)
 	}
@@ -323,6 +496,99 @@ func configInit(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// isInteractive reports whether prompts can be shown on in. Readers that are
+// not files (such as scripted input in tests) are treated as interactive.
+func isInteractive(in io.Reader) bool {
+	f, ok := in.(*os.File)
+	if !ok {
+		return true
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// runInitWizard prompts for a configuration name, endpoint and auth method
+// and stores the answers in cfg, making the new configuration current.
+func runInitWizard(cfg *gqlt.Config, in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	prompt := func(question, def string) (string, error) {
+		if def != "" {
+			fmt.Fprintf(out, "%s [%s]: ", question, def)
+		} else {
+			fmt.Fprintf(out, "%s: ", question)
+		}
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		if answer := strings.TrimSpace(line); answer != "" {
+			return answer, nil
+		}
+		return def, nil
+	}
+
+	name, err := prompt("Configuration name", "default")
+	if err != nil {
+		return err
+	}
+	if _, exists := cfg.Configs[name]; !exists {
+		if err := cfg.Create(name); err != nil {
+			return err
+		}
+	}
+	if err := cfg.SetCurrent(name); err != nil {
+		return err
+	}
+
+	endpoint, err := prompt("GraphQL endpoint", "")
+	if err != nil {
+		return err
+	}
+	if endpoint != "" {
+		if err := cfg.SetValue(name, "endpoint", endpoint); err != nil {
+			return err
+		}
+	}
+
+	method, err := prompt("Auth method (token/basic/api-key/oauth2/none)", "none")
+	if err != nil {
+		return err
+	}
+
+	var fields [][2]string
+	switch strings.ToLower(method) {
+	case "none":
+	case "token":
+		fields = [][2]string{{"Token", "auth.token"}}
+	case "basic":
+		fields = [][2]string{{"Username", "auth.username"}, {"Password", "auth.password"}}
+	case "api-key":
+		fields = [][2]string{{"API key", "auth.api_key"}}
+	case "oauth2":
+		fields = [][2]string{
+			{"Token URL", "oauth2.token_url"},
+			{"Client ID", "oauth2.client_id"},
+			{"Client secret", "oauth2.client_secret"},
+			{"Scopes", "oauth2.scopes"},
+		}
+	default:
+		return fmt.Errorf("unknown auth method '%s' (expected token, basic, api-key, oauth2 or none)", method)
+	}
+	for _, field := range fields {
+		value, err := prompt(field[0], "")
+		if err != nil {
+			return err
+		}
+		if err := cfg.SetValue(name, field[1], value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func configValidate(cmd *cobra.Command, args []string) error {
 	cfg, err := loadConfig()
 	if err != nil {
@@ -374,8 +640,221 @@ func configClone(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func configExport(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	entry, err := cfg.Export(name)
+	if err != nil {
+		return err
+	}
+	if configExportNoSecrets {
+		entry = entry.WithoutSecrets()
+	}
+
+	data, err := json.MarshalIndent(gqlt.ConfigExport{Name: name, Config: entry}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+	data = append(data, '\n')
+
+	if configExportOutFile == "" {
+		_, err := cmd.OutOrStdout().Write(data)
+		return err
+	}
+
+	if err := os.WriteFile(configExportOutFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write export file: %w", err)
+	}
+	fmt.Printf("Exported configuration '%s' to %s\n", name, configExportOutFile)
+	return nil
+}
+
+func configImport(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read import file: %w", err)
+	}
+
+	var export gqlt.ConfigExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return fmt.Errorf("failed to parse import file: %w", err)
+	}
+
+	name := export.Name
+	if configImportName != "" {
+		name = configImportName
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.Import(export.Config, name, configImportForce); err != nil {
+		return fmt.Errorf("%w (use --force to overwrite)", err)
+	}
+
+	if err := cfg.Save(configDir); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("Imported configuration '%s'\n", name)
+	return nil
+}
+
 // Helper functions
 
+// maskConfigEntry returns a copy of the entry with auth credentials and credential
+// headers masked, keeping only their last four characters
+func maskConfigEntry(entry gqlt.ConfigEntry) gqlt.ConfigEntry {
+	masked := entry
+	masked.Auth.Token = maskSecret(entry.Auth.Token)
+	masked.Auth.Password = maskSecret(entry.Auth.Password)
+	masked.Auth.APIKey = maskSecret(entry.Auth.APIKey)
+	masked.OAuth2.ClientSecret = maskSecret(entry.OAuth2.ClientSecret)
+
+	masked.Headers = make(map[string]string, len(entry.Headers))
+	for k, v := range entry.Headers {
+		if gqlt.IsSecretHeader(k) {
+			v = maskSecret(v)
+		}
+		masked.Headers[k] = v
+	}
+	return masked
+}
+
+// maskSecret replaces all but the last four characters of a secret with asterisks.
+// Secrets of four characters or fewer are masked completely.
+func maskSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	if len(secret) <= 4 {
+		return "****"
+	}
+	return "****" + secret[len(secret)-4:]
+}
+
+// pingResult is the health of one configuration's endpoint
+type pingResult struct {
+	Name      string `json:"name"`
+	Endpoint  string `json:"endpoint"`
+	Reachable bool   `json:"reachable"`
+	LatencyMs int64  `json:"latencyMs"`
+	Error     string `json:"error,omitempty"`
+}
+
+func configPing(cmd *cobra.Command, args []string) error {
+	quietMode := cmd.Flag("quiet").Value.String() == "true"
+	formatter := setupFormatter(cmd)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return formatter.FormatStructuredError(err, "CONFIG_LOAD_ERROR", quietMode)
+	}
+
+	// Without a name, every configuration that has an endpoint is pinged
+	var entries []gqlt.ConfigEntry
+	results := []pingResult{}
+	if len(args) > 0 {
+		entry, err := cfg.Resolve(args[0])
+		if _, exists := cfg.Configs[args[0]]; !exists {
+			formatter.FormatStructuredError(err, gqlt.ErrorCodeConfigNotFound, quietMode)
+			return err
+		}
+		entries = append(entries, entry)
+		results = append(results, pingResult{Name: args[0], Endpoint: entry.Endpoint})
+	} else {
+		names := make([]string, 0, len(cfg.Configs))
+		for name := range cfg.Configs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			entry, _ := cfg.Resolve(name)
+			if entry.Endpoint == "" {
+				continue
+			}
+			entries = append(entries, entry)
+			results = append(results, pingResult{Name: name, Endpoint: entry.Endpoint})
+		}
+	}
+
+	// Ping the endpoints concurrently, so one that hangs doesn't hold up the others
+	errs := make([]error, len(entries))
+	var wg sync.WaitGroup
+	for i, entry := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i].LatencyMs, errs[i] = pingEndpoint(cmd.Context(), entry)
+		}()
+	}
+	wg.Wait()
+
+	var firstErr error
+	unreachable := 0
+	for i := range results {
+		if errs[i] == nil {
+			results[i].Reachable = true
+			continue
+		}
+		results[i].Error = errs[i].Error()
+		unreachable++
+		if firstErr == nil {
+			firstErr = errs[i]
+		}
+	}
+
+	if err := formatter.FormatStructured(results, quietMode); err != nil {
+		return err
+	}
+	if unreachable > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d of %d endpoints unreachable: %w", unreachable, len(results), firstErr)
+	}
+	return nil
+}
+
+// pingEndpoint sends a { __typename } query to the endpoint of a configuration,
+// returning how long the server took to answer
+func pingEndpoint(ctx context.Context, entry gqlt.ConfigEntry) (int64, error) {
+	if entry.Endpoint == "" {
+		return 0, fmt.Errorf("no endpoint configured")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, configPingTimeout)
+	defer cancel()
+
+	client := gqlt.NewClient(entry.Endpoint, entry.Headers)
+	switch {
+	case entry.Auth.Username != "" && entry.Auth.Password != "":
+		client.SetAuth(entry.Auth.Username, entry.Auth.Password)
+	case entry.Auth.Token != "":
+		client.SetHeaders(map[string]string{"Authorization": "Bearer " + entry.Auth.Token})
+	case entry.Auth.APIKey != "":
+		client.SetHeaders(map[string]string{"X-API-Key": entry.Auth.APIKey})
+	case entry.OAuth2.TokenURL != "":
+		client.SetOAuth2(entry.OAuth2.TokenURL, entry.OAuth2.ClientID, entry.OAuth2.ClientSecret, entry.OAuth2.Scopes)
+	}
+	status := recordStatus(client)
+
+	start := time.Now()
+	_, err := client.ExecuteContext(ctx, "{ __typename }", nil, "")
+	latency := time.Since(start).Milliseconds()
+	if err == nil && *status >= 400 {
+		err = &httpStatusError{statusCode: *status}
+	}
+	return latency, err
+}
+
 func loadConfig() (*gqlt.Config, error) {
 	// Use the global configDir variable
 	return gqlt.Load(configDir)
@@ -1,8 +1,17 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/kluzzebass/gqlt"
 )
 
 func TestConfigInit(t *testing.T) {
@@ -211,3 +220,345 @@ func TestConfigSetNonExistentConfig(t *testing.T) {
 
 	// NOTE: Output is suppressed. Error return confirms the validation worked.
 }
+
+func TestConfigExportImport(t *testing.T) {
+	configDir = t.TempDir()
+	defer func() {
+		configDir = ""
+		configExportNoSecrets = false
+		configExportOutFile = ""
+		configImportName = ""
+		configImportForce = false
+	}()
+
+	cmd := createTestCommand()
+	for _, args := range [][]string{
+		{"config", "init"},
+		{"config", "create", "production"},
+		{"config", "set", "production", "endpoint", "https://api.example.com/graphql"},
+		{"config", "set", "production", "auth.token", "secret-token"},
+	} {
+		cmd.SetArgs(args)
+		if err := executeCommand(cmd); err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+	}
+
+	exportPath := filepath.Join(t.TempDir(), "production.json")
+	cmd.SetArgs([]string{"config", "export", "production", "--no-secrets", "--out-file", exportPath})
+	if err := executeCommand(cmd); err != nil {
+		t.Fatalf("config export failed: %v", err)
+	}
+
+	data, err := os.ReadFile(exportPath)
+	if err != nil {
+		t.Fatalf("Failed to read export file: %v", err)
+	}
+	if strings.Contains(string(data), "secret-token") {
+		t.Errorf("Expected export without secrets, got:\n%s", data)
+	}
+
+	// Importing under the existing name collides
+	cmd.SetArgs([]string{"config", "import", exportPath})
+	if err := executeCommand(cmd); err == nil {
+		t.Error("Expected import to fail on name collision")
+	}
+
+	cmd.SetArgs([]string{"config", "import", exportPath, "--name", "shared"})
+	if err := executeCommand(cmd); err != nil {
+		t.Fatalf("config import failed: %v", err)
+	}
+
+	cfg, err := gqlt.Load(configDir)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	shared, ok := cfg.Configs["shared"]
+	if !ok {
+		t.Fatal("Expected imported configuration 'shared' to exist")
+	}
+	if shared.Endpoint != "https://api.example.com/graphql" || shared.Auth.Token != "" {
+		t.Errorf("Unexpected imported configuration: %+v", shared)
+	}
+}
+
+func TestConfigSetInvalidEndpoint(t *testing.T) {
+	configDir = t.TempDir()
+	defer func() {
+		configDir = ""
+	}()
+
+	cmd := createTestCommand()
+	cmd.SetArgs([]string{"config", "init"})
+	if err := executeCommand(cmd); err != nil {
+		t.Fatalf("config init failed: %v", err)
+	}
+
+	errorCmd := createTestCommand()
+	if _, err := executeCommandWithOutput(errorCmd, []string{"config", "set", "default", "endpoint", "htp://api.example.com/graphql"}); err == nil {
+		t.Error("Expected error for malformed endpoint URL")
+	}
+}
+
+func TestMaskConfigEntry(t *testing.T) {
+	entry := gqlt.ConfigEntry{
+		Endpoint: "https://api.example.com/graphql",
+		Headers: map[string]string{
+			"Authorization": "Bearer abcdefgh1234",
+			"X-Tenant":      "acme",
+		},
+	}
+	entry.Auth.Token = "token-5678"
+	entry.Auth.Username = "admin"
+	entry.Auth.Password = "abc"
+
+	masked := maskConfigEntry(entry)
+	if masked.Auth.Token != "****5678" {
+		t.Errorf("Expected masked token, got %q", masked.Auth.Token)
+	}
+	if masked.Auth.Password != "****" {
+		t.Errorf("Expected short password fully masked, got %q", masked.Auth.Password)
+	}
+	if masked.Auth.Username != "admin" {
+		t.Errorf("Expected username to be shown, got %q", masked.Auth.Username)
+	}
+	if masked.Headers["Authorization"] != "****1234" || masked.Headers["X-Tenant"] != "acme" {
+		t.Errorf("Unexpected masked headers: %v", masked.Headers)
+	}
+	if entry.Auth.Token != "token-5678" || entry.Headers["Authorization"] != "Bearer abcdefgh1234" {
+		t.Error("Expected the original entry to be untouched")
+	}
+}
+
+func TestConfigShowReveal(t *testing.T) {
+	configDir = t.TempDir()
+	defer func() {
+		configDir = ""
+		configShowReveal = false
+	}()
+
+	cfg := gqlt.GetDefaultConfig()
+	cfg.SetValue("default", "auth.token", "super-secret-token")
+	if err := cfg.Save(configDir); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	show := func(args ...string) string {
+		var out bytes.Buffer
+		cmd := createTestCommand()
+		cmd.SetOut(&out)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(append([]string{"config", "show", "default"}, args...))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("config show failed: %v", err)
+		}
+		return out.String()
+	}
+
+	if out := show(); strings.Contains(out, "super-secret-token") || !strings.Contains(out, "****oken") {
+		t.Errorf("Expected token to be masked by default, got:\n%s", out)
+	}
+	if out := show("--reveal"); !strings.Contains(out, "super-secret-token") {
+		t.Errorf("Expected token in full with --reveal, got:\n%s", out)
+	}
+}
+
+func TestConfigListReveal(t *testing.T) {
+	configDir = t.TempDir()
+	defer func() {
+		configDir = ""
+		configListReveal = false
+	}()
+
+	cfg := gqlt.GetDefaultConfig()
+	cfg.SetValue("default", "auth.token", "super-secret-token")
+	cfg.Create("staging")
+	cfg.SetValue("staging", "oauth2.client_secret", "staging-client-secret")
+	if err := cfg.Save(configDir); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	list := func(args ...string) string {
+		var out bytes.Buffer
+		cmd := createTestCommand()
+		cmd.SetOut(&out)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(append([]string{"config", "list"}, args...))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("config list failed: %v", err)
+		}
+		return out.String()
+	}
+
+	out := list()
+	if strings.Contains(out, "super-secret-token") || strings.Contains(out, "staging-client-secret") || !strings.Contains(out, "****oken") {
+		t.Errorf("Expected secrets to be masked by default, got:\n%s", out)
+	}
+	out = list("--reveal")
+	if !strings.Contains(out, "super-secret-token") || !strings.Contains(out, "staging-client-secret") {
+		t.Errorf("Expected secrets in full with --reveal, got:\n%s", out)
+	}
+}
+
+func TestConfigCurrent(t *testing.T) {
+	configDir = t.TempDir()
+	defer func() { configDir = "" }()
+
+	for _, args := range [][]string{
+		{"config", "init"},
+		{"config", "create", "staging"},
+		{"config", "set", "staging", "endpoint", "https://staging.example.com/graphql"},
+		{"config", "use", "staging"},
+	} {
+		if _, err := executeCommandWithOutput(createTestCommand(), args); err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+	}
+
+	current := func(args ...string) string {
+		var out bytes.Buffer
+		cmd := createTestCommand()
+		cmd.SetOut(&out)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(append([]string{"config", "current"}, args...))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("config current failed: %v", err)
+		}
+		return out.String()
+	}
+
+	out := current()
+	if !strings.Contains(out, `"name":"staging"`) || !strings.Contains(out, "https://staging.example.com/graphql") {
+		t.Errorf("Expected name and endpoint in output, got:\n%s", out)
+	}
+	if out := current("--format", "table", "--quiet"); out != "staging\n" {
+		t.Errorf("Expected just the config name in quiet mode, got %q", out)
+	}
+
+	// A mistyped GQLT_CONFIG fails instead of quietly using the current configuration
+	t.Setenv(gqlt.EnvConfig, "stagign")
+	cmd := createTestCommand()
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"config", "current"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "stagign") {
+		t.Errorf("Expected an error naming the unknown configuration, got %v", err)
+	}
+}
+
+func TestConfigInitInteractive(t *testing.T) {
+	configDir = t.TempDir()
+	defer func() {
+		configDir = ""
+		configInitInteractive = false
+	}()
+
+	cmd := createTestCommand()
+	cmd.SetIn(strings.NewReader("staging\nhttps://staging.example.com/graphql\nbasic\nadmin\nhunter2\n"))
+	cmd.SetOut(io.Discard)
+	if _, err := executeCommandWithOutput(cmd, []string{"config", "init", "--interactive"}); err != nil {
+		t.Fatalf("config init --interactive failed: %v", err)
+	}
+
+	cfg, err := gqlt.Load(configDir)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Current != "staging" {
+		t.Errorf("Expected current config 'staging', got %q", cfg.Current)
+	}
+	entry := cfg.Configs["staging"]
+	if entry.Endpoint != "https://staging.example.com/graphql" {
+		t.Errorf("Unexpected endpoint %q", entry.Endpoint)
+	}
+	if entry.Auth.Username != "admin" || entry.Auth.Password != "hunter2" {
+		t.Errorf("Unexpected auth: %+v", entry.Auth)
+	}
+	if _, exists := cfg.Configs["default"]; !exists {
+		t.Error("Expected default configuration to be kept")
+	}
+}
+
+func TestConfigInitInteractiveRejectsUnknownAuth(t *testing.T) {
+	configDir = t.TempDir()
+	defer func() {
+		configDir = ""
+		configInitInteractive = false
+	}()
+
+	cmd := createTestCommand()
+	cmd.SetIn(strings.NewReader("\n\nkerberos\n"))
+	cmd.SetOut(io.Discard)
+	if _, err := executeCommandWithOutput(cmd, []string{"config", "init", "--interactive"}); err == nil {
+		t.Fatal("Expected error for unknown auth method")
+	}
+}
+
+func TestConfigPing(t *testing.T) {
+	configDir = t.TempDir()
+	defer func() { configDir = "" }()
+
+	var authorization string
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"__typename":"Query"}}`))
+	}))
+	defer up.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	downURL := down.URL
+	down.Close()
+
+	cfg := gqlt.GetDefaultConfig()
+	cfg.Create("up")
+	cfg.SetValue("up", "endpoint", up.URL)
+	cfg.SetValue("up", "auth.token", "secret")
+	cfg.Create("down")
+	cfg.SetValue("down", "endpoint", downURL)
+	if err := cfg.Save(configDir); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	ping := func(args ...string) ([]pingResult, error) {
+		var out bytes.Buffer
+		cmd := createTestCommand()
+		cmd.SetOut(&out)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(append([]string{"config", "ping", "--format", "json"}, args...))
+		err := cmd.Execute()
+
+		var output struct {
+			Data []pingResult `json:"data"`
+		}
+		if jsonErr := json.Unmarshal(out.Bytes(), &output); jsonErr != nil {
+			t.Fatalf("Failed to parse output %q: %v", out.String(), jsonErr)
+		}
+		return output.Data, err
+	}
+
+	// The default configuration has no endpoint and is left out
+	results, err := ping()
+	if err == nil || exitCodeForError(err) != exitCodeNetwork {
+		t.Errorf("Expected a network error for the unreachable endpoint, got %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected results for two configurations, got %+v", results)
+	}
+	if r := results[0]; r.Name != "down" || r.Endpoint != downURL || r.Reachable || r.Error == "" {
+		t.Errorf("Expected down to be unreachable, got %+v", r)
+	}
+	if r := results[1]; r.Name != "up" || r.Endpoint != up.URL || !r.Reachable || r.Error != "" {
+		t.Errorf("Expected up to be reachable, got %+v", r)
+	}
+	if authorization != "Bearer secret" {
+		t.Errorf("Expected the configured token to be sent, got %q", authorization)
+	}
+
+	results, err = ping("up")
+	if err != nil {
+		t.Errorf("Expected pinging up to succeed: %v", err)
+	}
+	if len(results) != 1 || !results[0].Reachable {
+		t.Errorf("Expected up to be reachable, got %+v", results)
+	}
+}
@@ -25,56 +25,162 @@ gqlt describe Query.users
 gqlt describe User --json
 
 # Show summary only
-gqlt describe User --summary`,
+gqlt describe User --summary
+
+# Output the SDL definition of a type
+gqlt describe User --sdl
+
+# List deprecated fields and enum values
+gqlt describe deprecated
+
+# Generate an example query for a Query field
+gqlt describe example user
+
+# Show schema statistics
+gqlt describe stats --format yaml
+
+# Generate a JSON Schema for an input type
+gqlt describe jsonschema CreateUserInput
+
+# List the fields, arguments and input fields that use a type
+gqlt describe references User`,
 	Args: cobra.ExactArgs(1),
 	RunE: describe,
 }
 
+var describeDeprecatedCmd = &cobra.Command{
+	Use:   "deprecated",
+	Short: "List deprecated fields and enum values",
+	Long: `List all deprecated fields of object and interface types and all deprecated
+enum values in the cached schema, along with their deprecation reasons.`,
+	Example: `gqlt describe deprecated
+gqlt describe deprecated --json`,
+	Args: cobra.NoArgs,
+	RunE: describeDeprecated,
+}
+
+var describeExampleCmd = &cobra.Command{
+	Use:   "example <field>",
+	Short: "Generate an example query for a Query field",
+	Long: `Generate an example query for a field of the Query type.
+Scalar fields of the returned type are selected, nested object fields are expanded
+up to --depth levels, and required arguments are stubbed with variables.`,
+	Example: `gqlt describe example user
+gqlt describe example users --depth 2`,
+	Args: cobra.ExactArgs(1),
+	RunE: describeExample,
+}
+
+var describeStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show schema statistics",
+	Long: `Show statistics about the cached schema: the number of types per kind, the
+average number of fields per object type, the most referenced types and the number
+of deprecated fields and enum values. Useful to assess the size of a schema before
+integrating with it.`,
+	Example: `gqlt describe stats
+gqlt describe stats --format yaml`,
+	Args: cobra.NoArgs,
+	RunE: describeStats,
+}
+
+var describeJSONSchemaCmd = &cobra.Command{
+	Use:   "jsonschema <InputType>",
+	Short: "Generate a JSON Schema for an input type",
+	Long: `Generate a JSON Schema document describing the values accepted by an input
+object type, e.g. to drive forms or validate variables. Non-null fields without a
+default are required, enums become string enums, scalars map to the closest JSON
+type and nested input types are referenced from "$defs".`,
+	Example: `gqlt describe jsonschema CreateUserInput
+gqlt describe jsonschema CreateUserInput > create-user.schema.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: describeJSONSchema,
+}
+
+var describeReferencesCmd = &cobra.Command{
+	Use:   "references <Type>",
+	Short: "List the fields, arguments and input fields that use a type",
+	Long: `List every field, argument and input field in the cached schema whose type is
+the given type, in lists or not. Use this to assess what changing or removing the
+type would affect.`,
+	Example: `gqlt describe references User
+gqlt describe references CreateUserInput --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: describeReferences,
+}
+
 var (
 	describeJSON    bool
 	describeSummary bool
 	describeSchema  string
+	describeSDL     bool
+	describeDepth   int
 )
 
 func init() {
 	rootCmd.AddCommand(describeCmd)
-
-	// Define flags
-	describeCmd.Flags().BoolVar(&describeJSON, "json", false, "output exact node JSON")
-	describeCmd.Flags().BoolVar(&describeSummary, "summary", false, "output plain text summary")
-	describeCmd.Flags().StringVar(&describeSchema, "schema", "", "schema file path (default is OS-specific)")
+	describeCmd.AddCommand(describeDeprecatedCmd)
+	describeCmd.AddCommand(describeExampleCmd)
+	describeCmd.AddCommand(describeStatsCmd)
+	describeCmd.AddCommand(describeJSONSchemaCmd)
+	describeCmd.AddCommand(describeReferencesCmd)
+
+	// Define flags (persistent so subcommands share them)
+	describeCmd.PersistentFlags().BoolVar(&describeJSON, "json", false, "output exact node JSON")
+	describeCmd.PersistentFlags().BoolVar(&describeSummary, "summary", false, "output plain text summary")
+	describeCmd.Flags().BoolVar(&describeSDL, "sdl", false, "output the SDL definition of a type")
+	describeCmd.PersistentFlags().StringVar(&describeSchema, "schema", "", "schema file path (default is OS-specific)")
+	describeExampleCmd.Flags().IntVar(&describeDepth, "depth", gqlt.DefaultExampleDepth, "number of object levels to expand")
 }
 
 func describe(cmd *cobra.Command, args []string) error {
+	analyzer, err := loadDescribeAnalyzer()
+	if err != nil {
+		return err
+	}
+
+	return describeTarget(analyzer, args[0])
+}
+
+// loadDescribeAnalyzer loads the schema analyzer from --schema or the
+// cached schema of the current configuration
+func loadDescribeAnalyzer() (*gqlt.Analyzer, error) {
 	// Load configuration
 	cfg, err := gqlt.Load(configDir)
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
 	// Merge config with flags
-	mergeConfigWithFlags(cfg)
+	if err := mergeConfigWithFlags(cfg); err != nil {
+		return nil, err
+	}
 
 	// Determine schema path
 	schemaPath := describeSchema
 	if schemaPath == "" {
 		// Use config-specific schema path
+		activeName, _, err := cfg.ResolveActive(configName)
+		if err != nil {
+			return nil, err
+		}
 		if configDir != "" {
-			schemaPath = gqlt.GetSchemaPathForConfigInDir(cfg.Current, configDir)
+			schemaPath = gqlt.GetSchemaPathForConfigInDir(activeName, configDir)
 		} else {
-			schemaPath = gqlt.GetSchemaPathForConfig(cfg.Current)
+			schemaPath = gqlt.GetSchemaPathForConfig(activeName)
 		}
 	}
 
 	// Load schema analyzer
 	analyzer, err := gqlt.LoadAnalyzerFromFile(schemaPath)
 	if err != nil {
-		return fmt.Errorf("failed to load schema: %w", err)
+		return nil, fmt.Errorf("failed to load schema: %w", err)
 	}
 
-	// Parse the target
-	target := args[0]
+	return analyzer, nil
+}
 
+func describeTarget(analyzer *gqlt.Analyzer, target string) error {
 	// Handle different target formats
 	if strings.HasPrefix(target, "Query.") || strings.HasPrefix(target, "Mutation.") || strings.HasPrefix(target, "Subscription.") {
 		// Field reference: Query.product, Mutation.createUser, etc.
@@ -107,6 +213,15 @@ func describeType(analyzer *gqlt.Analyzer, typeName string) error {
 		return encoder.Encode(typeObj)
 	}
 
+	if describeSDL {
+		sdl, err := analyzer.TypeToSDL(typeName)
+		if err != nil {
+			return err
+		}
+		fmt.Print(sdl)
+		return nil
+	}
+
 	// Output formatted description
 	desc, err := analyzer.GetTypeDescription(typeName)
 	if err != nil {
@@ -167,6 +282,14 @@ func printTypeDescription(desc *gqlt.TypeDescription) error {
 		}
 	}
 
+	// Show implementing types for interfaces
+	if len(desc.Implementors) > 0 {
+		fmt.Printf("\nImplemented By:\n")
+		for _, name := range desc.Implementors {
+			fmt.Printf("  %s\n", name)
+		}
+	}
+
 	// Show enum values if available
 	if len(desc.EnumValues) > 0 {
 		fmt.Printf("\nEnum Values:\n")
@@ -182,6 +305,122 @@ func printTypeDescription(desc *gqlt.TypeDescription) error {
 	return nil
 }
 
+func describeDeprecated(cmd *cobra.Command, args []string) error {
+	analyzer, err := loadDescribeAnalyzer()
+	if err != nil {
+		return err
+	}
+
+	deprecated, err := analyzer.ListDeprecated()
+	if err != nil {
+		return fmt.Errorf("failed to list deprecated fields: %w", err)
+	}
+
+	if describeJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(deprecated)
+	}
+
+	if len(deprecated) == 0 {
+		fmt.Println("No deprecated fields or enum values found")
+		return nil
+	}
+
+	fmt.Printf("Deprecated:\n")
+	for _, d := range deprecated {
+		fmt.Printf("  %s.%s (%s)\n", d.TypeName, d.Name, d.Kind)
+		if d.DeprecationReason != "" {
+			fmt.Printf("    %s\n", d.DeprecationReason)
+		}
+	}
+
+	return nil
+}
+
+func describeExample(cmd *cobra.Command, args []string) error {
+	analyzer, err := loadDescribeAnalyzer()
+	if err != nil {
+		return err
+	}
+
+	exampleQuery, err := analyzer.GenerateExampleQueryWithDepth(args[0], describeDepth)
+	if err != nil {
+		return fmt.Errorf("failed to generate example query: %w", err)
+	}
+
+	fmt.Print(exampleQuery)
+	return nil
+}
+
+func describeStats(cmd *cobra.Command, args []string) error {
+	quietMode := cmd.Flag("quiet").Value.String() == "true"
+	formatter := setupFormatter(cmd)
+
+	analyzer, err := loadDescribeAnalyzer()
+	if err != nil {
+		return formatter.FormatStructuredError(err, gqlt.ErrorCodeSchemaLoad, quietMode)
+	}
+
+	stats, err := analyzer.Stats()
+	if err != nil {
+		return formatter.FormatStructuredError(fmt.Errorf("failed to compute schema statistics: %w", err), gqlt.ErrorCodeSchemaLoad, quietMode)
+	}
+
+	return formatter.FormatStructured(stats, quietMode)
+}
+
+func describeJSONSchema(cmd *cobra.Command, args []string) error {
+	analyzer, err := loadDescribeAnalyzer()
+	if err != nil {
+		return err
+	}
+
+	schema, err := analyzer.InputTypeToJSONSchema(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to generate JSON Schema: %w", err)
+	}
+
+	encoder := json.NewEncoder(cmd.OutOrStdout())
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(schema)
+}
+
+func describeReferences(cmd *cobra.Command, args []string) error {
+	analyzer, err := loadDescribeAnalyzer()
+	if err != nil {
+		return err
+	}
+
+	references, err := analyzer.FindReferences(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to find references: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	if describeJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(references)
+	}
+
+	if len(references) == 0 {
+		fmt.Fprintf(out, "No references to %s found\n", args[0])
+		return nil
+	}
+
+	fmt.Fprintf(out, "References to %s:\n", args[0])
+	for _, r := range references {
+		if r.Argument != "" {
+			fmt.Fprintf(out, "  %s.%s(%s: %s)\n", r.TypeName, r.Field, r.Argument, r.Type)
+		} else {
+			fmt.Fprintf(out, "  %s.%s: %s\n", r.TypeName, r.Field, r.Type)
+		}
+	}
+
+	return nil
+}
+
 func printFieldDescription(desc *gqlt.FieldDescription) error {
 	fmt.Printf("FIELD %s.%s\n", desc.RootType, desc.Name)
 	if desc.Description != "" {
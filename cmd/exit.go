@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/kluzzebass/gqlt"
+	"github.com/spf13/cobra"
+	"golang.org/x/oauth2"
+)
+
+// Exit codes, so scripts and CI pipelines can react to different failures
+const (
+	exitCodeUsage   = 1 // Invalid flags or input, and any other failure
+	exitCodeGraphQL = 2 // The response has GraphQL errors
+	exitCodeNetwork = 3 // The server couldn't be reached or didn't answer in time
+	exitCodeAuth    = 4 // The server answered 401 Unauthorized or 403 Forbidden
+)
+
+// graphQLErrorsError is returned when a response has GraphQL errors. The
+// response has already been written, errors included.
+type graphQLErrorsError struct {
+	count int
+}
+
+func (e *graphQLErrorsError) Error() string {
+	return fmt.Sprintf("operation returned %d GraphQL error(s)", e.count)
+}
+
+// httpStatusError is returned when the server answers with an HTTP error status
+type httpStatusError struct {
+	statusCode int
+	err        error // The error reading the response, if any
+}
+
+func (e *httpStatusError) Error() string {
+	message := fmt.Sprintf("server responded with %d %s", e.statusCode, http.StatusText(e.statusCode))
+	if e.err != nil {
+		message += ": " + e.err.Error()
+	}
+	return message
+}
+
+func (e *httpStatusError) Unwrap() error {
+	return e.err
+}
+
+// exitCodeForError returns the exit code for an error returned by a command
+func exitCodeForError(err error) int {
+	var statusErr *httpStatusError
+	var tokenErr *oauth2.RetrieveError
+	var graphQLErr *graphQLErrorsError
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &statusErr) && (statusErr.statusCode == http.StatusUnauthorized || statusErr.statusCode == http.StatusForbidden):
+		return exitCodeAuth
+	case errors.As(err, &tokenErr):
+		// The OAuth2 token endpoint refused the client credentials
+		return exitCodeAuth
+	case errors.As(err, &graphQLErr):
+		return exitCodeGraphQL
+	case errors.As(err, &netErr), errors.Is(err, context.DeadlineExceeded):
+		return exitCodeNetwork
+	default:
+		return exitCodeUsage
+	}
+}
+
+// errorCodeForError returns the structured error code for a failed operation
+func errorCodeForError(err error) string {
+	switch exitCodeForError(err) {
+	case exitCodeAuth:
+		return gqlt.ErrorCodeAuthError
+	case exitCodeNetwork:
+		return gqlt.ErrorCodeNetworkError
+	default:
+		return gqlt.ErrorCodeGraphQLExecution
+	}
+}
+
+// recordStatus makes the client remember the HTTP status code of its last
+// response in the returned variable
+func recordStatus(client *gqlt.Client) *int {
+	status := new(int)
+	client.SetLogger(func(info gqlt.RequestLog) {
+		*status = info.StatusCode
+	})
+	return status
+}
+
+// reportError writes err as a structured error with the given code and returns it.
+// cobra doesn't print it again, nor the usage, so the output stays machine-readable.
+func reportError(cmd *cobra.Command, err error, code string) error {
+	gqlt.NewFormatter(outputFormat).FormatStructuredError(err, code, quietMode)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return err
+}
+
+// operationError returns the error for an executed operation: the server's
+// auth failure status if there was one, the execution error, or an error for
+// the response's GraphQL errors (unless --accept-partial is given and there is
+// data as well). Errors for responses that have already been written aren't
+// printed again by cobra.
+func operationError(cmd *cobra.Command, result *gqlt.Response, err error, statusCode int) error {
+	if statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden {
+		err = &httpStatusError{statusCode: statusCode, err: err}
+	}
+	if err == nil && result != nil && len(result.Errors) > 0 && !(acceptPartial && result.Data != nil) {
+		err = &graphQLErrorsError{count: len(result.Errors)}
+	}
+	if err != nil {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+	}
+	return err
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/kluzzebass/gqlt"
+	"github.com/spf13/cobra"
+)
+
+var formatCmd = &cobra.Command{
+	Use:   "format",
+	Short: "Pretty-print a GraphQL query",
+	Long: `Parse a GraphQL document and re-print it with consistent indentation.
+Operations, fragments and fields keep their original order and comments are preserved.
+
+The document is read from --query, --query-file or stdin. The formatted result is
+written to stdout, or back to the query file with --write.`,
+	Example: `gqlt format --query-file query.graphql
+gqlt format --query-file query.graphql --write
+echo '{users{id name}}' | gqlt format`,
+	Args: cobra.NoArgs,
+	RunE: formatQuery,
+}
+
+var (
+	formatQueryStr  string
+	formatQueryFile string
+	formatWrite     bool
+)
+
+func init() {
+	rootCmd.AddCommand(formatCmd)
+
+	formatCmd.Flags().StringVarP(&formatQueryStr, "query", "q", "", "GraphQL query to format")
+	formatCmd.Flags().StringVarP(&formatQueryFile, "query-file", "Q", "", "Path to GraphQL query file")
+	formatCmd.Flags().BoolVarP(&formatWrite, "write", "w", false, "Write the result back to the query file instead of stdout")
+}
+
+func formatQuery(cmd *cobra.Command, args []string) error {
+	quietMode := cmd.Flag("quiet").Value.String() == "true"
+	formatter := setupFormatter(cmd)
+
+	if formatWrite && formatQueryFile == "" {
+		err := fmt.Errorf("--write requires --query-file")
+		formatter.FormatStructuredError(err, gqlt.ErrorCodeInputValidation, quietMode)
+		return err
+	}
+
+	var src string
+	if formatQueryStr == "" && formatQueryFile == "" {
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			err = fmt.Errorf("failed to read query from stdin: %w", err)
+			formatter.FormatStructuredError(err, gqlt.ErrorCodeQueryLoad, quietMode)
+			return err
+		}
+		src = string(data)
+	} else {
+		var err error
+		src, err = gqlt.NewInput().LoadQuery(formatQueryStr, formatQueryFile)
+		if err != nil {
+			formatter.FormatStructuredError(err, gqlt.ErrorCodeQueryLoad, quietMode)
+			return err
+		}
+	}
+
+	formatted, err := gqlt.FormatQuery(src)
+	if err != nil {
+		formatter.FormatStructuredError(err, gqlt.ErrorCodeQueryParse, quietMode)
+		return err
+	}
+
+	if formatWrite {
+		if err := os.WriteFile(formatQueryFile, []byte(formatted), 0644); err != nil {
+			err = fmt.Errorf("failed to write query file: %w", err)
+			formatter.FormatStructuredError(err, gqlt.ErrorCodeSystemError, quietMode)
+			return err
+		}
+		return nil
+	}
+
+	fmt.Fprint(cmd.OutOrStdout(), formatted)
+	return nil
+}
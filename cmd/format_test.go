@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func resetFormatFlags() {
+	formatQueryStr = ""
+	formatQueryFile = ""
+	formatWrite = false
+}
+
+func TestFormatCommandStdin(t *testing.T) {
+	defer resetFormatFlags()
+
+	var out bytes.Buffer
+	cmd := createFullTestCommand()
+	cmd.SetIn(strings.NewReader("{users{id name}}"))
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"format"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("format failed: %v", err)
+	}
+
+	want := "query {\n  users {\n    id\n    name\n  }\n}\n"
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+}
+
+func TestFormatCommandWrite(t *testing.T) {
+	defer resetFormatFlags()
+
+	path := filepath.Join(t.TempDir(), "query.graphql")
+	if err := os.WriteFile(path, []byte("query GetUsers{users{id}}"), 0644); err != nil {
+		t.Fatalf("Failed to write query file: %v", err)
+	}
+
+	if _, err := executeCommandWithOutput(createFullTestCommand(), []string{"format", "--query-file", path, "--write"}); err != nil {
+		t.Fatalf("format --write failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read query file: %v", err)
+	}
+	if want := "query GetUsers {\n  users {\n    id\n  }\n}\n"; string(data) != want {
+		t.Errorf("Expected file to contain %q, got %q", want, string(data))
+	}
+}
+
+func TestFormatCommandErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"write without file", []string{"format", "--query", "{ a }", "--write"}},
+		{"syntax error", []string{"format", "--query", "query { a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer resetFormatFlags()
+			if _, err := executeCommandWithOutput(createFullTestCommand(), tt.args); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
@@ -52,16 +52,22 @@ func introspect(cmd *cobra.Command, args []string) error {
 	}
 
 	// Merge config with flags
-	mergeConfigWithFlags(cfg)
+	if err := mergeConfigWithFlags(cfg); err != nil {
+		return err
+	}
+	activeName, current, err := cfg.ResolveActive(configName)
+	if err != nil {
+		return err
+	}
 
 	// Determine output path
 	outputPath := introspectOut
 	if outputPath == "" {
 		// Use config-specific schema path
 		if configDir != "" {
-			outputPath = gqlt.GetSchemaPathForConfigInDir(cfg.Current, configDir)
+			outputPath = gqlt.GetSchemaPathForConfigInDir(activeName, configDir)
 		} else {
-			outputPath = gqlt.GetSchemaPathForConfig(cfg.Current)
+			outputPath = gqlt.GetSchemaPathForConfig(activeName)
 		}
 	}
 
@@ -79,7 +85,6 @@ func introspect(cmd *cobra.Command, args []string) error {
 	// Get endpoint from config or flag
 	endpoint := url
 	if endpoint == "" {
-		current := cfg.GetCurrent()
 		if current.Endpoint == "" {
 			return fmt.Errorf("no endpoint specified. Use --url flag or set endpoint in config")
 		}
@@ -95,7 +100,7 @@ func introspect(cmd *cobra.Command, args []string) error {
 	}
 
 	// Add headers from config
-	if current := cfg.GetCurrent(); current.Headers != nil {
+	if current.Headers != nil {
 		client.SetHeaders(current.Headers)
 	}
 
@@ -116,12 +121,12 @@ func introspect(cmd *cobra.Command, args []string) error {
 	// Save schema to file(s)
 	if configDir != "" {
 		// Use dual format saving (JSON + GraphQL)
-		if err := gqlt.SaveSchemaDual(result, cfg.Current, configDir); err != nil {
+		if err := gqlt.SaveSchemaDual(result, activeName, configDir); err != nil {
 			return fmt.Errorf("failed to save schema: %w", err)
 		}
 		fmt.Printf("Schema saved to %s and %s\n",
-			gqlt.GetJSONSchemaPathForConfigInDir(cfg.Current, configDir),
-			gqlt.GetGraphQLSchemaPathForConfigInDir(cfg.Current, configDir))
+			gqlt.GetJSONSchemaPathForConfigInDir(activeName, configDir),
+			gqlt.GetGraphQLSchemaPathForConfigInDir(activeName, configDir))
 	} else {
 		// Use single JSON format for backward compatibility
 		if err := gqlt.SaveSchema(result, outputPath); err != nil {
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/kluzzebass/gqlt"
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check a GraphQL query for common anti-patterns",
+	Long: `Check a GraphQL query for common anti-patterns:
+
+  UNUSED_VARIABLE    a declared variable is never used
+  UNUSED_FRAGMENT    a fragment is never spread
+  DEEP_NESTING       fields are nested deeper than --max-depth
+  MISSING_TYPENAME   a union or interface field does not select __typename
+
+The query is checked against --schema-file, or the cached schema of the active
+configuration. The command fails when there are findings, so it can be used in CI.`,
+	Example: `gqlt lint --query-file query.graphql --schema-file schema.json
+gqlt lint --query-file query.graphql --max-depth 5 --format json --quiet`,
+	Args: cobra.NoArgs,
+	RunE: lint,
+}
+
+var (
+	lintQuery      string
+	lintQueryFile  string
+	lintSchemaFile string
+	lintMaxDepth   int
+)
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().StringVarP(&lintQuery, "query", "q", "", "GraphQL query to lint")
+	lintCmd.Flags().StringVarP(&lintQueryFile, "query-file", "Q", "", "Path to GraphQL query file")
+	lintCmd.Flags().StringVar(&lintSchemaFile, "schema-file", "", "Schema file (JSON or SDL) to lint against (default is the cached schema of the active configuration)")
+	lintCmd.Flags().IntVar(&lintMaxDepth, "max-depth", gqlt.DefaultLintOptions().MaxDepth, "Deepest field nesting allowed (0 disables the check)")
+}
+
+func lint(cmd *cobra.Command, args []string) error {
+	quietMode := cmd.Flag("quiet").Value.String() == "true"
+	formatter := setupFormatter(cmd)
+
+	queryStr, err := gqlt.NewInput().LoadQuery(lintQuery, lintQueryFile)
+	if err != nil {
+		formatter.FormatStructuredError(err, gqlt.ErrorCodeQueryLoad, quietMode)
+		return err
+	}
+
+	schemaPath := lintSchemaFile
+	if schemaPath == "" {
+		cfg, err := gqlt.Load(configDir)
+		if err != nil {
+			formatter.FormatStructuredError(err, gqlt.ErrorCodeConfigLoad, quietMode)
+			return err
+		}
+		activeName, _, err := cfg.ResolveActive(configName)
+		if err != nil {
+			formatter.FormatStructuredError(err, gqlt.ErrorCodeConfigLoad, quietMode)
+			return err
+		}
+		if configDir != "" {
+			schemaPath = gqlt.GetSchemaPathForConfigInDir(activeName, configDir)
+		} else {
+			schemaPath = gqlt.GetSchemaPathForConfig(activeName)
+		}
+	}
+
+	analyzer, err := gqlt.LoadAnalyzerFromFile(schemaPath)
+	if err != nil {
+		err = fmt.Errorf("failed to load schema: %w", err)
+		formatter.FormatStructuredError(err, gqlt.ErrorCodeSchemaLoad, quietMode)
+		return err
+	}
+
+	findings, err := analyzer.LintQuery(queryStr, gqlt.LintOptions{MaxDepth: lintMaxDepth})
+	if err != nil {
+		formatter.FormatStructuredError(err, gqlt.ErrorCodeQueryValidation, quietMode)
+		return err
+	}
+
+	if len(findings) > 0 {
+		lintErr := fmt.Errorf("query has %d lint finding(s): %s", len(findings), findings[0].Message)
+		formatter.FormatStructuredErrorWithContext(
+			lintErr,
+			gqlt.ErrorCodeQueryLint,
+			"query_lint_error",
+			map[string]interface{}{
+				"findings": findings,
+			},
+			quietMode,
+		)
+		return lintErr
+	}
+
+	return formatter.FormatStructured(map[string]interface{}{
+		"valid":    true,
+		"findings": []gqlt.LintFinding{},
+	}, quietMode)
+}
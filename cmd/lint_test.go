@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const lintTestSchema = `
+type Query {
+  user(id: ID!): User
+  search(term: String!): [SearchResult!]!
+}
+
+type User {
+  id: ID!
+  name: String
+}
+
+type Post {
+  id: ID!
+  title: String
+}
+
+union SearchResult = User | Post
+`
+
+func resetLintFlags() {
+	lintQuery = ""
+	lintQueryFile = ""
+	lintSchemaFile = ""
+	lintMaxDepth = 8
+}
+
+func TestLintCommand(t *testing.T) {
+	schemaPath := filepath.Join(t.TempDir(), "schema.graphqls")
+	if err := os.WriteFile(schemaPath, []byte(lintTestSchema), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		wantCode string
+	}{
+		{
+			name:  "clean query",
+			query: `query GetUser($id: ID!) { user(id: $id) { id name } }`,
+		},
+		{
+			name:     "unused variable",
+			query:    `query GetUser($id: ID!, $name: String) { user(id: $id) { id } }`,
+			wantCode: "UNUSED_VARIABLE",
+		},
+		{
+			name:     "union without __typename",
+			query:    `{ search(term: "gql") { ... on User { id } ... on Post { id title } } }`,
+			wantCode: "MISSING_TYPENAME",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer resetLintFlags()
+
+			var stderr bytes.Buffer
+			cmd := createFullTestCommand()
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(&stderr)
+			cmd.SetArgs([]string{"lint", "--query", tt.query, "--schema-file", schemaPath})
+			err := cmd.Execute()
+
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("Expected no findings, got error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Expected lint to fail")
+			}
+			if !strings.Contains(stderr.String(), tt.wantCode) {
+				t.Errorf("Expected finding %s in output, got:\n%s", tt.wantCode, stderr.String())
+			}
+		})
+	}
+}
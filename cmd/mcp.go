@@ -17,16 +17,29 @@ var mcpCmd = &cobra.Command{
 	Short: "Start MCP (Model Context Protocol) server for AI agent integration",
 	Long: `Start an MCP server that provides GraphQL query execution and schema exploration to AI agents via JSON-RPC 2.0.
 This allows AI agents to execute GraphQL queries, introspect schemas, and explore types
-through a standardized protocol using stdin/stdout communication.
+through a standardized protocol using stdin/stdout communication, or over HTTP/SSE
+when --listen is given.
+
+The HTTP/SSE server has no authentication: anyone who can connect to it can use it to
+send requests to any endpoint. It therefore only listens on loopback addresses unless
+--allow-remote is given, and execute_query doesn't accept file uploads over it, so
+network clients can't read files on this machine.
 
 The MCP server provides tools for:
 - execute_query: Run GraphQL queries, mutations, and subscriptions (supports file uploads via local paths)
+- execute_batch: Run several queries and mutations against one endpoint in a single call
 - describe_type: Analyze specific GraphQL types and fields with detailed information
 - list_types: List GraphQL type names with optional regex filtering
+- search_fields: Find fields by name pattern across all types
+- compare_types: Compare the fields of two types in the same schema
+- introspect_schema: Fetch the full schema as SDL or introspection JSON
 - version: Get the current version of gqlt`,
 	Example: `# Start MCP server (stdin/stdout mode)
 gqlt mcp
 
+# Start MCP server for network clients (HTTP/SSE mode)
+gqlt mcp --listen localhost:8091
+
 # For Cursor integration, add to mcp.json:
 {
   "mcpServers": {
@@ -51,8 +64,18 @@ gqlt mcp
 	RunE: runMCPServer,
 }
 
+var (
+	mcpListen      string
+	mcpAllowRemote bool
+	mcpCacheDir    string
+)
+
 func init() {
 	rootCmd.AddCommand(mcpCmd)
+
+	mcpCmd.Flags().StringVarP(&mcpListen, "listen", "l", "", "Serve over HTTP/SSE on this address (host:port) instead of stdin/stdout; the server has no authentication, so only loopback addresses are allowed by default")
+	mcpCmd.Flags().BoolVar(&mcpAllowRemote, "allow-remote", false, "Allow --listen on non-loopback addresses (anyone who can connect can send requests through the server)")
+	mcpCmd.Flags().StringVar(&mcpCacheDir, "cache-dir", "", "Persist introspected schemas in this directory across restarts")
 }
 
 func runMCPServer(cmd *cobra.Command, args []string) error {
@@ -61,6 +84,10 @@ func runMCPServer(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create MCP server: %w", err)
 	}
+	if mcpCacheDir != "" {
+		server.SetCacheDir(mcpCacheDir)
+	}
+	server.SetAllowRemote(mcpAllowRemote)
 
 	// Set up graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -79,9 +106,13 @@ func runMCPServer(cmd *cobra.Command, args []string) error {
 	// Start server in a goroutine
 	serverErr := make(chan error, 1)
 	go func() {
-		fmt.Println("Starting MCP server (stdin/stdout mode)")
+		if mcpListen == "" {
+			fmt.Println("Starting MCP server (stdin/stdout mode)")
+		} else {
+			fmt.Printf("Starting MCP server (HTTP/SSE mode) on %s\n", mcpListen)
+		}
 		fmt.Println("Press Ctrl+C to stop the server")
-		serverErr <- server.Start(context.Background(), "")
+		serverErr <- server.Start(context.Background(), mcpListen)
 	}()
 
 	// Wait for server to start or error
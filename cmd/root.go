@@ -1,6 +1,9 @@
 package main
 
 import (
+	"io"
+	"os"
+
 	"github.com/kluzzebass/gqlt"
 	"github.com/spf13/cobra"
 )
@@ -23,7 +26,12 @@ AI-FRIENDLY FEATURES:
 - Structured JSON output with --format json
 - Machine-readable error codes for automation
 - Quiet mode (--quiet) for script integration
-- Comprehensive help with examples`,
+- Comprehensive help with examples
+
+CONFIGURATION SELECTION (highest precedence first):
+1. Explicit flags (--url, --use-config)
+2. Environment variables (GQLT_ENDPOINT, GQLT_CONFIG)
+3. The current configuration in the config file`,
 	Example: `# Basic query execution
 gqlt run --url https://api.example.com/graphql --query "{ users { id name } }"
 
@@ -46,6 +54,10 @@ gqlt run --token "your-token" --query "{ me { id } }"
 gqlt run --username user --password pass --query "{ me { id } }"
 gqlt run --api-key "your-api-key" --query "{ me { id } }"
 
+# Select configuration and endpoint in CI without editing the config file
+GQLT_CONFIG=staging gqlt run --query "{ users { id } }"
+GQLT_ENDPOINT=https://ci.example.com/graphql gqlt run --query "{ users { id } }"
+
 # Output formats
 gqlt run --format json --query "{ users { id } }"
 gqlt config list --format table
@@ -53,8 +65,12 @@ gqlt config show --format yaml`,
 	Version: getVersionInfo(),
 }
 
+// Execute runs the root command and exits with the code for its error, if any
+// (see exitCodeForError); cobra has already printed the error
 func Execute() {
-	cobra.CheckErr(rootCmd.Execute())
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(exitCodeForError(err))
+	}
 }
 
 // getVersionInfo returns detailed version information
@@ -78,4 +94,34 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&configName, "use-config", "", "use specific configuration by name (overrides current selection)")
 	rootCmd.PersistentFlags().StringVar(&outputFormat, "format", "json", "Output format: json|table|yaml (default: json)")
 	rootCmd.PersistentFlags().BoolVarP(&quietMode, "quiet", "", false, "Quiet mode - suppress non-essential output for automation")
+	rootCmd.PersistentFlags().Bool("compact", false, "Print JSON on a single line (default: indented on a terminal, compact otherwise)")
+}
+
+// compactJSON reports whether JSON written to w should be compact: as set with
+// --compact, or when w is not a terminal if the flag isn't given
+func compactJSON(cmd *cobra.Command, w io.Writer) bool {
+	if flag := cmd.Flag("compact"); flag != nil && flag.Changed {
+		return flag.Value.String() == "true"
+	}
+	return !isTerminal(w)
+}
+
+// setCompact applies compactJSON to a JSON formatter writing to w
+func setCompact(cmd *cobra.Command, formatter gqlt.Formatter, w io.Writer) {
+	if jsonFormatter, ok := formatter.(*gqlt.JSONFormatter); ok {
+		jsonFormatter.SetCompact(compactJSON(cmd, w))
+	}
+}
+
+// isTerminal reports whether w is a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
 }
@@ -2,10 +2,16 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -18,13 +24,28 @@ var runCmd = &cobra.Command{
 	Use:   "run",
 	Short: "Execute a GraphQL operation against an endpoint",
 	Long: `Execute a GraphQL operation (query or mutation) against a GraphQL endpoint.
-You can provide the query inline, from a file, or via stdin.`,
+You can provide the query inline, from a file, or via stdin.
+
+A query file can pull in shared fragments with "# import <path>" comment lines
+at its top; paths are relative to the importing file.
+
+The exit code tells failures apart: 1 for invalid flags or input, 2 for a
+response with GraphQL errors (unless it also has data and --accept-partial is
+given), 3 when the server can't be reached or times out, and 4 when it answers
+401 Unauthorized or 403 Forbidden.
+
+With --script or --watch, a summary of the operations run is printed to stderr
+at the end: how many succeeded, returned GraphQL errors or failed to reach the
+server, grouped by error code (left out with --quiet).`,
 	Example: `# Basic query
 gqlt run --url https://api.example.com/graphql --query "{ users { id name } }"
 
 # Query with variables
 gqlt run --url https://api.example.com/graphql --query "query($id: ID!) { user(id: $id) { name } }" --vars '{"id": "123"}'
 
+# Query file importing shared fragments ("# import ./fragments/user.graphql" at its top)
+gqlt run --url https://api.example.com/graphql --query-file queries/me.graphql
+
 # Query from stdin
 echo "{ users { id name } }" | gqlt run --url https://api.example.com/graphql
 
@@ -34,16 +55,71 @@ gqlt run --url https://api.example.com/graphql --query "mutation($file: Upload!)
 # Using configuration
 gqlt run --query "{ users { id name } }"  # Uses configured endpoint
 
+# Run the saved request of the current configuration
+gqlt config set default defaults.query "query GetUser($id: ID!) { user(id: $id) { name } }"
+gqlt config set default defaults.variables '{"id": "123"}'
+gqlt run
+
 # Authentication (precedence: Basic Auth > Bearer Token > API Key)
 gqlt run --username user --password pass --query "{ me { id } }"  # Basic auth (highest precedence)
 gqlt run --token "bearer-token" --query "{ me { id } }"          # Bearer token
 gqlt run --api-key "api-key" --query "{ me { id } }"             # API key (lowest precedence)
 
+# Treat partial data as success despite GraphQL errors
+gqlt run --accept-partial --query "{ users { id name riskyField } }"
+
 # Structured output for AI agents
 gqlt run --format json --quiet --query "{ users { id } }"
 
 # Multiple file uploads
-gqlt run --query "mutation($files: [Upload!]!) { uploadFiles(files: $files) }" --files-list files.txt`,
+gqlt run --query "mutation($files: [Upload!]!) { uploadFiles(files: $files) }" --files-list files.txt
+
+# Reuse a common set of headers from a file
+gqlt run --query "{ users { id } }" --header @headers.txt
+
+# Sign each request with values computed per request
+gqlt run --query "{ users { id } }" -H "X-Timestamp: {{now}}" -H 'X-Signature: {{hmac .Body "secret"}}'
+
+# Write the response to a file instead of stdout
+gqlt run --query "{ users { id name } }" --out-file results/users.json
+
+# Poll a dashboard query, hitting the server at most once a minute
+gqlt run --query "{ stats { activeUsers } }" --cache-ttl 1m
+
+# Refresh the saved schema of the configuration first if it is more than a day old
+gqlt run --query "{ users { id } }" --refresh-schema 24h
+
+# Accept loosely typed variables, e.g. from a shell script, using the saved schema
+gqlt run --query "query($limit: Int, $status: Status) { orders(limit: $limit, status: $status) { id } }" --vars '{"limit": "10", "status": "shipped"}' --coerce-vars
+
+# Show resolver timings from Apollo tracing
+gqlt run --query "{ user(id: 1) { name posts { title } } }" --explain
+
+# Poll a query every 5 seconds until it returns something different
+gqlt run --query "{ deployment(id: 7) { status } }" --watch 5s --watch-until-change
+
+# Poll a query and print only what changed between results
+gqlt run --query "{ queue { size oldest } }" --watch 10s --diff
+
+# Create a user, then fetch it using the ID from the first response
+# (steps.json: [{"query": "mutation { createUser(name: \"Ada\") { id } }"},
+#               {"query": "query($id: ID!) { user(id: $id) { name } }", "variables": {"id": "${step1.data.createUser.id}"}}])
+gqlt run --script steps.json
+
+# Run one operation of a file with several, sending only it and its fragments
+gqlt run --query-file operations.graphql --operation GetUser --only-operation
+
+# Stream a subscription into a slow consumer, keeping a replay of every message
+gqlt run --query "subscription { userCreated { id } }" --buffer 1000 --replay-file replay.jsonl | ./consumer
+
+# Print a single value for use in scripts
+gqlt run --query "{ user(id: 1) { name } }" --select data.user.name --quiet
+
+# Save a base64 payload from the response as a file
+gqlt run --query "mutation { exportReport { content } }" --save-field exportReport.content=report.pdf:base64
+
+# Connect to a server with a self-signed certificate
+gqlt run --url https://staging.example.com/graphql --query "{ users { id } }" --insecure`,
 	RunE: runGraphQL,
 }
 
@@ -63,6 +139,30 @@ var (
 	apiKey      string
 	timeout     string
 	maxMessages int
+	subBuffer   int
+	replayFile  string
+	outFile     string
+	insecure    bool
+	warnVars    bool
+	maxFileSize string
+	saveFields  []string
+	selectPath  string
+	cacheTTL    string
+	retries     int
+	explain     bool
+	watch       string
+	script      string
+	watchChange bool
+	watchCount  int
+	watchDiff   bool
+
+	acceptPartial    bool
+	onlyOperation    bool
+	refreshSchemaAge string
+	coerceVars       bool
+
+	// OAuth2 client credentials of the active configuration
+	configOAuth2 gqlt.OAuth2Config
 )
 
 func init() {
@@ -75,7 +175,7 @@ func init() {
 	runCmd.Flags().StringVarP(&operation, "operation", "o", "", "Operation name")
 	runCmd.Flags().StringVarP(&vars, "vars", "v", "", "JSON object with variables")
 	runCmd.Flags().StringVarP(&varsFile, "vars-file", "V", "", "Path to JSON file with variables")
-	runCmd.Flags().StringArrayVarP(&headers, "header", "H", []string{}, "HTTP header (key=value, repeatable)")
+	runCmd.Flags().StringArrayVarP(&headers, "header", "H", []string{}, "HTTP header (Key: Value, or @file with one header per line; repeatable)")
 	runCmd.Flags().StringArrayVarP(&files, "file", "f", []string{}, "File upload (name=path, repeatable, e.g. avatar=./photo.jpg)")
 	runCmd.Flags().StringVarP(&filesList, "files-list", "F", "", "File containing list of files to upload (one per line, format: name=path, supports # comments, ~ expansion, and relative paths)")
 	runCmd.Flags().StringVarP(&username, "username", "U", "", "Username for basic authentication")
@@ -84,65 +184,132 @@ func init() {
 	runCmd.Flags().StringVarP(&apiKey, "api-key", "k", "", "API key for authentication (sets X-API-Key header)")
 	runCmd.Flags().StringVar(&timeout, "timeout", "", "Subscription timeout (e.g. 30s, 5m)")
 	runCmd.Flags().IntVar(&maxMessages, "max-messages", 0, "Maximum subscription messages to receive (0 = unlimited)")
+	runCmd.Flags().IntVar(&subBuffer, "buffer", 0, "Queue up to this many subscription messages while the output falls behind, instead of holding up the connection (0 = off)")
+	runCmd.Flags().StringVar(&replayFile, "replay-file", "", "Append each subscription message to this file as a JSON line as soon as it arrives, so a crashed consumer can resume")
+	runCmd.Flags().IntVar(&retries, "retries", 0, "Retry requests rejected with 429 or 503 up to this many times, honoring Retry-After")
+	runCmd.Flags().StringVar(&cacheTTL, "cache-ttl", "", "Reuse the response of an identical query made within this duration (e.g. 30s, 5m; queries only)")
+	runCmd.Flags().StringVar(&outFile, "out-file", "", "Write the response to a file instead of stdout (errors still go to stderr)")
+	runCmd.Flags().BoolVar(&insecure, "insecure", false, "Skip TLS certificate verification (unsafe, for testing only)")
+	runCmd.Flags().StringVar(&maxFileSize, "max-file-size", "", "Refuse to upload files larger than this (bytes, or with a K, M or G suffix, e.g. 10M)")
+	runCmd.Flags().StringVar(&selectPath, "select", "", "Output only the value at a path in the response (e.g. data.user.name or data.users[0].id)")
+	runCmd.Flags().StringArrayVar(&saveFields, "save-field", []string{}, "Save the value at a dotted path in the response data to a file (path=file, repeatable; base64 data: URLs are decoded, and other base64 strings with path=file:base64)")
+	runCmd.Flags().StringVar(&script, "script", "", "Run the operations of a JSON script file in order; variables can reference earlier responses (e.g. ${step1.data.createUser.id})")
+	runCmd.Flags().StringVar(&watch, "watch", "", "Re-run the operation at this interval (e.g. 5s, 1m) and print each result until interrupted")
+	runCmd.Flags().BoolVar(&watchChange, "watch-until-change", false, "Stop watching once the response differs from the previous one")
+	runCmd.Flags().BoolVar(&watchDiff, "diff", false, "With --watch, print only what changed since the previous response")
+	runCmd.Flags().IntVar(&watchCount, "watch-count", 0, "Stop watching after this many runs (0 = until interrupted)")
+	runCmd.Flags().BoolVar(&explain, "explain", false, "Print the response's extensions (such as Apollo tracing resolver timings) as a tree on stderr")
+	runCmd.Flags().StringVar(&refreshSchemaAge, "refresh-schema", "", "Re-introspect and save the schema of the active configuration first if the saved one is older than this (e.g. 24h; defaults to the config's schema.refresh)")
+	runCmd.Flags().BoolVar(&onlyOperation, "only-operation", false, "Send only the operation selected by --operation and the fragments it uses, not the whole document")
+	runCmd.Flags().BoolVar(&acceptPartial, "accept-partial", false, "Succeed when the response has GraphQL errors but also data (the errors are still printed)")
+	runCmd.Flags().BoolVar(&coerceVars, "coerce-vars", false, "Convert variables to the types the saved schema expects where the meaning is obvious (e.g. \"42\" for an Int, \"shipped\" for an enum value SHIPPED)")
+	runCmd.Flags().BoolVar(&warnVars, "warn-vars", false, "Only warn when variables don't match the operation's declarations instead of failing")
 }
 
 func runGraphQL(cmd *cobra.Command, args []string) error {
 	// Step 7.5: Load configuration
 	cfg, err := gqlt.Load(configDir)
 	if err != nil {
-		formatter := gqlt.NewFormatter(outputFormat)
-		return formatter.FormatStructuredError(fmt.Errorf("failed to load config: %w", err), "CONFIG_LOAD_ERROR", quietMode)
+		err = fmt.Errorf("failed to load config: %w", err)
+		return reportError(cmd, err, "CONFIG_LOAD_ERROR")
 	}
 
 	// Merge config with CLI flags
-	mergeConfigWithFlags(cfg)
+	if err := mergeConfigWithFlags(cfg); err != nil {
+		return reportError(cmd, err, "CONFIG_LOAD_ERROR")
+	}
+	activeName, current, _ := cfg.ResolveActive(configName)
 
 	// Step 8: Input validation
 	if query != "" && queryFile != "" {
-		formatter := gqlt.NewFormatter(outputFormat)
-		return formatter.FormatStructuredError(fmt.Errorf("cannot specify both --query and --query-file"), "INPUT_VALIDATION_ERROR", quietMode)
+		err = fmt.Errorf("cannot specify both --query and --query-file")
+		return reportError(cmd, err, "INPUT_VALIDATION_ERROR")
 	}
 	if vars != "" && varsFile != "" {
-		formatter := gqlt.NewFormatter(outputFormat)
-		return formatter.FormatStructuredError(fmt.Errorf("cannot specify both --vars and --vars-file"), "INPUT_VALIDATION_ERROR", quietMode)
+		err = fmt.Errorf("cannot specify both --vars and --vars-file")
+		return reportError(cmd, err, "INPUT_VALIDATION_ERROR")
+	}
+
+	// Make sure the response can be saved before running the operation, so a mutation
+	// isn't executed only for its response to be lost
+	if outFile != "" {
+		if err := checkOutFile(outFile); err != nil {
+			err = fmt.Errorf("failed to open output file: %w", err)
+			return reportError(cmd, err, "OUTPUT_FILE_ERROR")
+		}
+	}
+
+	// Run a script of chained operations instead of a single operation
+	if script != "" {
+		if query != "" || queryFile != "" || vars != "" || varsFile != "" || len(files) > 0 || filesList != "" || watch != "" {
+			err = fmt.Errorf("--script cannot be combined with --query, --query-file, --vars, --vars-file, file uploads or --watch")
+			return reportError(cmd, err, gqlt.ErrorCodeInputValidation)
+		}
+		return runScript(cmd, script)
 	}
 
 	// Step 9: Helper resolution
+	// Fall back to the config's saved request for anything not given on the command line
+	// (the default operation and variables belong to the default query, so they only apply with it)
+	queryArg := query
+	useDefaultVariables := false
+	if queryArg == "" && queryFile == "" && current.DefaultQuery != "" {
+		queryArg = current.DefaultQuery
+		if operation == "" {
+			operation = current.DefaultOperation
+		}
+		useDefaultVariables = vars == "" && varsFile == ""
+	}
+
 	inputHandler := gqlt.NewInput()
-	queryStr, err := inputHandler.LoadQuery(query, queryFile)
+	queryStr, err := inputHandler.LoadQuery(queryArg, queryFile)
 	if err != nil {
-		formatter := gqlt.NewFormatter(outputFormat)
-		return formatter.FormatStructuredError(fmt.Errorf("failed to load query: %w", err), "QUERY_LOAD_ERROR", quietMode)
+		err = fmt.Errorf("failed to load query: %w", err)
+		return reportError(cmd, err, "QUERY_LOAD_ERROR")
+	}
+	if onlyOperation {
+		queryStr, err = gqlt.ExtractOperation(queryStr, operation)
+		if err != nil {
+			err = fmt.Errorf("failed to extract operation: %w", err)
+			return reportError(cmd, err, gqlt.ErrorCodeQueryParse)
+		}
 	}
 
 	varsMap, err := inputHandler.LoadVariables(vars, varsFile)
 	if err != nil {
-		formatter := gqlt.NewFormatter(outputFormat)
-		return formatter.FormatStructuredError(fmt.Errorf("failed to load variables: %w", err), "VARIABLES_LOAD_ERROR", quietMode)
+		err = fmt.Errorf("failed to load variables: %w", err)
+		return reportError(cmd, err, "VARIABLES_LOAD_ERROR")
+	}
+	if useDefaultVariables && current.DefaultVariables != nil {
+		varsMap = current.DefaultVariables
 	}
 
-	headersMap := inputHandler.LoadHeaders(headers)
+	headersMap, err := inputHandler.LoadHeaders(headers)
+	if err != nil {
+		err = fmt.Errorf("failed to load headers: %w", err)
+		return reportError(cmd, err, gqlt.ErrorCodeHeadersLoad)
+	}
 
 	// Parse file uploads
 	filesMap, err := inputHandler.ParseFiles(files)
 	if err != nil {
-		formatter := gqlt.NewFormatter(outputFormat)
-		return formatter.FormatStructuredError(fmt.Errorf("failed to parse files: %w", err), "FILES_PARSE_ERROR", quietMode)
+		err = fmt.Errorf("failed to parse files: %w", err)
+		return reportError(cmd, err, "FILES_PARSE_ERROR")
 	}
 
 	// Parse files from list if provided
 	if filesList != "" {
 		filesFromList, err := inputHandler.ParseFilesFromList(filesList)
 		if err != nil {
-			formatter := gqlt.NewFormatter(outputFormat)
-			return formatter.FormatStructuredError(fmt.Errorf("failed to parse files list: %w", err), "FILES_LIST_PARSE_ERROR", quietMode)
+			err = fmt.Errorf("failed to parse files list: %w", err)
+			return reportError(cmd, err, "FILES_LIST_PARSE_ERROR")
 		}
 
 		// Parse the files from list
 		filesFromListMap, err := inputHandler.ParseFiles(filesFromList)
 		if err != nil {
-			formatter := gqlt.NewFormatter(outputFormat)
-			return formatter.FormatStructuredError(fmt.Errorf("failed to parse files from list: %w", err), "FILES_LIST_PARSE_ERROR", quietMode)
+			err = fmt.Errorf("failed to parse files from list: %w", err)
+			return reportError(cmd, err, "FILES_LIST_PARSE_ERROR")
 		}
 
 		// Merge with existing files
@@ -154,17 +321,264 @@ func runGraphQL(cmd *cobra.Command, args []string) error {
 	// Step 9.5: Detect operation type
 	opInfo, err := gqlt.DetectOperationType(queryStr, operation)
 	if err != nil {
-		formatter := gqlt.NewFormatter(outputFormat)
-		return formatter.FormatStructuredError(fmt.Errorf("failed to detect operation type: %w", err), "QUERY_PARSE_ERROR", quietMode)
+		err = fmt.Errorf("failed to detect operation type: %w", err)
+		return reportError(cmd, err, gqlt.ErrorCodeQueryParse)
+	}
+
+	// Step 9.55: Coerce the variables to the types the schema expects
+	if coerceVars && len(varsMap) > 0 {
+		varsMap, err = coerceVariables(activeName, queryStr, operation, varsMap)
+		if err != nil {
+			return reportError(cmd, err, gqlt.ErrorCodeSchemaLoad)
+		}
+	}
+
+	// Step 9.6: Check the variables against the operation's declarations
+	// (uploaded files fill the variables their map paths start with, e.g. files.0
+	// fills an item of $files)
+	checkedVars := make(map[string]interface{}, len(varsMap)+len(filesMap))
+	for name, value := range varsMap {
+		checkedVars[name] = value
+	}
+	for name := range filesMap {
+		path := strings.Split(name, ".")
+		if value, exists := checkedVars[path[0]]; exists {
+			checkedVars[path[0]] = withUploadPlaceholder(value, path[1:], name)
+		} else {
+			checkedVars[path[0]] = name
+		}
+	}
+	problems, err := gqlt.ValidateVariables(queryStr, operation, checkedVars)
+	if err != nil {
+		err = fmt.Errorf("failed to validate variables: %w", err)
+		return reportError(cmd, err, gqlt.ErrorCodeQueryParse)
+	}
+	if len(problems) > 0 {
+		if !warnVars {
+			messages := make([]string, len(problems))
+			for i, problem := range problems {
+				messages[i] = problem.Message
+			}
+			validationErr := fmt.Errorf("variables don't match the operation: %s", strings.Join(messages, "; "))
+			return reportError(cmd, validationErr, gqlt.ErrorCodeVariablesValidation)
+		}
+		for _, problem := range problems {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", problem.Message)
+		}
 	}
 
 	// If it's a subscription, route to subscription handler
 	if opInfo.Type == gqlt.OperationTypeSubscription {
-		return runSubscription(queryStr, varsMap, operation, url, headersMap, timeout, maxMessages)
+		if watch != "" {
+			err = fmt.Errorf("--watch cannot be used with subscriptions, which already stream results")
+			return reportError(cmd, err, gqlt.ErrorCodeInputValidation)
+		}
+		var out io.Writer = os.Stdout
+		if outFile != "" {
+			file, err := openOutFile(outFile)
+			if err != nil {
+				err = fmt.Errorf("failed to open output file: %w", err)
+				return reportError(cmd, err, "OUTPUT_FILE_ERROR")
+			}
+			defer file.Close()
+			out = file
+		}
+		var replay io.Writer
+		if replayFile != "" {
+			file, err := os.OpenFile(replayFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+			if err != nil {
+				err = fmt.Errorf("failed to open replay file: %w", err)
+				return reportError(cmd, err, "OUTPUT_FILE_ERROR")
+			}
+			defer file.Close()
+			replay = file
+		}
+		return runSubscription(cmd, queryStr, varsMap, operation, headersMap, timeout, maxMessages, subBuffer, replay, out)
 	}
 
 	// Step 10: Run GraphQL call (queries and mutations)
-	// Create GraphQL client
+	client, err := newRunClient(headersMap)
+	if err != nil {
+		return reportError(cmd, err, gqlt.ErrorCodeInputValidation)
+	}
+
+	// Re-introspect the saved schema of the active configuration if it is stale
+	refreshAge := refreshSchemaAge
+	if refreshAge == "" {
+		refreshAge = current.SchemaRefresh
+	}
+	if refreshAge != "" {
+		if err := refreshSchema(client, activeName, refreshAge); err != nil {
+			return reportError(cmd, err, gqlt.ErrorCodeInputValidation)
+		}
+	}
+	status := recordStatus(client)
+
+	// Poll the operation repeatedly if requested
+	if watchDiff && watch == "" {
+		err = fmt.Errorf("--diff requires --watch")
+		return reportError(cmd, err, gqlt.ErrorCodeInputValidation)
+	}
+	if watch != "" {
+		interval, err := time.ParseDuration(watch)
+		if err == nil && interval <= 0 {
+			err = fmt.Errorf("interval must be positive")
+		}
+		if err == nil && len(filesMap) > 0 {
+			err = fmt.Errorf("cannot be used with file uploads")
+		}
+		if err != nil {
+			err = fmt.Errorf("invalid --watch: %w", err)
+			return reportError(cmd, err, gqlt.ErrorCodeInputValidation)
+		}
+
+		var out io.Writer = os.Stdout
+		if outFile != "" {
+			file := &lazyOutFile{path: outFile}
+			defer file.Close()
+			out = file
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		summary := &RunSummary{}
+		opts := watchOptions{interval: interval, maxRuns: watchCount, untilChange: watchChange, diff: watchDiff, summary: summary}
+		err = watchOperation(ctx, client, queryStr, varsMap, operation, opts, out)
+		printRunSummary(cmd, summary)
+		if err != nil {
+			return reportError(cmd, err, gqlt.ErrorCodeGraphQLExecution)
+		}
+		return nil
+	}
+
+	// JSON output of operations without uploads is streamed straight to the output,
+	// so large responses are never held in memory as a whole (unless parts of the
+	// data are to be selected or saved afterwards, the response may be cached,
+	// partial data is to be told apart from none, or the JSON is to be indented)
+	if outputFormat == "json" && len(filesMap) == 0 && len(saveFields) == 0 && selectPath == "" && cacheTTL == "" && !acceptPartial {
+		var out io.Writer = os.Stdout
+		if outFile != "" {
+			file := &lazyOutFile{path: outFile}
+			defer file.Close()
+			out = file
+		}
+		if compactJSON(cmd, out) {
+			result, err := client.ExecuteStream(queryStr, varsMap, operation, out)
+			if err != nil {
+				err = operationError(cmd, nil, fmt.Errorf("failed to execute GraphQL operation: %w", err), *status)
+				return reportError(cmd, err, errorCodeForError(err))
+			}
+			explainResponse(cmd, result)
+
+			// Fail if there were GraphQL errors or the server refused access (after outputting the response)
+			return operationError(cmd, result, nil, *status)
+		}
+	}
+
+	// Execute GraphQL operation (with or without files)
+	var result *gqlt.Response
+	if len(filesMap) > 0 {
+		// Use multipart/form-data for file uploads
+		if maxFileSize != "" {
+			limit, err := parseByteSize(maxFileSize)
+			if err != nil {
+				err = fmt.Errorf("invalid --max-file-size: %w", err)
+				return reportError(cmd, err, gqlt.ErrorCodeFilesParse)
+			}
+			client.SetMaxFileSize(limit)
+		}
+		result, err = client.ExecuteWithFiles(queryStr, varsMap, operation, filesMap)
+		if errors.Is(err, gqlt.ErrFileTooLarge) {
+			return reportError(cmd, err, gqlt.ErrorCodeFilesParse)
+		}
+		if err != nil {
+			err = operationError(cmd, nil, fmt.Errorf("failed to execute GraphQL operation with files: %w", err), *status)
+			return reportError(cmd, err, errorCodeForError(err))
+		}
+		if !quietMode && result.Upload != nil {
+			fmt.Fprintf(os.Stderr, "Uploaded %d file(s), %d bytes\n", result.Upload.Count, result.Upload.TotalBytes)
+		}
+	} else {
+		// Use regular JSON for operations without files
+		result, err = client.Execute(queryStr, varsMap, operation)
+		if err != nil {
+			err = operationError(cmd, nil, fmt.Errorf("failed to execute GraphQL operation: %w", err), *status)
+			return reportError(cmd, err, errorCodeForError(err))
+		}
+	}
+
+	// Step 11: Output formatting
+	formatter := gqlt.NewFormatter(outputFormat)
+
+	// Save requested fields of the response data before printing it
+	for _, spec := range saveFields {
+		if err := saveField(result.Data, spec); err != nil {
+			return reportError(cmd, err, gqlt.ErrorCodeSaveField)
+		}
+	}
+
+	// Route results to the output file if requested; errors keep going to stderr
+	var out io.Writer = os.Stdout
+	if outFile != "" {
+		file, err := openOutFile(outFile)
+		if err != nil {
+			err = fmt.Errorf("failed to open output file: %w", err)
+			return reportError(cmd, err, "OUTPUT_FILE_ERROR")
+		}
+		defer file.Close()
+		formatter.SetOutput(file)
+		out = file
+	}
+	setCompact(cmd, formatter, out)
+
+	// Narrow the output to a single value of the response if requested
+	if selectPath != "" {
+		value, err := gqlt.SelectPath(map[string]interface{}{"data": result.Data}, selectPath)
+		if err != nil {
+			err = fmt.Errorf("failed to select %s: %w", selectPath, err)
+			return reportError(cmd, err, gqlt.ErrorCodeSelectPath)
+		}
+		if err := writeSelection(formatter, out, value); err != nil {
+			return err
+		}
+		explainResponse(cmd, result)
+		return operationError(cmd, result, nil, *status)
+	}
+
+	// Use structured output for non-json formats (table, yaml)
+	if outputFormat != "json" {
+		// For structured output, include the full response
+		responseData := map[string]interface{}{
+			"data":   result.Data,
+			"errors": result.Errors,
+		}
+		if result.Extensions != nil {
+			responseData["extensions"] = result.Extensions
+		}
+		if err := formatter.FormatStructured(responseData, quietMode); err != nil {
+			return err
+		}
+		explainResponse(cmd, result)
+		return operationError(cmd, result, nil, *status)
+	}
+
+	// For JSON format, output the complete GraphQL response
+	mode := gqlt.ResponseModePretty
+	if compactJSON(cmd, out) {
+		mode = gqlt.ResponseModeCompact
+	}
+	if err := formatter.FormatResponse(result, mode); err != nil {
+		return err
+	}
+	explainResponse(cmd, result)
+
+	// Fail if there were GraphQL errors or the server refused access (after outputting the response)
+	return operationError(cmd, result, nil, *status)
+}
+
+// newRunClient creates the client for the run command, with the authentication
+// and request settings given on the command line
+func newRunClient(headersMap map[string]string) (*gqlt.Client, error) {
 	client := gqlt.NewClient(url, headersMap)
 
 	// Set authentication if provided
@@ -192,76 +606,206 @@ func runGraphQL(cmd *cobra.Command, args []string) error {
 		client.SetHeaders(map[string]string{
 			"X-API-Key": apiKey,
 		})
+	} else if configOAuth2.TokenURL != "" {
+		// Fetch bearer tokens with the configured OAuth2 client credentials
+		client.SetOAuth2(configOAuth2.TokenURL, configOAuth2.ClientID, configOAuth2.ClientSecret, configOAuth2.Scopes)
 	}
 
-	// Execute GraphQL operation (with or without files)
-	var result *gqlt.Response
-	if len(filesMap) > 0 {
-		// Use multipart/form-data for file uploads
-		result, err = client.ExecuteWithFiles(queryStr, varsMap, operation, filesMap)
+	// Disable certificate verification if requested, never silently
+	if insecure {
+		fmt.Fprintf(os.Stderr, "Warning: TLS certificate verification is disabled (--insecure). Connections are not secure.\n")
+		client.SetInsecureSkipVerify(true)
+	}
+
+	// Retry requests the server asks us to back off from
+	if retries > 0 {
+		client.SetRetry(retries, 0)
+	}
+
+	// Cache query responses on disk for repeated runs if requested
+	if cacheTTL != "" {
+		ttl, err := time.ParseDuration(cacheTTL)
 		if err != nil {
-			formatter := gqlt.NewFormatter(outputFormat)
-			return formatter.FormatStructuredError(fmt.Errorf("failed to execute GraphQL operation with files: %w", err), "GRAPHQL_EXECUTION_ERROR", quietMode)
+			return nil, fmt.Errorf("invalid cache TTL: %w", err)
+		}
+		client.SetResponseCache(responseCacheDir(), ttl)
+	}
+
+	return client, nil
+}
+
+// explainResponse prints the extensions of a response as a tree if --explain is
+// set. It goes to stderr, so the response itself stays machine-readable.
+func explainResponse(cmd *cobra.Command, result *gqlt.Response) {
+	if !explain {
+		return
+	}
+	tree := gqlt.FormatTracing(result.Extensions)
+	if tree == "" {
+		tree = "No extensions in the response (the server may need tracing enabled)\n"
+	}
+	fmt.Fprint(cmd.ErrOrStderr(), tree)
+}
+
+// writeSelection prints a value picked with --select. In quiet mode strings are
+// printed bare and other values as compact JSON, so the output can be used
+// directly in scripts; otherwise it goes through the formatter.
+func writeSelection(formatter gqlt.Formatter, out io.Writer, value interface{}) error {
+	if !quietMode {
+		return formatter.FormatStructured(value, quietMode)
+	}
+
+	if str, ok := value.(string); ok {
+		_, err := fmt.Fprintln(out, str)
+		return err
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode selection: %w", err)
+	}
+	_, err = fmt.Fprintln(out, string(encoded))
+	return err
+}
+
+// saveField writes the value at a dotted path in data to a file, given a
+// path=file spec. Strings are written as-is, or decoded first if they are base64
+// data: URLs or the spec is path=file:base64; other values are written as JSON.
+func saveField(data interface{}, spec string) error {
+	path, file, ok := strings.Cut(spec, "=")
+	if !ok || path == "" || file == "" {
+		return fmt.Errorf("invalid --save-field %q: expected path=file", spec)
+	}
+
+	file, plainBase64 := strings.CutSuffix(file, ":base64")
+
+	value, err := gqlt.ExtractPath(data, path)
+	if err != nil {
+		return fmt.Errorf("failed to save field %s: %w", path, err)
+	}
+
+	var content []byte
+	if str, ok := value.(string); ok {
+		content, err = decodeBase64(str, plainBase64)
+		if err != nil {
+			return fmt.Errorf("failed to save field %s: %w", path, err)
 		}
 	} else {
-		// Use regular JSON for operations without files
-		result, err = client.Execute(queryStr, varsMap, operation)
+		content, err = json.MarshalIndent(value, "", "  ")
 		if err != nil {
-			formatter := gqlt.NewFormatter(outputFormat)
-			return formatter.FormatStructuredError(fmt.Errorf("failed to execute GraphQL operation: %w", err), "GRAPHQL_EXECUTION_ERROR", quietMode)
+			return fmt.Errorf("failed to encode field %s: %w", path, err)
 		}
 	}
 
-	// Step 11: Output formatting
-	formatter := gqlt.NewFormatter(outputFormat)
+	out, err := openOutFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to save field %s: %w", path, err)
+	}
+	defer out.Close()
+	if _, err := out.Write(content); err != nil {
+		return fmt.Errorf("failed to save field %s: %w", path, err)
+	}
+	return nil
+}
 
-	// Use structured output for non-json formats (table, yaml)
-	if outputFormat != "json" {
-		// For structured output, include the full response
-		responseData := map[string]interface{}{
-			"data":   result.Data,
-			"errors": result.Errors,
-		}
-		if result.Extensions != nil {
-			responseData["extensions"] = result.Extensions
+// decodeBase64 returns the decoded bytes of a ";base64," data: URL, or of s itself
+// if plain is set, and s unchanged otherwise. Strings aren't decoded just because
+// they could be base64, since hex digests, UUIDs and many IDs could be too.
+func decodeBase64(s string, plain bool) ([]byte, error) {
+	if strings.HasPrefix(s, "data:") {
+		if _, payload, ok := strings.Cut(s, ";base64,"); ok {
+			s, plain = payload, true
 		}
-		return formatter.FormatStructured(responseData, quietMode)
 	}
+	if !plain {
+		return []byte(s), nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("value is not base64: %w", err)
+	}
+	return decoded, nil
+}
+
+// responseCacheDir is where --cache-ttl keeps cached responses, in the user's
+// cache directory (falling back to the temporary directory)
+func responseCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "gqlt", "responses")
+}
 
-	// For JSON format, output the complete GraphQL response as compact JSON
-	if err := formatter.FormatResponse(result, "compact"); err != nil {
+// openOutFile creates (or truncates) the file at path, creating its parent
+// directory if it does not exist yet
+func openOutFile(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	return os.Create(path)
+}
+
+// checkOutFile makes sure the file at path can be written, creating its parent
+// directory if needed, without creating or truncating the file itself
+func checkOutFile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if errors.Is(err, fs.ErrExist) {
+		// Opening an existing file for writing without truncating leaves it as it is
+		file, err = os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		return file.Close()
+	}
+	if err != nil {
 		return err
 	}
+	file.Close()
+	return os.Remove(path)
+}
 
-	// Exit with error code if there were GraphQL errors (after outputting the response)
-	if len(result.Errors) > 0 {
-		os.Exit(2)
+// lazyOutFile creates the output file on the first write, so a request that fails
+// before any response is read doesn't leave an empty or truncated file behind
+type lazyOutFile struct {
+	path string
+	file *os.File
+}
+
+func (f *lazyOutFile) Write(p []byte) (int, error) {
+	if f.file == nil {
+		file, err := openOutFile(f.path)
+		if err != nil {
+			return 0, fmt.Errorf("failed to open output file: %w", err)
+		}
+		f.file = file
 	}
+	return f.file.Write(p)
+}
 
-	return nil
+func (f *lazyOutFile) Close() error {
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Close()
 }
 
 // mergeConfigWithFlags merges configuration values with CLI flags
 // CLI flags take precedence over config values
-func mergeConfigWithFlags(cfg *gqlt.Config) {
-	var current *gqlt.ConfigEntry
-
-	// Use specific config name if provided, otherwise use current
-	if configName != "" {
-		if entry, exists := cfg.Configs[configName]; exists {
-			current = &entry
-		} else {
-			// Config name not found, fall back to current
-			current = cfg.GetCurrent()
-		}
-	} else {
-		current = cfg.GetCurrent()
+func mergeConfigWithFlags(cfg *gqlt.Config) error {
+	// --use-config takes precedence over GQLT_CONFIG and the current config
+	_, current, err := cfg.ResolveActive(configName)
+	if err != nil {
+		return err
 	}
 
 	// Only set values from config if CLI flags are not provided
 	if url == "" && current.Endpoint != "" {
 		url = current.Endpoint
 	}
+	configOAuth2 = current.OAuth2
 
 	// Add token to headers if provided
 	if token != "" {
@@ -282,12 +826,131 @@ func mergeConfigWithFlags(cfg *gqlt.Config) {
 			headers = append(headers, k+": "+v)
 		}
 	}
+	return nil
+}
+
+// runScript runs the steps of a script file in order, writing each step's
+// response as a JSON line and stopping at the first step that fails
+func runScript(cmd *cobra.Command, path string) error {
+	steps, err := gqlt.LoadScript(path)
+	if err != nil {
+		return reportError(cmd, err, gqlt.ErrorCodeScriptLoad)
+	}
+
+	headersMap, err := gqlt.NewInput().LoadHeaders(headers)
+	if err != nil {
+		err = fmt.Errorf("failed to load headers: %w", err)
+		return reportError(cmd, err, gqlt.ErrorCodeHeadersLoad)
+	}
+
+	client, err := newRunClient(headersMap)
+	if err != nil {
+		return reportError(cmd, err, gqlt.ErrorCodeInputValidation)
+	}
+
+	var out io.Writer = os.Stdout
+	if outFile != "" {
+		file := &lazyOutFile{path: outFile}
+		defer file.Close()
+		out = file
+	}
+	encoder := json.NewEncoder(out)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	summary := &RunSummary{}
+	stoppedAtStep := false // Whether the script failed at a step that was already counted
+	err = client.RunScript(ctx, steps, func(step gqlt.ScriptStep, response *gqlt.Response) error {
+		explainResponse(cmd, response)
+		summary.Add(response, nil)
+		err := encoder.Encode(struct {
+			Step       string                 `json:"step"`
+			Data       interface{}            `json:"data"`
+			Errors     []interface{}          `json:"errors,omitempty"`
+			Extensions map[string]interface{} `json:"extensions,omitempty"`
+		}{step.Name, response.Data, response.Errors, response.Extensions})
+		stoppedAtStep = err != nil || len(response.Errors) > 0
+		return err
+	})
+	if err != nil && !stoppedAtStep {
+		summary.Add(nil, err)
+	}
+	printRunSummary(cmd, summary)
+	if err != nil {
+		return reportError(cmd, err, gqlt.ErrorCodeScriptStep)
+	}
+	return nil
+}
+
+// watchOptions controls how watchOperation repeats an operation
+type watchOptions struct {
+	interval    time.Duration
+	maxRuns     int         // Stop after this many runs if positive
+	untilChange bool        // Stop once the response differs from the previous one
+	diff        bool        // Print only the changes after the first response
+	summary     *RunSummary // Records the outcome of every run if set
+}
+
+// watchOperation runs an operation every interval until ctx is cancelled, writing
+// each response to out as a line of compact JSON. With diff set, responses after
+// the first are written as {"changes": [...]} lines, and only when something
+// changed. Cancellation is a normal way to stop and is not an error.
+func watchOperation(ctx context.Context, client *gqlt.Client, query string, variables map[string]interface{}, operationName string, opts watchOptions, out io.Writer) error {
+	encoder := json.NewEncoder(out)
+	var previous *gqlt.Response
+	for run := 1; ; run++ {
+		result, err := client.ExecuteContext(ctx, query, variables, operationName)
+		if ctx.Err() != nil {
+			return nil
+		}
+		opts.summary.Add(result, err)
+		if err != nil {
+			return fmt.Errorf("failed to execute GraphQL operation: %w", err)
+		}
+
+		var changes []gqlt.Change
+		if previous != nil && (opts.diff || opts.untilChange) {
+			changes, err = gqlt.DiffJSON(previous, result)
+			if err != nil {
+				return fmt.Errorf("failed to compare responses: %w", err)
+			}
+		}
+
+		switch {
+		case previous == nil || !opts.diff:
+			err = encoder.Encode(result)
+		case len(changes) > 0:
+			err = encoder.Encode(map[string]interface{}{"changes": changes})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+
+		if opts.untilChange && len(changes) > 0 {
+			return nil
+		}
+		if opts.maxRuns > 0 && run >= opts.maxRuns {
+			return nil
+		}
+		previous = result
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(opts.interval):
+		}
+	}
 }
 
 // runSubscription handles GraphQL subscription operations via SSE or WebSocket
-func runSubscription(query string, variables map[string]interface{}, operationName string, url string, headers map[string]string, timeout string, maxMessages int) error {
-	// Create GraphQL client with original URL (client will choose SSE vs WebSocket)
-	client := gqlt.NewClient(url, headers)
+func runSubscription(cmd *cobra.Command, query string, variables map[string]interface{}, operationName string, headers map[string]string, timeout string, maxMessages int, buffer int, replay io.Writer, out io.Writer) error {
+	// Create GraphQL client with original URL (client will choose SSE vs WebSocket),
+	// with the same authentication and TLS settings as queries
+	client, err := newRunClient(headers)
+	if err != nil {
+		return reportError(cmd, err, gqlt.ErrorCodeInputValidation)
+	}
+	client.SetSubscriptionBuffer(buffer, replay)
 
 	// Create context with optional timeout
 	ctx := context.Background()
@@ -296,8 +959,8 @@ func runSubscription(query string, variables map[string]interface{}, operationNa
 	if timeout != "" {
 		duration, err := time.ParseDuration(timeout)
 		if err != nil {
-			formatter := gqlt.NewFormatter(outputFormat)
-			return formatter.FormatStructuredError(fmt.Errorf("invalid timeout format: %w", err), "INVALID_TIMEOUT", quietMode)
+			err = fmt.Errorf("invalid timeout format: %w", err)
+			return reportError(cmd, err, "INVALID_TIMEOUT")
 		}
 		ctx, cancel = context.WithTimeout(ctx, duration)
 	} else {
@@ -306,18 +969,14 @@ func runSubscription(query string, variables map[string]interface{}, operationNa
 	defer cancel()
 
 	// Set up signal handling for Ctrl+C
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-sigChan
-		cancel()
-	}()
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	// Subscribe
 	messages, errors, err := client.Subscribe(ctx, query, variables, operationName)
 	if err != nil {
-		formatter := gqlt.NewFormatter(outputFormat)
-		return formatter.FormatStructuredError(fmt.Errorf("failed to start subscription: %w", err), "SUBSCRIPTION_ERROR", quietMode)
+		err = fmt.Errorf("failed to start subscription: %w", err)
+		return reportError(cmd, err, "SUBSCRIPTION_ERROR")
 	}
 
 	// Track message count
@@ -336,7 +995,7 @@ func runSubscription(query string, variables map[string]interface{}, operationNa
 				Data:   msg.Data,
 				Errors: msg.Errors,
 			}
-			encoder := json.NewEncoder(os.Stdout)
+			encoder := json.NewEncoder(out)
 			if err := encoder.Encode(response); err != nil {
 				return fmt.Errorf("failed to encode message: %w", err)
 			}
@@ -353,8 +1012,7 @@ func runSubscription(query string, variables map[string]interface{}, operationNa
 				return nil
 			}
 			// Return error
-			formatter := gqlt.NewFormatter(outputFormat)
-			return formatter.FormatStructuredError(err, "SUBSCRIPTION_ERROR", quietMode)
+			return reportError(cmd, err, "SUBSCRIPTION_ERROR")
 
 		case <-ctx.Done():
 			// Context cancelled (Ctrl+C)
@@ -362,3 +1020,107 @@ func runSubscription(query string, variables map[string]interface{}, operationNa
 		}
 	}
 }
+
+// refreshSchema re-introspects the endpoint and saves the schema of the named
+// configuration where gqlt introspect does, if the saved schema is older than
+// maxAge. Failing to refresh the schema only prints a warning.
+func refreshSchema(client *gqlt.Client, configName, maxAge string) error {
+	age, err := time.ParseDuration(maxAge)
+	if err != nil {
+		return fmt.Errorf("invalid schema refresh interval: %w", err)
+	}
+
+	path := savedSchemaPath(configName)
+	if !gqlt.SchemaStale(path, age) {
+		return nil
+	}
+
+	result, err := client.Introspect()
+	if err == nil {
+		if data, ok := result.Data.(map[string]interface{}); !ok || data["__schema"] == nil {
+			err = fmt.Errorf("introspection returned no schema")
+		}
+	}
+	if err == nil {
+		if configDir != "" {
+			err = gqlt.SaveSchemaDual(result, configName, configDir)
+		} else {
+			err = gqlt.SaveSchema(result, path)
+		}
+	}
+	if err != nil && !quietMode {
+		fmt.Fprintf(os.Stderr, "Warning: failed to refresh schema: %v\n", err)
+	}
+	return nil
+}
+
+// withUploadPlaceholder returns a copy of a variable's value with the value at
+// path, where an uploaded file goes, replaced by placeholder, so the check sees
+// the file rather than the null the variables hold for it. Paths that don't exist
+// in the value are left for the check to report.
+func withUploadPlaceholder(value interface{}, path []string, placeholder string) interface{} {
+	if len(path) == 0 {
+		return placeholder
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = item
+		}
+		copied[path[0]] = withUploadPlaceholder(v[path[0]], path[1:], placeholder)
+		return copied
+	case []interface{}:
+		index, err := strconv.Atoi(path[0])
+		if err != nil || index < 0 || index >= len(v) {
+			return value
+		}
+		copied := append([]interface{}(nil), v...)
+		copied[index] = withUploadPlaceholder(v[index], path[1:], placeholder)
+		return copied
+	}
+	return value
+}
+
+// savedSchemaPath returns where gqlt introspect saves the schema of the named
+// configuration
+func savedSchemaPath(configName string) string {
+	if configDir != "" {
+		return gqlt.GetJSONSchemaPathForConfigInDir(configName, configDir)
+	}
+	return gqlt.GetSchemaPathForConfig(configName)
+}
+
+// coerceVariables converts the variables to the types the operation declares for
+// them, using the saved schema of the named configuration
+func coerceVariables(configName, queryStr, operationName string, variables map[string]interface{}) (map[string]interface{}, error) {
+	analyzer, err := gqlt.LoadAnalyzerFromFile(savedSchemaPath(configName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schema for --coerce-vars (run gqlt introspect first): %w", err)
+	}
+	coerced, err := analyzer.CoerceVariables(queryStr, operationName, variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to coerce variables: %w", err)
+	}
+	return coerced, nil
+}
+
+// parseByteSize parses a size in bytes with an optional K, M or G suffix
+// (powers of 1024), e.g. "512", "100K" or "10M"
+func parseByteSize(size string) (int64, error) {
+	s := strings.TrimSpace(strings.ToUpper(size))
+	multiplier := int64(1)
+	switch {
+	case strings.HasSuffix(s, "K"):
+		multiplier, s = 1<<10, strings.TrimSuffix(s, "K")
+	case strings.HasSuffix(s, "M"):
+		multiplier, s = 1<<20, strings.TrimSuffix(s, "M")
+	case strings.HasSuffix(s, "G"):
+		multiplier, s = 1<<30, strings.TrimSuffix(s, "G")
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("not a valid size: %q", size)
+	}
+	return n * multiplier, nil
+}
@@ -1,8 +1,22 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/kluzzebass/gqlt"
 	"github.com/spf13/cobra"
 )
 
@@ -202,8 +216,1298 @@ func TestRunCommandVariableFlags(t *testing.T) {
 	}
 }
 
+func TestRunCommandOutFile(t *testing.T) {
+	tempDir, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	response := &gqlt.Response{
+		Data: map[string]interface{}{
+			"users": []interface{}{
+				map[string]interface{}{"id": "1", "name": "Alice"},
+			},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	// Run flags are package globals, so isolate this test from the others
+	resetRunFlags()
+	defer resetRunFlags()
+
+	// Parent directory does not exist yet and must be created
+	outPath := filepath.Join(tempDir, "results", "users.json")
+
+	cmd := createFullTestCommand()
+	_, err := executeCommandWithOutput(cmd, []string{"run", "--url", server.URL, "--query", "{ users { id name } }", "--out-file", outPath})
+	if err != nil {
+		t.Fatalf("run with --out-file failed: %v", err)
+	}
+
+	got, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("Expected output file to be written: %v", err)
+	}
+
+	// The file should hold exactly what would have been written to stdout
+	var expected bytes.Buffer
+	formatter := gqlt.NewFormatter("json")
+	formatter.SetOutput(&expected)
+	if err := formatter.FormatResponse(response, "compact"); err != nil {
+		t.Fatalf("Failed to format expected response: %v", err)
+	}
+
+	if string(got) != expected.String() {
+		t.Errorf("Expected file contents %q, got %q", expected.String(), string(got))
+	}
+}
+
+func TestRunCommandOutFileCheckedFirst(t *testing.T) {
+	tempDir, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"deleteUser": true}}`))
+	}))
+	defer server.Close()
+
+	// The output file's parent is a regular file, so the file can't be created
+	blocker := filepath.Join(tempDir, "blocker")
+	if err := os.WriteFile(blocker, []byte("not a directory"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	resetRunFlags()
+	defer resetRunFlags()
+
+	for _, outPath := range []string{filepath.Join(blocker, "out.json"), tempDir} {
+		_, err := executeCommandWithOutput(createFullTestCommand(), []string{"run", "--url", server.URL, "--query", `mutation { deleteUser(id: "1") }`, "--out-file", outPath})
+		if err == nil || !strings.Contains(err.Error(), "failed to open output file") {
+			t.Errorf("Expected an output file error for %s, got %v", outPath, err)
+		}
+	}
+	if requests != 0 {
+		t.Errorf("Expected the mutation not to be sent, got %d requests", requests)
+	}
+
+	// A writable path is checked without leaving a file behind if the operation fails
+	outPath := filepath.Join(tempDir, "new", "out.json")
+	resetRunFlags()
+	executeCommandWithOutput(createFullTestCommand(), []string{"run", "--url", "http://127.0.0.1:1", "--query", "{ hello }", "--out-file", outPath})
+	if _, err := os.Stat(outPath); err == nil {
+		t.Error("Expected no output file after a failed request")
+	}
+}
+
+func TestRunCommandInsecure(t *testing.T) {
+	tempDir, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	// The test server uses a self-signed certificate
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"hello": "world"}}`))
+	}))
+	defer server.Close()
+
+	resetRunFlags()
+	defer resetRunFlags()
+
+	// The response is only written to the output file if the request succeeds
+	outPath := filepath.Join(tempDir, "secure.json")
+	cmd := createFullTestCommand()
+	executeCommandWithOutput(cmd, []string{"run", "--url", server.URL, "--query", "{ hello }", "--out-file", outPath})
+	if _, err := os.Stat(outPath); err == nil {
+		t.Error("Expected request to fail certificate verification without --insecure")
+	}
+
+	resetRunFlags()
+	outPath = filepath.Join(tempDir, "insecure.json")
+	cmd = createFullTestCommand()
+	if _, err := executeCommandWithOutput(cmd, []string{"run", "--url", server.URL, "--query", "{ hello }", "--out-file", outPath, "--insecure"}); err != nil {
+		t.Fatalf("run with --insecure failed: %v", err)
+	}
+	if _, err := os.Stat(outPath); err != nil {
+		t.Errorf("Expected request to succeed with --insecure: %v", err)
+	}
+}
+
+// resetRunFlags restores the run command's package-level flag variables to their defaults
+func resetRunFlags() {
+	url, query, queryFile, operation, vars, varsFile = "", "", "", "", "", ""
+	headers, files, filesList = []string{}, []string{}, ""
+	username, password, token, apiKey = "", "", "", ""
+	timeout, maxMessages, subBuffer, replayFile, outFile = "", 0, 0, "", ""
+	insecure, warnVars, maxFileSize = false, false, ""
+	saveFields, selectPath, cacheTTL, retries = []string{}, "", "", 0
+	explain, watch, watchChange, watchCount, watchDiff = false, "", false, 0, false
+	script, acceptPartial, onlyOperation, refreshSchemaAge, coerceVars = "", false, false, "", false
+
+	// Help tests leave --help set on the shared command
+	if helpFlag := runCmd.Flags().Lookup("help"); helpFlag != nil {
+		helpFlag.Value.Set("false")
+	}
+
+	// The inherited --compact flag keeps its state across test commands too
+	if compactFlag := runCmd.Flags().Lookup("compact"); compactFlag != nil {
+		compactFlag.Value.Set("false")
+		compactFlag.Changed = false
+	}
+}
+
 // NOTE: Detailed output validation testing requires the Formatter to write to
 // test buffers instead of os.Stdout. This would require modifying how formatters
 // are initialized in commands. Current tests verify command structure, flags,
 // and that commands execute without panicking. Integration tests with actual
 // binary execution validate end-to-end behavior.
+
+func TestRunCommandConfigDefaults(t *testing.T) {
+	type request struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+	var received request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = request{}
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"user": {"name": "Ada"}}}`))
+	}))
+	defer server.Close()
+
+	configDir = t.TempDir()
+	defer func() {
+		configDir = ""
+	}()
+
+	cfg := gqlt.GetDefaultConfig()
+	cfg.SetValue("default", "endpoint", server.URL)
+	cfg.SetValue("default", "defaults.query", `query GetUser($id: ID!) { user(id: $id) { name } } query Other { version }`)
+	cfg.SetValue("default", "defaults.operation", "GetUser")
+	cfg.SetValue("default", "defaults.variables", `{"id": "123"}`)
+	if err := cfg.Save(configDir); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	resetRunFlags()
+	defer resetRunFlags()
+
+	// With no arguments, run sends the saved request
+	cmd := createFullTestCommand()
+	if _, err := executeCommandWithOutput(cmd, []string{"run", "--out-file", filepath.Join(t.TempDir(), "out.json")}); err != nil {
+		t.Fatalf("run with config defaults failed: %v", err)
+	}
+	if !strings.Contains(received.Query, "GetUser") || received.OperationName != "GetUser" || received.Variables["id"] != "123" {
+		t.Errorf("Expected the saved request to be sent, got %+v", received)
+	}
+
+	// CLI flags override the saved request
+	resetRunFlags()
+	cmd = createFullTestCommand()
+	if _, err := executeCommandWithOutput(cmd, []string{"run", "--query", "query ($id: ID) { version }", "--vars", `{"id": "456"}`, "--out-file", filepath.Join(t.TempDir(), "out.json")}); err != nil {
+		t.Fatalf("run with overriding flags failed: %v", err)
+	}
+	if received.Query != "query ($id: ID) { version }" || received.OperationName != "" || received.Variables["id"] != "456" {
+		t.Errorf("Expected CLI flags to override the saved request, got %+v", received)
+	}
+
+	// The saved variables belong to the saved query, so they aren't sent with an ad-hoc one
+	resetRunFlags()
+	cmd = createFullTestCommand()
+	if _, err := executeCommandWithOutput(cmd, []string{"run", "--query", "{ __typename }", "--out-file", filepath.Join(t.TempDir(), "out.json")}); err != nil {
+		t.Fatalf("run with only --query failed: %v", err)
+	}
+	if received.Query != "{ __typename }" || len(received.Variables) != 0 {
+		t.Errorf("Expected the saved variables not to be sent with --query, got %+v", received)
+	}
+}
+
+func TestRunCommandQueryFileImports(t *testing.T) {
+	var received struct {
+		Query string `json:"query"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"me": {"id": "1", "name": "Ada"}}}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "user.graphql"), []byte("fragment UserFields on User { id name }"), 0644); err != nil {
+		t.Fatalf("Failed to write fragment file: %v", err)
+	}
+	queryFile := filepath.Join(dir, "query.graphql")
+	if err := os.WriteFile(queryFile, []byte("# import ./user.graphql\nquery Me { me { ...UserFields } }"), 0644); err != nil {
+		t.Fatalf("Failed to write query file: %v", err)
+	}
+
+	resetRunFlags()
+	defer resetRunFlags()
+
+	cmd := createFullTestCommand()
+	if _, err := executeCommandWithOutput(cmd, []string{"run", "--url", server.URL, "--query-file", queryFile, "--out-file", filepath.Join(dir, "out.json")}); err != nil {
+		t.Fatalf("run with imported fragment failed: %v", err)
+	}
+	if !strings.Contains(received.Query, "fragment UserFields on User") || !strings.Contains(received.Query, "...UserFields") {
+		t.Errorf("Expected the imported fragment in the sent document, got %q", received.Query)
+	}
+}
+
+func TestRunCommandVariablesValidation(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"user": {"name": "Ada"}}}`))
+	}))
+	defer server.Close()
+
+	query := `query GetUser($id: ID!, $verbose: Boolean = false) { user(id: $id) { name } }`
+	tests := []struct {
+		name    string
+		vars    string
+		warn    bool
+		wantErr string
+	}{
+		{name: "matching variables", vars: `{"id": "1", "verbose": true}`},
+		{name: "missing required variable", vars: `{"verbose": true}`, wantErr: `missing required variable "$id"`},
+		{name: "unexpected variable", vars: `{"id": "1", "limit": 10}`, wantErr: `unexpected variable "$limit"`},
+		{name: "wrong scalar type", vars: `{"id": "1", "verbose": "yes"}`, wantErr: `"$verbose" of type Boolean cannot be string`},
+		{name: "warning only", vars: `{"limit": 10}`, warn: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetRunFlags()
+			defer resetRunFlags()
+			requests = 0
+
+			args := []string{"run", "--url", server.URL, "--query", query, "--vars", tt.vars, "--out-file", filepath.Join(t.TempDir(), "out.json")}
+			if tt.warn {
+				args = append(args, "--warn-vars")
+			}
+			_, err := executeCommandWithOutput(createFullTestCommand(), args)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				if requests != 0 {
+					t.Error("Expected no request to be sent")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+			if requests != 1 {
+				t.Errorf("Expected the request to be sent, got %d requests", requests)
+			}
+		})
+	}
+}
+
+func TestRunCommandVariablesValidationListUpload(t *testing.T) {
+	var operations string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		operations = r.FormValue("operations")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"uploadFiles": true}}`))
+	}))
+	defer server.Close()
+
+	upload := filepath.Join(t.TempDir(), "a.png")
+	if err := os.WriteFile(upload, []byte("png"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	resetRunFlags()
+	defer resetRunFlags()
+
+	// Map paths into a list variable fill its items rather than naming variables
+	args := []string{"run", "--url", server.URL, "--query", `mutation($files: [Upload!]!) { uploadFiles(files: $files) }`,
+		"--vars", `{"files": [null]}`, "--file", "files.0=" + upload, "--out-file", filepath.Join(t.TempDir(), "out.json")}
+	if _, err := executeCommandWithOutput(createFullTestCommand(), args); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if !strings.Contains(operations, `"files":[null]`) {
+		t.Errorf("Expected the files variable to be sent, got operations %s", operations)
+	}
+}
+
+func TestRunCommandMaxFileSize(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"upload": true}}`))
+	}))
+	defer server.Close()
+
+	upload := filepath.Join(t.TempDir(), "photo.jpg")
+	if err := os.WriteFile(upload, make([]byte, 2048), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	query := `mutation($file: Upload!) { upload(file: $file) }`
+
+	tests := []struct {
+		name    string
+		limit   string
+		wantErr string
+	}{
+		{name: "within limit", limit: "2K"},
+		{name: "over limit", limit: "1K", wantErr: "exceeds the maximum upload size"},
+		{name: "invalid limit", limit: "lots", wantErr: `not a valid size: "lots"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetRunFlags()
+			defer resetRunFlags()
+			requests = 0
+
+			args := []string{"run", "--url", server.URL, "--query", query, "--file", "file=" + upload,
+				"--max-file-size", tt.limit, "--out-file", filepath.Join(t.TempDir(), "out.json")}
+			_, err := executeCommandWithOutput(createFullTestCommand(), args)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				if requests != 0 {
+					t.Error("Expected no request to be sent")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+			if requests != 1 {
+				t.Errorf("Expected the request to be sent, got %d requests", requests)
+			}
+		})
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := map[string]int64{"512": 512, "100K": 100 << 10, "10m": 10 << 20, "1G": 1 << 30}
+	for input, want := range tests {
+		got, err := parseByteSize(input)
+		if err != nil || got != want {
+			t.Errorf("parseByteSize(%q) = %d, %v; want %d", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "K", "-1", "1.5M", "10T"} {
+		if _, err := parseByteSize(input); err == nil {
+			t.Errorf("parseByteSize(%q) should fail", input)
+		}
+	}
+}
+
+func TestRunCommandSaveField(t *testing.T) {
+	payload := []byte("%PDF-1.7 binary \x00\x01\x02 report contents")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"export": map[string]interface{}{
+					"file":  map[string]interface{}{"content": base64.StdEncoding.EncodeToString(payload)},
+					"image": "data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte("PNG")),
+					"md5":   "d41d8cd98f00b204e9800998ecf8427e",
+					"url":   "https://example.com/report.pdf",
+					"pages": []int{1, 2},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	resetRunFlags()
+	defer resetRunFlags()
+
+	args := []string{"run", "--url", server.URL, "--query", `mutation { export { file { content } image md5 url pages } }`,
+		"--save-field", "export.file.content=" + filepath.Join(dir, "report.pdf") + ":base64",
+		"--save-field", "export.image=" + filepath.Join(dir, "image.png"),
+		"--save-field", "export.md5=" + filepath.Join(dir, "md5.txt"),
+		"--save-field", "export.url=" + filepath.Join(dir, "url.txt"),
+		"--save-field", "export.pages=" + filepath.Join(dir, "pages.json"),
+		"--out-file", filepath.Join(dir, "out.json")}
+	if _, err := executeCommandWithOutput(createFullTestCommand(), args); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"report.pdf": string(payload),
+		"image.png":  "PNG",
+		"md5.txt":    "d41d8cd98f00b204e9800998ecf8427e",
+		"url.txt":    "https://example.com/report.pdf",
+		"pages.json": "[\n  1,\n  2\n]",
+	}
+	for name, want := range expected {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		if string(got) != want {
+			t.Errorf("Expected %s to contain %q, got %q", name, want, got)
+		}
+	}
+
+	// A path that isn't in the response fails the command
+	resetRunFlags()
+	args = []string{"run", "--url", server.URL, "--query", `mutation { export { url } }`,
+		"--save-field", "export.missing=" + filepath.Join(dir, "missing.txt"),
+		"--out-file", filepath.Join(dir, "out.json")}
+	_, err := executeCommandWithOutput(createFullTestCommand(), args)
+	if err == nil || !strings.Contains(err.Error(), `field "export.missing" not found`) {
+		t.Errorf("Expected missing field error, got %v", err)
+	}
+}
+
+func TestDecodeBase64(t *testing.T) {
+	tests := map[string]string{
+		base64.StdEncoding.EncodeToString([]byte("hello, binary world")):                   base64.StdEncoding.EncodeToString([]byte("hello, binary world")),
+		"data:application/pdf;base64," + base64.StdEncoding.EncodeToString([]byte("%PDF")): "%PDF",
+		"d41d8cd98f00b204e9800998ecf8427e":                                                 "d41d8cd98f00b204e9800998ecf8427e",
+		"https://example.com/file.pdf":                                                     "https://example.com/file.pdf",
+		"plain text that is not base64":                                                    "plain text that is not base64",
+	}
+	for input, want := range tests {
+		if got, err := decodeBase64(input, false); err != nil || string(got) != want {
+			t.Errorf("decodeBase64(%q, false) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+
+	// Plain base64 is only decoded when asked for
+	if got, err := decodeBase64(base64.StdEncoding.EncodeToString([]byte("hello")), true); err != nil || string(got) != "hello" {
+		t.Errorf("decodeBase64 with plain = %q, %v; want %q", got, err, "hello")
+	}
+	if _, err := decodeBase64("plain text that is not base64", true); err == nil {
+		t.Error("Expected an error for a value that isn't base64")
+	}
+}
+
+func TestRunCommandSelect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"user": {"name": "Ada", "roles": [{"name": "admin"}, {"name": "ops"}]}}}`))
+	}))
+	defer server.Close()
+
+	quietMode = true
+	defer func() { quietMode = false }()
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr string
+	}{
+		{name: "nested field", path: "data.user.name", want: "Ada\n"},
+		{name: "array index", path: "data.user.roles[1].name", want: "ops\n"},
+		{name: "object as JSON", path: "data.user.roles[0]", want: `{"name":"admin"}` + "\n"},
+		{name: "missing path", path: "data.user.email", wantErr: `field "data.user.email" not found`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetRunFlags()
+			defer resetRunFlags()
+
+			out := filepath.Join(t.TempDir(), "out.txt")
+			args := []string{"run", "--url", server.URL, "--query", "{ user { name roles { name } } }", "--select", tt.path, "--out-file", out}
+			_, err := executeCommandWithOutput(createFullTestCommand(), args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+			got, err := os.ReadFile(out)
+			if err != nil {
+				t.Fatalf("Failed to read output: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRunCommandCacheTTL(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"stats": {"activeUsers": 42}}}`))
+	}))
+	defer server.Close()
+
+	// Keep cached responses out of the real user cache directory
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	for i := 0; i < 2; i++ {
+		resetRunFlags()
+		out := filepath.Join(t.TempDir(), "out.json")
+		args := []string{"run", "--url", server.URL, "--query", "{ stats { activeUsers } }", "--cache-ttl", "1m", "--out-file", out}
+		if _, err := executeCommandWithOutput(createFullTestCommand(), args); err != nil {
+			t.Fatalf("run failed: %v", err)
+		}
+		got, err := os.ReadFile(out)
+		if err != nil || !strings.Contains(string(got), `"activeUsers":42`) {
+			t.Errorf("Expected the response in the output, got %q (%v)", got, err)
+		}
+	}
+	resetRunFlags()
+
+	if requests != 1 {
+		t.Errorf("Expected the second run to be served from the cache, got %d requests", requests)
+	}
+
+	_, err := executeCommandWithOutput(createFullTestCommand(), []string{"run", "--url", server.URL, "--query", "{ stats { activeUsers } }", "--cache-ttl", "soon"})
+	resetRunFlags()
+	if err == nil || !strings.Contains(err.Error(), "invalid duration") {
+		t.Errorf("Expected an invalid TTL error, got %v", err)
+	}
+}
+
+func TestRunCommandRetries(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"ok": true}}`))
+	}))
+	defer server.Close()
+
+	resetRunFlags()
+	defer resetRunFlags()
+
+	out := filepath.Join(t.TempDir(), "out.json")
+	args := []string{"run", "--url", server.URL, "--query", "{ ok }", "--retries", "2", "--out-file", out}
+	if _, err := executeCommandWithOutput(createFullTestCommand(), args); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("Expected two retries, got %d requests", requests)
+	}
+	if got, _ := os.ReadFile(out); !strings.Contains(string(got), `"ok":true`) {
+		t.Errorf("Expected the successful response in the output, got %q", got)
+	}
+}
+
+func TestRunCommandExplain(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"user": {"name": "Ada"}}, "extensions": {"tracing": {
+			"version": 1, "duration": 1500000,
+			"parsing": {"startOffset": 0, "duration": 40000},
+			"validation": {"startOffset": 40000, "duration": 10000},
+			"execution": {"resolvers": [
+				{"path": ["user", "name"], "parentType": "User", "fieldName": "name", "returnType": "String", "startOffset": 900000, "duration": 5000},
+				{"path": ["user"], "parentType": "Query", "fieldName": "user", "returnType": "User", "startOffset": 100000, "duration": 800000}
+			]}
+		}}}`))
+	}))
+	defer server.Close()
+
+	resetRunFlags()
+	defer resetRunFlags()
+
+	var stderr bytes.Buffer
+	out := filepath.Join(t.TempDir(), "out.json")
+	cmd := createFullTestCommand()
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{"run", "--url", server.URL, "--query", "{ user { name } }", "--explain", "--out-file", out})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	for _, want := range []string{"Tracing (total 1.500ms)", "    user  Query.user: User  0.800ms", "      name  User.name: String  0.005ms"} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("Expected explain output to contain %q, got:\n%s", want, stderr.String())
+		}
+	}
+	if got, _ := os.ReadFile(out); !strings.Contains(string(got), `"name":"Ada"`) {
+		t.Errorf("Expected the response to still be written, got %q", got)
+	}
+}
+
+func TestWatchOperation(t *testing.T) {
+	var requests atomic.Int32
+	var onRequest func(n int32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		if onRequest != nil {
+			onRequest(n)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"data": {"status": %q}}`, map[bool]string{true: "done", false: "pending"}[n >= 3])
+	}))
+	defer server.Close()
+	client := gqlt.NewClient(server.URL, nil)
+
+	t.Run("max runs", func(t *testing.T) {
+		requests.Store(0)
+		var out bytes.Buffer
+		if err := watchOperation(context.Background(), client, "{ status }", nil, "", watchOptions{interval: time.Millisecond, maxRuns: 4}, &out); err != nil {
+			t.Fatalf("watchOperation failed: %v", err)
+		}
+		if requests.Load() != 4 {
+			t.Errorf("Expected 4 executions, got %d", requests.Load())
+		}
+		if lines := strings.Count(out.String(), "\n"); lines != 4 {
+			t.Errorf("Expected 4 results, got %d:\n%s", lines, out.String())
+		}
+	})
+
+	t.Run("until change", func(t *testing.T) {
+		requests.Store(0)
+		var out bytes.Buffer
+		if err := watchOperation(context.Background(), client, "{ status }", nil, "", watchOptions{interval: time.Millisecond, maxRuns: 10, untilChange: true}, &out); err != nil {
+			t.Fatalf("watchOperation failed: %v", err)
+		}
+		if requests.Load() != 3 {
+			t.Errorf("Expected to stop at the first change (3 executions), got %d", requests.Load())
+		}
+		if !strings.HasSuffix(out.String(), `{"data":{"status":"done"}}`+"\n") {
+			t.Errorf("Expected the changed response last, got:\n%s", out.String())
+		}
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		requests.Store(0)
+		var out bytes.Buffer
+		if err := watchOperation(context.Background(), client, "{ status }", nil, "", watchOptions{interval: time.Millisecond, maxRuns: 4, diff: true}, &out); err != nil {
+			t.Fatalf("watchOperation failed: %v", err)
+		}
+		// The full first response, nothing for the unchanged second, then the change
+		expected := `{"data":{"status":"pending"}}` + "\n" +
+			`{"changes":[{"kind":"CHANGED","path":"data.status","old":"pending","new":"done"}]}` + "\n"
+		if out.String() != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, out.String())
+		}
+	})
+
+	t.Run("context cancellation", func(t *testing.T) {
+		requests.Store(0)
+		ctx, cancel := context.WithCancel(context.Background())
+		onRequest = func(n int32) {
+			if n == 2 {
+				cancel()
+			}
+		}
+		defer func() { onRequest = nil }()
+
+		done := make(chan error, 1)
+		go func() {
+			done <- watchOperation(ctx, client, "{ status }", nil, "", watchOptions{interval: time.Millisecond}, io.Discard)
+		}()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("Expected cancellation to end the loop without an error, got %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("watchOperation did not stop after the context was cancelled")
+		}
+		if requests.Load() != 2 {
+			t.Errorf("Expected no runs after cancellation, got %d", requests.Load())
+		}
+	})
+}
+
+func TestRunCommandWatch(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"ok": true}}`))
+	}))
+	defer server.Close()
+
+	resetRunFlags()
+	defer resetRunFlags()
+
+	out := filepath.Join(t.TempDir(), "out.json")
+	args := []string{"run", "--url", server.URL, "--query", "{ ok }", "--watch", "10ms", "--watch-count", "3", "--out-file", out}
+	if _, err := executeCommandWithOutput(createFullTestCommand(), args); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 executions, got %d", requests)
+	}
+	got, _ := os.ReadFile(out)
+	if lines := strings.Count(string(got), "\n"); lines != 3 {
+		t.Errorf("Expected 3 results in the output, got %q", got)
+	}
+
+	resetRunFlags()
+	_, err := executeCommandWithOutput(createFullTestCommand(), []string{"run", "--url", server.URL, "--query", "{ ok }", "--watch", "0s"})
+	if err == nil || !strings.Contains(err.Error(), "interval must be positive") {
+		t.Errorf("Expected an invalid interval error, got %v", err)
+	}
+}
+
+func TestRunCommandScript(t *testing.T) {
+	var received []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		received = append(received, body.Variables)
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(body.Query, "createUser"):
+			w.Write([]byte(`{"data": {"createUser": {"id": "42"}}}`))
+		case strings.Contains(body.Query, "failing"):
+			w.Write([]byte(`{"data": null, "errors": [{"message": "boom"}]}`))
+		default:
+			w.Write([]byte(`{"data": {"user": {"id": "42", "name": "Ada"}}}`))
+		}
+	}))
+	defer server.Close()
+
+	writeScript := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "steps.json")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write script: %v", err)
+		}
+		return path
+	}
+
+	t.Run("create then fetch", func(t *testing.T) {
+		resetRunFlags()
+		defer resetRunFlags()
+		received = nil
+
+		script := writeScript(t, `[
+			{"query": "mutation { createUser(name: \"Ada\") { id } }"},
+			{"query": "query ($id: ID!) { user(id: $id) { id name } }", "variables": {"id": "${step1.data.createUser.id}"}}
+		]`)
+		out := filepath.Join(t.TempDir(), "out.json")
+		args := []string{"run", "--url", server.URL, "--script", script, "--out-file", out}
+		if _, err := executeCommandWithOutput(createFullTestCommand(), args); err != nil {
+			t.Fatalf("run failed: %v", err)
+		}
+		if len(received) != 2 || received[1]["id"] != "42" {
+			t.Errorf("Expected the fetch to use the created ID, got %v", received)
+		}
+		got, _ := os.ReadFile(out)
+		lines := strings.Split(strings.TrimSpace(string(got)), "\n")
+		if len(lines) != 2 || !strings.Contains(lines[0], `"step":"step1"`) || !strings.Contains(lines[1], `"name":"Ada"`) {
+			t.Errorf("Expected a result line per step, got %q", got)
+		}
+	})
+
+	t.Run("failing first step", func(t *testing.T) {
+		resetRunFlags()
+		defer resetRunFlags()
+		received = nil
+
+		script := writeScript(t, `[
+			{"name": "first", "query": "query failing { failing }"},
+			{"query": "{ user(id: 1) { id } }"}
+		]`)
+		out := filepath.Join(t.TempDir(), "out.json")
+		args := []string{"run", "--url", server.URL, "--script", script, "--out-file", out}
+		_, err := executeCommandWithOutput(createFullTestCommand(), args)
+		if err == nil || !strings.Contains(err.Error(), `step "first"`) {
+			t.Fatalf("Expected an error naming the first step, got %v", err)
+		}
+		if len(received) != 1 {
+			t.Errorf("Expected the script to stop after the first step, got %d requests", len(received))
+		}
+		if got, _ := os.ReadFile(out); !strings.Contains(string(got), "boom") {
+			t.Errorf("Expected the failing response in the output, got %q", got)
+		}
+	})
+
+	t.Run("combined with query", func(t *testing.T) {
+		resetRunFlags()
+		defer resetRunFlags()
+
+		script := writeScript(t, `[{"query": "{ ok }"}]`)
+		_, err := executeCommandWithOutput(createFullTestCommand(), []string{"run", "--url", server.URL, "--script", script, "--query", "{ ok }"})
+		if err == nil || !strings.Contains(err.Error(), "--script cannot be combined") {
+			t.Errorf("Expected a validation error, got %v", err)
+		}
+	})
+}
+
+func TestRunCommandExitCodes(t *testing.T) {
+	tempDir, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	handler := func(status int, body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		}
+	}
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closedURL := closed.URL
+	closed.Close()
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		url     string
+		args    []string
+		want    int
+	}{
+		{"success", handler(http.StatusOK, `{"data":{"hello":"world"}}`), "", nil, 0},
+		{"graphql errors", handler(http.StatusOK, `{"data":null,"errors":[{"message":"boom"}]}`), "", nil, exitCodeGraphQL},
+		{"unauthorized with a GraphQL body", handler(http.StatusUnauthorized, `{"errors":[{"message":"not logged in"}]}`), "", nil, exitCodeAuth},
+		{"forbidden without a GraphQL body", handler(http.StatusForbidden, `Forbidden`), "", nil, exitCodeAuth},
+		{"forbidden with uploads", handler(http.StatusForbidden, `Forbidden`), "", []string{"--query", "mutation($doc: Upload!) { upload(file: $doc) }", "--file", "doc=" + filepath.Join(tempDir, "upload.txt")}, exitCodeAuth},
+		{"unreachable server", nil, closedURL, nil, exitCodeNetwork},
+		{"server error", handler(http.StatusInternalServerError, `Internal Server Error`), "", nil, exitCodeUsage},
+		{"invalid input", handler(http.StatusOK, `{"data":{}}`), "", []string{"--vars", "{not json"}, exitCodeUsage},
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "upload.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatalf("Failed to write upload: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint := tt.url
+			if tt.handler != nil {
+				server := httptest.NewServer(tt.handler)
+				defer server.Close()
+				endpoint = server.URL
+			}
+
+			resetRunFlags()
+			defer resetRunFlags()
+
+			args := append([]string{"run", "--url", endpoint, "--query", "{ hello }", "--out-file", filepath.Join(tempDir, "out.json")}, tt.args...)
+			_, err := executeCommandWithOutput(createFullTestCommand(), args)
+			if got := exitCodeForError(err); got != tt.want {
+				t.Errorf("Expected exit code %d, got %d (error: %v)", tt.want, got, err)
+			}
+		})
+	}
+}
+
+func TestRunCommandExitCodesStructuredFormats(t *testing.T) {
+	tempDir, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Has("unauthorized") {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"errors":[{"message":"not logged in"}]}`))
+			return
+		}
+		w.Write([]byte(`{"data":null,"errors":[{"message":"boom"}]}`))
+	}))
+	defer server.Close()
+
+	defer func() {
+		outputFormat = "json"
+	}()
+	for _, format := range []string{"table", "yaml"} {
+		for endpoint, want := range map[string]int{
+			server.URL:                   exitCodeGraphQL,
+			server.URL + "?unauthorized": exitCodeAuth,
+		} {
+			resetRunFlags()
+			outputFormat = format
+			args := []string{"run", "--url", endpoint, "--query", "{ hello }", "--out-file", filepath.Join(tempDir, "out.txt")}
+			_, err := executeCommandWithOutput(createFullTestCommand(), args)
+			if got := exitCodeForError(err); got != want {
+				t.Errorf("%s output from %s: expected exit code %d, got %d (error: %v)", format, endpoint, want, got, err)
+			}
+		}
+	}
+	resetRunFlags()
+}
+
+func TestRunCommandStructuredErrorsOnly(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	resetRunFlags()
+	defer resetRunFlags()
+
+	// Input errors are written as structured errors, so cobra mustn't add its error line and usage
+	runCmd.SilenceErrors, runCmd.SilenceUsage = false, false
+	_, err := executeCommandWithOutput(createFullTestCommand(), []string{"run", "--url", "http://localhost:1", "--query", "{ hello }", "--vars", "{not json"})
+	if err == nil {
+		t.Fatal("Expected an error for invalid variables")
+	}
+	if !runCmd.SilenceErrors || !runCmd.SilenceUsage {
+		t.Error("Expected cobra's error and usage output to be silenced")
+	}
+}
+
+func TestRunCommandAcceptPartial(t *testing.T) {
+	tempDir, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.RawQuery, "nodata") {
+			w.Write([]byte(`{"data":null,"errors":[{"message":"boom"}]}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"user":{"id":"1","risky":null}},"errors":[{"message":"risky failed","path":["user","risky"]}]}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		url  string
+		args []string
+		want int
+	}{
+		{"partial data without the flag", server.URL, nil, exitCodeGraphQL},
+		{"partial data with the flag", server.URL, []string{"--accept-partial"}, 0},
+		{"no data with the flag", server.URL + "?nodata", []string{"--accept-partial"}, exitCodeGraphQL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetRunFlags()
+			defer resetRunFlags()
+
+			outPath := filepath.Join(tempDir, "out.json")
+			args := append([]string{"run", "--url", tt.url, "--query", "{ user { id risky } }", "--out-file", outPath}, tt.args...)
+			_, err := executeCommandWithOutput(createFullTestCommand(), args)
+			if got := exitCodeForError(err); got != tt.want {
+				t.Errorf("Expected exit code %d, got %d (error: %v)", tt.want, got, err)
+			}
+
+			// The errors are printed either way
+			out, readErr := os.ReadFile(outPath)
+			if readErr != nil {
+				t.Fatalf("Failed to read output: %v", readErr)
+			}
+			if !strings.Contains(string(out), `"errors"`) {
+				t.Errorf("Expected the errors in the output, got %s", out)
+			}
+		})
+	}
+}
+
+func TestRunCommandCompact(t *testing.T) {
+	tempDir, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"user":{"id":"1","name":"Ada"}}}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantLines int
+	}{
+		{"compact", []string{"--compact"}, 1},
+		{"indented", []string{"--compact=false"}, 8},
+		{"default for a file", nil, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetRunFlags()
+			defer resetRunFlags()
+
+			outPath := filepath.Join(tempDir, "out.json")
+			args := append([]string{"run", "--url", server.URL, "--query", "{ user { id name } }", "--out-file", outPath}, tt.args...)
+			if _, err := executeCommandWithOutput(createFullTestCommand(), args); err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+
+			out, err := os.ReadFile(outPath)
+			if err != nil {
+				t.Fatalf("Failed to read output: %v", err)
+			}
+			if lines := strings.Count(string(out), "\n"); lines != tt.wantLines {
+				t.Errorf("Expected %d lines, got %d:\n%s", tt.wantLines, lines, out)
+			}
+		})
+	}
+}
+
+func TestRunCommandOnlyOperation(t *testing.T) {
+	tempDir, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	var received struct {
+		Query         string `json:"query"`
+		OperationName string `json:"operationName"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"user":{"id":"1"}}}`))
+	}))
+	defer server.Close()
+
+	queryFile := filepath.Join(tempDir, "operations.graphql")
+	content := `query ListUsers { users { ...UserFields } }
+query GetUser { user(id: 1) { id } }
+fragment UserFields on User { id name }`
+	if err := os.WriteFile(queryFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write query file: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantWhole bool
+	}{
+		{"whole document", nil, true},
+		{"only the operation", []string{"--only-operation"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetRunFlags()
+			defer resetRunFlags()
+
+			args := append([]string{"run", "--url", server.URL, "--query-file", queryFile, "--operation", "GetUser", "--out-file", filepath.Join(tempDir, "out.json")}, tt.args...)
+			if _, err := executeCommandWithOutput(createFullTestCommand(), args); err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+			if received.OperationName != "GetUser" {
+				t.Errorf("Expected operation GetUser, got %q", received.OperationName)
+			}
+			if whole := strings.Contains(received.Query, "ListUsers"); whole != tt.wantWhole {
+				t.Errorf("Expected whole document %v, got query:\n%s", tt.wantWhole, received.Query)
+			}
+			if !strings.Contains(received.Query, "query GetUser") {
+				t.Errorf("Expected the GetUser operation, got query:\n%s", received.Query)
+			}
+		})
+	}
+
+	resetRunFlags()
+	defer resetRunFlags()
+	args := []string{"run", "--url", server.URL, "--query-file", queryFile, "--operation", "Missing", "--only-operation"}
+	if _, err := executeCommandWithOutput(createFullTestCommand(), args); err == nil {
+		t.Error("Expected an error for an unknown operation")
+	}
+}
+
+func TestRunCommandSubscriptionInsecure(t *testing.T) {
+	tempDir, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	// The test server uses a self-signed certificate and only speaks graphql-sse
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/event-stream" {
+			http.Error(w, "no websocket", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: next\ndata: {\"data\":{\"count\":1}}\n\n")
+		fmt.Fprint(w, "event: complete\ndata:\n\n")
+	}))
+	defer server.Close()
+
+	resetRunFlags()
+	defer resetRunFlags()
+
+	args := []string{"run", "--url", server.URL, "--query", "subscription { count }", "--out-file", filepath.Join(tempDir, "secure.jsonl")}
+	if _, err := executeCommandWithOutput(createFullTestCommand(), args); err == nil {
+		t.Error("Expected the subscription to fail certificate verification without --insecure")
+	}
+
+	resetRunFlags()
+	outPath := filepath.Join(tempDir, "insecure.jsonl")
+	args = []string{"run", "--url", server.URL, "--query", "subscription { count }", "--out-file", outPath, "--insecure"}
+	if _, err := executeCommandWithOutput(createFullTestCommand(), args); err != nil {
+		t.Fatalf("run with --insecure failed: %v", err)
+	}
+	if output, _ := os.ReadFile(outPath); !strings.Contains(string(output), `"count":1`) {
+		t.Errorf("Expected the subscription message in the output, got:\n%s", output)
+	}
+}
+
+func TestRunCommandSubscriptionReplayFile(t *testing.T) {
+	tempDir, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Refuse the WebSocket upgrade so the client falls back to graphql-sse
+		if r.Header.Get("Accept") != "text/event-stream" {
+			http.Error(w, "no websocket", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 1; i <= 3; i++ {
+			fmt.Fprintf(w, "event: next\ndata: {\"data\":{\"count\":%d}}\n\n", i)
+		}
+		fmt.Fprint(w, "event: complete\ndata:\n\n")
+	}))
+	defer server.Close()
+
+	resetRunFlags()
+	defer resetRunFlags()
+
+	outPath := filepath.Join(tempDir, "out.jsonl")
+	replayPath := filepath.Join(tempDir, "replay.jsonl")
+	if err := os.WriteFile(replayPath, []byte("{\"data\":{\"count\":0}}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write replay file: %v", err)
+	}
+	args := []string{"run", "--url", server.URL, "--query", "subscription { count }", "--buffer", "2", "--replay-file", replayPath, "--out-file", outPath}
+	if _, err := executeCommandWithOutput(createFullTestCommand(), args); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	output, _ := os.ReadFile(outPath)
+	if lines := strings.Count(string(output), "\n"); lines != 3 {
+		t.Errorf("Expected 3 messages in the output, got:\n%s", output)
+	}
+
+	// Messages are appended to the replay file
+	replay, _ := os.ReadFile(replayPath)
+	expected := "{\"data\":{\"count\":0}}\n" +
+		"{\"type\":\"data\",\"data\":{\"count\":1}}\n" +
+		"{\"type\":\"data\",\"data\":{\"count\":2}}\n" +
+		"{\"type\":\"data\",\"data\":{\"count\":3}}\n"
+	if string(replay) != expected {
+		t.Errorf("Expected replay file:\n%s\ngot:\n%s", expected, replay)
+	}
+}
+
+func TestRunCommandRefreshSchema(t *testing.T) {
+	tempDir, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	configDir = tempDir
+	defer func() { configDir = "" }()
+
+	introspection, err := gqlt.SDLToIntrospection("type Query { ok: Boolean }")
+	if err != nil {
+		t.Fatalf("SDLToIntrospection failed: %v", err)
+	}
+	var introspections int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(request.Query, "__schema") {
+			atomic.AddInt32(&introspections, 1)
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": introspection})
+			return
+		}
+		w.Write([]byte(`{"data":{"ok":true}}`))
+	}))
+	defer server.Close()
+
+	schemaPath := gqlt.GetJSONSchemaPathForConfigInDir("default", tempDir)
+	if err := os.MkdirAll(filepath.Dir(schemaPath), 0755); err != nil {
+		t.Fatalf("Failed to create schemas directory: %v", err)
+	}
+	if err := os.WriteFile(schemaPath, []byte(`{}`), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	stale := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(schemaPath, stale, stale); err != nil {
+		t.Fatalf("Failed to age schema: %v", err)
+	}
+
+	run := func() {
+		t.Helper()
+		resetRunFlags()
+		defer resetRunFlags()
+		args := []string{"run", "--url", server.URL, "--query", "{ ok }", "--refresh-schema", "24h", "--out-file", filepath.Join(tempDir, "out.json")}
+		if _, err := executeCommandWithOutput(createFullTestCommand(), args); err != nil {
+			t.Fatalf("run failed: %v", err)
+		}
+	}
+
+	// A stale schema is re-introspected and saved in both formats
+	run()
+	if n := atomic.LoadInt32(&introspections); n != 1 {
+		t.Fatalf("Expected the stale schema to be refreshed once, got %d introspections", n)
+	}
+	info, err := os.Stat(schemaPath)
+	if err != nil || !info.ModTime().After(stale) {
+		t.Errorf("Expected the schema to be saved again: %v", err)
+	}
+	if !gqlt.SchemaExists(gqlt.GetGraphQLSchemaPathForConfigInDir("default", tempDir)) {
+		t.Error("Expected the SDL schema to be saved")
+	}
+
+	// A fresh schema is left alone
+	run()
+	if n := atomic.LoadInt32(&introspections); n != 1 {
+		t.Errorf("Expected the fresh schema not to be refreshed, got %d introspections", n)
+	}
+}
+
+func TestRunCommandCoerceVars(t *testing.T) {
+	tempDir, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	configDir = tempDir
+	defer func() { configDir = "" }()
+
+	introspection, err := gqlt.SDLToIntrospection(`
+		type Query { orders(limit: Int, status: Status): [ID!]! }
+		enum Status { PENDING SHIPPED }
+	`)
+	if err != nil {
+		t.Fatalf("SDLToIntrospection failed: %v", err)
+	}
+	if err := gqlt.SaveSchemaDual(&gqlt.Response{Data: introspection}, "default", tempDir); err != nil {
+		t.Fatalf("SaveSchemaDual failed: %v", err)
+	}
+
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		received = request.Variables
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"orders":[]}}`))
+	}))
+	defer server.Close()
+
+	resetRunFlags()
+	defer resetRunFlags()
+	args := []string{"run", "--url", server.URL,
+		"--query", "query ($limit: Int, $status: Status) { orders(limit: $limit, status: $status) }",
+		"--vars", `{"limit": "10", "status": "shipped"}`, "--coerce-vars",
+		"--out-file", filepath.Join(tempDir, "out.json")}
+	if _, err := executeCommandWithOutput(createFullTestCommand(), args); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	if received["limit"] != 10.0 {
+		t.Errorf("Expected limit to be sent as the number 10, got %#v", received["limit"])
+	}
+	if received["status"] != "SHIPPED" {
+		t.Errorf("Expected status to be sent as SHIPPED, got %#v", received["status"])
+	}
+}
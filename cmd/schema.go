@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/kluzzebass/gqlt"
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Work with saved GraphQL schema files",
+	Long: `Work with saved GraphQL schema files (introspection JSON or SDL).
+
+AI-FRIENDLY FEATURES:
+- Structured output with --format json|table|yaml
+- Machine-readable change categories
+- Quiet mode for automation`,
+	Example: `# Compare two schema snapshots
+gqlt schema diff --old schema-v1.json --new schema-v2.json`,
+}
+
+var schemaDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare two schemas and report changes",
+	Long: `Compare two schema files and report added/removed types, added/removed fields,
+changed field types, and added/removed enum values.
+
+Removals and type narrowings are marked as breaking.`,
+	Example: `gqlt schema diff --old schema-v1.json --new schema-v2.json
+gqlt schema diff --old old.graphqls --new new.graphqls --format json --quiet`,
+	Args: cobra.NoArgs,
+	RunE: schemaDiff,
+}
+
+var (
+	schemaDiffOld string
+	schemaDiffNew string
+)
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+	schemaCmd.AddCommand(schemaDiffCmd)
+
+	schemaDiffCmd.Flags().StringVar(&schemaDiffOld, "old", "", "Path to the old schema file (JSON or SDL)")
+	schemaDiffCmd.Flags().StringVar(&schemaDiffNew, "new", "", "Path to the new schema file (JSON or SDL)")
+	schemaDiffCmd.MarkFlagRequired("old")
+	schemaDiffCmd.MarkFlagRequired("new")
+}
+
+func schemaDiff(cmd *cobra.Command, args []string) error {
+	quietMode := cmd.Flag("quiet").Value.String() == "true"
+	formatter := setupFormatter(cmd)
+
+	oldSchema, err := gqlt.LoadSchemaFromFile(schemaDiffOld)
+	if err != nil {
+		return formatter.FormatStructuredError(fmt.Errorf("failed to load old schema: %w", err), gqlt.ErrorCodeSchemaLoad, quietMode)
+	}
+
+	newSchema, err := gqlt.LoadSchemaFromFile(schemaDiffNew)
+	if err != nil {
+		return formatter.FormatStructuredError(fmt.Errorf("failed to load new schema: %w", err), gqlt.ErrorCodeSchemaLoad, quietMode)
+	}
+
+	report, err := gqlt.SchemaDiff(oldSchema, newSchema)
+	if err != nil {
+		return formatter.FormatStructuredError(fmt.Errorf("failed to diff schemas: %w", err), gqlt.ErrorCodeSchemaLoad, quietMode)
+	}
+
+	return formatter.FormatStructured(report, quietMode)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestSchemaCommandStructure(t *testing.T) {
+	// Test that schema command exists and has the diff subcommand
+	var schemaCmd *cobra.Command
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() == "schema" {
+			schemaCmd = cmd
+			break
+		}
+	}
+	if schemaCmd == nil {
+		t.Fatalf("Expected schema command to be registered")
+	}
+
+	found := false
+	for _, cmd := range schemaCmd.Commands() {
+		if cmd.Name() == "diff" {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Error("Expected schema subcommand 'diff' to be registered")
+	}
+}
+
+func TestSchemaDiffCommandFlags(t *testing.T) {
+	for _, flagName := range []string{"old", "new"} {
+		if schemaDiffCmd.Flag(flagName) == nil {
+			t.Errorf("Expected schema diff command to have flag '%s'", flagName)
+		}
+	}
+}
+
+func TestSchemaDiffCommand(t *testing.T) {
+	tempDir := t.TempDir()
+
+	oldPath := filepath.Join(tempDir, "old.graphqls")
+	newPath := filepath.Join(tempDir, "new.graphqls")
+	if err := os.WriteFile(oldPath, []byte("type Query { hello: String\n legacy: String }"), 0644); err != nil {
+		t.Fatalf("Failed to write old schema: %v", err)
+	}
+	if err := os.WriteFile(newPath, []byte("type Query { hello: String }"), 0644); err != nil {
+		t.Fatalf("Failed to write new schema: %v", err)
+	}
+
+	cmd := createFullTestCommand()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetArgs([]string{"schema", "diff", "--old", oldPath, "--new", newPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("schema diff failed: %v", err)
+	}
+
+	var output struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Changes []struct {
+				Change   string `json:"change"`
+				Path     string `json:"path"`
+				Breaking bool   `json:"breaking"`
+			} `json:"changes"`
+			BreakingCount int `json:"breakingCount"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+		t.Fatalf("Failed to parse output %q: %v", buf.String(), err)
+	}
+
+	if !output.Success {
+		t.Error("Expected success output")
+	}
+	if len(output.Data.Changes) != 1 || output.Data.Changes[0].Path != "Query.legacy" {
+		t.Errorf("Expected a single removal of Query.legacy, got %+v", output.Data.Changes)
+	}
+	if output.Data.BreakingCount != 1 {
+		t.Errorf("Expected 1 breaking change, got %d", output.Data.BreakingCount)
+	}
+}
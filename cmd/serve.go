@@ -1,9 +1,12 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
+	"math/rand"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
@@ -13,6 +16,7 @@ import (
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/gorilla/websocket"
 	"github.com/kluzzebass/gqlt/internal/mockserver/graph"
+	"github.com/kluzzebass/gqlt/internal/mockserver/sdlmock"
 	"github.com/spf13/cobra"
 	"github.com/vektah/gqlparser/v2/ast"
 )
@@ -20,6 +24,10 @@ import (
 var (
 	serveListen     string
 	servePlayground bool
+	serveSchema     string
+	serveSeed       string
+	serveLatency    time.Duration
+	serveErrorRate  float64
 )
 
 // serveCmd represents the serve command
@@ -37,7 +45,20 @@ The server includes:
 - GraphQL Playground for interactive testing
 - Relay Node pattern for global object identification
 
-The server is pre-seeded with sample data and ready to use immediately.`,
+The server is pre-seeded with sample data and ready to use immediately.
+With --seed, it instead starts with the data in a JSON file mapping type names
+(User, Todo, FileAttachment, LinkAttachment) to arrays of objects with the
+type's fields. IDs are assigned in order (User:1, User:2, ...), and a todo's
+createdBy, assignedTo and attachments fields take IDs of seeded entities.
+
+With --schema, the server instead serves the schema defined in an SDL file,
+answering introspection and resolving every query and mutation field with
+generated values of its type (subscriptions are not supported). This mocks any
+API, e.g. for frontend development.
+
+For resilience testing, --inject-latency delays every response and
+--inject-error-rate answers the given fraction of requests (0 to 1) with a
+GraphQL error instead of resolving them.`,
 	Example: `  # Start server on default address (localhost:8090)
   gqlt serve
 
@@ -53,6 +74,15 @@ The server is pre-seeded with sample data and ready to use immediately.`,
   # Start without playground
   gqlt serve --no-playground
 
+  # Start with your own data
+  gqlt serve --seed seed.json
+
+  # Mock your own API from its SDL
+  gqlt serve --schema schema.graphqls
+
+  # Simulate a slow, flaky API
+  gqlt serve --inject-latency 200ms --inject-error-rate 0.1
+
   # Test with queries
   gqlt serve &
   gqlt run --url http://localhost:8090/graphql --query '{ users { id name email } }'
@@ -68,11 +98,84 @@ func init() {
 
 	serveCmd.Flags().StringVarP(&serveListen, "listen", "l", "localhost:8090", "Address to listen on (host:port)")
 	serveCmd.Flags().BoolVar(&servePlayground, "playground", true, "Enable GraphQL Playground")
+	serveCmd.Flags().StringVar(&serveSchema, "schema", "", "Serve the schema in this SDL file with generated stub data instead of the built-in schema")
+	serveCmd.Flags().StringVar(&serveSeed, "seed", "", "Load the built-in schema's initial data from this JSON file (type name to array of objects)")
+	serveCmd.Flags().DurationVar(&serveLatency, "inject-latency", 0, "Delay every response by this duration")
+	serveCmd.Flags().Float64Var(&serveErrorRate, "inject-error-rate", 0, "Fraction of requests (0 to 1) to answer with a GraphQL error")
 }
 
 func serve(cmd *cobra.Command, args []string) error {
+	mux := http.NewServeMux()
+
+	// Setup HTTP handlers
+	graphqlHandler, err := newGraphQLHandler()
+	if err != nil {
+		return err
+	}
+	graphqlHandler, err = injectFaults(graphqlHandler, serveLatency, serveErrorRate)
+	if err != nil {
+		return err
+	}
+	mux.Handle("/graphql", graphqlHandler)
+
+	// Format display address for logging
+	displayAddr := serveListen
+	if displayAddr[0] == ':' {
+		displayAddr = "localhost" + displayAddr
+	}
+
+	if servePlayground {
+		mux.Handle("/", playground.Handler("GraphQL Playground", "/graphql"))
+		log.Printf("GraphQL Playground available at http://%s/", displayAddr)
+	}
+
+	log.Printf("GraphQL endpoint: http://%s/graphql", displayAddr)
+	log.Printf("Starting mock GraphQL server on %s...", serveListen)
+
+	// Start server
+	if err := http.ListenAndServe(serveListen, mux); err != nil {
+		return fmt.Errorf("failed to start server: %w", err)
+	}
+
+	return nil
+}
+
+// newGraphQLHandler creates the handler for the GraphQL endpoint: the built-in
+// mock schema (with the data given with --seed, if any), or stubs for the schema
+// given with --schema
+func newGraphQLHandler() (http.Handler, error) {
+	if serveSchema != "" && serveSeed != "" {
+		return nil, fmt.Errorf("--seed can't be used with --schema")
+	}
+	if serveSchema != "" {
+		sdl, err := os.ReadFile(serveSchema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema: %w", err)
+		}
+		resolver, err := sdlmock.NewResolver(string(sdl))
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Serving stub data for %s", serveSchema)
+		return resolver, nil
+	}
+
+	resolver := graph.NewResolver()
+	if serveSeed != "" {
+		data, err := graph.LoadSeedFile(serveSeed)
+		if err != nil {
+			return nil, err
+		}
+		store, err := graph.NewSeededStore(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid seed data: %w", err)
+		}
+		resolver = graph.NewResolverWithStore(store)
+		log.Printf("Loaded seed data from %s", serveSeed)
+	}
+
 	// Create GraphQL server
-	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: graph.NewResolver()}))
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: resolver}))
 
 	// Add transports for subscriptions and queries
 	srv.AddTransport(transport.SSE{})
@@ -96,27 +199,46 @@ func serve(cmd *cobra.Command, args []string) error {
 		Cache: lru.New[string](100),
 	})
 
-	// Setup HTTP handlers
-	http.Handle("/graphql", srv)
+	return srv, nil
+}
 
-	// Format display address for logging
-	displayAddr := serveListen
-	if displayAddr[0] == ':' {
-		displayAddr = "localhost" + displayAddr
+// injectFaults wraps a GraphQL handler to delay each response by latency and
+// answer a fraction errorRate of the requests with a GraphQL error. WebSocket
+// upgrades are delayed but never failed, since the error couldn't be delivered
+// over the subscription protocol.
+func injectFaults(next http.Handler, latency time.Duration, errorRate float64) (http.Handler, error) {
+	if latency < 0 {
+		return nil, fmt.Errorf("--inject-latency can't be negative")
 	}
-
-	if servePlayground {
-		http.Handle("/", playground.Handler("GraphQL Playground", "/graphql"))
-		log.Printf("GraphQL Playground available at http://%s/", displayAddr)
+	if errorRate < 0 || errorRate > 1 {
+		return nil, fmt.Errorf("--inject-error-rate must be between 0 and 1")
 	}
-
-	log.Printf("GraphQL endpoint: http://%s/graphql", displayAddr)
-	log.Printf("Starting mock GraphQL server on %s...", serveListen)
-
-	// Start server
-	if err := http.ListenAndServe(serveListen, nil); err != nil {
-		return fmt.Errorf("failed to start server: %w", err)
+	if latency == 0 && errorRate == 0 {
+		return next, nil
 	}
-
-	return nil
+	log.Printf("Injecting %s latency and a %.0f%% error rate", latency, errorRate*100)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if latency > 0 {
+			select {
+			case <-time.After(latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		if errorRate > 0 && r.Header.Get("Upgrade") == "" && rand.Float64() < errorRate {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": nil,
+				"errors": []map[string]interface{}{{
+					"message":    "injected error",
+					"extensions": map[string]interface{}{"code": "INJECTED_ERROR"},
+				}},
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}), nil
 }
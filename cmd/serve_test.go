@@ -1,7 +1,16 @@
 package main
 
 import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestServeCommand(t *testing.T) {
@@ -30,3 +39,185 @@ func TestServeCommand(t *testing.T) {
 	}
 }
 
+
+func TestServeCommandSchemaFlag(t *testing.T) {
+	if serveCmd.Flag("schema") == nil {
+		t.Fatalf("Expected serve command to have 'schema' flag")
+	}
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	schemaPath := filepath.Join(t.TempDir(), "schema.graphqls")
+	sdl := `type Query {
+  product(id: ID!): Product
+}
+
+type Product {
+  id: ID!
+  name: String!
+  price: Float!
+}`
+	if err := os.WriteFile(schemaPath, []byte(sdl), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	serveSchema = schemaPath
+	defer func() { serveSchema = "" }()
+
+	handler, err := newGraphQLHandler()
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	data := executeHTTPQuery(t, server.URL, `{ product(id: "1") { id name price } }`, nil)
+	product, ok := data["product"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected a product, got %v", data)
+	}
+	if _, ok := product["name"].(string); !ok {
+		t.Errorf("Expected a string name, got %v", product["name"])
+	}
+	if _, ok := product["price"].(float64); !ok {
+		t.Errorf("Expected a number price, got %v", product["price"])
+	}
+
+	serveSchema = filepath.Join(t.TempDir(), "missing.graphqls")
+	if _, err := newGraphQLHandler(); err == nil {
+		t.Error("Expected an error for a missing schema file")
+	}
+}
+
+func TestServeCommandSeedFlag(t *testing.T) {
+	if serveCmd.Flag("seed") == nil {
+		t.Fatalf("Expected serve command to have 'seed' flag")
+	}
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	seedPath := filepath.Join(t.TempDir(), "seed.json")
+	seed := `{
+  "User": [
+    {"name": "Dana Dev", "email": "dana@example.com", "role": "ADMIN"},
+    {"name": "Eve Editor", "email": "eve@example.com", "role": "GUEST"}
+  ]
+}`
+	if err := os.WriteFile(seedPath, []byte(seed), 0644); err != nil {
+		t.Fatalf("Failed to write seed file: %v", err)
+	}
+
+	serveSeed = seedPath
+	defer func() { serveSeed = "" }()
+
+	handler, err := newGraphQLHandler()
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	data := executeHTTPQuery(t, server.URL, `{ users { id name email role } }`, nil)
+	users, ok := data["users"].([]interface{})
+	if !ok || len(users) != 2 {
+		t.Fatalf("Expected the 2 seeded users, got %v", data)
+	}
+	emails := map[string]bool{}
+	for _, u := range users {
+		emails[u.(map[string]interface{})["email"].(string)] = true
+	}
+	if !emails["dana@example.com"] || !emails["eve@example.com"] {
+		t.Errorf("Expected the seeded users, got %v", users)
+	}
+
+	if err := os.WriteFile(seedPath, []byte(`{"User": [{"nickname": "dd"}]}`), 0644); err != nil {
+		t.Fatalf("Failed to write seed file: %v", err)
+	}
+	if _, err := newGraphQLHandler(); err == nil || !strings.Contains(err.Error(), "no field 'nickname'") {
+		t.Errorf("Expected an unknown field error, got %v", err)
+	}
+
+	serveSchema = "schema.graphqls"
+	defer func() { serveSchema = "" }()
+	if _, err := newGraphQLHandler(); err == nil {
+		t.Error("Expected an error when combining --seed and --schema")
+	}
+}
+
+func TestInjectFaults(t *testing.T) {
+	for _, name := range []string{"inject-latency", "inject-error-rate"} {
+		if serveCmd.Flag(name) == nil {
+			t.Fatalf("Expected serve command to have '%s' flag", name)
+		}
+	}
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"hello":"world"}}`))
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		handler, err := injectFaults(ok, 50*time.Millisecond, 0)
+		if err != nil {
+			t.Fatalf("injectFaults failed: %v", err)
+		}
+		start := time.Now()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":"{ hello }"}`)))
+		if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+			t.Errorf("Expected at least 50ms of latency, got %s", elapsed)
+		}
+		if !strings.Contains(rec.Body.String(), "world") {
+			t.Errorf("Expected the wrapped response, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("error rate", func(t *testing.T) {
+		handler, err := injectFaults(ok, 0, 0.25)
+		if err != nil {
+			t.Fatalf("injectFaults failed: %v", err)
+		}
+		const requests = 2000
+		failed := 0
+		for i := 0; i < requests; i++ {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":"{ hello }"}`)))
+			var response map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response["errors"] != nil {
+				failed++
+			}
+		}
+		if rate := float64(failed) / requests; rate < 0.2 || rate > 0.3 {
+			t.Errorf("Expected an error rate of about 0.25, got %.3f", rate)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		handler, err := injectFaults(ok, 0, 0)
+		if err != nil {
+			t.Fatalf("injectFaults failed: %v", err)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", nil))
+		if strings.Contains(rec.Body.String(), "errors") {
+			t.Errorf("Expected no injected errors, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := injectFaults(ok, -time.Second, 0); err == nil {
+			t.Error("Expected an error for negative latency")
+		}
+		if _, err := injectFaults(ok, 0, 1.5); err == nil {
+			t.Error("Expected an error for an error rate above 1")
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kluzzebass/gqlt"
+	"github.com/spf13/cobra"
+)
+
+// RunSummary counts the outcomes of the operations of a batch run (a script or
+// --watch), so they can be reported once the run is over
+type RunSummary struct {
+	Total         int            `json:"total"`
+	Succeeded     int            `json:"succeeded"`
+	GraphQLErrors int            `json:"graphqlErrors"`
+	NetworkErrors int            `json:"networkErrors"`
+	OtherErrors   int            `json:"otherErrors,omitempty"`
+	ErrorCodes    map[string]int `json:"errorCodes,omitempty"`
+}
+
+// Add records the outcome of one operation: its response, or the error that
+// kept it from getting one. GraphQL errors are grouped by their
+// extensions.code, or under GRAPHQL_ERRORS if they have none; other errors by
+// the error code the CLI reports for them.
+func (s *RunSummary) Add(response *gqlt.Response, err error) {
+	if s == nil {
+		return
+	}
+	s.Total++
+
+	switch {
+	case err != nil:
+		if exitCodeForError(err) == exitCodeNetwork {
+			s.NetworkErrors++
+		} else {
+			s.OtherErrors++
+		}
+		s.countCode(errorCodeForError(err))
+	case len(response.Errors) > 0:
+		s.GraphQLErrors++
+		codes := response.ErrorCodes()
+		if len(codes) == 0 {
+			codes = []string{gqlt.ErrorCodeGraphQLErrors}
+		}
+		for _, code := range codes {
+			s.countCode(code)
+		}
+	default:
+		s.Succeeded++
+	}
+}
+
+// countCode counts one failed operation under an error code
+func (s *RunSummary) countCode(code string) {
+	if s.ErrorCodes == nil {
+		s.ErrorCodes = map[string]int{}
+	}
+	s.ErrorCodes[code]++
+}
+
+// String describes the summary in one line, for table output
+func (s *RunSummary) String() string {
+	summary := fmt.Sprintf("%d operations: %d succeeded, %d with GraphQL errors, %d network errors",
+		s.Total, s.Succeeded, s.GraphQLErrors, s.NetworkErrors)
+	if s.OtherErrors > 0 {
+		summary += fmt.Sprintf(", %d other errors", s.OtherErrors)
+	}
+	if len(s.ErrorCodes) > 0 {
+		codes := make([]string, 0, len(s.ErrorCodes))
+		for code, count := range s.ErrorCodes {
+			codes = append(codes, fmt.Sprintf("%s: %d", code, count))
+		}
+		sort.Strings(codes)
+		summary += " (" + strings.Join(codes, ", ") + ")"
+	}
+	return summary
+}
+
+// printRunSummary writes the summary of a batch run to stderr with the output
+// formatter, keeping stdout for the responses. Quiet mode leaves it out.
+func printRunSummary(cmd *cobra.Command, summary *RunSummary) {
+	if quietMode {
+		return
+	}
+	formatter := gqlt.NewFormatter(outputFormat)
+	formatter.SetOutput(cmd.ErrOrStderr())
+	formatter.SetErrorOutput(cmd.ErrOrStderr())
+	setCompact(cmd, formatter, cmd.ErrOrStderr())
+	formatter.FormatStructured(summary, false)
+}
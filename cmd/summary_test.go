@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/kluzzebass/gqlt"
+)
+
+func TestRunSummary(t *testing.T) {
+	summary := &RunSummary{}
+	summary.Add(&gqlt.Response{Data: map[string]interface{}{"ok": true}}, nil)
+	summary.Add(&gqlt.Response{Data: map[string]interface{}{"ok": true}}, nil)
+	summary.Add(&gqlt.Response{Errors: []interface{}{
+		map[string]interface{}{"message": "denied", "extensions": map[string]interface{}{"code": "FORBIDDEN"}},
+	}}, nil)
+	summary.Add(&gqlt.Response{Errors: []interface{}{
+		map[string]interface{}{"message": "denied", "extensions": map[string]interface{}{"code": "FORBIDDEN"}},
+		map[string]interface{}{"message": "bad input", "extensions": map[string]interface{}{"code": "BAD_USER_INPUT"}},
+	}}, nil)
+	summary.Add(&gqlt.Response{Errors: []interface{}{map[string]interface{}{"message": "boom"}}}, nil)
+	summary.Add(nil, fmt.Errorf("failed to execute GraphQL operation: %w", context.DeadlineExceeded))
+	summary.Add(nil, errors.New("step \"login\": unknown reference"))
+
+	expected := &RunSummary{
+		Total:         7,
+		Succeeded:     2,
+		GraphQLErrors: 3,
+		NetworkErrors: 1,
+		OtherErrors:   1,
+		ErrorCodes: map[string]int{
+			"FORBIDDEN":                    2,
+			"BAD_USER_INPUT":               1,
+			gqlt.ErrorCodeGraphQLErrors:    1,
+			gqlt.ErrorCodeNetworkError:     1,
+			gqlt.ErrorCodeGraphQLExecution: 1,
+		},
+	}
+	if !reflect.DeepEqual(summary, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, summary)
+	}
+
+	line := "7 operations: 2 succeeded, 3 with GraphQL errors, 1 network errors, 1 other errors " +
+		"(BAD_USER_INPUT: 1, FORBIDDEN: 2, GRAPHQL_ERRORS: 1, GRAPHQL_EXECUTION_ERROR: 1, NETWORK_ERROR: 1)"
+	if summary.String() != line {
+		t.Errorf("Expected %q, got %q", line, summary.String())
+	}
+
+	// A nil summary ignores outcomes
+	var none *RunSummary
+	none.Add(&gqlt.Response{}, nil)
+}
+
+func TestPrintRunSummary(t *testing.T) {
+	summary := &RunSummary{}
+	summary.Add(&gqlt.Response{Data: map[string]interface{}{"ok": true}}, nil)
+
+	var stdout, stderr bytes.Buffer
+	cmd := createFullTestCommand()
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	printRunSummary(cmd, summary)
+
+	if stdout.Len() != 0 {
+		t.Errorf("Expected nothing on stdout, got %q", stdout.String())
+	}
+	var output struct {
+		Success bool       `json:"success"`
+		Data    RunSummary `json:"data"`
+	}
+	if err := json.Unmarshal(stderr.Bytes(), &output); err != nil {
+		t.Fatalf("Expected a JSON summary on stderr, got %q: %v", stderr.String(), err)
+	}
+	if output.Data.Total != 1 || output.Data.Succeeded != 1 {
+		t.Errorf("Expected 1 successful operation, got %+v", output.Data)
+	}
+}
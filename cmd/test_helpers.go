@@ -18,6 +18,7 @@ func createTestCommand() *cobra.Command {
 	cmd.PersistentFlags().String("use-config", "", "use specific configuration by name (overrides current selection)")
 	cmd.PersistentFlags().String("format", "json", "Output format: json|table|yaml (default: json)")
 	cmd.PersistentFlags().Bool("quiet", false, "Quiet mode - suppress non-essential output for automation")
+	cmd.PersistentFlags().Bool("compact", false, "Print JSON on a single line (default: indented on a terminal, compact otherwise)")
 	return cmd
 }
 
@@ -29,6 +30,7 @@ func createFullTestCommand() *cobra.Command {
 	cmd.PersistentFlags().String("use-config", "", "use specific configuration by name (overrides current selection)")
 	cmd.PersistentFlags().String("format", "json", "Output format: json|table|yaml (default: json)")
 	cmd.PersistentFlags().Bool("quiet", false, "Quiet mode - suppress non-essential output for automation")
+	cmd.PersistentFlags().Bool("compact", false, "Print JSON on a single line (default: indented on a terminal, compact otherwise)")
 
 	cmd.AddCommand(runCmd)
 	cmd.AddCommand(configCmd)
@@ -37,6 +39,10 @@ func createFullTestCommand() *cobra.Command {
 	cmd.AddCommand(validateCmd)
 	cmd.AddCommand(docsCmd)
 	cmd.AddCommand(versionCmd)
+	cmd.AddCommand(schemaCmd)
+	cmd.AddCommand(formatCmd)
+	cmd.AddCommand(lintCmd)
+	cmd.AddCommand(codegenCmd)
 	return cmd
 }
 
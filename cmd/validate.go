@@ -45,6 +45,8 @@ var validateQueryCmd = &cobra.Command{
 Returns structured validation results including syntax errors, type errors, and field availability.`,
 	Example: `gqlt validate query --query "{ users { id name } }" --url https://api.example.com/graphql
 gqlt validate query --query-file query.graphql --url https://api.example.com/graphql
+gqlt validate query --query-file query.graphql --schema-file schema.json
+gqlt validate query --query-file query.graphql --schema-file schema.json --max-complexity 1000
 gqlt validate query --query "{ users { id } }" --format json --quiet`,
 	Args: cobra.NoArgs,
 	RunE: validateQuery,
@@ -55,6 +57,10 @@ func init() {
 	validateQueryCmd.Flags().StringP("query", "q", "", "GraphQL query to validate")
 	validateQueryCmd.Flags().StringP("query-file", "Q", "", "Path to GraphQL query file")
 	validateQueryCmd.Flags().StringP("url", "u", "", "GraphQL endpoint URL")
+	validateQueryCmd.Flags().String("schema-file", "", "Validate against a saved schema file (JSON or SDL) instead of introspecting; takes precedence over --url")
+	validateQueryCmd.Flags().Int("max-complexity", 0, "Fail if the estimated query complexity exceeds this value (0 disables the check)")
+	validateQueryCmd.Flags().Int("field-weight", gqlt.DefaultComplexityOptions().FieldWeight, "Complexity cost of each selected field")
+	validateQueryCmd.Flags().Int("nesting-multiplier", gqlt.DefaultComplexityOptions().NestingMultiplier, "Complexity multiplier for list fields without a first/last/limit argument")
 }
 
 var validateConfigCmd = &cobra.Command{
@@ -72,8 +78,10 @@ var validateSchemaCmd = &cobra.Command{
 	Use:   "schema",
 	Short: "Validate a GraphQL schema",
 	Long: `Validate a GraphQL schema for correctness and completeness.
-Returns structured validation results with schema analysis.`,
+Returns structured validation results with schema analysis.
+If the endpoint has introspection disabled, the schema given with --schema-file is validated instead.`,
 	Example: `gqlt validate schema --url https://api.example.com/graphql
+gqlt validate schema --url https://api.example.com/graphql --schema-file schema.json
 gqlt validate schema --url https://api.example.com/graphql --format json --quiet`,
 	Args: cobra.NoArgs,
 	RunE: validateSchema,
@@ -86,6 +94,54 @@ func init() {
 
 	// Add flags to schema validation command
 	validateSchemaCmd.Flags().StringP("url", "u", "", "GraphQL endpoint URL")
+	validateSchemaCmd.Flags().String("schema-file", "", "Schema file (JSON or SDL) to validate instead if the endpoint has introspection disabled")
+}
+
+// introspectionFallback handles an introspection result without a schema from an
+// endpoint that has introspection disabled: the schema is loaded from schemaFile
+// if one was given, and otherwise a clear error is returned. Other results are
+// returned as they are, reporting whether the schema came from the file.
+func introspectionFallback(cmd *cobra.Command, client *gqlt.Client, schema *gqlt.Response, endpointURL, schemaFile string, formatter gqlt.Formatter, quietMode bool) (*gqlt.Response, bool, error) {
+	if schema != nil {
+		if data, ok := schema.Data.(map[string]interface{}); ok && data["__schema"] != nil {
+			return schema, false, nil
+		}
+	}
+	if supported, err := client.SupportsIntrospection(); err != nil || supported {
+		return schema, false, nil
+	}
+
+	if schemaFile == "" {
+		err := fmt.Errorf("introspection is disabled on %s; pass --schema-file with a saved schema instead", endpointURL)
+		formatter.FormatStructuredErrorWithContext(
+			err,
+			gqlt.ErrorCodeSchemaIntrospect,
+			"introspection_disabled",
+			map[string]interface{}{
+				"endpoint": endpointURL,
+			},
+			quietMode,
+		)
+		return nil, false, err
+	}
+
+	if !quietMode {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Introspection is disabled on %s; using the schema file %s\n", endpointURL, schemaFile)
+	}
+	schema, err := gqlt.LoadSchemaFromFile(schemaFile)
+	if err != nil {
+		formatter.FormatStructuredErrorWithContext(
+			err,
+			gqlt.ErrorCodeSchemaLoad,
+			"schema_load_error",
+			map[string]interface{}{
+				"schema_file": schemaFile,
+			},
+			quietMode,
+		)
+		return nil, false, err
+	}
+	return schema, true, nil
 }
 
 func validateQuery(cmd *cobra.Command, args []string) error {
@@ -100,6 +156,10 @@ func validateQuery(cmd *cobra.Command, args []string) error {
 	query := cmd.Flag("query").Value.String()
 	queryFile := cmd.Flag("query-file").Value.String()
 	endpointURL := cmd.Flag("url").Value.String()
+	schemaFile := cmd.Flag("schema-file").Value.String()
+	maxComplexity, _ := cmd.Flags().GetInt("max-complexity")
+	fieldWeight, _ := cmd.Flags().GetInt("field-weight")
+	nestingMultiplier, _ := cmd.Flags().GetInt("nesting-multiplier")
 
 	// Load query
 	inputHandler := gqlt.NewInput()
@@ -122,97 +182,187 @@ func validateQuery(cmd *cobra.Command, args []string) error {
 		)
 	}
 
-	// Load configuration if URL not provided
-	if endpointURL == "" {
-		cfg, err := gqlt.Load(configDir)
+	// Load the schema from a file if given, otherwise introspect the endpoint
+	var schema *gqlt.Response
+	if schemaFile != "" {
+		schema, err = gqlt.LoadSchemaFromFile(schemaFile)
 		if err != nil {
-			return formatter.FormatStructuredError(err, gqlt.ErrorCodeConfigLoad, quietMode)
+			return formatter.FormatStructuredErrorWithContext(
+				err,
+				gqlt.ErrorCodeSchemaLoad,
+				"schema_load_error",
+				map[string]interface{}{
+					"schema_file": schemaFile,
+				},
+				quietMode,
+			)
+		}
+	} else {
+		// Load configuration if URL not provided
+		if endpointURL == "" {
+			cfg, err := gqlt.Load(configDir)
+			if err != nil {
+				return formatter.FormatStructuredError(err, gqlt.ErrorCodeConfigLoad, quietMode)
+			}
+
+			_, current, err := cfg.ResolveActive(configName)
+			if err != nil {
+				formatter.FormatStructuredError(err, gqlt.ErrorCodeConfigLoad, quietMode)
+				return err
+			}
+			if current.Endpoint == "" {
+				return formatter.FormatStructuredError(
+					fmt.Errorf("no URL provided and no endpoint configured"),
+					gqlt.ErrorCodeInputValidation,
+					quietMode,
+				)
+			}
+			endpointURL = current.Endpoint
 		}
 
-		current := cfg.GetCurrent()
-		if current.Endpoint == "" {
-			return formatter.FormatStructuredError(
-				fmt.Errorf("no URL provided and no endpoint configured"),
-				gqlt.ErrorCodeInputValidation,
+		// Create client and introspect schema
+		client := gqlt.NewClient(endpointURL, nil)
+		introspectClient := gqlt.NewIntrospect(client)
+
+		schema, err = introspectClient.IntrospectSchema()
+		if err != nil {
+			return formatter.FormatStructuredErrorWithContext(
+				err,
+				gqlt.ErrorCodeSchemaIntrospect,
+				"schema_introspection_error",
+				map[string]interface{}{
+					"endpoint": endpointURL,
+				},
 				quietMode,
 			)
 		}
-		endpointURL = current.Endpoint
-	}
+		schema, _, err = introspectionFallback(cmd, client, schema, endpointURL, "", formatter, quietMode)
+		if err != nil {
+			return err
+		}
 
-	// Create client and introspect schema
-	client := gqlt.NewClient(endpointURL, nil)
-	introspectClient := gqlt.NewIntrospect(client)
+		// Check if the introspection returned a valid schema
+		if schema == nil || schema.Data == nil {
+			formatter.FormatStructuredErrorWithContext(
+				fmt.Errorf("no schema data returned from introspection"),
+				gqlt.ErrorCodeSchemaIntrospect,
+				"invalid_schema_response",
+				map[string]interface{}{
+					"endpoint": endpointURL,
+				},
+				quietMode,
+			)
+			return fmt.Errorf("no schema data returned from introspection")
+		}
 
-	schema, err := introspectClient.IntrospectSchema()
+		// Check if the schema contains the expected GraphQL introspection structure
+		schemaData, ok := schema.Data.(map[string]interface{})
+		if !ok {
+			formatter.FormatStructuredErrorWithContext(
+				fmt.Errorf("invalid schema data format"),
+				gqlt.ErrorCodeSchemaIntrospect,
+				"invalid_schema_format",
+				map[string]interface{}{
+					"endpoint": endpointURL,
+				},
+				quietMode,
+			)
+			return fmt.Errorf("invalid schema data format")
+		}
+
+		// Check if the schema contains the expected GraphQL introspection fields
+		if _, hasSchema := schemaData["__schema"]; !hasSchema {
+			formatter.FormatStructuredErrorWithContext(
+				fmt.Errorf("endpoint does not appear to be a GraphQL endpoint"),
+				gqlt.ErrorCodeSchemaIntrospect,
+				"not_graphql_endpoint",
+				map[string]interface{}{
+					"endpoint": endpointURL,
+				},
+				quietMode,
+			)
+			return fmt.Errorf("endpoint does not appear to be a GraphQL endpoint")
+		}
+	}
+
+	// Validate the query against the schema
+	analyzer, err := gqlt.NewAnalyzer(schema)
 	if err != nil {
 		return formatter.FormatStructuredErrorWithContext(
 			err,
-			gqlt.ErrorCodeSchemaIntrospect,
-			"schema_introspection_error",
+			gqlt.ErrorCodeSchemaLoad,
+			"schema_analysis_error",
 			map[string]interface{}{
 				"endpoint": endpointURL,
 			},
 			quietMode,
 		)
 	}
-
-	// Check if the introspection returned a valid schema
-	if schema == nil || schema.Data == nil {
-		formatter.FormatStructuredErrorWithContext(
-			fmt.Errorf("no schema data returned from introspection"),
-			gqlt.ErrorCodeSchemaIntrospect,
-			"invalid_schema_response",
+	validationErrors, err := analyzer.ValidateQuery(queryStr)
+	if err != nil {
+		return formatter.FormatStructuredErrorWithContext(
+			err,
+			gqlt.ErrorCodeSchemaLoad,
+			"schema_analysis_error",
 			map[string]interface{}{
 				"endpoint": endpointURL,
 			},
 			quietMode,
 		)
-		return fmt.Errorf("no schema data returned from introspection")
 	}
-
-	// Check if the schema contains the expected GraphQL introspection structure
-	schemaData, ok := schema.Data.(map[string]interface{})
-	if !ok {
+	if len(validationErrors) > 0 {
+		validationErr := fmt.Errorf("query failed validation with %d error(s): %s", len(validationErrors), validationErrors[0].Message)
 		formatter.FormatStructuredErrorWithContext(
-			fmt.Errorf("invalid schema data format"),
-			gqlt.ErrorCodeSchemaIntrospect,
-			"invalid_schema_format",
+			validationErr,
+			gqlt.ErrorCodeQueryValidation,
+			"query_validation_error",
 			map[string]interface{}{
 				"endpoint": endpointURL,
+				"errors":   validationErrors,
 			},
 			quietMode,
 		)
-		return fmt.Errorf("invalid schema data format")
+		return validationErr
 	}
 
-	// Check if the schema contains the expected GraphQL introspection fields
-	if _, hasSchema := schemaData["__schema"]; !hasSchema {
+	// Estimate the query complexity
+	complexity, err := analyzer.EstimateComplexityWithOptions(queryStr, gqlt.ComplexityOptions{
+		FieldWeight:       fieldWeight,
+		NestingMultiplier: nestingMultiplier,
+	})
+	if err != nil {
+		return formatter.FormatStructuredError(err, gqlt.ErrorCodeInputValidation, quietMode)
+	}
+	if maxComplexity > 0 && complexity > maxComplexity {
+		complexityErr := fmt.Errorf("query complexity %d exceeds maximum of %d", complexity, maxComplexity)
 		formatter.FormatStructuredErrorWithContext(
-			fmt.Errorf("endpoint does not appear to be a GraphQL endpoint"),
-			gqlt.ErrorCodeSchemaIntrospect,
-			"not_graphql_endpoint",
+			complexityErr,
+			gqlt.ErrorCodeQueryComplexity,
+			"query_complexity_error",
 			map[string]interface{}{
-				"endpoint": endpointURL,
+				"complexity":     complexity,
+				"max_complexity": maxComplexity,
 			},
 			quietMode,
 		)
-		return fmt.Errorf("endpoint does not appear to be a GraphQL endpoint")
+		return complexityErr
 	}
 
-	// Basic query validation (syntax check)
 	validationResult := map[string]interface{}{
-		"valid":    true,
-		"query":    queryStr,
-		"endpoint": endpointURL,
+		"valid":      true,
+		"query":      queryStr,
+		"complexity": complexity,
 		"checks": map[string]interface{}{
 			"syntax":           "valid",
 			"schema_available": true,
+			"schema":           "valid",
 		},
 	}
-
-	// TODO: Add actual GraphQL query validation against schema
-	// This would require a GraphQL query parser and validator
+	if schemaFile != "" {
+		validationResult["schema_file"] = schemaFile
+	} else {
+		validationResult["endpoint"] = endpointURL
+	}
 
 	return formatter.FormatStructured(validationResult, quietMode)
 }
@@ -285,6 +435,7 @@ func validateSchema(cmd *cobra.Command, args []string) error {
 	outputFormat := cmd.Root().Flag("format").Value.String()
 	quietMode := cmd.Root().Flag("quiet").Value.String() == "true"
 	endpointURL := cmd.Flag("url").Value.String()
+	schemaFile := cmd.Flag("schema-file").Value.String()
 
 	formatter := gqlt.NewFormatter(outputFormat)
 
@@ -295,7 +446,11 @@ func validateSchema(cmd *cobra.Command, args []string) error {
 			return formatter.FormatStructuredError(err, gqlt.ErrorCodeConfigLoad, quietMode)
 		}
 
-		current := cfg.GetCurrent()
+		_, current, err := cfg.ResolveActive(configName)
+		if err != nil {
+			formatter.FormatStructuredError(err, gqlt.ErrorCodeConfigLoad, quietMode)
+			return err
+		}
 		if current.Endpoint == "" {
 			return formatter.FormatStructuredError(
 				fmt.Errorf("no URL provided and no endpoint configured"),
@@ -322,6 +477,10 @@ func validateSchema(cmd *cobra.Command, args []string) error {
 			quietMode,
 		)
 	}
+	schema, fromFile, err := introspectionFallback(cmd, client, schema, endpointURL, schemaFile, formatter, quietMode)
+	if err != nil {
+		return err
+	}
 
 	// Check if the introspection returned a valid schema
 	if schema == nil || schema.Data == nil {
@@ -402,12 +561,15 @@ func validateSchema(cmd *cobra.Command, args []string) error {
 			"subscription_type": summary.SubscriptionType,
 		},
 		"checks": map[string]interface{}{
-			"introspection_successful": true,
+			"introspection_successful": !fromFile,
 			"schema_has_queries":       summary.QueryType != "",
 			"schema_has_mutations":     summary.MutationType != "",
 			"schema_has_subscriptions": summary.SubscriptionType != "",
 		},
 	}
+	if fromFile {
+		validationResult["schema_file"] = schemaFile
+	}
 
 	return formatter.FormatStructured(validationResult, quietMode)
 }
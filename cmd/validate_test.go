@@ -1,8 +1,15 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/kluzzebass/gqlt"
 	"github.com/spf13/cobra"
 )
 
@@ -166,3 +173,176 @@ func TestValidateCommandFlags(t *testing.T) {
 		t.Errorf("Expected validate schema command to have flag 'url'")
 	}
 }
+
+const validateTestSDL = `
+type Query {
+  user(id: ID!): User
+}
+
+type User {
+  id: ID!
+  name: String!
+}
+`
+
+// newIntrospectionServer serves the introspection result for the given SDL
+func newIntrospectionServer(t *testing.T, sdl string) *httptest.Server {
+	t.Helper()
+	introspection, err := gqlt.SDLToIntrospection(sdl)
+	if err != nil {
+		t.Fatalf("SDLToIntrospection failed: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": introspection})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestValidateQueryAgainstSchema(t *testing.T) {
+	server := newIntrospectionServer(t, validateTestSDL)
+
+	// The help flag persists on validateQueryCmd across tests
+	validateQueryCmd.Flags().Set("help", "false")
+
+	tests := []struct {
+		name    string
+		query   string
+		wantErr bool
+	}{
+		{
+			name:  "valid query",
+			query: `{ user(id: "1") { id name } }`,
+		},
+		{
+			name:    "unknown field",
+			query:   `{ user(id: "1") { id email } }`,
+			wantErr: true,
+		},
+		{
+			name:    "missing required argument",
+			query:   `{ user { id } }`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := createFullTestCommand()
+			_, err := executeCommandWithOutput(cmd, []string{"validate", "query", "--url", server.URL, "--query", tt.query})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate query error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateQueryWithSchemaFile(t *testing.T) {
+	introspection, err := gqlt.SDLToIntrospection(validateTestSDL)
+	if err != nil {
+		t.Fatalf("SDLToIntrospection failed: %v", err)
+	}
+	schemaPath := filepath.Join(t.TempDir(), "schema.json")
+	if err := gqlt.SaveSchema(&gqlt.Response{Data: introspection}, schemaPath); err != nil {
+		t.Fatalf("Failed to save schema: %v", err)
+	}
+
+	// The help flag persists on validateQueryCmd across tests
+	validateQueryCmd.Flags().Set("help", "false")
+	defer validateQueryCmd.Flags().Set("schema-file", "")
+
+	// No server is running at this URL; the schema file takes precedence
+	unreachable := "http://127.0.0.1:1/graphql"
+
+	cmd := createFullTestCommand()
+	if _, err := executeCommandWithOutput(cmd, []string{"validate", "query", "--schema-file", schemaPath, "--url", unreachable, "--query", `{ user(id: "1") { id name } }`}); err != nil {
+		t.Errorf("Expected query to validate against schema file: %v", err)
+	}
+
+	cmd = createFullTestCommand()
+	if _, err := executeCommandWithOutput(cmd, []string{"validate", "query", "--schema-file", schemaPath, "--url", unreachable, "--query", `{ user(id: "1") { email } }`}); err == nil {
+		t.Error("Expected invalid query to fail validation against schema file")
+	}
+}
+
+func TestValidateQueryMaxComplexity(t *testing.T) {
+	introspection, err := gqlt.SDLToIntrospection(validateTestSDL)
+	if err != nil {
+		t.Fatalf("SDLToIntrospection failed: %v", err)
+	}
+	schemaPath := filepath.Join(t.TempDir(), "schema.json")
+	if err := gqlt.SaveSchema(&gqlt.Response{Data: introspection}, schemaPath); err != nil {
+		t.Fatalf("Failed to save schema: %v", err)
+	}
+
+	// The help flag persists on validateQueryCmd across tests
+	validateQueryCmd.Flags().Set("help", "false")
+	defer func() {
+		validateQueryCmd.Flags().Set("schema-file", "")
+		validateQueryCmd.Flags().Set("max-complexity", "0")
+		validateQueryCmd.Flags().Set("field-weight", "1")
+	}()
+
+	// user, id and name cost one each
+	query := `{ user(id: "1") { id name } }`
+
+	cmd := createFullTestCommand()
+	if _, err := executeCommandWithOutput(cmd, []string{"validate", "query", "--schema-file", schemaPath, "--query", query, "--max-complexity", "3"}); err != nil {
+		t.Errorf("Expected query within complexity limit to pass: %v", err)
+	}
+
+	cmd = createFullTestCommand()
+	if _, err := executeCommandWithOutput(cmd, []string{"validate", "query", "--schema-file", schemaPath, "--query", query, "--max-complexity", "3", "--field-weight", "2"}); err == nil {
+		t.Error("Expected query exceeding complexity limit to fail")
+	}
+}
+
+func TestValidateIntrospectionDisabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"errors":[{"message":"GraphQL introspection is not allowed"}]}`))
+	}))
+	defer server.Close()
+
+	introspection, err := gqlt.SDLToIntrospection(validateTestSDL)
+	if err != nil {
+		t.Fatalf("SDLToIntrospection failed: %v", err)
+	}
+	schemaPath := filepath.Join(t.TempDir(), "schema.json")
+	if err := gqlt.SaveSchema(&gqlt.Response{Data: introspection}, schemaPath); err != nil {
+		t.Fatalf("Failed to save schema: %v", err)
+	}
+
+	// The help flag persists on the validate commands across tests
+	validateQueryCmd.Flags().Set("help", "false")
+	validateSchemaCmd.Flags().Set("help", "false")
+	defer validateSchemaCmd.Flags().Set("schema-file", "")
+
+	_, err = executeCommandWithOutput(createFullTestCommand(), []string{"validate", "query", "--url", server.URL, "--query", `{ user(id: "1") { id } }`})
+	if err == nil || !strings.Contains(err.Error(), "introspection is disabled") {
+		t.Errorf("Expected an introspection disabled error from validate query, got %v", err)
+	}
+
+	_, err = executeCommandWithOutput(createFullTestCommand(), []string{"validate", "schema", "--url", server.URL})
+	if err == nil || !strings.Contains(err.Error(), "introspection is disabled") {
+		t.Errorf("Expected an introspection disabled error from validate schema, got %v", err)
+	}
+
+	cmd := createFullTestCommand()
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{"validate", "schema", "--url", server.URL, "--schema-file", schemaPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Expected validate schema to fall back to the schema file: %v", err)
+	}
+	if !strings.Contains(stderr.String(), "Introspection is disabled on "+server.URL) {
+		t.Errorf("Expected a message about the fallback, got %q", stderr.String())
+	}
+}
@@ -0,0 +1,206 @@
+package gqlt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GenerateTypeScript generates TypeScript declarations for the types of a schema:
+// interfaces for object, interface and input object types, string literal unions
+// for enums, union types for unions and aliases for custom scalars. Built-in
+// scalars map to string, number and boolean, and custom scalars to the closest
+// TypeScript type (unknown if there is none). Nullable fields are optional and
+// also accept null, as do input fields with a default value. Introspection types
+// and fields are skipped and declarations are sorted by name, so the output
+// is stable.
+//
+// Example:
+//
+//	ts, err := gqlt.GenerateTypeScript(analyzer)
+//	// export interface User {
+//	//   id: string;
+//	//   name?: string | null;
+//	//   role: Role;
+//	// }
+//	//
+//	// export type Role = "ADMIN" | "USER";
+func GenerateTypeScript(analyzer *Analyzer) (string, error) {
+	types, err := analyzer.typesByName()
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(types))
+	for name := range types {
+		if strings.HasPrefix(name, "__") || isBuiltinScalar(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var ts strings.Builder
+	ts.WriteString("// Code generated by gqlt. DO NOT EDIT.\n")
+	for _, name := range names {
+		typeObj := types[name]
+		kind, _ := typeObj["kind"].(string)
+
+		ts.WriteString("\n")
+		writeTypeScriptDoc(&ts, "", typeObj)
+		switch kind {
+		case "OBJECT", "INTERFACE":
+			writeTypeScriptInterface(&ts, types, name, typeObj["fields"], false)
+		case "INPUT_OBJECT":
+			writeTypeScriptInterface(&ts, types, name, typeObj["inputFields"], true)
+		case "ENUM":
+			var values []string
+			enumValues, _ := typeObj["enumValues"].([]interface{})
+			for _, v := range enumValues {
+				valueObj, _ := v.(map[string]interface{})
+				if valueName, _ := valueObj["name"].(string); valueName != "" {
+					values = append(values, fmt.Sprintf("%q", valueName))
+				}
+			}
+			if len(values) == 0 {
+				values = []string{"never"}
+			}
+			fmt.Fprintf(&ts, "export type %s = %s;\n", name, strings.Join(values, " | "))
+		case "UNION":
+			var members []string
+			possibleTypes, _ := typeObj["possibleTypes"].([]interface{})
+			for _, p := range possibleTypes {
+				typeRef, _ := p.(map[string]interface{})
+				if memberName := namedTypeName(typeRef); memberName != "" {
+					members = append(members, memberName)
+				}
+			}
+			if len(members) == 0 {
+				members = []string{"never"}
+			}
+			fmt.Fprintf(&ts, "export type %s = %s;\n", name, strings.Join(members, " | "))
+		case "SCALAR":
+			fmt.Fprintf(&ts, "export type %s = %s;\n", name, scalarTypeScript(name))
+		default:
+			return "", fmt.Errorf("type '%s' has unknown kind '%s'", name, kind)
+		}
+	}
+
+	return ts.String(), nil
+}
+
+// writeTypeScriptInterface writes an interface with a property per field. Input
+// fields with a default value may be left out, so they are optional too.
+func writeTypeScriptInterface(ts *strings.Builder, types map[string]map[string]interface{}, name string, fields interface{}, input bool) {
+	fmt.Fprintf(ts, "export interface %s {\n", name)
+	list, _ := fields.([]interface{})
+	for _, f := range list {
+		fieldObj, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		fieldName, _ := fieldObj["name"].(string)
+		if fieldName == "" || strings.HasPrefix(fieldName, "__") {
+			continue
+		}
+		typeRef, _ := fieldObj["type"].(map[string]interface{})
+
+		kind, _ := typeRef["kind"].(string)
+		optional := kind != "NON_NULL"
+		if defaultValue, _ := fieldObj["defaultValue"].(string); input && defaultValue != "" {
+			optional = true
+		}
+
+		writeTypeScriptDoc(ts, "  ", fieldObj)
+		marker := ""
+		if optional {
+			marker = "?"
+		}
+		fmt.Fprintf(ts, "  %s%s: %s;\n", fieldName, marker, typeRefTypeScript(types, typeRef))
+	}
+	ts.WriteString("}\n")
+}
+
+// typeRefTypeScript converts a (possibly wrapped) type reference, adding null
+// unless the type is wrapped in NON_NULL
+func typeRefTypeScript(types map[string]map[string]interface{}, typeRef map[string]interface{}) string {
+	kind, _ := typeRef["kind"].(string)
+	ofType, _ := typeRef["ofType"].(map[string]interface{})
+	switch kind {
+	case "NON_NULL":
+		return nonNullTypeScript(types, ofType)
+	default:
+		return nonNullTypeScript(types, typeRef) + " | null"
+	}
+}
+
+// nonNullTypeScript converts a type reference whose value can't be null
+func nonNullTypeScript(types map[string]map[string]interface{}, typeRef map[string]interface{}) string {
+	kind, _ := typeRef["kind"].(string)
+	if kind == "LIST" {
+		ofType, _ := typeRef["ofType"].(map[string]interface{})
+		item := typeRefTypeScript(types, ofType)
+		if strings.Contains(item, " | ") {
+			item = "(" + item + ")"
+		}
+		return item + "[]"
+	}
+
+	name, _ := typeRef["name"].(string)
+	if isBuiltinScalar(name) {
+		return scalarTypeScript(name)
+	}
+	if _, ok := types[name]; !ok {
+		return "unknown"
+	}
+	return name
+}
+
+// scalarTypeScript maps a scalar to the TypeScript type of its JSON values
+func scalarTypeScript(name string) string {
+	switch scalarJSONSchemas[name]["type"] {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	default:
+		return "unknown"
+	}
+}
+
+// isBuiltinScalar reports whether a type is one of the scalars every schema has
+func isBuiltinScalar(name string) bool {
+	switch name {
+	case "Int", "Float", "String", "Boolean", "ID":
+		return true
+	}
+	return false
+}
+
+// writeTypeScriptDoc writes a JSDoc comment with the description and deprecation
+// reason of a type or field, if it has either
+func writeTypeScriptDoc(ts *strings.Builder, indent string, obj map[string]interface{}) {
+	var lines []string
+	if description, _ := obj["description"].(string); description != "" {
+		lines = append(lines, strings.Split(description, "\n")...)
+	}
+	if deprecated, _ := obj["isDeprecated"].(bool); deprecated {
+		reason, _ := obj["deprecationReason"].(string)
+		lines = append(lines, strings.TrimSpace("@deprecated "+reason))
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	if len(lines) == 1 {
+		fmt.Fprintf(ts, "%s/** %s */\n", indent, strings.ReplaceAll(lines[0], "*/", "*\\/"))
+		return
+	}
+	fmt.Fprintf(ts, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(ts, "%s * %s\n", indent, strings.ReplaceAll(line, "*/", "*\\/"))
+	}
+	fmt.Fprintf(ts, "%s */\n", indent)
+}
@@ -0,0 +1,316 @@
+package gqlt
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultGoPackage is the package name used by GenerateGo when none is given
+const DefaultGoPackage = "models"
+
+// goInitialisms are words written in all caps in Go identifiers
+var goInitialisms = map[string]bool{
+	"API": true, "CSS": true, "DNS": true, "HTML": true, "HTTP": true, "HTTPS": true,
+	"ID": true, "IP": true, "JSON": true, "SQL": true, "SSH": true, "TLS": true,
+	"UI": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// GenerateGo generates Go type declarations for the types of a schema, in a file
+// of the given package (DefaultGoPackage if empty). Object and input object types
+// become structs with a json tag per field, and enums become string types with a
+// constant per value. Nullable fields are pointers, lists are slices, and
+// nullable input fields are omitted from JSON when nil. Interfaces and unions
+// can hold several types, so they are decoded generically as maps. Built-in
+// scalars map to string, int, float64 and bool, and custom scalars are aliases
+// of the closest Go type (interface{} if there is none). The output is gofmt'ed.
+//
+// Example:
+//
+//	src, err := gqlt.GenerateGo(analyzer, "models")
+//	// type User struct {
+//	// 	ID   string  `json:"id"`
+//	// 	Name *string `json:"name"`
+//	// 	Role Role    `json:"role"`
+//	// }
+func GenerateGo(analyzer *Analyzer, packageName string) (string, error) {
+	if packageName == "" {
+		packageName = DefaultGoPackage
+	}
+	if !token.IsIdentifier(packageName) || token.IsKeyword(packageName) {
+		return "", fmt.Errorf("invalid Go package name '%s'", packageName)
+	}
+
+	types, err := analyzer.typesByName()
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(types))
+	for name := range types {
+		if strings.HasPrefix(name, "__") || isBuiltinScalar(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	gen := &goGenerator{types: types}
+	var src strings.Builder
+	src.WriteString("// Code generated by gqlt. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n", packageName)
+	for _, name := range names {
+		typeObj := types[name]
+		kind, _ := typeObj["kind"].(string)
+		goType := goName(name)
+
+		src.WriteString("\n")
+		writeGoDoc(&src, "", typeObj)
+		switch kind {
+		case "OBJECT":
+			gen.writeStruct(&src, name, typeObj["fields"], false)
+		case "INPUT_OBJECT":
+			gen.writeStruct(&src, name, typeObj["inputFields"], true)
+		case "ENUM":
+			fmt.Fprintf(&src, "type %s string\n", goType)
+			enumValues, _ := typeObj["enumValues"].([]interface{})
+			if len(enumValues) == 0 {
+				continue
+			}
+			src.WriteString("\nconst (\n")
+			for _, v := range enumValues {
+				valueObj, _ := v.(map[string]interface{})
+				valueName, _ := valueObj["name"].(string)
+				if valueName == "" {
+					continue
+				}
+				writeGoDoc(&src, "\t", valueObj)
+				fmt.Fprintf(&src, "\t%s%s %s = %q\n", goType, goName(strings.ToLower(valueName)), goType, valueName)
+			}
+			src.WriteString(")\n")
+		case "INTERFACE", "UNION":
+			fmt.Fprintf(&src, "type %s = map[string]interface{}\n", goType)
+		case "SCALAR":
+			fmt.Fprintf(&src, "type %s = %s\n", goType, scalarGo(name))
+		default:
+			return "", fmt.Errorf("type '%s' has unknown kind '%s'", name, kind)
+		}
+	}
+
+	formatted, err := format.Source([]byte(src.String()))
+	if err != nil {
+		return "", fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return string(formatted), nil
+}
+
+// goGenerator holds the schema's types while generating Go code
+type goGenerator struct {
+	types map[string]map[string]interface{}
+}
+
+// writeStruct writes a struct with a field per GraphQL field
+func (g *goGenerator) writeStruct(src *strings.Builder, name string, fields interface{}, input bool) {
+	fmt.Fprintf(src, "type %s struct {\n", goName(name))
+	list, _ := fields.([]interface{})
+	for _, f := range list {
+		fieldObj, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		fieldName, _ := fieldObj["name"].(string)
+		if fieldName == "" || strings.HasPrefix(fieldName, "__") {
+			continue
+		}
+		typeRef, _ := fieldObj["type"].(map[string]interface{})
+
+		fieldType := g.typeRef(typeRef)
+		kind, _ := typeRef["kind"].(string)
+		if kind == "NON_NULL" && g.cycles(name, typeRef) {
+			// A struct can't contain itself, so references back to it are pointers
+			fieldType = "*" + fieldType
+		}
+		tag := fieldName
+		if input && kind != "NON_NULL" {
+			tag += ",omitempty"
+		}
+
+		writeGoDoc(src, "\t", fieldObj)
+		fmt.Fprintf(src, "\t%s %s `json:%q`\n", goName(fieldName), fieldType, tag)
+	}
+	src.WriteString("}\n")
+}
+
+// typeRef converts a (possibly wrapped) type reference, using a pointer unless
+// the type is wrapped in NON_NULL or its values can already be nil
+func (g *goGenerator) typeRef(typeRef map[string]interface{}) string {
+	kind, _ := typeRef["kind"].(string)
+	ofType, _ := typeRef["ofType"].(map[string]interface{})
+	if kind == "NON_NULL" {
+		return g.nonNull(ofType)
+	}
+
+	goType := g.nonNull(typeRef)
+	if strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[") || goType == "interface{}" || g.nilable(namedTypeName(typeRef)) {
+		return goType
+	}
+	return "*" + goType
+}
+
+// nonNull converts a type reference whose value can't be null
+func (g *goGenerator) nonNull(typeRef map[string]interface{}) string {
+	kind, _ := typeRef["kind"].(string)
+	if kind == "LIST" {
+		ofType, _ := typeRef["ofType"].(map[string]interface{})
+		return "[]" + g.typeRef(ofType)
+	}
+
+	name, _ := typeRef["name"].(string)
+	if isBuiltinScalar(name) {
+		return scalarGo(name)
+	}
+	if _, ok := g.types[name]; !ok {
+		return "interface{}"
+	}
+	return goName(name)
+}
+
+// nilable reports whether the Go type generated for a named type can be nil
+func (g *goGenerator) nilable(name string) bool {
+	typeObj, ok := g.types[name]
+	if !ok {
+		return true
+	}
+	switch kind, _ := typeObj["kind"].(string); kind {
+	case "INTERFACE", "UNION":
+		return true
+	case "SCALAR":
+		return !isBuiltinScalar(name) && scalarGo(name) == "interface{}"
+	}
+	return false
+}
+
+// cycles reports whether a non-null, non-list field of the struct for typeName
+// leads back to it through other such fields
+func (g *goGenerator) cycles(typeName string, typeRef map[string]interface{}) bool {
+	seen := map[string]bool{}
+	var reaches func(typeRef map[string]interface{}) bool
+	reaches = func(typeRef map[string]interface{}) bool {
+		kind, _ := typeRef["kind"].(string)
+		if kind != "NON_NULL" {
+			return false
+		}
+		ofType, _ := typeRef["ofType"].(map[string]interface{})
+		if kind, _ := ofType["kind"].(string); kind == "LIST" {
+			return false
+		}
+
+		name := namedTypeName(ofType)
+		if name == typeName {
+			return true
+		}
+		if seen[name] {
+			return false
+		}
+		seen[name] = true
+
+		typeObj, ok := g.types[name]
+		if !ok {
+			return false
+		}
+		fields, _ := typeObj["fields"].([]interface{})
+		if inputFields, ok := typeObj["inputFields"].([]interface{}); ok {
+			fields = inputFields
+		}
+		for _, f := range fields {
+			fieldObj, _ := f.(map[string]interface{})
+			fieldType, _ := fieldObj["type"].(map[string]interface{})
+			if reaches(fieldType) {
+				return true
+			}
+		}
+		return false
+	}
+	return reaches(typeRef)
+}
+
+// scalarGo maps a scalar to the Go type of its JSON values
+func scalarGo(name string) string {
+	switch name {
+	case "Int":
+		return "int"
+	case "Float":
+		return "float64"
+	}
+	switch scalarJSONSchemas[name]["type"] {
+	case "string":
+		return "string"
+	case "integer":
+		return "int64"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	default:
+		return "interface{}"
+	}
+}
+
+// goName converts a GraphQL name to an exported Go identifier, e.g. "userId" to
+// "UserID" and "created_at" to "CreatedAt"
+func goName(name string) string {
+	var words []string
+	var word []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		boundary := r == '_' ||
+			(unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))))
+		if boundary && len(word) > 0 {
+			words = append(words, string(word))
+			word = nil
+		}
+		if r != '_' {
+			word = append(word, r)
+		}
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+
+	var sb strings.Builder
+	for _, w := range words {
+		if upper := strings.ToUpper(w); goInitialisms[upper] {
+			sb.WriteString(upper)
+			continue
+		}
+		r := []rune(w)
+		sb.WriteString(string(unicode.ToUpper(r[0])) + string(r[1:]))
+	}
+	if sb.Len() == 0 || !unicode.IsLetter([]rune(sb.String())[0]) {
+		return "X" + sb.String()
+	}
+	return sb.String()
+}
+
+// writeGoDoc writes a doc comment with the description and deprecation reason of
+// a type, field or enum value, if it has either
+func writeGoDoc(src *strings.Builder, indent string, obj map[string]interface{}) {
+	var lines []string
+	if description, _ := obj["description"].(string); description != "" {
+		lines = strings.Split(description, "\n")
+	}
+	if deprecated, _ := obj["isDeprecated"].(bool); deprecated {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		reason, _ := obj["deprecationReason"].(string)
+		lines = append(lines, strings.TrimSpace("Deprecated: "+reason))
+	}
+	for _, line := range lines {
+		fmt.Fprintf(src, "%s// %s\n", indent, line)
+	}
+}
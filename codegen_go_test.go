@@ -0,0 +1,168 @@
+package gqlt
+
+import (
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+const goCodegenTestSDL = `
+	type Query {
+		user(id: ID!): User
+	}
+
+	"A registered user"
+	type User {
+		id: ID!
+		name: String
+		age: Int!
+		score: Float
+		active: Boolean!
+		role: Role!
+		tags: [String]!
+		friends: [User!]
+		avatarUrl: String
+		manager: User!
+		joined: DateTime
+		metadata: JSON
+		lastResult: SearchResult
+		created_at: String @deprecated(reason: "Use joined")
+	}
+
+	type Post {
+		title: String!
+	}
+
+	enum Role {
+		ADMIN
+		SUPER_USER
+	}
+
+	union SearchResult = User | Post
+
+	input CreateUserInput {
+		name: String!
+		role: Role = ADMIN
+		tags: [String!]
+	}
+
+	scalar DateTime
+	scalar JSON
+`
+
+func TestGenerateGo(t *testing.T) {
+	analyzer, err := NewAnalyzerFromSDL(goCodegenTestSDL)
+	if err != nil {
+		t.Fatalf("NewAnalyzerFromSDL failed: %v", err)
+	}
+
+	src, err := GenerateGo(analyzer, "models")
+	if err != nil {
+		t.Fatalf("GenerateGo failed: %v", err)
+	}
+
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		t.Fatalf("Generated code doesn't format: %v\n%s", err, src)
+	}
+	if string(formatted) != src {
+		t.Errorf("Expected generated code to be gofmt'ed, got:\n%s", src)
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), "models.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("Generated code doesn't parse: %v\n%s", err, src)
+	}
+	if file.Name.Name != "models" {
+		t.Errorf("Expected package models, got %s", file.Name.Name)
+	}
+
+	// Collect the fields of every struct as "Name Type Tag"
+	structs := map[string][]string{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		if st, ok := spec.Type.(*ast.StructType); ok {
+			for _, field := range st.Fields.List {
+				var typ strings.Builder
+				format.Node(&typ, token.NewFileSet(), field.Type)
+				structs[spec.Name.Name] = append(structs[spec.Name.Name], field.Names[0].Name+" "+typ.String()+" "+field.Tag.Value)
+			}
+		}
+		return false
+	})
+
+	wantUser := []string{
+		"ID string `json:\"id\"`",
+		"Name *string `json:\"name\"`",
+		"Age int `json:\"age\"`",
+		"Score *float64 `json:\"score\"`",
+		"Active bool `json:\"active\"`",
+		"Role Role `json:\"role\"`",
+		"Tags []*string `json:\"tags\"`",
+		"Friends []User `json:\"friends\"`",
+		"AvatarURL *string `json:\"avatarUrl\"`",
+		"Manager *User `json:\"manager\"`",
+		"Joined *DateTime `json:\"joined\"`",
+		"Metadata JSON `json:\"metadata\"`",
+		"LastResult SearchResult `json:\"lastResult\"`",
+		"CreatedAt *string `json:\"created_at\"`",
+	}
+	if strings.Join(structs["User"], "\n") != strings.Join(wantUser, "\n") {
+		t.Errorf("Unexpected User fields:\n%s\nwant:\n%s", strings.Join(structs["User"], "\n"), strings.Join(wantUser, "\n"))
+	}
+
+	wantInput := []string{
+		"Name string `json:\"name\"`",
+		"Role *Role `json:\"role,omitempty\"`",
+		"Tags []string `json:\"tags,omitempty\"`",
+	}
+	if strings.Join(structs["CreateUserInput"], "\n") != strings.Join(wantInput, "\n") {
+		t.Errorf("Unexpected CreateUserInput fields:\n%s", strings.Join(structs["CreateUserInput"], "\n"))
+	}
+
+	for _, want := range []string{
+		"// A registered user\ntype User struct {",
+		"type Role string",
+		"RoleAdmin     Role = \"ADMIN\"",
+		"RoleSuperUser Role = \"SUPER_USER\"",
+		"type SearchResult = map[string]interface{}",
+		"type DateTime = string",
+		"type JSON = interface{}",
+		"// Deprecated: Use joined",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, src)
+		}
+	}
+	if strings.Contains(src, "__") {
+		t.Errorf("Expected introspection types to be skipped, got:\n%s", src)
+	}
+
+	if _, err := GenerateGo(analyzer, "not a package"); err == nil {
+		t.Error("Expected an error for an invalid package name")
+	}
+}
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"id":         "ID",
+		"userId":     "UserID",
+		"avatarURL":  "AvatarURL",
+		"created_at": "CreatedAt",
+		"HTMLBody":   "HTMLBody",
+		"user2Name":  "User2Name",
+		"_internal":  "Internal",
+		"admin":      "Admin",
+	}
+	for name, want := range tests {
+		if got := goName(name); got != want {
+			t.Errorf("goName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package gqlt
+
+import (
+	"strings"
+	"testing"
+)
+
+const typeScriptTestSDL = `
+	type Query {
+		user(id: ID!): User
+		search(term: String!): [SearchResult!]!
+	}
+
+	"A registered user"
+	type User {
+		id: ID!
+		name: String
+		age: Int!
+		score: Float
+		active: Boolean!
+		role: Role!
+		tags: [String]!
+		friends: [User!]
+		joined: DateTime
+		nickname: String @deprecated(reason: "Use name")
+	}
+
+	type Post {
+		title: String!
+	}
+
+	enum Role {
+		ADMIN
+		USER
+	}
+
+	union SearchResult = User | Post
+
+	input CreateUserInput {
+		name: String!
+		role: Role = USER
+		tags: [String!]
+	}
+
+	scalar DateTime
+	scalar JSON
+`
+
+func TestGenerateTypeScript(t *testing.T) {
+	analyzer, err := NewAnalyzerFromSDL(typeScriptTestSDL)
+	if err != nil {
+		t.Fatalf("NewAnalyzerFromSDL failed: %v", err)
+	}
+
+	ts, err := GenerateTypeScript(analyzer)
+	if err != nil {
+		t.Fatalf("GenerateTypeScript failed: %v", err)
+	}
+
+	expected := []string{
+		`/** A registered user */
+export interface User {
+  id: string;
+  name?: string | null;
+  age: number;
+  score?: number | null;
+  active: boolean;
+  role: Role;
+  tags: (string | null)[];
+  friends?: User[] | null;
+  joined?: DateTime | null;
+  /** @deprecated Use name */
+  nickname?: string | null;
+}
+`,
+		`export type Role = "ADMIN" | "USER";
+`,
+		`export type SearchResult = User | Post;
+`,
+		`export interface CreateUserInput {
+  name: string;
+  role?: Role | null;
+  tags?: string[] | null;
+}
+`,
+		`export type DateTime = string;
+`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(ts, want) {
+			t.Errorf("Expected output to contain:\n%s\nGot:\n%s", want, ts)
+		}
+	}
+
+	if !strings.Contains(ts, "export type JSON = unknown;\n") {
+		t.Errorf("Expected an unknown custom scalar to map to unknown, got:\n%s", ts)
+	}
+
+	for _, unwanted := range []string{"__Schema", "export type String", "export type ID"} {
+		if strings.Contains(ts, unwanted) {
+			t.Errorf("Expected output not to contain %q", unwanted)
+		}
+	}
+
+	// Declarations are sorted by name
+	if strings.Index(ts, "interface CreateUserInput") > strings.Index(ts, "interface User") {
+		t.Errorf("Expected declarations in name order, got:\n%s", ts)
+	}
+}
@@ -0,0 +1,137 @@
+package gqlt
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// ComplexityOptions configures how EstimateComplexityWithOptions scores a query
+type ComplexityOptions struct {
+	// FieldWeight is the cost of selecting a single field
+	FieldWeight int
+	// NestingMultiplier is applied to the selections of list fields that have no
+	// pagination argument, as an estimate of how many items they return
+	NestingMultiplier int
+}
+
+// DefaultComplexityOptions returns the options used by EstimateComplexity
+func DefaultComplexityOptions() ComplexityOptions {
+	return ComplexityOptions{
+		FieldWeight:       1,
+		NestingMultiplier: 10,
+	}
+}
+
+// paginationArguments are the arguments whose integer value bounds the size of a list
+var paginationArguments = []string{"first", "last", "limit"}
+
+// EstimateComplexity estimates the cost of executing a query using the default options.
+// Every selected field adds its weight, multiplied by the number of items its parent
+// lists are expected to return. A list's size is taken from a first, last or limit
+// argument when one is given as a literal, and from the nesting multiplier otherwise.
+// For documents with several operations the most expensive one is reported.
+//
+// Example:
+//
+//	cost, err := analyzer.EstimateComplexity(`{ users(first: 100) { id name } }`)
+//	// cost == 201
+func (a *Analyzer) EstimateComplexity(query string) (int, error) {
+	return a.EstimateComplexityWithOptions(query, DefaultComplexityOptions())
+}
+
+// EstimateComplexityWithOptions works like EstimateComplexity with a custom field
+// weight and nesting multiplier. The query must be valid against the schema.
+func (a *Analyzer) EstimateComplexityWithOptions(query string, opts ComplexityOptions) (int, error) {
+	if opts.FieldWeight < 0 || opts.NestingMultiplier < 1 {
+		return 0, fmt.Errorf("field weight must not be negative and nesting multiplier must be at least 1")
+	}
+
+	schema, err := a.astSchema()
+	if err != nil {
+		return 0, err
+	}
+
+	doc, errs := gqlparser.LoadQuery(schema, query)
+	if errs != nil {
+		return 0, fmt.Errorf("query is invalid: %w", errs)
+	}
+
+	complexity := 0
+	for _, op := range doc.Operations {
+		if cost := selectionComplexity(op.SelectionSet, 1, opts); cost > complexity {
+			complexity = cost
+		}
+	}
+	return complexity, nil
+}
+
+// selectionComplexity scores a selection set whose parent is expected to be repeated multiplier times
+func selectionComplexity(selectionSet ast.SelectionSet, multiplier int, opts ComplexityOptions) int {
+	cost := 0
+	for _, selection := range selectionSet {
+		switch sel := selection.(type) {
+		case *ast.Field:
+			cost = saturatingAdd(cost, saturatingMul(multiplier, opts.FieldWeight))
+			if len(sel.SelectionSet) == 0 {
+				continue
+			}
+			childMultiplier := multiplier
+			if size, ok := paginationSize(sel); ok {
+				childMultiplier = saturatingMul(childMultiplier, size)
+			} else if sel.Definition != nil && isListType(sel.Definition.Type) {
+				childMultiplier = saturatingMul(childMultiplier, opts.NestingMultiplier)
+			}
+			cost = saturatingAdd(cost, selectionComplexity(sel.SelectionSet, childMultiplier, opts))
+		case *ast.InlineFragment:
+			cost = saturatingAdd(cost, selectionComplexity(sel.SelectionSet, multiplier, opts))
+		case *ast.FragmentSpread:
+			if sel.Definition != nil {
+				cost = saturatingAdd(cost, selectionComplexity(sel.Definition.SelectionSet, multiplier, opts))
+			}
+		}
+	}
+	return cost
+}
+
+// saturatingMul multiplies two non-negative ints, stopping at math.MaxInt instead
+// of overflowing, so huge list sizes can't wrap around to a small cost
+func saturatingMul(a, b int) int {
+	if a != 0 && b > math.MaxInt/a {
+		return math.MaxInt
+	}
+	return a * b
+}
+
+// saturatingAdd adds two non-negative ints, stopping at math.MaxInt instead of
+// overflowing
+func saturatingAdd(a, b int) int {
+	if a > math.MaxInt-b {
+		return math.MaxInt
+	}
+	return a + b
+}
+
+// paginationSize returns the literal value of a field's pagination argument, if any
+func paginationSize(field *ast.Field) (int, bool) {
+	for _, name := range paginationArguments {
+		arg := field.Arguments.ForName(name)
+		if arg == nil || arg.Value == nil || arg.Value.Kind != ast.IntValue {
+			continue
+		}
+		size, err := strconv.Atoi(arg.Value.Raw)
+		if err != nil || size < 0 {
+			continue
+		}
+		return size, true
+	}
+	return 0, false
+}
+
+// isListType reports whether a type is a list, ignoring non-null wrappers
+func isListType(t *ast.Type) bool {
+	return t != nil && t.Elem != nil
+}
@@ -0,0 +1,83 @@
+package gqlt
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAnalyzer_EstimateComplexity(t *testing.T) {
+	analyzer, _ := newExampleTestAnalyzer(t)
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{
+			name:  "shallow query",
+			query: `{ user(id: "1") { id name } }`,
+			want:  3,
+		},
+		{
+			name:  "fragments are counted",
+			query: `query { user(id: "1") { ...UserFields } } fragment UserFields on User { id name }`,
+			want:  3,
+		},
+		{
+			name:  "pagination argument multiplies nested fields",
+			query: `{ users(first: 100) { id name } }`,
+			want:  201,
+		},
+		{
+			// users: 1, friends: 1000, id: 10000, friends: 10000, id: 100000
+			name:  "deeply nested lists",
+			query: `{ users(first: 1000) { friends { id friends { id } } } }`,
+			want:  121001,
+		},
+		{
+			name:  "huge pagination sizes saturate instead of overflowing",
+			query: `{ users(first: 1000000000) { posts(first: 1000000000) { author { posts(first: 1000000000) { id } } } } }`,
+			want:  math.MaxInt,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := analyzer.EstimateComplexity(tt.query)
+			if err != nil {
+				t.Fatalf("EstimateComplexity failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected complexity %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAnalyzer_EstimateComplexityWithOptions(t *testing.T) {
+	analyzer, _ := newExampleTestAnalyzer(t)
+
+	// user: 2, friends: 2, id: 2*5
+	got, err := analyzer.EstimateComplexityWithOptions(`{ user(id: "1") { friends { id } } }`, ComplexityOptions{
+		FieldWeight:       2,
+		NestingMultiplier: 5,
+	})
+	if err != nil {
+		t.Fatalf("EstimateComplexityWithOptions failed: %v", err)
+	}
+	if got != 14 {
+		t.Errorf("Expected complexity 14, got %d", got)
+	}
+
+	if _, err := analyzer.EstimateComplexityWithOptions(`{ version }`, ComplexityOptions{FieldWeight: 1}); err == nil {
+		t.Error("Expected error for invalid nesting multiplier")
+	}
+}
+
+func TestAnalyzer_EstimateComplexity_InvalidQuery(t *testing.T) {
+	analyzer, _ := newExampleTestAnalyzer(t)
+
+	if _, err := analyzer.EstimateComplexity(`{ user(id: "1") { missing } }`); err == nil {
+		t.Error("Expected error for invalid query")
+	}
+}
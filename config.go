@@ -4,21 +4,44 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
+	"unicode"
 )
 
 // Config represents the main configuration structure that manages multiple named configurations.
 // It allows switching between different GraphQL endpoints and their associated settings.
 type Config struct {
+	Version int                    `json:"version"` // config file format version (see ConfigVersion)
 	Current string                 `json:"current"` // active config name (defaults to "default")
 	Configs map[string]ConfigEntry `json:"configs"` // named configurations
 }
 
+// ConfigVersion is the current config file format version. Files written by older
+// versions are migrated when loaded.
+const ConfigVersion = 1
+
+// configMigrations upgrade a config from the version at their index to the next version
+var configMigrations = []func(*Config){
+	// Version 0 files predate the version field; make sure every entry has a headers map
+	func(c *Config) {
+		for name, entry := range c.Configs {
+			if entry.Headers == nil {
+				entry.Headers = make(map[string]string)
+				c.Configs[name] = entry
+			}
+		}
+	},
+}
+
 // ConfigEntry represents a single configuration for a GraphQL endpoint.
 // It contains the endpoint URL, headers, authentication credentials, default output format, and optional documentation.
+// An entry with a Base inherits the endpoint, headers and auth of the named configuration;
+// its own non-empty values take precedence.
 type ConfigEntry struct {
 	Endpoint string            `json:"endpoint"` // GraphQL endpoint URL
 	Headers  map[string]string `json:"headers"`  // HTTP headers to send with requests
@@ -28,7 +51,22 @@ type ConfigEntry struct {
 		Password string `json:"password,omitempty"` // Password for basic authentication
 		APIKey   string `json:"api_key,omitempty"`  // API key for authentication
 	} `json:"auth"`
-	Comment string `json:"_comment,omitempty"` // AI-friendly documentation
+	OAuth2           OAuth2Config           `json:"oauth2,omitzero"`             // OAuth2 client credentials for fetching bearer tokens
+	DefaultQuery     string                 `json:"default_query,omitempty"`     // Query used by run when none is given
+	DefaultOperation string                 `json:"default_operation,omitempty"` // Operation name used with DefaultQuery when none is given
+	DefaultVariables map[string]interface{} `json:"default_variables,omitempty"` // Variables used by run when none are given
+	SchemaRefresh    string                 `json:"schema_refresh,omitempty"`    // Age (e.g. 24h) after which run re-introspects the saved schema
+	Base             string                 `json:"base,omitempty"`              // Name of a configuration to inherit settings from
+	Comment          string                 `json:"_comment,omitempty"`          // AI-friendly documentation
+}
+
+// OAuth2Config holds the settings for fetching bearer tokens with the OAuth2
+// client credentials flow (see Client.SetOAuth2)
+type OAuth2Config struct {
+	TokenURL     string   `json:"token_url,omitempty"`     // Token endpoint URL
+	ClientID     string   `json:"client_id,omitempty"`     // Client ID
+	ClientSecret string   `json:"client_secret,omitempty"` // Client secret
+	Scopes       []string `json:"scopes,omitempty"`        // Scopes to request
 }
 
 // Schema represents the configuration schema for AI understanding
@@ -191,6 +229,17 @@ func Load(configDir string) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	// Upgrade files written by older versions and save them in the current format
+	if config.Version > ConfigVersion {
+		return nil, fmt.Errorf("config file version %d is newer than the supported version %d", config.Version, ConfigVersion)
+	}
+	if config.Version < ConfigVersion {
+		config.migrate()
+		if err := config.writeFile(path); err != nil {
+			return nil, fmt.Errorf("failed to save migrated config: %w", err)
+		}
+	}
+
 	// Ensure default config exists
 	if config.Configs == nil {
 		config.Configs = make(map[string]ConfigEntry)
@@ -216,7 +265,23 @@ func Load(configDir string) (*Config, error) {
 //	}
 func (c *Config) Save(configDir string) error {
 	// Use config directory
-	path := getConfigPathForDir(configDir)
+	return c.writeFile(getConfigPathForDir(configDir))
+}
+
+// migrate applies all migrations from the config's version up to ConfigVersion
+func (c *Config) migrate() {
+	if c.Configs == nil {
+		c.Configs = make(map[string]ConfigEntry)
+	}
+	for c.Version < ConfigVersion {
+		configMigrations[c.Version](c)
+		c.Version++
+	}
+}
+
+// writeFile writes the configuration as JSON to path, stamped with the current version
+func (c *Config) writeFile(path string) error {
+	c.Version = ConfigVersion
 
 	// Ensure directory exists
 	dir := filepath.Dir(path)
@@ -241,11 +306,13 @@ func (c *Config) Save(configDir string) error {
 //	current := config.GetCurrent()
 //	fmt.Printf("Current endpoint: %s\n", current.Endpoint)
 func (c *Config) GetCurrent() *ConfigEntry {
-	if entry, exists := c.Configs[c.Current]; exists {
+	if _, exists := c.Configs[c.Current]; exists {
+		entry, _ := c.resolveEntry(c.Current)
 		return &entry
 	}
 	// Fallback to default
-	if entry, exists := c.Configs["default"]; exists {
+	if _, exists := c.Configs["default"]; exists {
+		entry, _ := c.resolveEntry("default")
 		return &entry
 	}
 	// Last resort
@@ -253,6 +320,151 @@ func (c *Config) GetCurrent() *ConfigEntry {
 	return &defaultEntry
 }
 
+// Resolve returns the named configuration merged with the configurations it
+// inherits from (see ConfigEntry.Base). An error is returned if it doesn't exist,
+// or along with the entry merged so far if its base chain is broken.
+//
+// Example:
+//
+//	entry, err := config.Resolve("staging")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("Staging endpoint: %s\n", entry.Endpoint)
+func (c *Config) Resolve(name string) (ConfigEntry, error) {
+	return c.resolveEntry(name)
+}
+
+// Environment variables that select the active configuration without editing the config file
+const (
+	EnvConfig   = "GQLT_CONFIG"   // name of the configuration to use
+	EnvEndpoint = "GQLT_ENDPOINT" // endpoint URL overriding the active configuration's endpoint
+)
+
+// ResolveActive returns the name and entry of the effective configuration.
+// The configuration is selected with the following precedence, highest first:
+//
+//  1. the name argument (the --use-config flag), if that configuration exists
+//  2. the GQLT_CONFIG environment variable
+//  3. the file's current configuration (see GetCurrent)
+//
+// GQLT_CONFIG naming a configuration that doesn't exist is an error rather than
+// falling back to the current configuration, so a typo in CI can't quietly
+// target another environment.
+//
+// If GQLT_ENDPOINT is set, it replaces the endpoint of the returned entry. Explicit
+// CLI flags such as --url still take precedence over it.
+//
+// Example:
+//
+//	name, entry, err := config.ResolveActive("")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("Using %s at %s\n", name, entry.Endpoint)
+func (c *Config) ResolveActive(name string) (string, *ConfigEntry, error) {
+	candidates := []string{name, os.Getenv(EnvConfig), c.Current}
+	if _, exists := c.Configs[name]; name == "" || !exists {
+		if envName := candidates[1]; envName != "" {
+			if _, exists := c.Configs[envName]; !exists {
+				return "", nil, fmt.Errorf("configuration '%s' named by %s does not exist", envName, EnvConfig)
+			}
+		}
+	}
+
+	var active string
+	var entry *ConfigEntry
+	for _, candidate := range candidates {
+		if _, exists := c.Configs[candidate]; candidate != "" && exists {
+			e, _ := c.resolveEntry(candidate)
+			active = candidate
+			entry = &e
+			break
+		}
+	}
+	if entry == nil {
+		active = "default"
+		entry = c.GetCurrent()
+	}
+
+	if endpoint := os.Getenv(EnvEndpoint); endpoint != "" {
+		entry.Endpoint = endpoint
+	}
+	return active, entry, nil
+}
+
+// resolveEntry returns the named entry merged with its chain of base configurations.
+// If a base does not exist or the chain contains a cycle, the chain is cut at that
+// point and the entry merged so far is returned together with an error.
+func (c *Config) resolveEntry(name string) (ConfigEntry, error) {
+	entry, exists := c.Configs[name]
+	if !exists {
+		return ConfigEntry{}, fmt.Errorf("configuration '%s' does not exist", name)
+	}
+
+	var err error
+	chain := []ConfigEntry{entry}
+	visited := map[string]bool{name: true}
+	for base := entry.Base; base != ""; {
+		if visited[base] {
+			err = fmt.Errorf("configuration '%s' has a cyclic base chain through '%s'", name, base)
+			break
+		}
+		baseEntry, exists := c.Configs[base]
+		if !exists {
+			err = fmt.Errorf("base configuration '%s' of '%s' does not exist", base, name)
+			break
+		}
+		visited[base] = true
+		chain = append(chain, baseEntry)
+		base = baseEntry.Base
+	}
+
+	// Apply the chain from the outermost base down to the entry itself
+	merged := ConfigEntry{Headers: make(map[string]string)}
+	for i := len(chain) - 1; i >= 0; i-- {
+		merged.inherit(chain[i])
+	}
+	merged.Base = entry.Base
+	merged.Comment = entry.Comment
+	return merged, err
+}
+
+// inherit overrides the entry's settings with the non-empty settings of other
+func (e *ConfigEntry) inherit(other ConfigEntry) {
+	if other.Endpoint != "" {
+		e.Endpoint = other.Endpoint
+	}
+	for k, v := range other.Headers {
+		e.Headers[k] = v
+	}
+	// Credentials are replaced as a whole, as a mix of the base's and the entry's
+	// could authenticate with the base's (e.g. basic auth taking precedence over
+	// the entry's token)
+	if other.hasCredentials() {
+		e.Auth = other.Auth
+		e.OAuth2 = other.OAuth2
+	}
+	if other.DefaultQuery != "" {
+		e.DefaultQuery = other.DefaultQuery
+	}
+	if other.DefaultOperation != "" {
+		e.DefaultOperation = other.DefaultOperation
+	}
+	if other.SchemaRefresh != "" {
+		e.SchemaRefresh = other.SchemaRefresh
+	}
+	if other.DefaultVariables != nil {
+		e.DefaultVariables = other.DefaultVariables
+	}
+}
+
+// hasCredentials reports whether any auth or OAuth2 setting of the entry is set
+func (e *ConfigEntry) hasCredentials() bool {
+	return e.Auth.Token != "" || e.Auth.Username != "" || e.Auth.Password != "" || e.Auth.APIKey != "" ||
+		e.OAuth2.TokenURL != "" || e.OAuth2.ClientID != "" || e.OAuth2.ClientSecret != "" || len(e.OAuth2.Scopes) > 0
+}
+
 // GetHeaders returns the HTTP headers for this configuration entry,
 // including computed authentication headers based on stored credentials.
 func (e *ConfigEntry) GetHeaders() map[string]string {
@@ -326,6 +538,82 @@ func (c *Config) Delete(name string) error {
 	return nil
 }
 
+// ConfigExport is a single named configuration entry in a form that can be shared
+// and imported into another configuration file
+type ConfigExport struct {
+	Name   string      `json:"name"`
+	Config ConfigEntry `json:"config"`
+}
+
+// Export returns a copy of a configuration entry for sharing.
+//
+// Example:
+//
+//	entry, err := config.Export("production")
+//	shareable := entry.WithoutSecrets()
+func (c *Config) Export(name string) (ConfigEntry, error) {
+	entry, exists := c.Configs[name]
+	if !exists {
+		return ConfigEntry{}, fmt.Errorf("configuration '%s' does not exist", name)
+	}
+
+	headers := make(map[string]string, len(entry.Headers))
+	for k, v := range entry.Headers {
+		headers[k] = v
+	}
+	entry.Headers = headers
+	return entry, nil
+}
+
+// Import adds a configuration entry under the given name. It fails if a configuration
+// with that name already exists, unless force is set, in which case it is replaced.
+func (c *Config) Import(entry ConfigEntry, name string, force bool) error {
+	if name == "" {
+		return fmt.Errorf("configuration name is required")
+	}
+	if _, exists := c.Configs[name]; exists && !force {
+		return fmt.Errorf("configuration '%s' already exists", name)
+	}
+	if c.Configs == nil {
+		c.Configs = make(map[string]ConfigEntry)
+	}
+	if entry.Headers == nil {
+		entry.Headers = make(map[string]string)
+	}
+	c.Configs[name] = entry
+	return nil
+}
+
+// WithoutSecrets returns a copy of the entry with all stored credentials removed:
+// the auth settings and any Authorization, Proxy-Authorization, Cookie or X-API-Key header.
+func (e ConfigEntry) WithoutSecrets() ConfigEntry {
+	stripped := e
+	stripped.Auth.Token = ""
+	stripped.Auth.Username = ""
+	stripped.Auth.Password = ""
+	stripped.Auth.APIKey = ""
+	stripped.OAuth2.ClientSecret = ""
+
+	stripped.Headers = make(map[string]string, len(e.Headers))
+	for k, v := range e.Headers {
+		if !IsSecretHeader(k) {
+			stripped.Headers[k] = v
+		}
+	}
+	return stripped
+}
+
+// IsSecretHeader reports whether a header carries credentials (Authorization,
+// Proxy-Authorization, Cookie or X-API-Key).
+func IsSecretHeader(name string) bool {
+	for _, secret := range redactedHeaders {
+		if strings.EqualFold(name, secret) {
+			return true
+		}
+	}
+	return false
+}
+
 // SetValue sets a value in a configuration entry
 func (c *Config) SetValue(name, key, value string) error {
 	entry, exists := c.Configs[name]
@@ -335,6 +623,9 @@ func (c *Config) SetValue(name, key, value string) error {
 
 	switch key {
 	case "endpoint":
+		if err := validateEndpoint(value); err != nil {
+			return err
+		}
 		entry.Endpoint = value
 	case "auth.token":
 		entry.Auth.Token = value
@@ -344,10 +635,54 @@ func (c *Config) SetValue(name, key, value string) error {
 		entry.Auth.Password = value
 	case "auth.api_key":
 		entry.Auth.APIKey = value
+	case "oauth2.token_url":
+		if value != "" {
+			if err := validateEndpoint(value); err != nil {
+				return fmt.Errorf("invalid OAuth2 token URL: %w", err)
+			}
+		}
+		entry.OAuth2.TokenURL = value
+	case "oauth2.client_id":
+		entry.OAuth2.ClientID = value
+	case "oauth2.client_secret":
+		entry.OAuth2.ClientSecret = value
+	case "oauth2.scopes":
+		entry.OAuth2.Scopes = strings.FieldsFunc(value, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+		if len(entry.OAuth2.Scopes) == 0 {
+			entry.OAuth2.Scopes = nil
+		}
+	case "base":
+		entry.Base = value
+	case "defaults.query":
+		entry.DefaultQuery = value
+	case "defaults.operation":
+		entry.DefaultOperation = value
+	case "defaults.variables":
+		if value == "" {
+			entry.DefaultVariables = nil
+			break
+		}
+		var variables map[string]interface{}
+		if err := json.Unmarshal([]byte(value), &variables); err != nil {
+			return fmt.Errorf("invalid default variables: must be a JSON object: %w", err)
+		}
+		entry.DefaultVariables = variables
+	case "schema.refresh":
+		if value != "" {
+			if _, err := time.ParseDuration(value); err != nil {
+				return fmt.Errorf("invalid schema refresh interval: %w", err)
+			}
+		}
+		entry.SchemaRefresh = value
 	default:
 		// Handle headers.<name> pattern
 		if strings.HasPrefix(key, "headers.") {
 			headerName := strings.TrimPrefix(key, "headers.")
+			if err := validateHeaderName(headerName); err != nil {
+				return err
+			}
 			if entry.Headers == nil {
 				entry.Headers = make(map[string]string)
 			}
@@ -361,6 +696,34 @@ func (c *Config) SetValue(name, key, value string) error {
 	return nil
 }
 
+// validateEndpoint checks that an endpoint is an absolute http or https URL
+func validateEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint URL '%s': %w", endpoint, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid endpoint URL '%s': scheme must be http or https", endpoint)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid endpoint URL '%s': host is missing", endpoint)
+	}
+	return nil
+}
+
+// validateHeaderName checks that a header name is a valid HTTP token
+func validateHeaderName(name string) error {
+	if name == "" {
+		return fmt.Errorf("header name is empty")
+	}
+	for _, r := range name {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("!#$%&'*+-.^_`|~", r)) {
+			return fmt.Errorf("invalid header name '%s': contains %q", name, r)
+		}
+	}
+	return nil
+}
+
 // Validate checks if the configuration is valid
 func (c *Config) Validate() []string {
 	var errors []string
@@ -373,7 +736,12 @@ func (c *Config) Validate() []string {
 		errors = append(errors, fmt.Sprintf("current configuration '%s' does not exist", c.Current))
 	}
 
-	for name, entry := range c.Configs {
+	for name := range c.Configs {
+		entry, err := c.resolveEntry(name)
+		if err != nil {
+			errors = append(errors, err.Error())
+			continue
+		}
 		if entry.Endpoint == "" && name != "default" {
 			errors = append(errors, fmt.Sprintf("configuration '%s' has no endpoint", name))
 		}
@@ -393,6 +761,7 @@ func GetSchema() *Schema {
 // GetDefaultConfig returns a default configuration
 func GetDefaultConfig() *Config {
 	return &Config{
+		Version: ConfigVersion,
 		Current: "default",
 		Configs: map[string]ConfigEntry{
 			"default": getDefaultConfigEntry(),
@@ -1,8 +1,11 @@
 package gqlt
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
@@ -452,3 +455,456 @@ func TestDualFormatSchemaPaths(t *testing.T) {
 		t.Errorf("Schema path should be absolute and non-empty, got: %s", schemaPath)
 	}
 }
+
+func TestConfigExportImport(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Create("production")
+	config.SetValue("production", "endpoint", "https://api.example.com/graphql")
+	config.SetValue("production", "auth.token", "secret-token")
+	config.SetValue("production", "auth.password", "secret-password")
+	config.SetValue("production", "headers.Authorization", "Bearer secret")
+	config.SetValue("production", "headers.X-Tenant", "acme")
+
+	t.Run("export with secrets", func(t *testing.T) {
+		entry, err := config.Export("production")
+		if err != nil {
+			t.Fatalf("Export failed: %v", err)
+		}
+		if entry.Auth.Token != "secret-token" || entry.Headers["Authorization"] != "Bearer secret" {
+			t.Errorf("Expected export to keep credentials, got %+v", entry)
+		}
+
+		// The export is a copy; changing it leaves the config untouched
+		entry.Headers["X-Tenant"] = "changed"
+		if config.Configs["production"].Headers["X-Tenant"] != "acme" {
+			t.Error("Expected exported headers to be a copy")
+		}
+	})
+
+	t.Run("export with secrets stripped", func(t *testing.T) {
+		entry, err := config.Export("production")
+		if err != nil {
+			t.Fatalf("Export failed: %v", err)
+		}
+		stripped := entry.WithoutSecrets()
+
+		if stripped.Auth.Token != "" || stripped.Auth.Password != "" {
+			t.Errorf("Expected auth credentials to be stripped, got %+v", stripped.Auth)
+		}
+		if _, ok := stripped.Headers["Authorization"]; ok {
+			t.Error("Expected Authorization header to be stripped")
+		}
+		if stripped.Headers["X-Tenant"] != "acme" || stripped.Endpoint != "https://api.example.com/graphql" {
+			t.Errorf("Expected non-secret settings to be kept, got %+v", stripped)
+		}
+		if entry.Auth.Token != "secret-token" {
+			t.Error("Expected WithoutSecrets to leave the original entry untouched")
+		}
+	})
+
+	t.Run("export missing config", func(t *testing.T) {
+		if _, err := config.Export("missing"); err == nil {
+			t.Error("Expected error exporting a missing configuration")
+		}
+	})
+
+	t.Run("import collision", func(t *testing.T) {
+		target := GetDefaultConfig()
+		target.Create("production")
+
+		entry, _ := config.Export("production")
+		if err := target.Import(entry, "production", false); err == nil {
+			t.Error("Expected error importing over an existing configuration")
+		}
+		if target.Configs["production"].Endpoint != "" {
+			t.Error("Expected failed import to leave the existing configuration untouched")
+		}
+
+		if err := target.Import(entry, "production", true); err != nil {
+			t.Fatalf("Import with force failed: %v", err)
+		}
+		if target.Configs["production"].Endpoint != "https://api.example.com/graphql" {
+			t.Error("Expected forced import to replace the configuration")
+		}
+
+		if err := target.Import(entry, "staging", false); err != nil {
+			t.Fatalf("Import under a new name failed: %v", err)
+		}
+		if _, ok := target.Configs["staging"]; !ok {
+			t.Error("Expected imported configuration to exist")
+		}
+	})
+}
+
+func TestResolveActive(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Create("production")
+	config.SetValue("production", "endpoint", "https://prod.example.com/graphql")
+	config.Create("staging")
+	config.SetValue("staging", "endpoint", "https://staging.example.com/graphql")
+	config.SetCurrent("production")
+
+	tests := []struct {
+		name         string
+		flag         string
+		envConfig    string
+		envEndpoint  string
+		wantName     string
+		wantEndpoint string
+		wantErr      bool
+	}{
+		{
+			name:         "current config",
+			wantName:     "production",
+			wantEndpoint: "https://prod.example.com/graphql",
+		},
+		{
+			name:         "env config overrides current",
+			envConfig:    "staging",
+			wantName:     "staging",
+			wantEndpoint: "https://staging.example.com/graphql",
+		},
+		{
+			name:         "flag overrides env config",
+			flag:         "production",
+			envConfig:    "staging",
+			wantName:     "production",
+			wantEndpoint: "https://prod.example.com/graphql",
+		},
+		{
+			name:      "unknown env config is an error",
+			envConfig: "missing",
+			wantErr:   true,
+		},
+		{
+			name:         "flag overrides unknown env config",
+			flag:         "staging",
+			envConfig:    "missing",
+			wantName:     "staging",
+			wantEndpoint: "https://staging.example.com/graphql",
+		},
+		{
+			name:         "env endpoint overrides config endpoint",
+			envConfig:    "staging",
+			envEndpoint:  "https://ci.example.com/graphql",
+			wantName:     "staging",
+			wantEndpoint: "https://ci.example.com/graphql",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvConfig, tt.envConfig)
+			t.Setenv(EnvEndpoint, tt.envEndpoint)
+
+			name, entry, err := config.ResolveActive(tt.flag)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), EnvConfig) {
+					t.Errorf("Expected an error naming %s, got %v", EnvConfig, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveActive failed: %v", err)
+			}
+			if name != tt.wantName {
+				t.Errorf("ResolveActive() name = %q, want %q", name, tt.wantName)
+			}
+			if entry.Endpoint != tt.wantEndpoint {
+				t.Errorf("ResolveActive() endpoint = %q, want %q", entry.Endpoint, tt.wantEndpoint)
+			}
+		})
+	}
+
+	// The env endpoint never modifies the stored configuration
+	if config.Configs["staging"].Endpoint != "https://staging.example.com/graphql" {
+		t.Error("Expected GQLT_ENDPOINT to leave the stored configuration untouched")
+	}
+}
+
+func TestConfigInheritance(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Create("common")
+	config.SetValue("common", "endpoint", "https://prod.example.com/graphql")
+	config.SetValue("common", "headers.X-Tenant", "acme")
+	config.SetValue("common", "auth.token", "shared-token")
+	config.Create("staging")
+	config.SetValue("staging", "base", "common")
+	config.SetValue("staging", "endpoint", "https://staging.example.com/graphql")
+	config.SetCurrent("staging")
+
+	current := config.GetCurrent()
+	if current.Endpoint != "https://staging.example.com/graphql" {
+		t.Errorf("Expected staging endpoint to override base, got %s", current.Endpoint)
+	}
+	if current.Headers["X-Tenant"] != "acme" {
+		t.Errorf("Expected header inherited from base, got %v", current.Headers)
+	}
+	if got := current.GetHeaders()["Authorization"]; got != "Bearer shared-token" {
+		t.Errorf("Expected Authorization from inherited token, got %q", got)
+	}
+
+	// The stored entries are unchanged by resolution
+	if config.Configs["staging"].Auth.Token != "" || len(config.Configs["staging"].Headers) != 0 {
+		t.Errorf("Expected stored staging entry to be untouched, got %+v", config.Configs["staging"])
+	}
+
+	if errs := config.Validate(); len(errs) != 0 {
+		t.Errorf("Expected valid configuration, got %v", errs)
+	}
+}
+
+func TestConfigInheritanceCredentials(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Create("common")
+	config.SetValue("common", "auth.username", "admin")
+	config.SetValue("common", "auth.password", "secret")
+	config.SetValue("common", "oauth2.client_id", "shared-client")
+	config.Create("staging")
+	config.SetValue("staging", "base", "common")
+	config.SetValue("staging", "auth.token", "staging-token")
+
+	// The entry's token replaces the base's credentials rather than being outranked by them
+	entry, err := config.Resolve("staging")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if got := entry.GetHeaders()["Authorization"]; got != "Bearer staging-token" {
+		t.Errorf("Expected Authorization from the entry's token, got %q", got)
+	}
+	if entry.Auth.Username != "" || entry.Auth.Password != "" || entry.OAuth2.ClientID != "" {
+		t.Errorf("Expected no credentials inherited from the base, got %+v", entry)
+	}
+
+	// Without credentials of its own, the entry inherits the base's
+	config.Create("dev")
+	config.SetValue("dev", "base", "common")
+	entry, err = config.Resolve("dev")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if entry.Auth.Username != "admin" || entry.Auth.Password != "secret" || entry.OAuth2.ClientID != "shared-client" {
+		t.Errorf("Expected the base's credentials to be inherited, got %+v", entry)
+	}
+}
+
+func TestConfigInheritanceCycle(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Create("a")
+	config.Create("b")
+	config.SetValue("a", "base", "b")
+	config.SetValue("a", "headers.X-From", "a")
+	config.SetValue("b", "base", "a")
+	config.SetValue("b", "endpoint", "https://b.example.com/graphql")
+	config.SetCurrent("a")
+
+	// Resolution stops at the cycle instead of looping forever
+	current := config.GetCurrent()
+	if current.Endpoint != "https://b.example.com/graphql" || current.Headers["X-From"] != "a" {
+		t.Errorf("Expected settings merged up to the cycle, got %+v", current)
+	}
+
+	errs := config.Validate()
+	found := false
+	for _, e := range errs {
+		if strings.Contains(e, "cyclic") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected Validate to report the cycle, got %v", errs)
+	}
+}
+
+func TestSetValueValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{name: "valid https URL", key: "endpoint", value: "https://api.example.com/graphql"},
+		{name: "valid http URL with port", key: "endpoint", value: "http://localhost:4000/graphql"},
+		{name: "misspelled scheme", key: "endpoint", value: "htp://api.example.com/graphql", wantErr: true},
+		{name: "missing scheme", key: "endpoint", value: "api.example.com/graphql", wantErr: true},
+		{name: "missing host", key: "endpoint", value: "https:///graphql", wantErr: true},
+		{name: "valid header name", key: "headers.X-Request-ID", value: "abc"},
+		{name: "header name with spaces", key: "headers.X Request ID", value: "abc", wantErr: true},
+		{name: "empty header name", key: "headers.", value: "abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			err := config.SetValue("default", tt.key, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetValue(%q, %q) error = %v, wantErr %v", tt.key, tt.value, err, tt.wantErr)
+			}
+			if tt.wantErr && config.Configs["default"].Endpoint != "" {
+				t.Error("Expected invalid value not to be stored")
+			}
+		})
+	}
+}
+
+func TestLoadMigratesVersionZero(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.json")
+
+	// A config file written before the version field existed
+	legacy := `{
+  "current": "production",
+  "configs": {
+    "production": {
+      "endpoint": "https://api.example.com/graphql"
+    }
+  }
+}`
+	if err := os.WriteFile(configPath, []byte(legacy), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	config, err := Load(tempDir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if config.Version != ConfigVersion {
+		t.Errorf("Expected migrated version %d, got %d", ConfigVersion, config.Version)
+	}
+	production := config.Configs["production"]
+	if production.Endpoint != "https://api.example.com/graphql" || production.Headers == nil {
+		t.Errorf("Expected migrated entry with headers map, got %+v", production)
+	}
+
+	// The migrated config is saved back with the current version
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	var saved Config
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("Failed to parse saved config: %v", err)
+	}
+	if saved.Version != ConfigVersion {
+		t.Errorf("Expected saved version %d, got %d", ConfigVersion, saved.Version)
+	}
+	if saved.Configs["production"].Endpoint != "https://api.example.com/graphql" {
+		t.Errorf("Expected saved config to keep its settings, got %+v", saved.Configs)
+	}
+}
+
+func TestLoadNewerVersion(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.json")
+
+	data := fmt.Sprintf(`{"version": %d, "current": "default", "configs": {}}`, ConfigVersion+1)
+	if err := os.WriteFile(configPath, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	if _, err := Load(tempDir); err == nil {
+		t.Error("Expected error loading a config file from a newer version")
+	}
+}
+
+func TestSetValueDefaults(t *testing.T) {
+	config := GetDefaultConfig()
+
+	if err := config.SetValue("default", "defaults.query", "{ users { id } }"); err != nil {
+		t.Fatalf("SetValue defaults.query failed: %v", err)
+	}
+	if err := config.SetValue("default", "defaults.operation", "Users"); err != nil {
+		t.Fatalf("SetValue defaults.operation failed: %v", err)
+	}
+	if err := config.SetValue("default", "defaults.variables", `{"first": 10}`); err != nil {
+		t.Fatalf("SetValue defaults.variables failed: %v", err)
+	}
+
+	entry := config.Configs["default"]
+	if entry.DefaultQuery != "{ users { id } }" || entry.DefaultOperation != "Users" || entry.DefaultVariables["first"] != float64(10) {
+		t.Errorf("Unexpected defaults: %+v", entry)
+	}
+
+	if err := config.SetValue("default", "defaults.variables", `[1, 2]`); err == nil {
+		t.Error("Expected error for default variables that are not a JSON object")
+	}
+}
+
+func TestSetValueSchemaRefresh(t *testing.T) {
+	config := GetDefaultConfig()
+	if err := config.SetValue("default", "schema.refresh", "24h"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if refresh := config.Configs["default"].SchemaRefresh; refresh != "24h" {
+		t.Errorf("Expected schema refresh 24h, got %q", refresh)
+	}
+	if err := config.SetValue("default", "schema.refresh", "daily"); err == nil {
+		t.Error("Expected an error for an invalid interval")
+	}
+
+	// The interval is inherited from a base configuration
+	config.Create("staging")
+	config.SetValue("staging", "base", "default")
+	config.SetCurrent("staging")
+	if refresh := config.GetCurrent().SchemaRefresh; refresh != "24h" {
+		t.Errorf("Expected the inherited schema refresh 24h, got %q", refresh)
+	}
+}
+
+func TestSetValueOAuth2(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Create("common")
+	for key, value := range map[string]string{
+		"oauth2.token_url":     "https://auth.example.com/oauth/token",
+		"oauth2.client_id":     "my-client",
+		"oauth2.client_secret": "my-secret",
+		"oauth2.scopes":        "read:users, write:users",
+	} {
+		if err := config.SetValue("common", key, value); err != nil {
+			t.Fatalf("SetValue %s failed: %v", key, err)
+		}
+	}
+
+	want := OAuth2Config{
+		TokenURL:     "https://auth.example.com/oauth/token",
+		ClientID:     "my-client",
+		ClientSecret: "my-secret",
+		Scopes:       []string{"read:users", "write:users"},
+	}
+	if got := config.Configs["common"].OAuth2; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+
+	if err := config.SetValue("common", "oauth2.token_url", "not a url"); err == nil {
+		t.Error("Expected an error for an invalid token URL")
+	}
+
+	// The settings are inherited and the secret is stripped on export
+	config.Create("staging")
+	config.SetValue("staging", "base", "common")
+	config.SetCurrent("staging")
+	current := config.GetCurrent()
+	if !reflect.DeepEqual(current.OAuth2, want) {
+		t.Errorf("Expected inherited OAuth2 settings, got %+v", current.OAuth2)
+	}
+	if stripped := current.WithoutSecrets(); stripped.OAuth2.ClientSecret != "" || stripped.OAuth2.ClientID != "my-client" {
+		t.Errorf("Expected only the client secret to be stripped, got %+v", stripped.OAuth2)
+	}
+
+	// OAuth2 settings are saved under their own key, leaving the auth settings alone
+	var saved map[string]interface{}
+	data, _ := json.Marshal(config.Configs["common"])
+	json.Unmarshal(data, &saved)
+	if _, ok := saved["oauth2"].(map[string]interface{}); !ok {
+		t.Errorf("Expected oauth2 settings at the top of the entry, got %s", data)
+	}
+
+	// Entries without OAuth2 settings don't mention them when saved
+	data, err := json.Marshal(config.Configs["default"])
+	if err != nil {
+		t.Fatalf("Failed to marshal entry: %v", err)
+	}
+	if strings.Contains(string(data), "oauth2") {
+		t.Errorf("Expected no oauth2 settings in %s", data)
+	}
+}
@@ -0,0 +1,95 @@
+package gqlt
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// DiffJSON compares two JSON values, such as the data of two responses, and
+// reports the differences between them in key order. Objects are compared key by
+// key and lists index by index; any other difference, including a change of
+// type, is reported as a changed value. Paths use the same syntax as SelectPath
+// (e.g. "user.posts[0].title"), with an empty path for the values themselves.
+// Values are compared by their JSON encoding, so structs, typed maps and numbers
+// of any Go type can be compared with decoded JSON.
+//
+// Example:
+//
+//	changes, err := gqlt.DiffJSON(previous.Data, current.Data)
+//	for _, change := range changes {
+//	    fmt.Printf("%s %s: %v -> %v\n", change.Kind, change.Path, change.Old, change.New)
+//	}
+func DiffJSON(oldValue, newValue interface{}) ([]Change, error) {
+	oldJSON, err := normalizeJSON(oldValue)
+	if err != nil {
+		return nil, fmt.Errorf("invalid old value: %w", err)
+	}
+	newJSON, err := normalizeJSON(newValue)
+	if err != nil {
+		return nil, fmt.Errorf("invalid new value: %w", err)
+	}
+
+	changes := []Change{}
+	diffJSONValues(&changes, "", oldJSON, newJSON)
+	return changes, nil
+}
+
+// diffJSONValues appends the differences between two decoded JSON values at path
+func diffJSONValues(changes *[]Change, path string, oldValue, newValue interface{}) {
+	switch oldTyped := oldValue.(type) {
+	case map[string]interface{}:
+		if newTyped, ok := newValue.(map[string]interface{}); ok {
+			for _, key := range unionKeys(oldTyped, newTyped) {
+				keyPath := key
+				if path != "" {
+					keyPath = path + "." + key
+				}
+				oldChild, inOld := oldTyped[key]
+				newChild, inNew := newTyped[key]
+				switch {
+				case !inOld:
+					*changes = append(*changes, Change{Kind: ChangeValueAdded, Path: keyPath, New: newChild})
+				case !inNew:
+					*changes = append(*changes, Change{Kind: ChangeValueRemoved, Path: keyPath, Old: oldChild})
+				default:
+					diffJSONValues(changes, keyPath, oldChild, newChild)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if newTyped, ok := newValue.([]interface{}); ok {
+			for i := 0; i < len(oldTyped) || i < len(newTyped); i++ {
+				indexPath := fmt.Sprintf("%s[%d]", path, i)
+				switch {
+				case i >= len(oldTyped):
+					*changes = append(*changes, Change{Kind: ChangeValueAdded, Path: indexPath, New: newTyped[i]})
+				case i >= len(newTyped):
+					*changes = append(*changes, Change{Kind: ChangeValueRemoved, Path: indexPath, Old: oldTyped[i]})
+				default:
+					diffJSONValues(changes, indexPath, oldTyped[i], newTyped[i])
+				}
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(oldValue, newValue) {
+		*changes = append(*changes, Change{Kind: ChangeValueChanged, Path: path, Old: oldValue, New: newValue})
+	}
+}
+
+// normalizeJSON converts a value to the types produced by decoding JSON, so
+// values from different sources compare equal when their JSON does
+func normalizeJSON(value interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
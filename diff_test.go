@@ -0,0 +1,111 @@
+package gqlt
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDiffJSON(t *testing.T) {
+	decode := func(s string) interface{} {
+		var v interface{}
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			t.Fatalf("Failed to decode %s: %v", s, err)
+		}
+		return v
+	}
+
+	tests := []struct {
+		name     string
+		old, new string
+		want     []Change
+	}{
+		{
+			name: "identical",
+			old:  `{"user": {"name": "Ada", "tags": ["a", "b"]}}`,
+			new:  `{"user": {"tags": ["a", "b"], "name": "Ada"}}`,
+			want: []Change{},
+		},
+		{
+			name: "added keys",
+			old:  `{"user": {"name": "Ada"}}`,
+			new:  `{"user": {"name": "Ada", "email": "ada@example.com"}, "count": 1}`,
+			want: []Change{
+				{Kind: ChangeValueAdded, Path: "count", New: float64(1)},
+				{Kind: ChangeValueAdded, Path: "user.email", New: "ada@example.com"},
+			},
+		},
+		{
+			name: "removed keys",
+			old:  `{"user": {"name": "Ada", "address": {"city": "London"}}}`,
+			new:  `{"user": {"name": "Ada"}}`,
+			want: []Change{
+				{Kind: ChangeValueRemoved, Path: "user.address", Old: map[string]interface{}{"city": "London"}},
+			},
+		},
+		{
+			name: "changed scalars",
+			old:  `{"status": "pending", "progress": 10, "done": false, "eta": null}`,
+			new:  `{"status": "running", "progress": 55, "done": false, "eta": "5m"}`,
+			want: []Change{
+				{Kind: ChangeValueChanged, Path: "eta", Old: nil, New: "5m"},
+				{Kind: ChangeValueChanged, Path: "progress", Old: float64(10), New: float64(55)},
+				{Kind: ChangeValueChanged, Path: "status", Old: "pending", New: "running"},
+			},
+		},
+		{
+			name: "lists",
+			old:  `{"items": [{"id": 1}, {"id": 2}, {"id": 3}]}`,
+			new:  `{"items": [{"id": 1}, {"id": 5}]}`,
+			want: []Change{
+				{Kind: ChangeValueChanged, Path: "items[1].id", Old: float64(2), New: float64(5)},
+				{Kind: ChangeValueRemoved, Path: "items[2]", Old: map[string]interface{}{"id": float64(3)}},
+			},
+		},
+		{
+			name: "type change",
+			old:  `{"value": {"a": 1}}`,
+			new:  `{"value": [1]}`,
+			want: []Change{
+				{Kind: ChangeValueChanged, Path: "value", Old: map[string]interface{}{"a": float64(1)}, New: []interface{}{float64(1)}},
+			},
+		},
+		{
+			name: "root scalar",
+			old:  `1`,
+			new:  `2`,
+			want: []Change{{Kind: ChangeValueChanged, Path: "", Old: float64(1), New: float64(2)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DiffJSON(decode(tt.old), decode(tt.new))
+			if err != nil {
+				t.Fatalf("DiffJSON failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unexpected changes:\n got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffJSONGoValues(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	changes, err := DiffJSON(user{Name: "Ada", Age: 36}, map[string]interface{}{"name": "Ada", "age": 37})
+	if err != nil {
+		t.Fatalf("DiffJSON failed: %v", err)
+	}
+	want := []Change{{Kind: ChangeValueChanged, Path: "age", Old: float64(36), New: float64(37)}}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Expected %+v, got %+v", want, changes)
+	}
+
+	if _, err := DiffJSON(make(chan int), nil); err == nil {
+		t.Error("Expected an error for a value without a JSON encoding")
+	}
+}
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"fmt"
+	"net/http"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/kluzzebass/gqlt"
 )
@@ -91,6 +95,238 @@ func TestMockServerBasic(t *testing.T) {
 	}
 }
 
+// TestMockServerRequestLog tests the mock server's request assertion helpers
+// with concurrent requests
+func TestMockServerRequestLog(t *testing.T) {
+	mock := NewMockGraphQLServer()
+	defer mock.Close()
+
+	if _, ok := mock.LastRequest(); ok {
+		t.Error("Expected no last request before any were made")
+	}
+
+	mock.AddDefaultHandler(func(response *gqlt.Response) {
+		response.Data = map[string]interface{}{"ok": true}
+	})
+
+	// Send requests concurrently while adding handlers
+	const requests = 20
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			client := gqlt.NewClient(mock.URL(), nil)
+			name := fmt.Sprintf("Op%d", i%2)
+			if _, err := client.Execute(fmt.Sprintf("query %s { ok }", name), map[string]interface{}{"i": i}, name); err != nil {
+				t.Errorf("Query failed: %v", err)
+			}
+		}(i)
+		mock.AddHandler(fmt.Sprintf("Unused%d", i), func(response *gqlt.Response) {})
+	}
+	wg.Wait()
+
+	if count := mock.RequestCount(); count != requests {
+		t.Errorf("Expected %d requests, got %d", requests, count)
+	}
+	if log := mock.GetRequestLog(); len(log) != requests {
+		t.Errorf("Expected %d logged requests, got %d", requests, len(log))
+	}
+	if !mock.AssertOperationCalled("Op0") || !mock.AssertOperationCalled("Op1") {
+		t.Error("Expected Op0 and Op1 to have been called")
+	}
+	if mock.AssertOperationCalled("Missing") {
+		t.Error("Expected Missing not to have been called")
+	}
+
+	client := gqlt.NewClient(mock.URL(), nil)
+	if _, err := client.Execute("query Final { ok }", map[string]interface{}{"last": true}, "Final"); err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	last, ok := mock.LastRequest()
+	if !ok || last.OperationName != "Final" || last.Query != "query Final { ok }" || last.Variables["last"] != true {
+		t.Errorf("Unexpected last request: %+v", last)
+	}
+}
+
+// TestMockServerRequestLogConcurrentAccess reads and clears the request log
+// while requests are being recorded; run with -race to check synchronization
+func TestMockServerRequestLogConcurrentAccess(t *testing.T) {
+	mock := NewMockGraphQLServer()
+	defer mock.Close()
+	mock.AddDefaultHandler(func(response *gqlt.Response) {
+		response.Data = map[string]interface{}{"ok": true}
+	})
+
+	const requests = 50
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			client := gqlt.NewClient(mock.URL(), nil)
+			if _, err := client.Execute("query Ping { ok }", nil, "Ping"); err != nil {
+				t.Errorf("Query failed: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for _, request := range mock.GetRequestLog() {
+				if request.OperationName != "Ping" {
+					t.Errorf("Unexpected logged request: %+v", request)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if count := mock.RequestCount(); count != requests {
+		t.Errorf("Expected %d requests, got %d", requests, count)
+	}
+
+	// The returned log is a copy
+	log := mock.GetRequestLog()
+	log[0].OperationName = "Changed"
+	if mock.GetRequestLog()[0].OperationName != "Ping" {
+		t.Error("Expected GetRequestLog to return a copy")
+	}
+
+	mock.ClearRequestLog()
+	if count := mock.RequestCount(); count != 0 {
+		t.Errorf("Expected an empty log after clearing, got %d requests", count)
+	}
+	if _, ok := mock.LastRequest(); ok {
+		t.Error("Expected no last request after clearing")
+	}
+}
+
+// TestMockServerSequence tests that successive calls get successive responses
+func TestMockServerSequence(t *testing.T) {
+	mock := NewMockGraphQLServer()
+	defer mock.Close()
+
+	mock.AddSequence("GetStatus", []*gqlt.Response{
+		{Errors: []interface{}{map[string]interface{}{"message": "temporarily unavailable"}}},
+		{Data: map[string]interface{}{"status": "starting"}},
+		{Data: map[string]interface{}{"status": "ready"}},
+	})
+
+	client := gqlt.NewClient(mock.URL(), nil)
+	call := func() *gqlt.Response {
+		response, err := client.Execute("query GetStatus { status }", nil, "GetStatus")
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		return response
+	}
+
+	if response := call(); len(response.Errors) != 1 || response.Data != nil {
+		t.Errorf("Expected the first call to fail, got %+v", response)
+	}
+	for _, want := range []string{"starting", "ready", "ready"} {
+		response := call()
+		if len(response.Errors) > 0 {
+			t.Fatalf("Unexpected errors: %v", response.Errors)
+		}
+		data, _ := response.Data.(map[string]interface{})
+		if data["status"] != want {
+			t.Errorf("Expected status %q, got %v", want, data["status"])
+		}
+	}
+}
+
+// TestMockServerQueryMatcher tests routing anonymous queries by their content
+func TestMockServerQueryMatcher(t *testing.T) {
+	mock := NewMockGraphQLServer()
+	defer mock.Close()
+
+	mock.AddQueryMatcher(func(request Request) bool {
+		return strings.Contains(request.Query, "users")
+	}, func(response *gqlt.Response) {
+		response.Data = map[string]interface{}{"users": []interface{}{}}
+	})
+	mock.AddQueryMatcher(func(request Request) bool {
+		return strings.Contains(request.Query, "posts")
+	}, func(response *gqlt.Response) {
+		response.Data = map[string]interface{}{"posts": []interface{}{}}
+	})
+	mock.AddHandler("GetUsers", func(response *gqlt.Response) {
+		response.Data = map[string]interface{}{"named": true}
+	})
+	mock.AddDefaultHandler(func(response *gqlt.Response) {
+		response.Data = map[string]interface{}{"default": true}
+	})
+
+	client := gqlt.NewClient(mock.URL(), nil)
+	tests := []struct {
+		query         string
+		operationName string
+		want          string
+	}{
+		{"{ users { id } }", "", "users"},
+		{"{ posts { id } }", "", "posts"},
+		{"{ comments { id } }", "", "default"},
+		{"query GetUsers { users { id } }", "GetUsers", "users"}, // Matchers take precedence
+		{"query GetUsers { me { id } }", "GetUsers", "named"},
+	}
+	for _, tt := range tests {
+		response, err := client.Execute(tt.query, nil, tt.operationName)
+		if err != nil {
+			t.Fatalf("Query %q failed: %v", tt.query, err)
+		}
+		data, _ := response.Data.(map[string]interface{})
+		if _, ok := data[tt.want]; !ok {
+			t.Errorf("Expected query %q to be routed to the %s handler, got %v", tt.query, tt.want, data)
+		}
+	}
+}
+
+// TestMockServerStatusResponse tests that clients see non-200 status codes
+func TestMockServerStatusResponse(t *testing.T) {
+	mock := NewMockGraphQLServer()
+	defer mock.Close()
+
+	mock.AddStatusResponse("Broken", http.StatusInternalServerError, &gqlt.Response{
+		Errors: []interface{}{map[string]interface{}{"message": "internal server error"}},
+	})
+	mock.AddStatusResponse("Private", http.StatusUnauthorized, nil)
+	mock.AddStatusResponse("Busy", http.StatusServiceUnavailable, nil)
+
+	client := gqlt.NewClient(mock.URL(), nil)
+	var statusCodes []int
+	var errs []error
+	client.SetLogger(func(info gqlt.RequestLog) {
+		statusCodes = append(statusCodes, info.StatusCode)
+		errs = append(errs, info.Error)
+	})
+
+	response, err := client.Execute("query Broken { broken }", nil, "Broken")
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(response.Errors) != 1 {
+		t.Errorf("Expected the error body of the 500 response, got %+v", response)
+	}
+
+	if _, err := client.Execute("query Private { secret }", nil, "Private"); err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	// A retryable status is retried
+	client.SetRetry(1, time.Millisecond)
+	if _, err := client.Execute("query Busy { busy }", nil, "Busy"); err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if err := errs[2]; err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Expected the first attempt to fail with 503, got %v", err)
+	}
+
+	want := []int{http.StatusInternalServerError, http.StatusUnauthorized, http.StatusServiceUnavailable, http.StatusServiceUnavailable}
+	if fmt.Sprint(statusCodes) != fmt.Sprint(want) {
+		t.Errorf("Expected status codes %v, got %v", want, statusCodes)
+	}
+}
+
 // TestTestHelperBasic tests basic test helper functionality
 func TestTestHelperBasic(t *testing.T) {
 	// Test field path splitting
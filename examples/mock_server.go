@@ -5,22 +5,54 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/kluzzebass/gqlt"
 )
 
-// MockGraphQLServer provides a test GraphQL server
+// Request is a GraphQL request received by the mock server
+type Request struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+// ResponseHandler fills in the response to a request
+type ResponseHandler func(*gqlt.Response)
+
+// route fills in the response to a request and returns its HTTP status code
+type route func(*gqlt.Response) int
+
+// okRoute answers with the response filled in by handler and status 200
+func okRoute(handler ResponseHandler) route {
+	return func(response *gqlt.Response) int {
+		handler(response)
+		return http.StatusOK
+	}
+}
+
+// queryMatcher routes the requests it matches to its handler
+type queryMatcher struct {
+	match func(Request) bool
+	route route
+}
+
+// MockGraphQLServer provides a test GraphQL server that records the requests
+// it receives
 type MockGraphQLServer struct {
 	server   *httptest.Server
-	handlers map[string]func(*gqlt.Response) // operation name -> handler
+	mu       sync.Mutex
+	matchers []queryMatcher
+	handlers map[string]route // operation name -> handler
+	requests []Request
 }
 
 // NewMockGraphQLServer creates a new mock GraphQL server
 func NewMockGraphQLServer() *MockGraphQLServer {
 	mock := &MockGraphQLServer{
-		handlers: make(map[string]func(*gqlt.Response)),
+		handlers: make(map[string]route),
 	}
 
 	mock.server = httptest.NewServer(http.HandlerFunc(mock.handleRequest))
@@ -38,45 +70,141 @@ func (m *MockGraphQLServer) URL() string {
 }
 
 // AddHandler adds a handler for a specific operation
-func (m *MockGraphQLServer) AddHandler(operationName string, handler func(*gqlt.Response)) {
-	m.handlers[operationName] = handler
+func (m *MockGraphQLServer) AddHandler(operationName string, handler ResponseHandler) {
+	m.addRoute(operationName, okRoute(handler))
+}
+
+// AddStatusResponse answers a specific operation with the given HTTP status
+// code and body (an empty response if nil), e.g. to test how a client handles 401 or 500
+// responses
+func (m *MockGraphQLServer) AddStatusResponse(operationName string, statusCode int, body *gqlt.Response) {
+	m.addRoute(operationName, func(response *gqlt.Response) int {
+		if body != nil {
+			*response = *body
+		}
+		return statusCode
+	})
+}
+
+// addRoute sets the route for a specific operation
+func (m *MockGraphQLServer) addRoute(operationName string, r route) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers[operationName] = r
 }
 
 // AddDefaultHandler adds a default handler for unmatched operations
-func (m *MockGraphQLServer) AddDefaultHandler(handler func(*gqlt.Response)) {
-	m.handlers[""] = handler
+func (m *MockGraphQLServer) AddDefaultHandler(handler ResponseHandler) {
+	m.AddHandler("", handler)
+}
+
+// AddQueryMatcher adds a handler for the requests match accepts. Matchers are
+// tried in the order they were added, before the handlers for operation names,
+// so they can route e.g. anonymous queries by their content.
+func (m *MockGraphQLServer) AddQueryMatcher(match func(Request) bool, handler ResponseHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.matchers = append(m.matchers, queryMatcher{match: match, route: okRoute(handler)})
+}
+
+// AddSequence adds a handler for a specific operation that answers the Nth call
+// with the Nth response, repeating the last one once the sequence is used up.
+// This simulates e.g. a transient failure followed by success.
+func (m *MockGraphQLServer) AddSequence(operationName string, responses []*gqlt.Response) {
+	var mu sync.Mutex
+	calls := 0
+	m.AddHandler(operationName, func(response *gqlt.Response) {
+		if len(responses) == 0 {
+			return
+		}
+		mu.Lock()
+		next := responses[min(calls, len(responses)-1)]
+		calls++
+		mu.Unlock()
+		*response = *next
+	})
+}
+
+// GetRequestLog returns a copy of the requests received so far, oldest first
+func (m *MockGraphQLServer) GetRequestLog() []Request {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Request(nil), m.requests...)
+}
+
+// ClearRequestLog forgets the requests received so far
+func (m *MockGraphQLServer) ClearRequestLog() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = nil
+}
+
+// LastRequest returns the most recent request, if any was received
+func (m *MockGraphQLServer) LastRequest() (Request, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.requests) == 0 {
+		return Request{}, false
+	}
+	return m.requests[len(m.requests)-1], true
+}
+
+// RequestCount returns the number of requests received so far
+func (m *MockGraphQLServer) RequestCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.requests)
+}
+
+// AssertOperationCalled reports whether a request for the named operation was
+// received
+func (m *MockGraphQLServer) AssertOperationCalled(name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, request := range m.requests {
+		if request.OperationName == name {
+			return true
+		}
+	}
+	return false
 }
 
 // handleRequest handles incoming GraphQL requests
 func (m *MockGraphQLServer) handleRequest(w http.ResponseWriter, r *http.Request) {
 	// Parse GraphQL request
-	var request struct {
-		Query         string                 `json:"query"`
-		Variables     map[string]interface{} `json:"variables"`
-		OperationName string                 `json:"operationName"`
-	}
-
+	var request Request
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
 
-	// Find handler
+	// Record the request and find its handler
+	m.mu.Lock()
+	m.requests = append(m.requests, request)
+	matchers := m.matchers
 	handler, exists := m.handlers[request.OperationName]
 	if !exists {
 		handler, exists = m.handlers[""] // Default handler
-		if !exists {
-			http.Error(w, "No handler for operation", http.StatusNotFound)
-			return
+	}
+	m.mu.Unlock()
+	for _, matcher := range matchers {
+		if matcher.match(request) {
+			handler, exists = matcher.route, true
+			break
 		}
 	}
+	if !exists {
+		http.Error(w, "No handler for operation", http.StatusNotFound)
+		return
+	}
 
 	// Create response
 	response := &gqlt.Response{}
-	handler(response)
+	status := handler(response)
 
 	// Send response
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(response)
 }
 
@@ -151,6 +151,10 @@ func main() {
 		"Authorization: Bearer token",
 		"Content-Type: application/json",
 	}
-	headersMap := inputHandler.LoadHeaders(headersList)
-	fmt.Printf("Loaded headers: %+v\n", headersMap)
+	headersMap, err := inputHandler.LoadHeaders(headersList)
+	if err != nil {
+		log.Printf("Failed to load headers: %v", err)
+	} else {
+		fmt.Printf("Loaded headers: %+v\n", headersMap)
+	}
 }
@@ -0,0 +1,33 @@
+package gqlt
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// FormatIndent is the indentation used by FormatQuery for each nesting level
+const FormatIndent = "  "
+
+// FormatQuery parses a GraphQL document and re-prints it with consistent
+// indentation. Operations, fragments and fields keep their original order.
+// Comments are preserved, but a comment at the end of a line is moved onto its
+// own line before the next item. Formatting an already formatted document
+// returns it unchanged.
+//
+// Example:
+//
+//	pretty, err := gqlt.FormatQuery(`query GetUser($id:ID!){user(id:$id){id name}}`)
+func FormatQuery(src string) (string, error) {
+	doc, gqlErr := parser.ParseQuery(&ast.Source{Name: "query", Input: src})
+	if gqlErr != nil {
+		return "", fmt.Errorf("failed to parse GraphQL query: %w", gqlErr)
+	}
+
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf, formatter.WithIndent(FormatIndent), formatter.WithComments()).FormatQueryDocument(doc)
+	return buf.String(), nil
+}
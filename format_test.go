@@ -0,0 +1,57 @@
+package gqlt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatQuery(t *testing.T) {
+	minified := `query GetUser($id:ID!,$first:Int=10){user(id:$id){id name friends(first:$first)@include(if:true){...UserFields}}} fragment UserFields on User{id email}`
+
+	got, err := FormatQuery(minified)
+	if err != nil {
+		t.Fatalf("FormatQuery failed: %v", err)
+	}
+
+	want := `query GetUser ($id: ID!, $first: Int = 10) {
+  user(id: $id) {
+    id
+    name
+    friends(first: $first) @include(if: true) {
+      ... UserFields
+    }
+  }
+}
+fragment UserFields on User {
+  id
+  email
+}
+`
+	if got != want {
+		t.Errorf("Unexpected formatted query:\n%s\nwant:\n%s", got, want)
+	}
+
+	again, err := FormatQuery(got)
+	if err != nil {
+		t.Fatalf("FormatQuery failed on formatted input: %v", err)
+	}
+	if again != got {
+		t.Errorf("Expected formatting to be idempotent, second pass gave:\n%s", again)
+	}
+}
+
+func TestFormatQueryKeepsComments(t *testing.T) {
+	got, err := FormatQuery("# Fetch users\nquery Users{users{id # primary key\nname}}")
+	if err != nil {
+		t.Fatalf("FormatQuery failed: %v", err)
+	}
+	if !strings.Contains(got, "# Fetch users") || !strings.Contains(got, "# primary key") {
+		t.Errorf("Expected comments to be preserved, got:\n%s", got)
+	}
+}
+
+func TestFormatQuerySyntaxError(t *testing.T) {
+	if _, err := FormatQuery(`query { user { id name }`); err == nil {
+		t.Fatal("Expected error for unbalanced braces")
+	}
+}
@@ -232,8 +232,12 @@ Add to `~/.config/claude-desktop/config.json` (macOS/Linux) or `%APPDATA%\Claude
 
 **Available MCP Tools:**
 - `execute_query`: Run GraphQL queries, mutations, and subscriptions (supports file uploads)
+- `execute_batch`: Run several queries and mutations against one endpoint in one call, with per-operation timing and errors
 - `describe_type`: Analyze specific GraphQL types with detailed field information
 - `list_types`: List and filter GraphQL type names (supports regex patterns and kind filtering)
+- `search_fields`: Find which types have fields matching a name pattern (e.g. `email`, `createdAt`)
+- `compare_types`: Show fields only in one of two types and fields whose types differ
+- `introspect_schema`: Fetch the full schema as SDL or introspection JSON in one call
 - `version`: Get the current version of gqlt
 
 **File Upload Support:**
@@ -268,9 +272,27 @@ Subscriptions will:
 - Return each message as a complete GraphQL response
 - Clean up WebSocket connection on completion or error
 
+**Batch Execution:**
+The `execute_batch` tool runs operations in order against a shared endpoint and headers, saving round trips for multi-step plans:
+
+```json
+{
+  "endpoint": "https://api.example.com/graphql",
+  "headers": {"Authorization": "Bearer token"},
+  "operations": [
+    {"query": "{ me { id } }"},
+    {"query": "query($id: ID!) { user(id: $id) { name } }", "variables": {"id": "42"}}
+  ]
+}
+```
+
+Each result has the operation's `data`, `errors` and `elapsed_ms`, or an `error` if it couldn't be executed; a failed operation doesn't stop the ones after it. Subscriptions aren't supported in a batch.
+
 **Tool Parameters:**
 - Schema-related tools (`describe_type` and `list_types`) support `noCache` parameter to force fresh schema introspection
+- Introspected schemas are cached for 10 minutes; start the server with `--cache-dir` to keep them across restarts
 - `execute_query` supports `files` parameter for file uploads via local filesystem paths
+- `execute_query` supports `timeoutMs` parameter to bound queries and mutations (default: 30s)
 
 ### Mode 3: Go Library
 
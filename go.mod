@@ -4,9 +4,12 @@ go 1.25.1
 
 require (
 	github.com/99designs/gqlgen v0.17.81
+	github.com/gorilla/websocket v1.5.0
 	github.com/modelcontextprotocol/go-sdk v1.0.0
 	github.com/spf13/cobra v1.10.1
 	github.com/vektah/gqlparser/v2 v2.5.30
+	golang.org/x/oauth2 v0.36.0
+	golang.org/x/time v0.14.0
 	nhooyr.io/websocket v1.8.17
 )
 
@@ -16,7 +19,6 @@ require (
 	github.com/go-viper/mapstructure/v2 v2.4.0 // indirect
 	github.com/google/jsonschema-go v0.3.0 // indirect
 	github.com/google/uuid v1.6.0 // indirect
-	github.com/gorilla/websocket v1.5.0 // indirect
 	github.com/hashicorp/golang-lru/v2 v2.0.7 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/russross/blackfriday/v2 v2.1.0 // indirect
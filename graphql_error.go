@@ -0,0 +1,98 @@
+package gqlt
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// GraphQLError is an error in the errors list of a GraphQL response
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Locations  []Location             `json:"locations,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// Location is a position in a GraphQL document, counting lines and columns from 1
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Error returns the error's message
+func (e GraphQLError) Error() string {
+	return e.Message
+}
+
+// ParsedErrors returns the response's errors as GraphQLErrors. Errors keeps the
+// raw values; an error that isn't an object with a string message can't be parsed.
+//
+// Example:
+//
+//	errs, err := response.ParsedErrors()
+//	for _, e := range errs {
+//	    fmt.Println(e.Message, e.Path, e.Extensions["code"])
+//	}
+func (r *Response) ParsedErrors() ([]GraphQLError, error) {
+	if len(r.Errors) == 0 {
+		return nil, nil
+	}
+
+	parsed := make([]GraphQLError, 0, len(r.Errors))
+	for i, raw := range r.Errors {
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("error %d is not an object", i)
+		}
+		if _, ok := obj["message"].(string); !ok {
+			return nil, fmt.Errorf("error %d has no message", i)
+		}
+
+		content, err := json.Marshal(obj)
+		if err != nil {
+			return nil, fmt.Errorf("error %d: %w", i, err)
+		}
+		var gqlErr GraphQLError
+		if err := json.Unmarshal(content, &gqlErr); err != nil {
+			return nil, fmt.Errorf("error %d: %w", i, err)
+		}
+		parsed = append(parsed, gqlErr)
+	}
+	return parsed, nil
+}
+
+// ErrorCodes returns the distinct codes in the errors' extensions.code fields,
+// the convention many servers use for machine-readable errors (e.g.
+// UNAUTHENTICATED), in the order they first appear. Errors without a string
+// code are skipped.
+func (r *Response) ErrorCodes() []string {
+	var codes []string
+	seen := map[string]bool{}
+	for _, raw := range r.Errors {
+		obj, _ := raw.(map[string]interface{})
+		extensions, _ := obj["extensions"].(map[string]interface{})
+		code, _ := extensions["code"].(string)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+	return codes
+}
+
+// HasErrorCode reports whether any error has the given extensions.code
+//
+// Example:
+//
+//	if response.HasErrorCode("UNAUTHENTICATED") {
+//	    // refresh the token and retry
+//	}
+func (r *Response) HasErrorCode(code string) bool {
+	for _, c := range r.ErrorCodes() {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package gqlt
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestResponseParsedErrors(t *testing.T) {
+	var response Response
+	body := `{
+		"data": {"user": null},
+		"errors": [
+			{
+				"message": "User not found",
+				"path": ["user", "friends", 0, "name"],
+				"locations": [{"line": 2, "column": 3}, {"line": 5, "column": 7}],
+				"extensions": {"code": "NOT_FOUND", "retryable": false}
+			},
+			{"message": "Rate limited"}
+		]
+	}`
+	if err := json.Unmarshal([]byte(body), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	errs, err := response.ParsedErrors()
+	if err != nil {
+		t.Fatalf("ParsedErrors failed: %v", err)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 errors, got %d", len(errs))
+	}
+
+	want := GraphQLError{
+		Message:    "User not found",
+		Path:       []interface{}{"user", "friends", float64(0), "name"},
+		Locations:  []Location{{Line: 2, Column: 3}, {Line: 5, Column: 7}},
+		Extensions: map[string]interface{}{"code": "NOT_FOUND", "retryable": false},
+	}
+	if !reflect.DeepEqual(errs[0], want) {
+		t.Errorf("Expected %+v, got %+v", want, errs[0])
+	}
+	if errs[0].Error() != "User not found" {
+		t.Errorf("Expected the message as the error string, got %q", errs[0].Error())
+	}
+	if errs[1].Message != "Rate limited" || errs[1].Path != nil || errs[1].Locations != nil || errs[1].Extensions != nil {
+		t.Errorf("Expected only a message, got %+v", errs[1])
+	}
+
+	// The raw errors are kept
+	if len(response.Errors) != 2 {
+		t.Errorf("Expected the raw errors to be kept, got %v", response.Errors)
+	}
+}
+
+func TestResponseParsedErrorsInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		errors []interface{}
+	}{
+		{"not an object", []interface{}{"boom"}},
+		{"no message", []interface{}{map[string]interface{}{"path": []interface{}{"user"}}}},
+		{"invalid locations", []interface{}{map[string]interface{}{"message": "x", "locations": "line 1"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &Response{Errors: tt.errors}
+			if _, err := response.ParsedErrors(); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+
+	if errs, err := (&Response{}).ParsedErrors(); err != nil || errs != nil {
+		t.Errorf("Expected no errors for a response without errors, got %v, %v", errs, err)
+	}
+}
+
+func TestResponseErrorCodes(t *testing.T) {
+	response := &Response{Errors: []interface{}{
+		map[string]interface{}{"message": "Not logged in", "extensions": map[string]interface{}{"code": "UNAUTHENTICATED"}},
+		map[string]interface{}{"message": "No access", "extensions": map[string]interface{}{"code": "FORBIDDEN"}},
+		map[string]interface{}{"message": "Also not logged in", "extensions": map[string]interface{}{"code": "UNAUTHENTICATED"}},
+		map[string]interface{}{"message": "No code", "extensions": map[string]interface{}{"retryable": true}},
+		map[string]interface{}{"message": "Numeric code", "extensions": map[string]interface{}{"code": 42}},
+		map[string]interface{}{"message": "No extensions"},
+		"not an object",
+	}}
+
+	codes := response.ErrorCodes()
+	if !reflect.DeepEqual(codes, []string{"UNAUTHENTICATED", "FORBIDDEN"}) {
+		t.Errorf("Expected the distinct codes in order, got %v", codes)
+	}
+	if !response.HasErrorCode("FORBIDDEN") {
+		t.Error("Expected HasErrorCode to find FORBIDDEN")
+	}
+	if response.HasErrorCode("NOT_FOUND") {
+		t.Error("Expected HasErrorCode not to find NOT_FOUND")
+	}
+
+	empty := &Response{Data: map[string]interface{}{"ok": true}}
+	if codes := empty.ErrorCodes(); codes != nil {
+		t.Errorf("Expected no codes for a response without errors, got %v", codes)
+	}
+	if empty.HasErrorCode("UNAUTHENTICATED") {
+		t.Error("Expected HasErrorCode to be false without errors")
+	}
+}
@@ -0,0 +1,76 @@
+package gqlt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// headerTemplateFuncs are the functions available in header value templates
+var headerTemplateFuncs = template.FuncMap{
+	"now":  func() string { return time.Now().UTC().Format(time.RFC3339) },
+	"uuid": newRequestID,
+	"hmac": func(message, key string) string {
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(message))
+		return hex.EncodeToString(mac.Sum(nil))
+	},
+}
+
+// headerTemplateData is the data header value templates are evaluated with
+type headerTemplateData struct {
+	Body string // The GraphQL request as JSON (its operations part for uploads)
+}
+
+// expandHeader evaluates a header value containing template actions, so
+// headers can carry values computed for each request. The available functions
+// are {{now}} (the current time in RFC 3339 format), {{uuid}} (a random UUID)
+// and {{hmac message key}} (the hex-encoded HMAC-SHA256 of message), and
+// {{.Body}} is the request body, so {{hmac .Body "secret"}} signs the request.
+// Values without "{{" are returned unchanged.
+func expandHeader(name, value string, body []byte) (string, error) {
+	if !strings.Contains(value, "{{") {
+		return value, nil
+	}
+	tmpl, err := template.New(name).Funcs(headerTemplateFuncs).Parse(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid template in header %s: %w", name, err)
+	}
+	var expanded strings.Builder
+	if err := tmpl.Execute(&expanded, headerTemplateData{Body: string(body)}); err != nil {
+		return "", fmt.Errorf("failed to expand header %s: %w", name, err)
+	}
+	return expanded.String(), nil
+}
+
+// expandHeaders returns headers with the templates in their values expanded
+// (see expandHeader) for the given request body
+func expandHeaders(headers map[string]string, body []byte) (map[string]string, error) {
+	expanded := make(map[string]string, len(headers))
+	for k, v := range headers {
+		value, err := expandHeader(k, v, body)
+		if err != nil {
+			return nil, err
+		}
+		expanded[k] = value
+	}
+	return expanded, nil
+}
+
+// setHeaders adds the client's headers to a request, expanding templates in
+// their values for the given request body
+func (c *Client) setHeaders(req *http.Request, body []byte) error {
+	headers, err := expandHeaders(c.headers, body)
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return nil
+}
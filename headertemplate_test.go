@@ -0,0 +1,91 @@
+package gqlt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestClient_HeaderTemplates(t *testing.T) {
+	var received []http.Header
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, r.Header.Clone())
+		bodies = append(bodies, string(body))
+		w.Write([]byte(`{"data": {"ok": true}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, map[string]string{
+		"X-Request-Nonce": "{{uuid}}",
+		"X-Timestamp":     "{{now}}",
+		"X-Signature":     `{{hmac .Body "secret"}}`,
+		"X-Fixed":         `{{hmac "body" "key"}}`,
+		"X-Plain":         "plain value",
+	})
+
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	seen := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		if _, err := client.Execute(`{ ok }`, nil, ""); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		header := received[len(received)-1]
+
+		nonce := header.Get("X-Request-Nonce")
+		if !uuid.MatchString(nonce) {
+			t.Errorf("Expected a UUID, got %q", nonce)
+		}
+		if seen[nonce] {
+			t.Errorf("Expected a unique UUID per request, got %q twice", nonce)
+		}
+		seen[nonce] = true
+
+		timestamp, err := time.Parse(time.RFC3339, header.Get("X-Timestamp"))
+		if err != nil {
+			t.Errorf("Expected an RFC3339 timestamp: %v", err)
+		} else if time.Since(timestamp) > time.Minute {
+			t.Errorf("Expected the current time, got %v", timestamp)
+		}
+
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(bodies[len(bodies)-1]))
+		if signature := header.Get("X-Signature"); signature != hex.EncodeToString(mac.Sum(nil)) {
+			t.Errorf("Expected the signature of the request body, got %q", signature)
+		}
+
+		mac = hmac.New(sha256.New, []byte("key"))
+		mac.Write([]byte("body"))
+		if fixed := header.Get("X-Fixed"); fixed != hex.EncodeToString(mac.Sum(nil)) {
+			t.Errorf("Expected the HMAC of \"body\", got %q", fixed)
+		}
+
+		if plain := header.Get("X-Plain"); plain != "plain value" {
+			t.Errorf("Expected the plain value unchanged, got %q", plain)
+		}
+	}
+}
+
+func TestClient_HeaderTemplatesInvalid(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"data": {"ok": true}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, map[string]string{"X-Signature": "{{hmac}"})
+	if _, err := client.Execute(`{ ok }`, nil, ""); err == nil {
+		t.Error("Expected an error for an invalid header template")
+	}
+	if requests != 0 {
+		t.Errorf("Expected no request to be sent, got %d", requests)
+	}
+}
@@ -0,0 +1,291 @@
+package gqlt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+)
+
+// IncrementalAccept is the Accept header ExecuteIncremental sends to ask for
+// incremental delivery of @defer and @stream results
+const IncrementalAccept = "multipart/mixed; deferSpec=20220824, application/json"
+
+// ExecuteIncremental executes an operation that uses @defer or @stream and
+// delivers its results as they arrive. The server is asked for an incremental
+// multipart/mixed response; every part is merged into the payload received so
+// far, and a snapshot of the merged response is sent on the returned channel,
+// with HasNext set while more parts are expected. Servers that answer with a
+// plain JSON response produce a single message. Both channels are closed once
+// the response is complete, the context is cancelled or an error has been sent.
+//
+// Example:
+//
+//	responses, errs, err := client.ExecuteIncremental(ctx,
+//	    `query { user(id: 1) { name ... @defer { friends { name } } } }`, nil, "")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for response := range responses {
+//	    fmt.Printf("%v (more: %v)\n", response.Data, response.HasNext)
+//	}
+//	if err := <-errs; err != nil {
+//	    log.Fatal(err)
+//	}
+func (c *Client) ExecuteIncremental(ctx context.Context, query string, variables map[string]interface{}, operationName string) (<-chan *Response, <-chan error, error) {
+	req, bodySize, err := c.newRequest(ctx, query, variables, operationName)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", IncrementalAccept)
+
+	responses := make(chan *Response, 10)
+	errors := make(chan error, 1)
+
+	go func() {
+		defer close(responses)
+		defer close(errors)
+
+		deliver := func(response *Response) error {
+			snapshot := snapshotResponse(response)
+			snapshot.RequestID = c.requestID(req)
+			select {
+			case responses <- snapshot:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		_, err := c.send(req, func() int { return bodySize }, func(body io.Reader, header http.Header) error {
+			if boundary, ok := multipartBoundary(header); ok {
+				return readIncremental(body, boundary, &Response{}, deliver)
+			}
+
+			var result Response
+			if err := json.NewDecoder(body).Decode(&result); err != nil {
+				return fmt.Errorf("failed to parse GraphQL response: %w", err)
+			}
+			return deliver(&result)
+		})
+		if err != nil && ctx.Err() == nil {
+			errors <- err
+		}
+	}()
+
+	return responses, errors, nil
+}
+
+// multipartBoundary returns the boundary of a multipart/mixed response, which
+// defaults to "-" as in the incremental delivery specification
+func multipartBoundary(header http.Header) (string, bool) {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		return "", false
+	}
+	if boundary := params["boundary"]; boundary != "" {
+		return boundary, true
+	}
+	return "-", true
+}
+
+// incrementalPayload is a part of an incremental delivery response, or an entry
+// of its "incremental" list. Parts carrying a path themselves follow the older
+// format where each part is a single patch.
+type incrementalPayload struct {
+	Data        interface{}            `json:"data"`
+	Items       []interface{}          `json:"items"`
+	Path        []interface{}          `json:"path"`
+	Errors      []interface{}          `json:"errors"`
+	Extensions  map[string]interface{} `json:"extensions"`
+	HasNext     *bool                  `json:"hasNext"`
+	Incremental []incrementalPayload   `json:"incremental"`
+}
+
+// readIncremental reads the parts of a multipart/mixed incremental response,
+// merging each into result and passing the merged result to onPart (if set)
+// after every part. Empty keep-alive parts are skipped.
+func readIncremental(body io.Reader, boundary string, result *Response, onPart func(*Response) error) error {
+	reader := multipart.NewReader(body, boundary)
+	initial := true
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read incremental response: %w", err)
+		}
+
+		var payload incrementalPayload
+		err = json.NewDecoder(part).Decode(&payload)
+		part.Close()
+		if err == io.EOF {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to parse incremental response part: %w", err)
+		}
+		if payload.HasNext == nil && payload.Data == nil && payload.Path == nil && len(payload.Incremental) == 0 && len(payload.Errors) == 0 {
+			continue
+		}
+
+		if initial && payload.Path == nil {
+			result.Data = payload.Data
+			result.Errors = append(result.Errors, payload.Errors...)
+			initial = false
+		} else if err := mergeIncremental(result, payload); err != nil {
+			return err
+		}
+		for key, value := range payload.Extensions {
+			if result.Extensions == nil {
+				result.Extensions = make(map[string]interface{})
+			}
+			result.Extensions[key] = value
+		}
+		result.HasNext = payload.HasNext != nil && *payload.HasNext
+
+		if onPart != nil {
+			if err := onPart(result); err != nil {
+				return err
+			}
+		}
+		if !result.HasNext {
+			return nil
+		}
+	}
+}
+
+// mergeIncremental applies the patches of a subsequent part to result
+func mergeIncremental(result *Response, payload incrementalPayload) error {
+	patches := payload.Incremental
+	if payload.Path != nil {
+		patches = []incrementalPayload{payload}
+	} else {
+		result.Errors = append(result.Errors, payload.Errors...)
+	}
+
+	for _, patch := range patches {
+		result.Errors = append(result.Errors, patch.Errors...)
+
+		var err error
+		switch {
+		case patch.Items != nil:
+			// Streamed items continue the list at the path's parent
+			if len(patch.Path) == 0 {
+				return fmt.Errorf("incremental items without a path")
+			}
+			result.Data, err = patchAt(result.Data, patch.Path[:len(patch.Path)-1], func(node interface{}) (interface{}, error) {
+				list, ok := node.([]interface{})
+				if !ok && node != nil {
+					return nil, fmt.Errorf("incremental items for %v target a %s, not a list", patch.Path, jsonKind(node))
+				}
+				return append(list, patch.Items...), nil
+			})
+		case patch.Data != nil:
+			fields, ok := patch.Data.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("incremental data for %v is a %s, not an object", patch.Path, jsonKind(patch.Data))
+			}
+			result.Data, err = patchAt(result.Data, patch.Path, func(node interface{}) (interface{}, error) {
+				object, ok := node.(map[string]interface{})
+				if !ok && node != nil {
+					return nil, fmt.Errorf("incremental data for %v targets a %s, not an object", patch.Path, jsonKind(node))
+				}
+				if object == nil {
+					object = make(map[string]interface{})
+				}
+				mergeObjects(object, fields)
+				return object, nil
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// patchAt replaces the value at path in node with the result of apply, returning
+// the updated node
+func patchAt(node interface{}, path []interface{}, apply func(interface{}) (interface{}, error)) (interface{}, error) {
+	if len(path) == 0 {
+		return apply(node)
+	}
+
+	switch key := path[0].(type) {
+	case string:
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("incremental path segment %q does not point into an object", key)
+		}
+		child, err := patchAt(object[key], path[1:], apply)
+		if err != nil {
+			return nil, err
+		}
+		object[key] = child
+		return object, nil
+	case float64:
+		list, ok := node.([]interface{})
+		index := int(key)
+		if !ok || index < 0 || index >= len(list) {
+			return nil, fmt.Errorf("incremental path index %d is not in a list", index)
+		}
+		child, err := patchAt(list[index], path[1:], apply)
+		if err != nil {
+			return nil, err
+		}
+		list[index] = child
+		return list, nil
+	default:
+		return nil, fmt.Errorf("invalid incremental path segment %v", key)
+	}
+}
+
+// mergeObjects deep-merges the fields of src into dst
+func mergeObjects(dst, src map[string]interface{}) {
+	for key, value := range src {
+		if srcObject, ok := value.(map[string]interface{}); ok {
+			if dstObject, ok := dst[key].(map[string]interface{}); ok {
+				mergeObjects(dstObject, srcObject)
+				continue
+			}
+		}
+		dst[key] = value
+	}
+}
+
+// snapshotResponse copies a response being merged, so later parts don't change
+// responses already delivered
+func snapshotResponse(response *Response) *Response {
+	snapshot := *response
+	snapshot.Data = cloneJSON(response.Data)
+	snapshot.Errors = append([]interface{}(nil), response.Errors...)
+	if response.Extensions != nil {
+		snapshot.Extensions, _ = cloneJSON(response.Extensions).(map[string]interface{})
+	}
+	return &snapshot
+}
+
+// cloneJSON deep-copies decoded JSON objects and lists
+func cloneJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		clone := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			clone[key] = cloneJSON(item)
+		}
+		return clone
+	case []interface{}:
+		clone := make([]interface{}, len(v))
+		for i, item := range v {
+			clone[i] = cloneJSON(item)
+		}
+		return clone
+	default:
+		return v
+	}
+}
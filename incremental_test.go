@@ -0,0 +1,133 @@
+package gqlt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// writeIncremental writes a multipart/mixed incremental response with the given parts
+func writeIncremental(w http.ResponseWriter, parts ...string) {
+	w.Header().Set("Content-Type", `multipart/mixed; boundary="-"; deferSpec=20220824`)
+	for _, part := range parts {
+		fmt.Fprintf(w, "\r\n---\r\nContent-Type: application/json; charset=utf-8\r\n\r\n%s", part)
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+	fmt.Fprint(w, "\r\n-----\r\n")
+}
+
+func TestClient_ExecuteIncremental(t *testing.T) {
+	var accept string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		writeIncremental(w,
+			`{"data": {"user": {"name": "Ada"}}, "hasNext": true}`,
+			`{"incremental": [{"data": {"friends": [{"name": "Grace"}]}, "path": ["user"]}], "hasNext": false}`,
+		)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	responses, errs, err := client.ExecuteIncremental(context.Background(),
+		`query { user { name ... @defer { friends { name } } } }`, nil, "")
+	if err != nil {
+		t.Fatalf("ExecuteIncremental failed: %v", err)
+	}
+
+	var received []*Response
+	for response := range responses {
+		received = append(received, response)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if accept != IncrementalAccept {
+		t.Errorf("Expected Accept %q, got %q", IncrementalAccept, accept)
+	}
+	if len(received) != 2 {
+		t.Fatalf("Expected 2 responses, got %d", len(received))
+	}
+
+	initial := map[string]interface{}{"user": map[string]interface{}{"name": "Ada"}}
+	if !reflect.DeepEqual(received[0].Data, initial) || !received[0].HasNext {
+		t.Errorf("Expected the initial payload with more to come, got %v (hasNext %v)", received[0].Data, received[0].HasNext)
+	}
+	merged := map[string]interface{}{"user": map[string]interface{}{
+		"name":    "Ada",
+		"friends": []interface{}{map[string]interface{}{"name": "Grace"}},
+	}}
+	if !reflect.DeepEqual(received[1].Data, merged) || received[1].HasNext {
+		t.Errorf("Expected the patch merged into the payload, got %v (hasNext %v)", received[1].Data, received[1].HasNext)
+	}
+}
+
+func TestClient_ExecuteMergesIncremental(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeIncremental(w,
+			`{"data": {"posts": [{"id": "1"}]}, "hasNext": true}`,
+			`{}`,
+			`{"incremental": [{"items": [{"id": "2"}, {"id": "3"}], "path": ["posts", 1]}], "hasNext": true}`,
+			`{"incremental": [{"data": null, "path": ["posts", 0], "errors": [{"message": "deferred field failed"}]}], "hasNext": false}`,
+		)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	result, err := client.Execute(`query { posts @stream(initialCount: 1) { id } }`, nil, "")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	expected := map[string]interface{}{"posts": []interface{}{
+		map[string]interface{}{"id": "1"},
+		map[string]interface{}{"id": "2"},
+		map[string]interface{}{"id": "3"},
+	}}
+	if !reflect.DeepEqual(result.Data, expected) {
+		t.Errorf("Expected streamed items to be appended, got %v", result.Data)
+	}
+	if len(result.Errors) != 1 {
+		t.Errorf("Expected the patch error to be collected, got %v", result.Errors)
+	}
+	if result.HasNext {
+		t.Error("Expected the merged response to be complete")
+	}
+}
+
+func TestClient_ExecuteIncrementalPlainJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"ok": true}}`))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := NewClient(server.URL, nil)
+	responses, errs, err := client.ExecuteIncremental(ctx, `{ ok }`, nil, "")
+	if err != nil {
+		t.Fatalf("ExecuteIncremental failed: %v", err)
+	}
+
+	count := 0
+	for response := range responses {
+		count++
+		if response.HasNext || response.Data == nil {
+			t.Errorf("Expected a single complete response, got %+v", response)
+		}
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 response, got %d", count)
+	}
+}
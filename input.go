@@ -27,6 +27,11 @@ func NewInput() *Input {
 // If queryFile is provided, it reads and returns the file contents.
 // If both are provided, query takes precedence.
 //
+// A query file may import other files with "# import <path>" comment lines at
+// its top, before the first definition. Paths are relative to the importing
+// file. Imported files are appended to the query (recursively, each file at most
+// once), so shared fragments can live in their own files.
+//
 // Example:
 //
 //	query, err := input.LoadQuery("", "query.graphql")
@@ -39,16 +44,97 @@ func (i *Input) LoadQuery(query, queryFile string) (string, error) {
 	}
 
 	if queryFile != "" {
-		data, err := os.ReadFile(queryFile)
-		if err != nil {
-			return "", fmt.Errorf("failed to read query file: %w", err)
+		var sb strings.Builder
+		if err := i.loadQueryFile(queryFile, nil, make(map[string]bool), &sb); err != nil {
+			return "", err
 		}
-		return string(data), nil
+		return sb.String(), nil
 	}
 
 	return "", fmt.Errorf("either query or queryFile must be provided")
 }
 
+// LoadOperation loads a query file like LoadQuery and returns only the named
+// operation and the fragments it uses (see ExtractOperation), for files that
+// define several operations. An empty name selects the file's only operation.
+//
+// Example:
+//
+//	query, err := input.LoadOperation("operations.graphql", "GetUser")
+func (i *Input) LoadOperation(queryFile, operationName string) (string, error) {
+	query, err := i.LoadQuery("", queryFile)
+	if err != nil {
+		return "", err
+	}
+	return ExtractOperation(query, operationName)
+}
+
+// loadQueryFile appends a query file and the files it imports to sb. The stack of
+// files being imported is used to detect cycles and loaded tracks files that have
+// already been included.
+func (i *Input) loadQueryFile(path string, stack []string, loaded map[string]bool, sb *strings.Builder) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve absolute path for '%s': %v", path, err)
+	}
+	for idx, importing := range stack {
+		if importing == absPath {
+			return fmt.Errorf("import cycle: %s", strings.Join(append(stack[idx:], absPath), " -> "))
+		}
+	}
+	if loaded[absPath] {
+		return nil
+	}
+	loaded[absPath] = true
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if len(stack) > 0 {
+			return fmt.Errorf("failed to read file imported from %s: %w", stack[len(stack)-1], err)
+		}
+		return fmt.Errorf("failed to read query file: %w", err)
+	}
+
+	if written := sb.String(); written != "" && !strings.HasSuffix(written, "\n") {
+		sb.WriteByte('\n')
+	}
+	sb.Write(data)
+
+	for _, imported := range queryImports(string(data)) {
+		imported, err := expandHomeDir(imported)
+		if err != nil {
+			return err
+		}
+		if !filepath.IsAbs(imported) {
+			imported = filepath.Join(filepath.Dir(absPath), imported)
+		}
+		if err := i.loadQueryFile(imported, append(stack, absPath), loaded, sb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryImports returns the paths of the "# import <path>" lines in the leading
+// comment block of a query file
+func queryImports(query string) []string {
+	var imports []string
+	for _, line := range strings.Split(query, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, "#"))
+		if len(fields) == 2 && fields[0] == "import" {
+			imports = append(imports, strings.Trim(fields[1], `"'`))
+		}
+	}
+	return imports
+}
+
 // LoadVariables loads GraphQL variables from a JSON string or file.
 // If vars is provided, it parses the JSON string directly.
 // If varsFile is provided, it reads and parses the file contents.
@@ -87,18 +173,29 @@ func (i *Input) LoadVariables(vars, varsFile string) (map[string]interface{}, er
 }
 
 // LoadHeaders parses header strings into a map.
-// Each header string should be in the format "Key: Value".
+// Each header string should be in the format "Key: Value". A header string of the
+// form "@path" reads additional headers from a file instead, one "Key: Value" per
+// line; blank lines and lines starting with # are skipped and ~ is expanded.
+// Inline headers that can't be parsed are ignored, while malformed lines in a
+// header file are reported with their line number.
 //
 // Example:
 //
-//	headers := input.LoadHeaders([]string{
+//	headers, err := input.LoadHeaders([]string{
 //	    "Authorization: Bearer token",
-//	    "Content-Type: application/json",
+//	    "@headers.txt",
 //	})
-func (i *Input) LoadHeaders(headers []string) map[string]string {
+func (i *Input) LoadHeaders(headers []string) (map[string]string, error) {
 	headersMap := make(map[string]string)
 
 	for _, header := range headers {
+		if strings.HasPrefix(header, "@") {
+			if err := i.loadHeadersFile(strings.TrimPrefix(header, "@"), headersMap); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
 		parts := strings.SplitN(header, ":", 2)
 		if len(parts) == 2 {
 			key := strings.TrimSpace(parts[0])
@@ -107,7 +204,35 @@ func (i *Input) LoadHeaders(headers []string) map[string]string {
 		}
 	}
 
-	return headersMap
+	return headersMap, nil
+}
+
+// loadHeadersFile reads "Key: Value" lines from a header file into headersMap
+func (i *Input) loadHeadersFile(path string, headersMap map[string]string) error {
+	path, err := expandHomeDir(path)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read headers file: %w", err)
+	}
+
+	for lineNum, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue // Skip empty lines and comments
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return fmt.Errorf("invalid header format at line %d of %s: '%s', expected 'Key: Value'", lineNum+1, path, line)
+		}
+		headersMap[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+
+	return nil
 }
 
 // ParseFiles parses file upload specifications
@@ -192,18 +317,9 @@ func (i *Input) resolveFilePath(line string) (string, error) {
 	path := strings.TrimSpace(parts[1])
 
 	// Handle ~ expansion
-	if strings.HasPrefix(path, "~/") {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return "", fmt.Errorf("failed to get home directory: %v", err)
-		}
-		path = filepath.Join(homeDir, path[2:])
-	} else if path == "~" {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return "", fmt.Errorf("failed to get home directory: %v", err)
-		}
-		path = homeDir
+	path, err := expandHomeDir(path)
+	if err != nil {
+		return "", err
 	}
 
 	// Resolve relative paths to absolute paths
@@ -214,3 +330,18 @@ func (i *Input) resolveFilePath(line string) (string, error) {
 
 	return name + "=" + absPath, nil
 }
+
+// expandHomeDir replaces a leading ~ in a path with the user's home directory
+func expandHomeDir(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %v", err)
+	}
+	if path == "~" {
+		return homeDir, nil
+	}
+	return filepath.Join(homeDir, path[2:]), nil
+}
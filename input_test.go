@@ -3,6 +3,7 @@ package gqlt
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -89,6 +90,114 @@ func TestInput_LoadQuery(t *testing.T) {
 	}
 }
 
+func TestInput_LoadQueryImports(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		return path
+	}
+
+	writeFile("fragments/user.graphql", "# import ./common.graphql\nfragment UserFields on User { id name ...Timestamps }")
+	writeFile("fragments/common.graphql", "fragment Timestamps on Node { createdAt }")
+	writeFile("fragments/post.graphql", "# import \"common.graphql\"\nfragment PostFields on Post { title ...Timestamps }")
+	queryFile := writeFile("query.graphql", `# import ./fragments/user.graphql
+# import ./fragments/post.graphql
+
+query Feed { me { ...UserFields } posts { ...PostFields } }`)
+
+	input := NewInput()
+	got, err := input.LoadQuery("", queryFile)
+	if err != nil {
+		t.Fatalf("LoadQuery failed: %v", err)
+	}
+
+	if !strings.HasPrefix(got, "# import ./fragments/user.graphql") {
+		t.Errorf("Expected the query file to come first, got:\n%s", got)
+	}
+	for _, fragment := range []string{"fragment UserFields", "fragment PostFields", "fragment Timestamps"} {
+		if strings.Count(got, fragment) != 1 {
+			t.Errorf("Expected %q to be included once, got:\n%s", fragment, got)
+		}
+	}
+
+	// Files without imports are returned unchanged
+	plain := writeFile("plain.graphql", "{ version }")
+	if got, err := input.LoadQuery("", plain); err != nil || got != "{ version }" {
+		t.Errorf("Expected plain file unchanged, got %q (err %v)", got, err)
+	}
+
+	// Import lines after the first definition are ignored
+	late := writeFile("late.graphql", "{ version }\n# import ./missing.graphql\n")
+	if _, err := input.LoadQuery("", late); err != nil {
+		t.Errorf("Expected import after a definition to be ignored, got %v", err)
+	}
+}
+
+func TestInput_LoadOperation(t *testing.T) {
+	dir := t.TempDir()
+	queryFile := filepath.Join(dir, "operations.graphql")
+	content := `query ListUsers { users { ...UserFields } }
+query GetUser($id: ID!) { user(id: $id) { id } }
+fragment UserFields on User { id name }`
+	if err := os.WriteFile(queryFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write query file: %v", err)
+	}
+
+	input := NewInput()
+	got, err := input.LoadOperation(queryFile, "GetUser")
+	if err != nil {
+		t.Fatalf("LoadOperation failed: %v", err)
+	}
+	if !strings.Contains(got, "query GetUser") || strings.Contains(got, "ListUsers") || strings.Contains(got, "UserFields") {
+		t.Errorf("Expected only GetUser, got:\n%s", got)
+	}
+
+	got, err = input.LoadOperation(queryFile, "ListUsers")
+	if err != nil {
+		t.Fatalf("LoadOperation failed: %v", err)
+	}
+	if !strings.Contains(got, "query ListUsers") || !strings.Contains(got, "fragment UserFields") || strings.Contains(got, "GetUser") {
+		t.Errorf("Expected ListUsers and its fragment, got:\n%s", got)
+	}
+
+	if _, err := input.LoadOperation(queryFile, "Missing"); err == nil {
+		t.Error("Expected an error for an unknown operation")
+	}
+	if _, err := input.LoadOperation(filepath.Join(dir, "missing.graphql"), "GetUser"); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestInput_LoadQueryImportErrors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		return path
+	}
+
+	write("a.graphql", "# import ./b.graphql\nfragment A on User { id }")
+	write("b.graphql", "# import ./a.graphql\nfragment B on User { id }")
+	cyclic := write("cyclic.graphql", "# import ./a.graphql\n{ me { ...A } }")
+	missing := write("missing.graphql", "# import ./nope.graphql\n{ me { id } }")
+
+	input := NewInput()
+	if _, err := input.LoadQuery("", cyclic); err == nil || !strings.Contains(err.Error(), "import cycle") || !strings.Contains(err.Error(), "a.graphql -> ") {
+		t.Errorf("Expected import cycle error, got %v", err)
+	}
+	if _, err := input.LoadQuery("", missing); err == nil || !strings.Contains(err.Error(), "imported from "+missing) {
+		t.Errorf("Expected missing import error naming the importing file, got %v", err)
+	}
+}
+
 func TestInput_LoadVariables(t *testing.T) {
 	input := NewInput()
 
@@ -189,7 +298,10 @@ func TestInput_LoadHeaders(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := input.LoadHeaders(tt.headers)
+			got, err := input.LoadHeaders(tt.headers)
+			if err != nil {
+				t.Fatalf("LoadHeaders() error = %v", err)
+			}
 			if len(got) != len(tt.want) {
 				t.Errorf("LoadHeaders() got %d headers, want %d", len(got), len(tt.want))
 			}
@@ -304,3 +416,70 @@ func TestInput_ParseFilesFromList(t *testing.T) {
 		})
 	}
 }
+
+func TestInput_LoadHeaders_File(t *testing.T) {
+	input := NewInput()
+
+	headersFile := filepath.Join(t.TempDir(), "headers.txt")
+	content := "# Common headers\nAuthorization: Bearer file-token\n\nX-Request-Source: gqlt\nX-Empty:\n"
+	if err := os.WriteFile(headersFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write headers file: %v", err)
+	}
+
+	got, err := input.LoadHeaders([]string{"@" + headersFile, "Authorization: Bearer inline-token", "X-Trace: 1"})
+	if err != nil {
+		t.Fatalf("LoadHeaders() error = %v", err)
+	}
+
+	want := map[string]string{
+		// Later headers override earlier ones
+		"Authorization":    "Bearer inline-token",
+		"X-Request-Source": "gqlt",
+		"X-Empty":          "",
+		"X-Trace":          "1",
+	}
+	if len(got) != len(want) {
+		t.Errorf("LoadHeaders() got %d headers, want %d: %v", len(got), len(want), got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("LoadHeaders() header %s = %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestInput_LoadHeaders_FileErrors(t *testing.T) {
+	input := NewInput()
+
+	malformed := filepath.Join(t.TempDir(), "headers.txt")
+	if err := os.WriteFile(malformed, []byte("Authorization: Bearer token\n# comment\nNotAHeader\n"), 0644); err != nil {
+		t.Fatalf("Failed to write headers file: %v", err)
+	}
+
+	_, err := input.LoadHeaders([]string{"@" + malformed})
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Expected error mentioning line 3, got %v", err)
+	}
+
+	if _, err := input.LoadHeaders([]string{"@" + filepath.Join(t.TempDir(), "missing.txt")}); err == nil {
+		t.Error("Expected error for missing headers file")
+	}
+}
+
+func TestInput_LoadHeaders_FileHomeDir(t *testing.T) {
+	input := NewInput()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.WriteFile(filepath.Join(home, "headers.txt"), []byte("X-Home: yes\n"), 0644); err != nil {
+		t.Fatalf("Failed to write headers file: %v", err)
+	}
+
+	got, err := input.LoadHeaders([]string{"@~/headers.txt"})
+	if err != nil {
+		t.Fatalf("LoadHeaders() error = %v", err)
+	}
+	if got["X-Home"] != "yes" {
+		t.Errorf("Expected header from home directory file, got %v", got)
+	}
+}
@@ -14,3 +14,10 @@ func NewResolver() *Resolver {
 		store: NewStore(),
 	}
 }
+
+// NewResolverWithStore creates a new Resolver serving the data in store
+func NewResolverWithStore(store *Store) *Resolver {
+	return &Resolver{
+		store: store,
+	}
+}
@@ -0,0 +1,208 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kluzzebass/gqlt/internal/mockserver/graph/model"
+)
+
+// SeedData maps a type name to the entities of that type to load into a store,
+// each given as an object with the type's fields
+type SeedData map[string][]map[string]interface{}
+
+// seedOrder is the order types are seeded in, so todos can reference the users
+// and attachments seeded before them
+var seedOrder = []string{"User", "FileAttachment", "LinkAttachment", "Todo"}
+
+// seedTypes maps each seedable type to its model struct
+var seedTypes = map[string]reflect.Type{
+	"User":           reflect.TypeOf(model.User{}),
+	"Todo":           reflect.TypeOf(model.Todo{}),
+	"FileAttachment": reflect.TypeOf(model.FileAttachment{}),
+	"LinkAttachment": reflect.TypeOf(model.LinkAttachment{}),
+}
+
+// LoadSeedFile reads seed data from a JSON file mapping type names to arrays of
+// objects
+func LoadSeedFile(path string) (SeedData, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seed file: %w", err)
+	}
+
+	var data SeedData
+	if err := json.Unmarshal(content, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse seed file: %w", err)
+	}
+	return data, nil
+}
+
+// NewSeededStore creates a new Store holding only the given seed data instead
+// of the sample users
+func NewSeededStore(data SeedData) (*Store, error) {
+	s := newEmptyStore()
+	if err := s.Seed(data); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Seed adds the entities in data to the store. Every field must exist on its
+// type. IDs are assigned in order ("User:1", "User:2", ...), so a todo's
+// createdBy and assignedTo fields take the ID of a user and its attachments
+// field a list of attachment IDs.
+func (s *Store) Seed(data SeedData) error {
+	for typeName := range data {
+		if _, ok := seedTypes[typeName]; !ok {
+			return fmt.Errorf("unknown seed type '%s' (expected one of %s)", typeName, strings.Join(seedOrder, ", "))
+		}
+	}
+
+	for _, typeName := range seedOrder {
+		for i, fields := range data[typeName] {
+			if err := s.seedEntity(typeName, fields); err != nil {
+				return fmt.Errorf("%s[%d]: %w", typeName, i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// seedEntity validates the fields of one seed object and creates the entity
+func (s *Store) seedEntity(typeName string, fields map[string]interface{}) error {
+	known := jsonFieldNames(seedTypes[typeName])
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !known[name] {
+			return fmt.Errorf("type '%s' has no field '%s'", typeName, name)
+		}
+		if name == "id" {
+			return fmt.Errorf("field 'id' can't be seeded, IDs are assigned automatically")
+		}
+	}
+
+	// Relationships are given as IDs and resolved once the rest is decoded
+	references := map[string]interface{}{}
+	if typeName == "Todo" {
+		for _, name := range []string{"createdBy", "assignedTo", "attachments"} {
+			if value, ok := fields[name]; ok {
+				references[name] = value
+				delete(fields, name)
+			}
+		}
+	}
+
+	content, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	switch typeName {
+	case "User":
+		user := &model.User{CreatedAt: now, Role: model.UserRoleUser}
+		if err := json.Unmarshal(content, user); err != nil {
+			return err
+		}
+		id, created := s.users.Create(user)
+		created.ID = id
+	case "FileAttachment":
+		attachment := &model.FileAttachment{CreatedAt: now}
+		if err := json.Unmarshal(content, attachment); err != nil {
+			return err
+		}
+		id, created := s.fileAttachments.Create(attachment)
+		created.ID = id
+	case "LinkAttachment":
+		attachment := &model.LinkAttachment{CreatedAt: now}
+		if err := json.Unmarshal(content, attachment); err != nil {
+			return err
+		}
+		id, created := s.linkAttachments.Create(attachment)
+		created.ID = id
+	case "Todo":
+		todo := &model.Todo{
+			Status:    model.TodoStatusPending,
+			Priority:  model.TodoPriorityNormal,
+			CreatedAt: now,
+			UpdatedAt: now,
+			Tags:      []string{},
+		}
+		if err := json.Unmarshal(content, todo); err != nil {
+			return err
+		}
+		return s.seedTodo(todo, references)
+	}
+	return nil
+}
+
+// seedTodo resolves the relationships of a seeded todo and creates it
+func (s *Store) seedTodo(todo *model.Todo, references map[string]interface{}) error {
+	for _, name := range []string{"createdBy", "assignedTo"} {
+		value, ok := references[name]
+		if !ok {
+			continue
+		}
+		id, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("field '%s' must be a user ID", name)
+		}
+		user, _ := s.GetUser(id)
+		if user == nil {
+			return fmt.Errorf("field '%s' references unknown user '%s'", name, id)
+		}
+		if name == "createdBy" {
+			todo.CreatedBy = user
+		} else {
+			todo.AssignedTo = user
+		}
+	}
+
+	var attachmentIDs []string
+	if value, ok := references["attachments"]; ok {
+		list, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("field 'attachments' must be a list of attachment IDs")
+		}
+		for _, item := range list {
+			id, _ := item.(string)
+			if file, _ := s.GetFileAttachment(id); file != nil {
+				todo.Attachments = append(todo.Attachments, file)
+			} else if link, _ := s.GetLinkAttachment(id); link != nil {
+				todo.Attachments = append(todo.Attachments, link)
+			} else {
+				return fmt.Errorf("field 'attachments' references unknown attachment '%v'", item)
+			}
+			attachmentIDs = append(attachmentIDs, id)
+		}
+	}
+
+	id, created := s.todos.Create(todo)
+	created.ID = id
+	for _, attachmentID := range attachmentIDs {
+		s.AddAttachmentToTodo(id, attachmentID)
+	}
+	return nil
+}
+
+// jsonFieldNames returns the JSON names of a struct's fields
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
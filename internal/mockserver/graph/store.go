@@ -150,7 +150,17 @@ type Store struct {
 
 // NewStore creates a new Store with pre-seeded data
 func NewStore() *Store {
-	s := &Store{
+	s := newEmptyStore()
+
+	// Pre-seed with 3 sample users
+	s.seedUsers()
+
+	return s
+}
+
+// newEmptyStore creates a new Store without any data
+func newEmptyStore() *Store {
+	return &Store{
 		users:           NewEntityStore[*model.User]("User"),
 		todos:           NewEntityStore[*model.Todo]("Todo"),
 		fileAttachments: NewEntityStore[*model.FileAttachment]("FileAttachment"),
@@ -159,11 +169,6 @@ func NewStore() *Store {
 		todoSubscribers: NewSubscriberManager[*model.Todo](),
 		userSubscribers: NewSubscriberManager[*model.User](),
 	}
-
-	// Pre-seed with 3 sample users
-	s.seedUsers()
-
-	return s
 }
 
 // seedUsers creates 3 initial users with different roles
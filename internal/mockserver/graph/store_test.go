@@ -1,6 +1,7 @@
 package graph
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/kluzzebass/gqlt/internal/mockserver/graph/model"
@@ -188,3 +189,69 @@ func TestConcurrentAccess(t *testing.T) {
 	}
 }
 
+
+func TestNewSeededStore(t *testing.T) {
+	store, err := NewSeededStore(SeedData{
+		"User": {
+			{"name": "Dana Dev", "email": "dana@example.com", "role": "ADMIN"},
+			{"name": "Eve Editor", "email": "eve@example.com"},
+		},
+		"LinkAttachment": {
+			{"title": "Spec", "url": "https://example.com/spec"},
+		},
+		"Todo": {
+			{"title": "Write docs", "priority": "HIGH", "createdBy": "User:1", "assignedTo": "User:2", "attachments": []interface{}{"LinkAttachment:1"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewSeededStore failed: %v", err)
+	}
+
+	// Only the seeded users exist
+	if users := store.GetUsers(); len(users) != 2 {
+		t.Fatalf("Expected 2 seeded users, got %d", len(users))
+	}
+	user, _ := store.GetUser("User:1")
+	if user == nil || user.Name != "Dana Dev" || user.Role != model.UserRoleAdmin {
+		t.Errorf("Unexpected seeded user: %+v", user)
+	}
+	if other, _ := store.GetUser("User:2"); other == nil || other.Role != model.UserRoleUser || other.CreatedAt.IsZero() {
+		t.Errorf("Expected defaults for unset fields, got %+v", other)
+	}
+
+	todo, _ := store.GetTodo("Todo:1")
+	if todo == nil {
+		t.Fatal("Expected the seeded todo")
+	}
+	if todo.Priority != model.TodoPriorityHigh || todo.Status != model.TodoStatusPending {
+		t.Errorf("Unexpected todo priority/status: %s/%s", todo.Priority, todo.Status)
+	}
+	if todo.CreatedBy != user || todo.AssignedTo == nil || todo.AssignedTo.ID != "User:2" {
+		t.Errorf("Expected references to the seeded users, got %+v and %+v", todo.CreatedBy, todo.AssignedTo)
+	}
+	if len(todo.Attachments) != 1 || todo.Attachments[0].GetID() != "LinkAttachment:1" {
+		t.Errorf("Expected the seeded attachment, got %v", todo.Attachments)
+	}
+}
+
+func TestNewSeededStoreErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data SeedData
+		want string
+	}{
+		{"unknown type", SeedData{"Widget": {{"name": "x"}}}, "unknown seed type 'Widget'"},
+		{"unknown field", SeedData{"User": {{"name": "x", "nickname": "y"}}}, "User[0]: type 'User' has no field 'nickname'"},
+		{"id field", SeedData{"User": {{"id": "User:9"}}}, "field 'id' can't be seeded"},
+		{"wrong type", SeedData{"User": {{"name": 42}}}, "User[0]:"},
+		{"unknown reference", SeedData{"Todo": {{"title": "x", "createdBy": "User:1"}}}, "references unknown user 'User:1'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSeededStore(tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
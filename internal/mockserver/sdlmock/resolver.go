@@ -0,0 +1,425 @@
+// Package sdlmock serves any GraphQL schema given as SDL, answering operations
+// with generated stub data instead of real resolvers. It lets the serve command
+// mock an arbitrary API, e.g. for frontend development.
+package sdlmock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kluzzebass/gqlt"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// ListLength is the number of items generated for list fields
+const ListLength = 2
+
+// Response is the result of an operation
+type Response struct {
+	Data   interface{}   `json:"data"`
+	Errors gqlerror.List `json:"errors,omitempty"`
+}
+
+// Resolver executes operations against a schema, resolving every field with a
+// value of its type. Values are pseudo-random but derived from the field's path
+// in the response, so the same query always returns the same data. Introspection
+// queries are answered from the schema itself.
+type Resolver struct {
+	schema        *ast.Schema
+	introspection map[string]interface{}
+}
+
+// NewResolver creates a resolver for the schema defined by sdl
+func NewResolver(sdl string) (*Resolver, error) {
+	schema, gqlErr := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: sdl})
+	if gqlErr != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", gqlErr)
+	}
+
+	introspection, err := gqlt.SDLToIntrospection(sdl)
+	if err != nil {
+		return nil, err
+	}
+
+	// Work on plain decoded JSON, like a client would see it
+	data, err := json.Marshal(introspection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode introspection data: %w", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode introspection data: %w", err)
+	}
+
+	r := &Resolver{schema: schema}
+	r.introspection, _ = decoded["__schema"].(map[string]interface{})
+	r.fixTypeKinds(r.introspection)
+	return r, nil
+}
+
+// fixTypeKinds sets the kind of named type references from the schema, since
+// the introspection data only knows the kinds of built-in types
+func (r *Resolver) fixTypeKinds(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if name, ok := v["name"].(string); ok {
+			if _, isRef := v["ofType"]; isRef {
+				if def := r.schema.Types[name]; def != nil {
+					v["kind"] = string(def.Kind)
+				}
+			}
+		}
+		for _, item := range v {
+			r.fixTypeKinds(item)
+		}
+	case []interface{}:
+		for _, item := range v {
+			r.fixTypeKinds(item)
+		}
+	}
+}
+
+// Execute runs an operation. Subscriptions are not supported.
+func (r *Resolver) Execute(query, operationName string, variables map[string]interface{}) *Response {
+	doc, errs := gqlparser.LoadQuery(r.schema, query)
+	if errs != nil {
+		return &Response{Errors: errs}
+	}
+
+	op := doc.Operations.ForName(operationName)
+	if op == nil {
+		if operationName == "" {
+			return &Response{Errors: gqlerror.List{gqlerror.Errorf("operation name is required when the document has several operations")}}
+		}
+		return &Response{Errors: gqlerror.List{gqlerror.Errorf("operation %q not found", operationName)}}
+	}
+
+	vars, err := validator.VariableValues(r.schema, op, variables)
+	if err != nil {
+		return &Response{Errors: gqlerror.List{gqlerror.WrapIfUnwrapped(err)}}
+	}
+
+	var root *ast.Definition
+	switch op.Operation {
+	case ast.Query:
+		root = r.schema.Query
+	case ast.Mutation:
+		root = r.schema.Mutation
+	default:
+		return &Response{Errors: gqlerror.List{gqlerror.Errorf("%s operations are not supported by schema mocks", op.Operation)}}
+	}
+
+	e := &execution{resolver: r, vars: vars}
+	return &Response{Data: e.object(op.SelectionSet, root, "")}
+}
+
+// ServeHTTP answers GraphQL requests sent as JSON POST bodies or GET parameters
+func (r *Resolver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var params struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		params.Query = req.URL.Query().Get("query")
+		params.OperationName = req.URL.Query().Get("operationName")
+		if vars := req.URL.Query().Get("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &params.Variables); err != nil {
+				http.Error(w, "invalid variables: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(req.Body).Decode(&params); err != nil {
+			http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(r.Execute(params.Query, params.OperationName, params.Variables))
+}
+
+// execution holds the state of a single operation
+type execution struct {
+	resolver *Resolver
+	vars     map[string]interface{}
+}
+
+// fieldGroup is the fields selected under one response key
+type fieldGroup struct {
+	key    string
+	fields []*ast.Field
+}
+
+// collectFields gathers the fields of a selection set that apply to an object
+// type, grouped by response key in selection order
+func (e *execution) collectFields(selections ast.SelectionSet, def *ast.Definition, groups []fieldGroup) []fieldGroup {
+	for _, selection := range selections {
+		switch s := selection.(type) {
+		case *ast.Field:
+			if e.skipped(s.Directives) {
+				continue
+			}
+			key := s.Alias
+			if key == "" {
+				key = s.Name
+			}
+			found := false
+			for i := range groups {
+				if groups[i].key == key {
+					groups[i].fields = append(groups[i].fields, s)
+					found = true
+					break
+				}
+			}
+			if !found {
+				groups = append(groups, fieldGroup{key: key, fields: []*ast.Field{s}})
+			}
+		case *ast.InlineFragment:
+			if !e.skipped(s.Directives) && e.applies(s.TypeCondition, def) {
+				groups = e.collectFields(s.SelectionSet, def, groups)
+			}
+		case *ast.FragmentSpread:
+			if !e.skipped(s.Directives) && s.Definition != nil && e.applies(s.Definition.TypeCondition, def) {
+				groups = e.collectFields(s.Definition.SelectionSet, def, groups)
+			}
+		}
+	}
+	return groups
+}
+
+// skipped evaluates @skip and @include
+func (e *execution) skipped(directives ast.DirectiveList) bool {
+	if d := directives.ForName("skip"); d != nil && d.ArgumentMap(e.vars)["if"] == true {
+		return true
+	}
+	if d := directives.ForName("include"); d != nil && d.ArgumentMap(e.vars)["if"] == false {
+		return true
+	}
+	return false
+}
+
+// applies reports whether a fragment with the type condition applies to def
+func (e *execution) applies(condition string, def *ast.Definition) bool {
+	if condition == "" || condition == def.Name {
+		return true
+	}
+	for _, possible := range e.resolver.schema.GetPossibleTypes(e.resolver.schema.Types[condition]) {
+		if possible.Name == def.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// object resolves a selection set on an object type
+func (e *execution) object(selections ast.SelectionSet, def *ast.Definition, path string) *orderedObject {
+	result := &orderedObject{values: map[string]interface{}{}}
+	for _, group := range e.collectFields(selections, def, nil) {
+		field := group.fields[0]
+		fieldPath := group.key
+		if path != "" {
+			fieldPath = path + "." + group.key
+		}
+
+		var value interface{}
+		switch {
+		case field.Name == "__typename":
+			value = def.Name
+		case def == e.resolver.schema.Query && field.Name == "__schema":
+			value = e.fromJSON(group.fields, e.resolver.introspection, field.Definition.Type)
+		case def == e.resolver.schema.Query && field.Name == "__type":
+			name, _ := field.ArgumentMap(e.vars)["name"].(string)
+			value = e.fromJSON(group.fields, e.resolver.introspectedType(name), field.Definition.Type)
+		default:
+			value = e.stub(group.fields, field.Definition.Type, fieldPath, field.Name)
+		}
+		result.set(group.key, value)
+	}
+	return result
+}
+
+// mergedSelections combines the selection sets of the fields of a group
+func mergedSelections(fields []*ast.Field) ast.SelectionSet {
+	if len(fields) == 1 {
+		return fields[0].SelectionSet
+	}
+	var selections ast.SelectionSet
+	for _, field := range fields {
+		selections = append(selections, field.SelectionSet...)
+	}
+	return selections
+}
+
+// stub generates a value of a type for a field
+func (e *execution) stub(fields []*ast.Field, t *ast.Type, path, fieldName string) interface{} {
+	if t.Elem != nil {
+		list := make([]interface{}, ListLength)
+		for i := range list {
+			list[i] = e.stub(fields, t.Elem, fmt.Sprintf("%s.%d", path, i), fieldName)
+		}
+		return list
+	}
+
+	schema := e.resolver.schema
+	def := schema.Types[t.NamedType]
+	if def == nil {
+		return nil
+	}
+
+	rng := rand.New(rand.NewSource(pathSeed(path)))
+	switch def.Kind {
+	case ast.Enum:
+		if len(def.EnumValues) == 0 {
+			return nil
+		}
+		return def.EnumValues[rng.Intn(len(def.EnumValues))].Name
+	case ast.Object:
+		return e.object(mergedSelections(fields), def, path)
+	case ast.Interface, ast.Union:
+		possible := schema.GetPossibleTypes(def)
+		if len(possible) == 0 {
+			return nil
+		}
+		return e.object(mergedSelections(fields), possible[rng.Intn(len(possible))], path)
+	default:
+		return scalarValue(def.Name, fieldName, rng)
+	}
+}
+
+// scalarValue generates a value for a scalar, using the field name to make
+// strings recognizable
+func scalarValue(scalar, fieldName string, rng *rand.Rand) interface{} {
+	n := rng.Intn(1000) + 1
+	switch scalar {
+	case "ID":
+		return fmt.Sprint(n)
+	case "Int":
+		return rng.Intn(100)
+	case "Float":
+		return float64(rng.Intn(10000)) / 100
+	case "Boolean":
+		return rng.Intn(2) == 1
+	case "DateTime":
+		return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(rng.Intn(365*24)) * time.Hour).Format(time.RFC3339)
+	case "Date":
+		return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, rng.Intn(365)).Format("2006-01-02")
+	case "UUID":
+		b := make([]byte, 16)
+		rng.Read(b)
+		b[6] = (b[6] & 0x0f) | 0x40
+		b[8] = (b[8] & 0x3f) | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+	case "Email", "EmailAddress":
+		return fmt.Sprintf("user%d@example.com", n)
+	case "URL", "URI":
+		return fmt.Sprintf("https://example.com/%s/%d", strings.ToLower(fieldName), n)
+	default:
+		return fmt.Sprintf("%s-%d", fieldName, n)
+	}
+}
+
+// pathSeed derives a random seed from a response path
+func pathSeed(path string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(path))
+	return int64(h.Sum64())
+}
+
+// introspectedType returns the introspection data of a type, or nil
+func (r *Resolver) introspectedType(name string) interface{} {
+	types, _ := r.introspection["types"].([]interface{})
+	for _, t := range types {
+		if typeObj, ok := t.(map[string]interface{}); ok && typeObj["name"] == name {
+			return typeObj
+		}
+	}
+	return nil
+}
+
+// fromJSON resolves the selections of an introspection field against the
+// introspection data, which has a key per introspection field
+func (e *execution) fromJSON(fields []*ast.Field, value interface{}, t *ast.Type) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		elem := t
+		if t.Elem != nil {
+			elem = t.Elem
+		}
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = e.fromJSON(fields, item, elem)
+		}
+		return list
+	case map[string]interface{}:
+		def := e.resolver.schema.Types[t.Name()]
+		if def == nil {
+			return nil
+		}
+		result := &orderedObject{values: map[string]interface{}{}}
+		for _, group := range e.collectFields(mergedSelections(fields), def, nil) {
+			field := group.fields[0]
+			if field.Name == "__typename" {
+				result.set(group.key, def.Name)
+				continue
+			}
+			result.set(group.key, e.fromJSON(group.fields, v[field.Name], field.Definition.Type))
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+// orderedObject is a response object that keeps its fields in selection order
+type orderedObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (o *orderedObject) set(key string, value interface{}) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// MarshalJSON encodes the fields in order
+func (o *orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,199 @@
+package sdlmock
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testSDL = `
+type Query {
+  user(id: ID!): User
+  users: [User!]!
+  search(term: String!): [SearchResult!]!
+}
+
+type Mutation {
+  createUser(name: String!): User!
+}
+
+type Subscription {
+  userCreated: User!
+}
+
+type User {
+  id: ID!
+  name: String!
+  age: Int
+  score: Float!
+  active: Boolean!
+  role: Role!
+  joined: DateTime!
+}
+
+type Post {
+  title: String!
+}
+
+enum Role {
+  ADMIN
+  USER
+}
+
+union SearchResult = User | Post
+
+scalar DateTime
+`
+
+// post sends a GraphQL request to the handler and decodes the response
+func post(t *testing.T, handler http.Handler, query string, variables map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	body, _ := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var response map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response %q: %v", rec.Body.String(), err)
+	}
+	return response
+}
+
+func TestResolver(t *testing.T) {
+	resolver, err := NewResolver(testSDL)
+	if err != nil {
+		t.Fatalf("NewResolver failed: %v", err)
+	}
+
+	t.Run("typed stub values", func(t *testing.T) {
+		response := post(t, resolver, `query($id: ID!) { user(id: $id) { __typename id name age score active role joined } }`, map[string]interface{}{"id": "1"})
+		if response["errors"] != nil {
+			t.Fatalf("Unexpected errors: %v", response["errors"])
+		}
+		user := response["data"].(map[string]interface{})["user"].(map[string]interface{})
+		if user["__typename"] != "User" {
+			t.Errorf("Expected __typename User, got %v", user["__typename"])
+		}
+		if _, ok := user["id"].(string); !ok {
+			t.Errorf("Expected a string id, got %v", user["id"])
+		}
+		if name, _ := user["name"].(string); !strings.HasPrefix(name, "name-") {
+			t.Errorf("Expected a generated name, got %v", user["name"])
+		}
+		if _, ok := user["age"].(float64); !ok {
+			t.Errorf("Expected a number age, got %v", user["age"])
+		}
+		if _, ok := user["active"].(bool); !ok {
+			t.Errorf("Expected a boolean active, got %v", user["active"])
+		}
+		if role := user["role"]; role != "ADMIN" && role != "USER" {
+			t.Errorf("Expected a Role value, got %v", role)
+		}
+		if joined, _ := user["joined"].(string); !strings.HasPrefix(joined, "2024-") {
+			t.Errorf("Expected a DateTime, got %v", user["joined"])
+		}
+
+		// The same query returns the same data
+		again := post(t, resolver, `query($id: ID!) { user(id: $id) { __typename id name age score active role joined } }`, map[string]interface{}{"id": "1"})
+		first, _ := json.Marshal(response)
+		second, _ := json.Marshal(again)
+		if !bytes.Equal(first, second) {
+			t.Errorf("Expected stable data, got %s and %s", first, second)
+		}
+	})
+
+	t.Run("lists, aliases and fragments", func(t *testing.T) {
+		response := post(t, resolver, `{
+			all: users { ...UserName }
+			search(term: "a") {
+				__typename
+				... on Post { title }
+				... on User { id @skip(if: true) name }
+			}
+		}
+		fragment UserName on User { name }`, nil)
+		if response["errors"] != nil {
+			t.Fatalf("Unexpected errors: %v", response["errors"])
+		}
+		data := response["data"].(map[string]interface{})
+		users := data["all"].([]interface{})
+		if len(users) != ListLength {
+			t.Fatalf("Expected %d users, got %v", ListLength, users)
+		}
+		if _, ok := users[0].(map[string]interface{})["name"].(string); !ok {
+			t.Errorf("Expected the fragment's fields, got %v", users[0])
+		}
+		for _, item := range data["search"].([]interface{}) {
+			result := item.(map[string]interface{})
+			switch result["__typename"] {
+			case "Post":
+				if _, ok := result["title"]; !ok {
+					t.Errorf("Expected a title for a Post, got %v", result)
+				}
+			case "User":
+				if _, ok := result["id"]; ok {
+					t.Errorf("Expected id to be skipped, got %v", result)
+				}
+			default:
+				t.Errorf("Expected a User or Post, got %v", result)
+			}
+		}
+	})
+
+	t.Run("mutation", func(t *testing.T) {
+		response := post(t, resolver, `mutation { createUser(name: "Ada") { id } }`, nil)
+		if _, ok := response["data"].(map[string]interface{})["createUser"].(map[string]interface{}); !ok {
+			t.Errorf("Expected a created user, got %v", response)
+		}
+	})
+
+	t.Run("introspection", func(t *testing.T) {
+		response := post(t, resolver, `{
+			__schema { queryType { name } types { name } }
+			__type(name: "User") { kind fields { name type { kind name ofType { kind name } } } }
+		}`, nil)
+		if response["errors"] != nil {
+			t.Fatalf("Unexpected errors: %v", response["errors"])
+		}
+		data := response["data"].(map[string]interface{})
+		schema := data["__schema"].(map[string]interface{})
+		if schema["queryType"].(map[string]interface{})["name"] != "Query" {
+			t.Errorf("Expected query type Query, got %v", schema["queryType"])
+		}
+		userType := data["__type"].(map[string]interface{})
+		if userType["kind"] != "OBJECT" {
+			t.Errorf("Expected User to be an OBJECT, got %v", userType["kind"])
+		}
+		fields := userType["fields"].([]interface{})
+		role := fields[5].(map[string]interface{})
+		if role["name"] != "role" || role["type"].(map[string]interface{})["ofType"].(map[string]interface{})["kind"] != "ENUM" {
+			t.Errorf("Expected role to reference the Role enum, got %v", role)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		response := post(t, resolver, `{ missing }`, nil)
+		if response["errors"] == nil {
+			t.Errorf("Expected a validation error, got %v", response)
+		}
+		response = post(t, resolver, `query($id: ID!) { user(id: $id) { id } }`, nil)
+		if response["errors"] == nil {
+			t.Errorf("Expected a missing variable error, got %v", response)
+		}
+		response = post(t, resolver, `subscription { userCreated { id } }`, nil)
+		if response["errors"] == nil {
+			t.Errorf("Expected subscriptions to be rejected, got %v", response)
+		}
+	})
+}
+
+func TestNewResolverInvalidSDL(t *testing.T) {
+	if _, err := NewResolver(`type Query { user: Missing }`); err == nil {
+		t.Error("Expected an error for an invalid schema")
+	}
+}
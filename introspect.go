@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 )
 
 // Introspect handles GraphQL schema introspection operations.
@@ -122,6 +124,16 @@ func SchemaExists(path string) bool {
 	return err == nil
 }
 
+// SchemaStale reports whether the schema file at path is missing or was saved more
+// than maxAge ago, judging by its modification time
+func SchemaStale(path string, maxAge time.Duration) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return true
+	}
+	return time.Since(info.ModTime()) > maxAge
+}
+
 // convertIntrospectionToSDL converts introspection JSON to GraphQL SDL
 func convertIntrospectionToSDL(schema *Response) (string, error) {
 	// Extract schema data
@@ -144,91 +156,350 @@ func convertIntrospectionToSDL(schema *Response) (string, error) {
 		return "", fmt.Errorf("invalid types format")
 	}
 
+	// Add custom directive definitions
+	if err := writeDirectivesSDL(&sdl, schemaObj["directives"]); err != nil {
+		return "", err
+	}
+
+	for _, typeMap := range sortedSDLTypes(schemaObj, types) {
+		if err := writeTypeSDL(&sdl, typeMap); err != nil {
+			return "", err
+		}
+	}
+
+	return sdl.String(), nil
+}
+
+// sortedSDLTypes returns the non-introspection types in a stable order: the query, mutation
+// and subscription root types first, followed by all other types sorted by name. Fields keep
+// their declared order. This keeps SDL output identical across runs, even when the server
+// returns types in a varying order.
+func sortedSDLTypes(schemaObj map[string]interface{}, types []interface{}) []map[string]interface{} {
+	rootRank := map[string]int{}
+	for i, key := range []string{"queryType", "mutationType", "subscriptionType"} {
+		if rootType, ok := schemaObj[key].(map[string]interface{}); ok {
+			if name, ok := rootType["name"].(string); ok {
+				rootRank[name] = i
+			}
+		}
+	}
+
+	var sorted []map[string]interface{}
 	for _, typeObj := range types {
 		typeMap, ok := typeObj.(map[string]interface{})
 		if !ok {
 			continue
 		}
 
-		name, _ := typeMap["name"].(string)
-		kind, _ := typeMap["kind"].(string)
-		description, _ := typeMap["description"].(string)
-
 		// Skip introspection types
+		name, _ := typeMap["name"].(string)
 		if strings.HasPrefix(name, "__") {
 			continue
 		}
 
-		// Add description if present
-		if description != "" {
-			sdl.WriteString(fmt.Sprintf("\"\"\"%s\"\"\"\n", description))
+		sorted = append(sorted, typeMap)
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		nameI, _ := sorted[i]["name"].(string)
+		nameJ, _ := sorted[j]["name"].(string)
+		rankI, rootI := rootRank[nameI]
+		rankJ, rootJ := rootRank[nameJ]
+		if rootI != rootJ {
+			return rootI
+		}
+		if rootI {
+			return rankI < rankJ
 		}
+		return nameI < nameJ
+	})
+	return sorted
+}
 
-		// Add type definition based on kind
-		switch kind {
-		case "OBJECT":
-			sdl.WriteString(fmt.Sprintf("type %s {\n", name))
-			// Add fields
-			if fields, ok := typeMap["fields"].([]interface{}); ok {
-				for _, field := range fields {
-					if fieldMap, ok := field.(map[string]interface{}); ok {
-						fieldName, _ := fieldMap["name"].(string)
-						fieldType := formatType(fieldMap["type"])
-						fieldDesc, _ := fieldMap["description"].(string)
-
-						if fieldDesc != "" {
-							sdl.WriteString(fmt.Sprintf("  \"\"\"%s\"\"\"\n", fieldDesc))
-						}
-						sdl.WriteString(fmt.Sprintf("  %s: %s\n", fieldName, fieldType))
-					}
+// writeTypeSDL writes the SDL definition of a single introspection type, followed by a blank line
+func writeTypeSDL(sdl *strings.Builder, typeMap map[string]interface{}) error {
+	name, _ := typeMap["name"].(string)
+	kind, _ := typeMap["kind"].(string)
+	description, _ := typeMap["description"].(string)
+
+	// Add description if present
+	writeDescriptionSDL(sdl, description, "")
+
+	// Add type definition based on kind
+	switch kind {
+	case "OBJECT", "INTERFACE":
+		keyword := "type"
+		if kind == "INTERFACE" {
+			keyword = "interface"
+		}
+		sdl.WriteString(fmt.Sprintf("%s %s", keyword, name))
+		if interfaces := typeNames(typeMap["interfaces"]); len(interfaces) > 0 {
+			sdl.WriteString(" implements " + strings.Join(interfaces, " & "))
+		}
+		sdl.WriteString(" {\n")
+		// Add fields
+		if err := writeFieldsSDL(sdl, typeMap["fields"]); err != nil {
+			return fmt.Errorf("type %s: %w", name, err)
+		}
+		sdl.WriteString("}\n\n")
+	case "INPUT_OBJECT":
+		sdl.WriteString(fmt.Sprintf("input %s {\n", name))
+		// Add input fields
+		if err := writeFieldsSDL(sdl, typeMap["inputFields"]); err != nil {
+			return fmt.Errorf("type %s: %w", name, err)
+		}
+		sdl.WriteString("}\n\n")
+	case "ENUM":
+		sdl.WriteString(fmt.Sprintf("enum %s {\n", name))
+		// Add enum values
+		if enumValues, ok := typeMap["enumValues"].([]interface{}); ok {
+			for _, value := range enumValues {
+				if valueMap, ok := value.(map[string]interface{}); ok {
+					valueName, _ := valueMap["name"].(string)
+					valueDesc, _ := valueMap["description"].(string)
+
+					writeDescriptionSDL(sdl, valueDesc, "  ")
+					sdl.WriteString(fmt.Sprintf("  %s%s\n", valueName, deprecatedSDL(valueMap)))
 				}
 			}
-			sdl.WriteString("}\n\n")
-		case "INPUT_OBJECT":
-			sdl.WriteString(fmt.Sprintf("input %s {\n", name))
-			// Add input fields
-			if inputFields, ok := typeMap["inputFields"].([]interface{}); ok {
-				for _, field := range inputFields {
-					if fieldMap, ok := field.(map[string]interface{}); ok {
-						fieldName, _ := fieldMap["name"].(string)
-						fieldType := formatType(fieldMap["type"])
-						fieldDesc, _ := fieldMap["description"].(string)
-
-						if fieldDesc != "" {
-							sdl.WriteString(fmt.Sprintf("  \"\"\"%s\"\"\"\n", fieldDesc))
-						}
-						sdl.WriteString(fmt.Sprintf("  %s: %s\n", fieldName, fieldType))
-					}
-				}
+		}
+		sdl.WriteString("}\n\n")
+	case "UNION":
+		sdl.WriteString(fmt.Sprintf("union %s = %s\n\n", name, strings.Join(typeNames(typeMap["possibleTypes"]), " | ")))
+	case "SCALAR":
+		sdl.WriteString(fmt.Sprintf("scalar %s\n\n", name))
+	}
+	return nil
+}
+
+// writeFieldsSDL writes the fields (or input fields) of a type, one per line, with
+// their arguments (or default values)
+func writeFieldsSDL(sdl *strings.Builder, fields interface{}) error {
+	fieldList, ok := fields.([]interface{})
+	if !ok {
+		return nil
+	}
+	for _, field := range fieldList {
+		if fieldMap, ok := field.(map[string]interface{}); ok {
+			fieldName, _ := fieldMap["name"].(string)
+			fieldType, err := formatType(fieldMap["type"])
+			if err != nil {
+				return fmt.Errorf("field %s: %w", fieldName, err)
 			}
-			sdl.WriteString("}\n\n")
-		case "ENUM":
-			sdl.WriteString(fmt.Sprintf("enum %s {\n", name))
-			// Add enum values
-			if enumValues, ok := typeMap["enumValues"].([]interface{}); ok {
-				for _, value := range enumValues {
-					if valueMap, ok := value.(map[string]interface{}); ok {
-						valueName, _ := valueMap["name"].(string)
-						valueDesc, _ := valueMap["description"].(string)
-
-						if valueDesc != "" {
-							sdl.WriteString(fmt.Sprintf("  \"\"\"%s\"\"\"\n", valueDesc))
-						}
-						sdl.WriteString(fmt.Sprintf("  %s\n", valueName))
-					}
-				}
+			args, err := formatArgumentsSDL(fieldMap["args"])
+			if err != nil {
+				return fmt.Errorf("field %s: %w", fieldName, err)
 			}
-			sdl.WriteString("}\n\n")
-		case "SCALAR":
-			sdl.WriteString(fmt.Sprintf("scalar %s\n\n", name))
+			if defaultValue, ok := fieldMap["defaultValue"].(string); ok && defaultValue != "" {
+				fieldType += " = " + defaultValue
+			}
+			fieldDesc, _ := fieldMap["description"].(string)
+
+			writeDescriptionSDL(sdl, fieldDesc, "  ")
+			sdl.WriteString(fmt.Sprintf("  %s%s: %s%s\n", fieldName, args, fieldType, deprecatedSDL(fieldMap)))
 		}
 	}
+	return nil
+}
 
-	return sdl.String(), nil
+// builtinDirectives are the directives every GraphQL server defines implicitly
+var builtinDirectives = map[string]bool{
+	"skip":        true,
+	"include":     true,
+	"deprecated":  true,
+	"specifiedBy": true,
+	"oneOf":       true,
+	"defer":       true,
+}
+
+// writeDirectivesSDL writes the definitions of all non-built-in directives, sorted by name
+func writeDirectivesSDL(sdl *strings.Builder, directives interface{}) error {
+	directiveList, _ := directives.([]interface{})
+
+	var custom []map[string]interface{}
+	for _, directive := range directiveList {
+		directiveMap, ok := directive.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := directiveMap["name"].(string)
+		if name == "" || builtinDirectives[name] {
+			continue
+		}
+		custom = append(custom, directiveMap)
+	}
+
+	sort.SliceStable(custom, func(i, j int) bool {
+		nameI, _ := custom[i]["name"].(string)
+		nameJ, _ := custom[j]["name"].(string)
+		return nameI < nameJ
+	})
+
+	for _, directiveMap := range custom {
+		name, _ := directiveMap["name"].(string)
+		description, _ := directiveMap["description"].(string)
+
+		args, err := formatArgumentsSDL(directiveMap["args"])
+		if err != nil {
+			return fmt.Errorf("directive @%s: %w", name, err)
+		}
+
+		writeDescriptionSDL(sdl, description, "")
+		sdl.WriteString(fmt.Sprintf("directive @%s%s", name, args))
+		if repeatable, _ := directiveMap["isRepeatable"].(bool); repeatable {
+			sdl.WriteString(" repeatable")
+		}
+		sdl.WriteString(fmt.Sprintf(" on %s\n\n", strings.Join(stringList(directiveMap["locations"]), " | ")))
+	}
+	return nil
+}
+
+// formatArgumentsSDL formats a list of introspection input values as an SDL argument list
+func formatArgumentsSDL(args interface{}) (string, error) {
+	argList, _ := args.([]interface{})
+
+	var parts []string
+	for _, arg := range argList {
+		argMap, ok := arg.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		argName, _ := argMap["name"].(string)
+		argType, err := formatType(argMap["type"])
+		if err != nil {
+			return "", fmt.Errorf("argument %s: %w", argName, err)
+		}
+
+		part := fmt.Sprintf("%s: %s", argName, argType)
+		if defaultValue, ok := argMap["defaultValue"].(string); ok && defaultValue != "" {
+			part += " = " + defaultValue
+		}
+		parts = append(parts, part)
+	}
+
+	if len(parts) == 0 {
+		return "", nil
+	}
+	return "(" + strings.Join(parts, ", ") + ")", nil
+}
+
+// deprecatedSDL returns the @deprecated directive for a deprecated field or enum value,
+// or an empty string if it is not deprecated
+func deprecatedSDL(item map[string]interface{}) string {
+	if deprecated, _ := item["isDeprecated"].(bool); !deprecated {
+		return ""
+	}
+	reason, _ := item["deprecationReason"].(string)
+	if reason == "" {
+		return " @deprecated"
+	}
+	return fmt.Sprintf(" @deprecated(reason: %s)", quoteGraphQLString(reason))
+}
+
+// quoteGraphQLString returns s as a GraphQL string literal. Unlike strconv.Quote it
+// only uses the escape sequences GraphQL defines.
+func quoteGraphQLString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
 }
 
+// stringList converts a decoded JSON string array to a []string
+func stringList(list interface{}) []string {
+	switch values := list.(type) {
+	case []string:
+		return values
+	case []interface{}:
+		var result []string
+		for _, value := range values {
+			if str, ok := value.(string); ok {
+				result = append(result, str)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// writeDescriptionSDL writes a description as a block string at the given indentation.
+// Single-line descriptions stay on one line; multi-line descriptions (and descriptions
+// ending in a quote) put the quotes on their own lines. Embedded triple quotes are escaped.
+func writeDescriptionSDL(sdl *strings.Builder, description, indent string) {
+	if description == "" {
+		return
+	}
+
+	description = strings.ReplaceAll(description, `"""`, `\"""`)
+	if !strings.Contains(description, "\n") && !strings.HasSuffix(description, `"`) {
+		sdl.WriteString(fmt.Sprintf("%s\"\"\"%s\"\"\"\n", indent, description))
+		return
+	}
+
+	sdl.WriteString(indent + `"""` + "\n")
+	for _, line := range strings.Split(description, "\n") {
+		if line == "" {
+			sdl.WriteString("\n")
+			continue
+		}
+		sdl.WriteString(indent + line + "\n")
+	}
+	sdl.WriteString(indent + `"""` + "\n")
+}
+
+// typeNames returns the names of a list of introspection type references
+func typeNames(list interface{}) []string {
+	var names []string
+	items, _ := list.([]interface{})
+	for _, item := range items {
+		if typeMap, ok := item.(map[string]interface{}); ok {
+			if name, _ := typeMap["name"].(string); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// MaxTypeDepth is the maximum number of NON_NULL and LIST wrappers allowed around a named
+// type in introspection data. Converting deeper (or cyclic) type references to SDL fails.
+var MaxTypeDepth = 32
+
 // formatType formats a GraphQL type from introspection data
-func formatType(typeObj interface{}) string {
+func formatType(typeObj interface{}) (string, error) {
+	return formatTypeDepth(typeObj, 0)
+}
+
+// formatTypeDepth formats a type reference nested inside depth wrappers
+func formatTypeDepth(typeObj interface{}, depth int) (string, error) {
+	if depth > MaxTypeDepth {
+		return "", fmt.Errorf("type reference is nested more than %d levels deep or is cyclic", MaxTypeDepth)
+	}
+
 	if typeMap, ok := typeObj.(map[string]interface{}); ok {
 		kind, _ := typeMap["kind"].(string)
 		name, _ := typeMap["name"].(string)
@@ -236,14 +507,22 @@ func formatType(typeObj interface{}) string {
 
 		switch kind {
 		case "NON_NULL":
-			return formatType(ofType) + "!"
+			inner, err := formatTypeDepth(ofType, depth+1)
+			if err != nil {
+				return "", err
+			}
+			return inner + "!", nil
 		case "LIST":
-			return "[" + formatType(ofType) + "]"
-		case "SCALAR", "OBJECT", "ENUM", "INPUT_OBJECT":
+			inner, err := formatTypeDepth(ofType, depth+1)
+			if err != nil {
+				return "", err
+			}
+			return "[" + inner + "]", nil
+		case "SCALAR", "OBJECT", "INTERFACE", "UNION", "ENUM", "INPUT_OBJECT":
 			if name != "" {
-				return name
+				return name, nil
 			}
 		}
 	}
-	return "String" // fallback
+	return "String", nil // fallback
 }
@@ -3,7 +3,12 @@ package gqlt
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
 )
 
 func TestNewIntrospect(t *testing.T) {
@@ -126,3 +131,345 @@ func TestIntrospect_SaveSchemaEdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestFormatTypeDepthLimit(t *testing.T) {
+	// Wrap a named type in n alternating LIST and NON_NULL wrappers
+	wrap := func(n int) map[string]interface{} {
+		typeRef := map[string]interface{}{"kind": "SCALAR", "name": "String"}
+		for i := 0; i < n; i++ {
+			kind := "LIST"
+			if i%2 == 0 {
+				kind = "NON_NULL"
+			}
+			typeRef = map[string]interface{}{"kind": kind, "ofType": typeRef}
+		}
+		return typeRef
+	}
+
+	t.Run("within limit", func(t *testing.T) {
+		got, err := formatType(wrap(4))
+		if err != nil {
+			t.Fatalf("formatType() error = %v", err)
+		}
+		if got != "[[String!]!]" {
+			t.Errorf("formatType() = %q, want %q", got, "[[String!]!]")
+		}
+	})
+
+	t.Run("deeply nested", func(t *testing.T) {
+		if _, err := formatType(wrap(MaxTypeDepth + 1)); err == nil {
+			t.Error("Expected error for type reference exceeding MaxTypeDepth")
+		}
+	})
+
+	t.Run("self-referential", func(t *testing.T) {
+		typeRef := map[string]interface{}{"kind": "LIST"}
+		typeRef["ofType"] = typeRef
+		if _, err := formatType(typeRef); err == nil {
+			t.Error("Expected error for cyclic type reference")
+		}
+	})
+}
+
+func TestConvertIntrospectionToSDLCyclicType(t *testing.T) {
+	typeRef := map[string]interface{}{"kind": "NON_NULL"}
+	typeRef["ofType"] = typeRef
+
+	schema := &Response{
+		Data: map[string]interface{}{
+			"__schema": map[string]interface{}{
+				"types": []interface{}{
+					map[string]interface{}{
+						"kind": "OBJECT",
+						"name": "Query",
+						"fields": []interface{}{
+							map[string]interface{}{"name": "broken", "type": typeRef},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	_, err := convertIntrospectionToSDL(schema)
+	if err == nil {
+		t.Fatal("Expected error for cyclic type reference")
+	}
+	if !strings.Contains(err.Error(), "Query") || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("Expected error to name the type and field, got: %v", err)
+	}
+}
+
+func TestConvertIntrospectionToSDLDeterministic(t *testing.T) {
+	sdl := `
+type Query {
+  user(id: ID!): User
+  users: [User!]!
+}
+
+type Mutation {
+  createUser(name: String!): User
+}
+
+type User {
+  name: String!
+  id: ID!
+  role: Role
+}
+
+enum Role {
+  ADMIN
+  MEMBER
+}
+
+input UserFilter {
+  role: Role
+  name: String
+}
+`
+
+	// SDLToIntrospection builds the types array from a map, so each call may order types differently
+	convert := func() string {
+		introspection, err := SDLToIntrospection(sdl)
+		if err != nil {
+			t.Fatalf("SDLToIntrospection failed: %v", err)
+		}
+		result, err := convertIntrospectionToSDL(&Response{Data: introspection})
+		if err != nil {
+			t.Fatalf("convertIntrospectionToSDL failed: %v", err)
+		}
+		return result
+	}
+
+	first := convert()
+	for i := 0; i < 10; i++ {
+		if got := convert(); got != first {
+			t.Fatalf("SDL output differs between runs:\n%s\n---\n%s", first, got)
+		}
+	}
+
+	// Root types come first, followed by the remaining types by name
+	order := []string{"type Query", "type Mutation", "scalar Boolean", "scalar ID", "enum Role", "type User", "input UserFilter"}
+	last := -1
+	for _, decl := range order {
+		idx := strings.Index(first, decl)
+		if idx == -1 {
+			t.Fatalf("Expected SDL to contain %q:\n%s", decl, first)
+		}
+		if idx < last {
+			t.Errorf("Expected %q to appear after the previous type:\n%s", decl, first)
+		}
+		last = idx
+	}
+
+	// Fields keep their declared order
+	if strings.Index(first, "  name: String!") > strings.Index(first, "  id: ID!") {
+		t.Errorf("Expected User fields in declared order:\n%s", first)
+	}
+}
+
+func TestConvertIntrospectionToSDLDescriptions(t *testing.T) {
+	sdl := `
+"""A user of the system"""
+type Query {
+  "The current user"
+  me: User
+}
+
+"""A user of the system"""
+type User {
+  """
+  The user's display name.
+  Shown on their "profile" page.
+  """
+  name: String!
+  "Quoted with \"\"\" inside"
+  bio: String
+}
+`
+	introspection, err := SDLToIntrospection(sdl)
+	if err != nil {
+		t.Fatalf("SDLToIntrospection failed: %v", err)
+	}
+	result, err := convertIntrospectionToSDL(&Response{Data: introspection})
+	if err != nil {
+		t.Fatalf("convertIntrospectionToSDL failed: %v", err)
+	}
+
+	if !strings.Contains(result, "\"\"\"A user of the system\"\"\"\ntype User {") {
+		t.Errorf("Expected type description immediately before type User:\n%s", result)
+	}
+	if !strings.Contains(result, "  \"\"\"\n  The user's display name.\n  Shown on their \"profile\" page.\n  \"\"\"\n  name: String!") {
+		t.Errorf("Expected multi-line field description as an indented block:\n%s", result)
+	}
+
+	// The generated SDL parses back with the same descriptions
+	doc, err := parser.ParseSchema(&ast.Source{Input: result})
+	if err != nil {
+		t.Fatalf("Generated SDL does not parse: %v\n%s", err, result)
+	}
+	user := doc.Definitions.ForName("User")
+	if got := user.Fields.ForName("name").Description; got != "The user's display name.\nShown on their \"profile\" page." {
+		t.Errorf("name description = %q", got)
+	}
+	if got := user.Fields.ForName("bio").Description; got != `Quoted with """ inside` {
+		t.Errorf("bio description = %q", got)
+	}
+}
+
+func TestConvertIntrospectionToSDLDirectives(t *testing.T) {
+	sdl := `
+"""Caches the field result"""
+directive @cacheControl(maxAge: Int = 60, scope: String) on FIELD_DEFINITION | OBJECT
+
+type Query {
+  user: User
+  profile: User @deprecated(reason: "Use user instead")
+  legacy: String @deprecated
+}
+
+type User {
+  id: ID!
+  role: Role
+}
+
+enum Role {
+  ADMIN
+  GUEST @deprecated(reason: "Guests were removed")
+}
+`
+	introspection, err := SDLToIntrospection(sdl)
+	if err != nil {
+		t.Fatalf("SDLToIntrospection failed: %v", err)
+	}
+	result, err := convertIntrospectionToSDL(&Response{Data: introspection})
+	if err != nil {
+		t.Fatalf("convertIntrospectionToSDL failed: %v", err)
+	}
+
+	want := "\"\"\"Caches the field result\"\"\"\ndirective @cacheControl(maxAge: Int = 60, scope: String) on FIELD_DEFINITION | OBJECT\n"
+	if !strings.HasPrefix(result, want) {
+		t.Errorf("Expected SDL to start with the custom directive definition:\n%s", result)
+	}
+
+	for _, builtin := range []string{"directive @skip", "directive @include", "directive @deprecated"} {
+		if strings.Contains(result, builtin) {
+			t.Errorf("Expected built-in %q to be omitted:\n%s", builtin, result)
+		}
+	}
+
+	for _, line := range []string{
+		`  profile: User @deprecated(reason: "Use user instead")`,
+		`  legacy: String @deprecated(reason: "No longer supported")`,
+		`  GUEST @deprecated(reason: "Guests were removed")`,
+		"  user: User\n",
+	} {
+		if !strings.Contains(result, line) {
+			t.Errorf("Expected SDL to contain %q:\n%s", line, result)
+		}
+	}
+
+	// The generated SDL parses back with the directive and deprecations intact
+	doc, err := parser.ParseSchema(&ast.Source{Input: result})
+	if err != nil {
+		t.Fatalf("Generated SDL does not parse: %v\n%s", err, result)
+	}
+	if doc.Directives.ForName("cacheControl") == nil {
+		t.Error("Expected parsed SDL to define @cacheControl")
+	}
+	profile := doc.Definitions.ForName("Query").Fields.ForName("profile")
+	if profile.Directives.ForName("deprecated") == nil {
+		t.Error("Expected Query.profile to be deprecated in parsed SDL")
+	}
+}
+
+func TestConvertIntrospectionToSDLArguments(t *testing.T) {
+	sdl := `
+type Query {
+  user(id: ID!, verbose: Boolean = false): User
+  users(filter: UserFilter, first: Int = 10): [User!]!
+}
+
+type User {
+  id: ID!
+  avatar(size: Int = 64): String @deprecated(reason: "Use \"picture\"\t\u0001 \u00e9 instead")
+}
+
+input UserFilter {
+  name: String = "any"
+  limit: Int = 20
+}
+`
+	introspection, err := SDLToIntrospection(sdl)
+	if err != nil {
+		t.Fatalf("SDLToIntrospection failed: %v", err)
+	}
+	result, err := convertIntrospectionToSDL(&Response{Data: introspection})
+	if err != nil {
+		t.Fatalf("convertIntrospectionToSDL failed: %v", err)
+	}
+
+	for _, line := range []string{
+		"  user(id: ID!, verbose: Boolean = false): User\n",
+		"  users(filter: UserFilter, first: Int = 10): [User!]!\n",
+		"  name: String = \"any\"\n",
+		"  limit: Int = 20\n",
+	} {
+		if !strings.Contains(result, line) {
+			t.Errorf("Expected SDL to contain %q:\n%s", line, result)
+		}
+	}
+
+	// The generated SDL parses back with the arguments and deprecation reason intact
+	doc, err := parser.ParseSchema(&ast.Source{Input: result})
+	if err != nil {
+		t.Fatalf("Generated SDL does not parse: %v\n%s", err, result)
+	}
+	if arg := doc.Definitions.ForName("Query").Fields.ForName("user").Arguments.ForName("id"); arg == nil || arg.Type.String() != "ID!" {
+		t.Errorf("Expected Query.user to take id: ID!, got %v", arg)
+	}
+	deprecated := doc.Definitions.ForName("User").Fields.ForName("avatar").Directives.ForName("deprecated")
+	if deprecated == nil {
+		t.Fatal("Expected User.avatar to be deprecated in parsed SDL")
+	}
+	if got := deprecated.Arguments.ForName("reason").Value.Raw; got != "Use \"picture\"\t\x01 \u00e9 instead" {
+		t.Errorf("deprecation reason = %q", got)
+	}
+}
+
+func TestQuoteGraphQLString(t *testing.T) {
+	tests := map[string]string{
+		"plain":          `"plain"`,
+		`say "hi"\now`:   `"say \"hi\"\\now"`,
+		"tab\tnew\nline": `"tab\tnew\nline"`,
+		"bell\x07":       `"bell\u0007"`,
+		"café ☕":         `"café ☕"`,
+	}
+	for input, want := range tests {
+		if got := quoteGraphQLString(input); got != want {
+			t.Errorf("quoteGraphQLString(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
+
+func TestSchemaStale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.json")
+	if !SchemaStale(path, time.Hour) {
+		t.Error("Expected a missing schema to be stale")
+	}
+
+	if err := os.WriteFile(path, []byte(`{}`), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	if SchemaStale(path, time.Hour) {
+		t.Error("Expected a schema saved just now to be fresh")
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatalf("Failed to age schema: %v", err)
+	}
+	if !SchemaStale(path, time.Hour) {
+		t.Error("Expected a schema saved two hours ago to be stale after an hour")
+	}
+}
@@ -0,0 +1,207 @@
+package gqlt
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/validator/rules"
+)
+
+// Lint finding codes reported by LintQuery
+const (
+	LintUnusedVariable  = "UNUSED_VARIABLE"
+	LintUnusedFragment  = "UNUSED_FRAGMENT"
+	LintDeepNesting     = "DEEP_NESTING"
+	LintMissingTypename = "MISSING_TYPENAME"
+)
+
+// LintOptions configures LintQuery
+type LintOptions struct {
+	// MaxDepth is the deepest field nesting allowed before a DEEP_NESTING finding
+	// is reported; 0 disables the check
+	MaxDepth int
+}
+
+// DefaultLintOptions returns the options used by the lint command
+func DefaultLintOptions() LintOptions {
+	return LintOptions{
+		MaxDepth: 8,
+	}
+}
+
+// LintQuery checks a query for common anti-patterns: variables and fragments that
+// are never used, selections nested deeper than opts.MaxDepth, and fields returning
+// a union or interface without selecting __typename. Findings are ordered by their
+// position in the query. An error is returned if the query is invalid against the
+// schema, since linting assumes a query that would otherwise execute.
+//
+// Example:
+//
+//	findings, err := analyzer.LintQuery(`query ($id: ID) { users { id } }`, gqlt.DefaultLintOptions())
+//	// findings[0].Code == gqlt.LintUnusedVariable
+func (a *Analyzer) LintQuery(query string, opts LintOptions) ([]LintFinding, error) {
+	if opts.MaxDepth < 0 {
+		return nil, fmt.Errorf("max depth must not be negative")
+	}
+
+	schema, err := a.astSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	// Unused variables and fragments are reported as findings instead of errors
+	validationRules := rules.NewDefaultRules()
+	validationRules.RemoveRule(rules.NoUnusedVariablesRule.Name)
+	validationRules.RemoveRule(rules.NoUnusedFragmentsRule.Name)
+
+	doc, errs := gqlparser.LoadQueryWithRules(schema, query, validationRules)
+	if errs != nil {
+		return nil, fmt.Errorf("query is invalid: %w", errs)
+	}
+
+	l := &linter{schema: schema, doc: doc, opts: opts, reported: make(map[ast.Position]bool)}
+	usedFragments := make(map[string]bool)
+	for _, op := range doc.Operations {
+		l.lintOperation(op, usedFragments)
+	}
+	for _, fragment := range doc.Fragments {
+		if !usedFragments[fragment.Name] {
+			l.add(LintUnusedFragment, fragment.Position, "Fragment %q is never used", fragment.Name)
+		}
+		l.checkTypename(fragment.SelectionSet)
+	}
+
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		if l.findings[i].Line != l.findings[j].Line {
+			return l.findings[i].Line < l.findings[j].Line
+		}
+		return l.findings[i].Column < l.findings[j].Column
+	})
+	return l.findings, nil
+}
+
+// linter collects findings while walking a validated query document
+type linter struct {
+	schema   *ast.Schema
+	doc      *ast.QueryDocument
+	opts     LintOptions
+	findings []LintFinding
+	reported map[ast.Position]bool
+}
+
+func (l *linter) add(code string, pos *ast.Position, format string, args ...interface{}) {
+	finding := LintFinding{Code: code, Message: fmt.Sprintf(format, args...)}
+	if pos != nil {
+		finding.Line = pos.Line
+		finding.Column = pos.Column
+	}
+	l.findings = append(l.findings, finding)
+}
+
+func (l *linter) lintOperation(op *ast.OperationDefinition, usedFragments map[string]bool) {
+	usedVariables := make(map[string]bool)
+	l.walk(op.SelectionSet, 1, usedVariables, usedFragments, make(map[string]bool))
+	collectDirectiveVariables(op.Directives, usedVariables)
+
+	operation := "anonymous operation"
+	if op.Name != "" {
+		operation = fmt.Sprintf("operation %q", op.Name)
+	}
+	for _, def := range op.VariableDefinitions {
+		if !usedVariables[def.Variable] {
+			l.add(LintUnusedVariable, def.Position, "Variable \"$%s\" is never used in %s", def.Variable, operation)
+		}
+	}
+
+	l.checkTypename(op.SelectionSet)
+}
+
+// walk follows a selection set through inline fragments and fragment spreads,
+// recording the variables and fragments it uses and reporting fields nested
+// deeper than the maximum depth
+func (l *linter) walk(set ast.SelectionSet, depth int, variables, fragments, visiting map[string]bool) {
+	for _, selection := range set {
+		switch sel := selection.(type) {
+		case *ast.Field:
+			collectArgumentVariables(sel.Arguments, variables)
+			collectDirectiveVariables(sel.Directives, variables)
+			if l.opts.MaxDepth > 0 && depth == l.opts.MaxDepth+1 && !l.reported[*sel.Position] {
+				l.reported[*sel.Position] = true
+				l.add(LintDeepNesting, sel.Position, "Field %q is nested %d levels deep (maximum %d)", sel.Alias, depth, l.opts.MaxDepth)
+			}
+			l.walk(sel.SelectionSet, depth+1, variables, fragments, visiting)
+		case *ast.InlineFragment:
+			collectDirectiveVariables(sel.Directives, variables)
+			l.walk(sel.SelectionSet, depth, variables, fragments, visiting)
+		case *ast.FragmentSpread:
+			collectDirectiveVariables(sel.Directives, variables)
+			fragments[sel.Name] = true
+			fragment := l.doc.Fragments.ForName(sel.Name)
+			if fragment == nil || visiting[sel.Name] {
+				continue
+			}
+			visiting[sel.Name] = true
+			l.walk(fragment.SelectionSet, depth, variables, fragments, visiting)
+			delete(visiting, sel.Name)
+		}
+	}
+}
+
+// checkTypename reports fields returning a union or interface whose selection set
+// does not include __typename, so clients can't tell the concrete type apart
+func (l *linter) checkTypename(set ast.SelectionSet) {
+	for _, selection := range set {
+		switch sel := selection.(type) {
+		case *ast.Field:
+			if sel.Definition != nil && len(sel.SelectionSet) > 0 {
+				typeName := sel.Definition.Type.Name()
+				if def := l.schema.Types[typeName]; def != nil && (def.Kind == ast.Union || def.Kind == ast.Interface) && !selectsTypename(sel.SelectionSet) {
+					kind := "union"
+					if def.Kind == ast.Interface {
+						kind = "interface"
+					}
+					l.add(LintMissingTypename, sel.Position, "Field %q returns %s %q but does not select __typename", sel.Alias, kind, typeName)
+				}
+			}
+			l.checkTypename(sel.SelectionSet)
+		case *ast.InlineFragment:
+			l.checkTypename(sel.SelectionSet)
+		}
+	}
+}
+
+// selectsTypename reports whether a selection set selects __typename directly
+func selectsTypename(set ast.SelectionSet) bool {
+	for _, selection := range set {
+		if field, ok := selection.(*ast.Field); ok && field.Name == "__typename" {
+			return true
+		}
+	}
+	return false
+}
+
+func collectDirectiveVariables(directives ast.DirectiveList, variables map[string]bool) {
+	for _, directive := range directives {
+		collectArgumentVariables(directive.Arguments, variables)
+	}
+}
+
+func collectArgumentVariables(args ast.ArgumentList, variables map[string]bool) {
+	for _, arg := range args {
+		collectValueVariables(arg.Value, variables)
+	}
+}
+
+func collectValueVariables(value *ast.Value, variables map[string]bool) {
+	if value == nil {
+		return
+	}
+	if value.Kind == ast.Variable {
+		variables[value.Raw] = true
+	}
+	for _, child := range value.Children {
+		collectValueVariables(child.Value, variables)
+	}
+}
@@ -0,0 +1,123 @@
+package gqlt
+
+import (
+	"reflect"
+	"testing"
+)
+
+const lintTestSDL = `
+type Query {
+  user(id: ID!): User
+  search(term: String!): [SearchResult!]!
+  node(id: ID!): Node
+}
+
+interface Node {
+  id: ID!
+}
+
+type User implements Node {
+  id: ID!
+  name: String
+  friends: [User!]!
+}
+
+type Post implements Node {
+  id: ID!
+  title: String
+}
+
+union SearchResult = User | Post
+`
+
+func TestAnalyzer_LintQuery(t *testing.T) {
+	analyzer, err := NewAnalyzerFromSDL(lintTestSDL)
+	if err != nil {
+		t.Fatalf("NewAnalyzerFromSDL failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		maxDepth int
+		want     []LintFinding
+	}{
+		{
+			name:  "clean query",
+			query: `query GetUser($id: ID!) { user(id: $id) { ...UserFields } } fragment UserFields on User { id name }`,
+		},
+		{
+			name:  "unused variable",
+			query: `query GetUser($id: ID!, $unused: String) { user(id: $id) { id } }`,
+			want: []LintFinding{
+				{Code: LintUnusedVariable, Message: `Variable "$unused" is never used in operation "GetUser"`, Line: 1, Column: 25},
+			},
+		},
+		{
+			name:  "variable used through fragment",
+			query: `query GetUser($id: ID!) { ...Root } fragment Root on Query { user(id: $id) { id } }`,
+		},
+		{
+			name:  "unused fragment",
+			query: `{ user(id: "1") { id } } fragment Unused on User { name }`,
+			want: []LintFinding{
+				{Code: LintUnusedFragment, Message: `Fragment "Unused" is never used`, Line: 1, Column: 26},
+			},
+		},
+		{
+			name:  "union without __typename",
+			query: `{ search(term: "a") { ... on User { id } ... on Post { title } } }`,
+			want: []LintFinding{
+				{Code: LintMissingTypename, Message: `Field "search" returns union "SearchResult" but does not select __typename`, Line: 1, Column: 3},
+			},
+		},
+		{
+			name:  "union with __typename",
+			query: `{ search(term: "a") { __typename ... on User { id } } }`,
+		},
+		{
+			name:  "interface without __typename",
+			query: `{ node(id: "1") { id } }`,
+			want: []LintFinding{
+				{Code: LintMissingTypename, Message: `Field "node" returns interface "Node" but does not select __typename`, Line: 1, Column: 3},
+			},
+		},
+		{
+			name:     "nesting beyond maximum",
+			query:    `{ user(id: "1") { friends { friends { id } } } }`,
+			maxDepth: 3,
+			want: []LintFinding{
+				{Code: LintDeepNesting, Message: `Field "id" is nested 4 levels deep (maximum 3)`, Line: 1, Column: 39},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultLintOptions()
+			if tt.maxDepth > 0 {
+				opts.MaxDepth = tt.maxDepth
+			}
+			findings, err := analyzer.LintQuery(tt.query, opts)
+			if err != nil {
+				t.Fatalf("LintQuery failed: %v", err)
+			}
+			if len(findings) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(findings, tt.want) {
+				t.Errorf("Expected findings %+v, got %+v", tt.want, findings)
+			}
+		})
+	}
+}
+
+func TestAnalyzer_LintQueryInvalid(t *testing.T) {
+	analyzer, err := NewAnalyzerFromSDL(lintTestSDL)
+	if err != nil {
+		t.Fatalf("NewAnalyzerFromSDL failed: %v", err)
+	}
+	if _, err := analyzer.LintQuery(`{ user(id: "1") { nmae } }`, DefaultLintOptions()); err == nil {
+		t.Error("Expected error for a query that fails validation")
+	}
+}
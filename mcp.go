@@ -2,8 +2,15 @@ package gqlt
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
 	"regexp"
 	"sync"
 	"time"
@@ -11,12 +18,30 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+// DefaultQueryTimeout bounds execute_query requests that don't specify timeoutMs
+const DefaultQueryTimeout = 30 * time.Second
+
+// DefaultSchemaCacheTTL is how long an introspected schema is served from the cache
+const DefaultSchemaCacheTTL = 10 * time.Minute
+
 // SDKServer wraps the official MCP SDK server with gqlt functionality
 type SDKServer struct {
 	server      *mcp.Server
 	client      *Client
-	schemaCache map[string]interface{} // endpoint -> schema data
+	schemaCache map[string]schemaCacheEntry // endpoint -> schema data
 	cacheMutex  sync.RWMutex
+	cacheTTL    time.Duration
+	now         func() time.Time // clock used for cache expiry, replaceable in tests
+	cacheDir    string           // optional directory for persisting cached schemas
+	httpServer  *http.Server     // set while serving over HTTP/SSE
+	httpMutex   sync.Mutex
+	allowRemote bool // whether Start may listen on non-loopback addresses
+}
+
+// schemaCacheEntry holds an introspected schema and when it was fetched
+type schemaCacheEntry struct {
+	data      interface{}
+	fetchedAt time.Time
 }
 
 // NewSDKServer creates a new MCP server using the official SDK
@@ -33,7 +58,9 @@ func NewSDKServer() (*SDKServer, error) {
 	sdkServer := &SDKServer{
 		server:      server,
 		client:      client,
-		schemaCache: make(map[string]interface{}),
+		schemaCache: make(map[string]schemaCacheEntry),
+		cacheTTL:    DefaultSchemaCacheTTL,
+		now:         time.Now,
 	}
 
 	// Register all tools using the official SDK pattern
@@ -46,20 +73,218 @@ func NewSDKServer() (*SDKServer, error) {
 	return sdkServer, nil
 }
 
-// Start starts the MCP server using stdin/stdout
+// Start starts the MCP server. With an empty address it serves over stdin/stdout;
+// otherwise it listens on the given host:port and serves over HTTP with server-sent events.
+// The HTTP server has no authentication, so unless SetAllowRemote was called only
+// loopback addresses are accepted.
 func (s *SDKServer) Start(ctx context.Context, address string) error {
-	log.Printf("Starting MCP server using stdin/stdout")
+	if address == "" {
+		log.Printf("Starting MCP server using stdin/stdout")
+
+		// Use the SDK's built-in stdin/stdout transport following the official pattern
+		return s.server.Run(ctx, &mcp.StdioTransport{})
+	}
+
+	if !s.allowRemote && !isLoopbackAddress(address) {
+		return fmt.Errorf("refusing to listen on %s: the HTTP/SSE server has no authentication, so only loopback addresses are allowed unless remote access is enabled", address)
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+
+	log.Printf("Starting MCP server using HTTP/SSE on %s", listener.Addr())
+	return s.serveHTTP(ctx, listener)
+}
+
+// isLoopbackAddress reports whether a host:port address only accepts connections from
+// the local machine. An empty host listens on all interfaces, so it doesn't.
+func isLoopbackAddress(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// servingHTTP reports whether the server is serving network clients over HTTP/SSE
+func (s *SDKServer) servingHTTP() bool {
+	s.httpMutex.Lock()
+	defer s.httpMutex.Unlock()
+	return s.httpServer != nil
+}
+
+// serveHTTP serves the MCP server over HTTP/SSE on the listener until the context
+// is cancelled or Stop is called
+func (s *SDKServer) serveHTTP(ctx context.Context, listener net.Listener) error {
+	handler := mcp.NewSSEHandler(func(*http.Request) *mcp.Server {
+		return s.server
+	}, nil)
+	httpServer := &http.Server{Handler: handler}
+
+	s.httpMutex.Lock()
+	s.httpServer = httpServer
+	s.httpMutex.Unlock()
+
+	// Shut down when the context is cancelled
+	go func() {
+		<-ctx.Done()
+		httpServer.Close()
+	}()
 
-	// Use the SDK's built-in stdin/stdout transport following the official pattern
-	return s.server.Run(ctx, &mcp.StdioTransport{})
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
 }
 
 // Stop stops the MCP server
 func (s *SDKServer) Stop(ctx context.Context) error {
-	// The SDK handles graceful shutdown
+	s.httpMutex.Lock()
+	httpServer := s.httpServer
+	s.httpMutex.Unlock()
+
+	// The SDK handles graceful shutdown of the stdio transport
+	if httpServer == nil {
+		return nil
+	}
+
+	// Open SSE streams never go idle, so force them closed if shutdown times out
+	if err := httpServer.Shutdown(ctx); err != nil {
+		httpServer.Close()
+		return err
+	}
 	return nil
 }
 
+// SetSchemaCacheTTL sets how long introspected schemas are cached before being
+// fetched again. A TTL of zero or less disables caching.
+func (s *SDKServer) SetSchemaCacheTTL(d time.Duration) {
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+	s.cacheTTL = d
+}
+
+// SetAllowRemote allows Start to listen on addresses other than loopback ones. The
+// HTTP/SSE server has no authentication, so anyone who can reach it can send requests
+// through it to any endpoint.
+func (s *SDKServer) SetAllowRemote(allow bool) {
+	s.allowRemote = allow
+}
+
+// SetCacheDir enables persisting introspected schemas to dir so they survive server
+// restarts. Persisted schemas are loaded on first use and ignored once older than the
+// cache TTL. An empty dir disables persistence.
+func (s *SDKServer) SetCacheDir(dir string) {
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+	s.cacheDir = dir
+}
+
+// schemaCachePath returns the file in cacheDir a schema for the endpoint is persisted to
+func schemaCachePath(cacheDir, endpoint string) string {
+	sum := sha256.Sum256([]byte(endpoint))
+	return filepath.Join(cacheDir, hex.EncodeToString(sum[:])+".json")
+}
+
+// loadPersistedSchema loads a schema for the endpoint from the cache directory into the
+// in-memory cache, if persistence is enabled and the file is not older than the cache TTL
+func (s *SDKServer) loadPersistedSchema(endpoint string) (interface{}, bool) {
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+
+	if s.cacheDir == "" {
+		return nil, false
+	}
+
+	path := schemaCachePath(s.cacheDir, endpoint)
+	info, err := os.Stat(path)
+	if err != nil || s.now().Sub(info.ModTime()) >= s.cacheTTL {
+		return nil, false
+	}
+
+	schema, err := LoadSchemaFromFile(path)
+	if err != nil || schema.Data == nil {
+		log.Printf("Ignoring unreadable cached schema %s: %v", path, err)
+		return nil, false
+	}
+
+	s.schemaCache[endpoint] = schemaCacheEntry{data: schema.Data, fetchedAt: info.ModTime()}
+	return schema.Data, true
+}
+
+// cachedSchema returns the introspected schema for an endpoint, serving it from the
+// cache unless noCache is set or the cached entry is older than the cache TTL
+func (s *SDKServer) cachedSchema(endpoint string, headers map[string]string, noCache bool) (interface{}, error) {
+	// Check cache first, unless noCache is true
+	if !noCache {
+		s.cacheMutex.RLock()
+		entry, exists := s.schemaCache[endpoint]
+		fresh := exists && s.now().Sub(entry.fetchedAt) < s.cacheTTL
+		s.cacheMutex.RUnlock()
+		if fresh {
+			return entry.data, nil
+		}
+
+		// Fall back to a schema persisted by an earlier server
+		if data, ok := s.loadPersistedSchema(endpoint); ok {
+			return data, nil
+		}
+	}
+
+	client := s.clientFor(endpoint, headers)
+
+	// Introspect the schema
+	result, err := client.Introspect()
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if introspection returned data
+	if result.Data == nil {
+		errorMsg := "Schema introspection returned no data"
+		if len(result.Errors) > 0 {
+			if errMap, ok := result.Errors[0].(map[string]interface{}); ok {
+				if msg, ok := errMap["message"].(string); ok {
+					errorMsg = msg
+				}
+			}
+		}
+		return nil, fmt.Errorf("%s", errorMsg)
+	}
+
+	// Cache the schema
+	s.cacheMutex.Lock()
+	s.schemaCache[endpoint] = schemaCacheEntry{data: result.Data, fetchedAt: s.now()}
+	cacheDir := s.cacheDir
+	s.cacheMutex.Unlock()
+
+	// Persisting is best effort; the in-memory cache still works without it
+	if cacheDir != "" {
+		if err := SaveSchema(result, schemaCachePath(cacheDir, endpoint)); err != nil {
+			log.Printf("Failed to persist schema for %s: %v", endpoint, err)
+		}
+	}
+
+	return result.Data, nil
+}
+
+// clientFor returns a client for an endpoint and set of headers. Clients aren't
+// pooled, so callers never share cookies and nothing accumulates per set of headers;
+// keep-alive connections are still reused through the transport all clients share.
+func (s *SDKServer) clientFor(endpoint string, headers map[string]string) *Client {
+	client := NewClient(endpoint, nil)
+	if len(headers) > 0 {
+		client.SetHeaders(headers)
+	}
+	return client
+}
+
 // registerTools registers all MCP tools with the server
 func (s *SDKServer) registerTools() error {
 	// Add GraphQL execution tool
@@ -68,6 +293,12 @@ func (s *SDKServer) registerTools() error {
 		Description: "Execute a GraphQL query, mutation, or subscription",
 	}, s.handleExecuteQuery)
 
+	// Add batch execution tool
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "execute_batch",
+		Description: "Execute several GraphQL queries or mutations against one endpoint in a single call",
+	}, s.handleExecuteBatch)
+
 	// Add type description tool
 	mcp.AddTool(s.server, &mcp.Tool{
 		Name:        "describe_type",
@@ -80,6 +311,24 @@ func (s *SDKServer) registerTools() error {
 		Description: "List GraphQL type names with optional filtering",
 	}, s.handleListTypes)
 
+	// Add field search tool
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "search_fields",
+		Description: "Find fields whose name matches a regex across all object, interface, and input types",
+	}, s.handleSearchFields)
+
+	// Add type comparison tool
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "compare_types",
+		Description: "Compare the fields of two GraphQL types in the same schema",
+	}, s.handleCompareTypes)
+
+	// Add schema introspection tool
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "introspect_schema",
+		Description: "Fetch the full GraphQL schema as SDL or introspection JSON",
+	}, s.handleIntrospectSchema)
+
 	// Add version tool
 	mcp.AddTool(s.server, &mcp.Tool{
 		Name:        "version",
@@ -103,6 +352,7 @@ type ExecuteQueryInput struct {
 	Files         map[string]string      `json:"files,omitempty" jsonschema:"File uploads (variable name to local file path mapping, e.g. {'avatar': '/path/to/photo.jpg'})"`
 	Timeout       string                 `json:"timeout,omitempty" jsonschema:"Subscription timeout duration (e.g., '30s', '1m') - only for subscriptions, default: 30s"`
 	MaxMessages   int                    `json:"maxMessages,omitempty" jsonschema:"Maximum number of subscription messages to collect - only for subscriptions, default: unlimited"`
+	TimeoutMs     int                    `json:"timeoutMs,omitempty" jsonschema:"Query and mutation timeout in milliseconds - not used for subscriptions, default: 30000"`
 }
 
 // ExecuteQueryOutput defines the output schema for the execute_query tool
@@ -112,6 +362,35 @@ type ExecuteQueryOutput struct {
 	ElapsedMs int64       `json:"elapsed_ms" jsonschema:"Query execution time in milliseconds"`
 }
 
+// BatchOperation is one operation of an execute_batch call
+type BatchOperation struct {
+	Query         string                 `json:"query" jsonschema:"The GraphQL query string"`
+	Variables     map[string]interface{} `json:"variables,omitempty" jsonschema:"Variables to pass to the query"`
+	OperationName string                 `json:"operationName,omitempty" jsonschema:"The operation name to execute"`
+}
+
+// ExecuteBatchInput defines the input schema for the execute_batch tool
+type ExecuteBatchInput struct {
+	Operations []BatchOperation  `json:"operations" jsonschema:"The queries and mutations to execute, in order"`
+	Endpoint   string            `json:"endpoint" jsonschema:"GraphQL endpoint URL shared by all operations"`
+	Headers    map[string]string `json:"headers,omitempty" jsonschema:"HTTP headers to include with every operation"`
+	TimeoutMs  int               `json:"timeoutMs,omitempty" jsonschema:"Timeout for each operation in milliseconds, default: 30000"`
+}
+
+// BatchResult is the outcome of one operation of an execute_batch call
+type BatchResult struct {
+	Data      interface{} `json:"data" jsonschema:"The GraphQL response data"`
+	Errors    interface{} `json:"errors,omitempty" jsonschema:"Any GraphQL errors"`
+	Error     string      `json:"error,omitempty" jsonschema:"Why the operation couldn't be executed, if it couldn't"`
+	ElapsedMs int64       `json:"elapsed_ms" jsonschema:"Operation execution time in milliseconds"`
+}
+
+// ExecuteBatchOutput defines the output schema for the execute_batch tool
+type ExecuteBatchOutput struct {
+	Results   []BatchResult `json:"results" jsonschema:"The result of each operation, in the order given"`
+	ElapsedMs int64         `json:"elapsed_ms" jsonschema:"Total execution time in milliseconds"`
+}
+
 // DescribeTypeInput defines the input schema for the describe_type tool
 type DescribeTypeInput struct {
 	TypeName   string            `json:"typeName" jsonschema:"The GraphQL type name to describe"`
@@ -142,6 +421,62 @@ type ListTypesOutput struct {
 	Count     int      `json:"count" jsonschema:"Total number of matching types"`
 }
 
+// SearchFieldsInput defines the input schema for the search_fields tool
+type SearchFieldsInput struct {
+	Endpoint     string            `json:"endpoint" jsonschema:"GraphQL endpoint URL"`
+	FieldPattern string            `json:"fieldPattern" jsonschema:"Regex pattern to match field names (e.g., 'email', '^created', '(?i)id$')"`
+	Headers      map[string]string `json:"headers,omitempty" jsonschema:"HTTP headers to include"`
+}
+
+// FieldMatch describes a field found by the search_fields tool
+type FieldMatch struct {
+	TypeName  string `json:"typeName" jsonschema:"The type that declares the field"`
+	FieldName string `json:"fieldName" jsonschema:"The matching field name"`
+	FieldType string `json:"fieldType" jsonschema:"The field's GraphQL type (e.g., 'String!', '[User!]!')"`
+}
+
+// SearchFieldsOutput defines the output schema for the search_fields tool
+type SearchFieldsOutput struct {
+	Matches []FieldMatch `json:"matches" jsonschema:"Fields matching the pattern"`
+	Count   int          `json:"count" jsonschema:"Total number of matching fields"`
+}
+
+// CompareTypesInput defines the input schema for the compare_types tool
+type CompareTypesInput struct {
+	Endpoint string            `json:"endpoint" jsonschema:"GraphQL endpoint URL"`
+	TypeA    string            `json:"typeA" jsonschema:"The first type name to compare"`
+	TypeB    string            `json:"typeB" jsonschema:"The second type name to compare"`
+	Headers  map[string]string `json:"headers,omitempty" jsonschema:"HTTP headers to include"`
+}
+
+// FieldTypeDifference describes a field present in both compared types with different types
+type FieldTypeDifference struct {
+	FieldName string `json:"fieldName" jsonschema:"The field name"`
+	TypeA     string `json:"typeA" jsonschema:"The field's type in the first type"`
+	TypeB     string `json:"typeB" jsonschema:"The field's type in the second type"`
+}
+
+// CompareTypesOutput defines the output schema for the compare_types tool
+type CompareTypesOutput struct {
+	OnlyInA   []string              `json:"onlyInA" jsonschema:"Fields only present in the first type"`
+	OnlyInB   []string              `json:"onlyInB" jsonschema:"Fields only present in the second type"`
+	Differing []FieldTypeDifference `json:"differing" jsonschema:"Fields present in both types with differing types"`
+}
+
+// IntrospectSchemaInput defines the input schema for the introspect_schema tool
+type IntrospectSchemaInput struct {
+	Endpoint string            `json:"endpoint" jsonschema:"GraphQL endpoint URL"`
+	Headers  map[string]string `json:"headers,omitempty" jsonschema:"HTTP headers to include"`
+	Format   string            `json:"format,omitempty" jsonschema:"Output format: 'sdl' (default) or 'json' for the raw introspection result"`
+}
+
+// IntrospectSchemaOutput defines the output schema for the introspect_schema tool
+type IntrospectSchemaOutput struct {
+	Format string      `json:"format" jsonschema:"The format of the returned schema"`
+	SDL    string      `json:"sdl,omitempty" jsonschema:"The schema in GraphQL SDL (format 'sdl')"`
+	Schema interface{} `json:"schema,omitempty" jsonschema:"The raw introspection result (format 'json')"`
+}
+
 // VersionInput defines the input schema for the version tool
 type VersionInput struct {
 	// No input parameters required
@@ -175,13 +510,28 @@ func (s *SDKServer) handleExecuteQuery(ctx context.Context, req *mcp.CallToolReq
 		return s.handleSubscription(ctx, input)
 	}
 
-	// Create a new client for this specific endpoint
-	client := NewClient(input.Endpoint, nil)
+	// Network clients must not be able to read files on the server's host
+	if len(input.Files) > 0 && s.servingHTTP() {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: "File uploads are not available when the server is serving over HTTP/SSE",
+				},
+			},
+			IsError: true,
+		}, ExecuteQueryOutput{}, nil
+	}
+
+	// Create a client for this endpoint and headers (its connections are pooled)
+	client := s.clientFor(input.Endpoint, input.Headers)
 
-	// Set headers if provided
-	if len(input.Headers) > 0 {
-		client.SetHeaders(input.Headers)
+	// Bound the request so a hung endpoint can't wedge the server
+	timeout := DefaultQueryTimeout
+	if input.TimeoutMs > 0 {
+		timeout = time.Duration(input.TimeoutMs) * time.Millisecond
 	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
 	// Execute the query with or without files
 	start := time.Now()
@@ -189,15 +539,26 @@ func (s *SDKServer) handleExecuteQuery(ctx context.Context, req *mcp.CallToolReq
 	var execErr error
 
 	if len(input.Files) > 0 {
-		// Use ExecuteWithFiles for file uploads
-		result, execErr = client.ExecuteWithFiles(input.Query, input.Variables, input.OperationName, input.Files)
+		// Use ExecuteWithFilesContext for file uploads
+		result, execErr = client.ExecuteWithFilesContext(timeoutCtx, input.Query, input.Variables, input.OperationName, input.Files)
 	} else {
-		// Use regular Execute for queries without files
-		result, execErr = client.Execute(input.Query, input.Variables, input.OperationName)
+		// Use regular ExecuteContext for queries without files
+		result, execErr = client.ExecuteContext(timeoutCtx, input.Query, input.Variables, input.OperationName)
 	}
 
 	elapsed := time.Since(start)
 
+	if errors.Is(execErr, context.DeadlineExceeded) {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: fmt.Sprintf("Query timed out after %v", timeout),
+				},
+			},
+			IsError: true,
+		}, ExecuteQueryOutput{}, nil
+	}
+
 	if execErr != nil {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
@@ -216,6 +577,78 @@ func (s *SDKServer) handleExecuteQuery(ctx context.Context, req *mcp.CallToolReq
 	}, nil
 }
 
+// handleExecuteBatch executes the operations of a batch one after another against
+// the endpoint, so later operations see the effects of earlier mutations. An
+// operation that fails doesn't stop the ones after it; its result carries the
+// error instead. Subscriptions aren't supported in a batch.
+func (s *SDKServer) handleExecuteBatch(ctx context.Context, req *mcp.CallToolRequest, input ExecuteBatchInput) (
+	*mcp.CallToolResult,
+	ExecuteBatchOutput,
+	error,
+) {
+	if len(input.Operations) == 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: "Batch has no operations",
+				},
+			},
+			IsError: true,
+		}, ExecuteBatchOutput{}, nil
+	}
+
+	start := time.Now()
+	results := make([]BatchResult, len(input.Operations))
+	for i, op := range input.Operations {
+		opStart := time.Now()
+
+		if opInfo, err := DetectOperationType(op.Query, op.OperationName); err == nil && opInfo.Type == OperationTypeSubscription {
+			results[i] = BatchResult{Error: "Subscriptions are not supported in a batch; use execute_query"}
+			continue
+		}
+
+		result, output, err := s.handleExecuteQuery(ctx, req, ExecuteQueryInput{
+			Query:         op.Query,
+			Variables:     op.Variables,
+			OperationName: op.OperationName,
+			Endpoint:      input.Endpoint,
+			Headers:       input.Headers,
+			TimeoutMs:     input.TimeoutMs,
+		})
+		if err != nil {
+			return nil, ExecuteBatchOutput{}, err
+		}
+		if result != nil && result.IsError {
+			results[i] = BatchResult{Error: toolResultText(result), ElapsedMs: time.Since(opStart).Milliseconds()}
+			continue
+		}
+		results[i] = BatchResult{
+			Data:      output.Data,
+			Errors:    output.Errors,
+			ElapsedMs: output.ElapsedMs,
+		}
+	}
+
+	return nil, ExecuteBatchOutput{
+		Results:   results,
+		ElapsedMs: time.Since(start).Milliseconds(),
+	}, nil
+}
+
+// toolResultText joins the text content of a tool result
+func toolResultText(result *mcp.CallToolResult) string {
+	var text string
+	for _, content := range result.Content {
+		if textContent, ok := content.(*mcp.TextContent); ok {
+			if text != "" {
+				text += "\n"
+			}
+			text += textContent.Text
+		}
+	}
+	return text
+}
+
 // handleSubscription handles GraphQL subscriptions with timeout and message limits
 func (s *SDKServer) handleSubscription(ctx context.Context, input ExecuteQueryInput) (
 	*mcp.CallToolResult,
@@ -245,7 +678,7 @@ func (s *SDKServer) handleSubscription(ctx context.Context, input ExecuteQueryIn
 
 	// Create client and subscribe
 	// Note: Client.Subscribe() will handle WebSocket/SSE protocol detection and fallback
-	client := NewClient(input.Endpoint, input.Headers)
+	client := s.clientFor(input.Endpoint, input.Headers)
 	messages, errors, err := client.Subscribe(timeoutCtx, input.Query, input.Variables, input.OperationName)
 	if err != nil {
 		return &mcp.CallToolResult{
@@ -349,9 +782,6 @@ func (s *SDKServer) handleDescribeType(ctx context.Context, req *mcp.CallToolReq
 	DescribeTypeOutput,
 	error,
 ) {
-	var schemaData interface{}
-	var cacheKey string
-
 	// If SchemaFile is provided, load from local file
 	if input.SchemaFile != "" {
 		analyzer, err := LoadAnalyzerFromFile(input.SchemaFile)
@@ -383,67 +813,20 @@ func (s *SDKServer) handleDescribeType(ctx context.Context, req *mcp.CallToolReq
 	}
 
 	// Use endpoint for schema introspection
-	cacheKey = input.Endpoint
-	var exists bool
-
-	// Check cache first, unless NoCache is true
-	if !input.NoCache {
-		s.cacheMutex.RLock()
-		schemaData, exists = s.schemaCache[cacheKey]
-		s.cacheMutex.RUnlock()
-	}
-
-	// If not in cache or NoCache is true, introspect and cache it
-	if !exists || input.NoCache {
-		client := NewClient(input.Endpoint, nil)
-
-		// Set headers if provided
-		if len(input.Headers) > 0 {
-			client.SetHeaders(input.Headers)
-		}
-
-		// Introspect the schema
-		result, err := client.Introspect()
-		if err != nil {
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					&mcp.TextContent{
-						Text: fmt.Sprintf("Schema introspection failed: %v", err),
-					},
-				},
-				IsError: true,
-			}, DescribeTypeOutput{}, nil
-		}
-
-		// Check if introspection returned data
-		if result.Data == nil {
-			errorMsg := "Schema introspection returned no data"
-			if len(result.Errors) > 0 {
-				if errMap, ok := result.Errors[0].(map[string]interface{}); ok {
-					if msg, ok := errMap["message"].(string); ok {
-						errorMsg = msg
-					}
-				}
-			}
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					&mcp.TextContent{
-						Text: fmt.Sprintf("Schema introspection failed: %s", errorMsg),
-					},
+	schemaData, err := s.cachedSchema(input.Endpoint, input.Headers, input.NoCache)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: fmt.Sprintf("Schema introspection failed: %v", err),
 				},
-				IsError: true,
-			}, DescribeTypeOutput{}, nil
-		}
-
-		// Cache the schema
-		s.cacheMutex.Lock()
-		s.schemaCache[cacheKey] = result.Data
-		schemaData = result.Data
-		s.cacheMutex.Unlock()
+			},
+			IsError: true,
+		}, DescribeTypeOutput{}, nil
 	}
 
 	// Parse the schema to find the specific type
-	typeInfo, err := s.extractTypeInfo(schemaData, input.TypeName, cacheKey)
+	typeInfo, err := s.extractTypeInfo(schemaData, input.TypeName, input.Endpoint)
 	if err != nil {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
@@ -465,9 +848,6 @@ func (s *SDKServer) handleListTypes(ctx context.Context, req *mcp.CallToolReques
 	ListTypesOutput,
 	error,
 ) {
-	var schemaData interface{}
-	var cacheKey string
-
 	// If SchemaFile is provided, load from local file
 	if input.SchemaFile != "" {
 		analyzer, err := LoadAnalyzerFromFile(input.SchemaFile)
@@ -483,7 +863,7 @@ func (s *SDKServer) handleListTypes(ctx context.Context, req *mcp.CallToolReques
 		}
 
 		// Get schema data from analyzer
-		schemaData = analyzer.schemaData
+		schemaData := analyzer.schemaData
 
 		// List matching types
 		typeNames, err := s.listMatchingTypes(map[string]interface{}{"__schema": schemaData}, input.Filter, input.Kind)
@@ -505,101 +885,189 @@ func (s *SDKServer) handleListTypes(ctx context.Context, req *mcp.CallToolReques
 	}
 
 	// Use endpoint for schema introspection
-	cacheKey = input.Endpoint
-	var exists bool
+	schemaData, err := s.cachedSchema(input.Endpoint, input.Headers, input.NoCache)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: fmt.Sprintf("Schema introspection failed: %v", err),
+				},
+			},
+			IsError: true,
+		}, ListTypesOutput{TypeNames: []string{}, Count: 0}, nil
+	}
 
-	// Check cache first, unless NoCache is true
-	if !input.NoCache {
-		s.cacheMutex.RLock()
-		schemaData, exists = s.schemaCache[cacheKey]
-		s.cacheMutex.RUnlock()
+	// Parse the schema to find matching types
+	typeNames, err := s.listMatchingTypes(schemaData, input.Filter, input.Kind)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: fmt.Sprintf("Failed to list types: %v", err),
+				},
+			},
+			IsError: true,
+		}, ListTypesOutput{TypeNames: []string{}, Count: 0}, nil
 	}
 
-	// If not in cache or NoCache is true, introspect and cache it
-	if !exists || input.NoCache {
-		client := NewClient(input.Endpoint, nil)
+	return nil, ListTypesOutput{
+		TypeNames: typeNames,
+		Count:     len(typeNames),
+	}, nil
+}
 
-		// Set headers if provided
-		if len(input.Headers) > 0 {
-			client.SetHeaders(input.Headers)
-		}
+func (s *SDKServer) handleSearchFields(ctx context.Context, req *mcp.CallToolRequest, input SearchFieldsInput) (
+	*mcp.CallToolResult,
+	SearchFieldsOutput,
+	error,
+) {
+	schemaData, err := s.cachedSchema(input.Endpoint, input.Headers, false)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: fmt.Sprintf("Schema introspection failed: %v", err),
+				},
+			},
+			IsError: true,
+		}, SearchFieldsOutput{Matches: []FieldMatch{}, Count: 0}, nil
+	}
 
-		// Introspect the schema
-		result, err := client.Introspect()
-		if err != nil {
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					&mcp.TextContent{
-						Text: fmt.Sprintf("Schema introspection failed: %v", err),
-					},
+	matches, err := s.searchFields(schemaData, input.FieldPattern)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: fmt.Sprintf("Failed to search fields: %v", err),
 				},
-				IsError: true,
-			}, ListTypesOutput{TypeNames: []string{}, Count: 0}, nil
-		}
+			},
+			IsError: true,
+		}, SearchFieldsOutput{Matches: []FieldMatch{}, Count: 0}, nil
+	}
 
-		// Check if introspection returned data
-		if result.Data == nil {
-			errorMsg := "Schema introspection returned no data"
-			if len(result.Errors) > 0 {
-				if errMap, ok := result.Errors[0].(map[string]interface{}); ok {
-					if msg, ok := errMap["message"].(string); ok {
-						errorMsg = msg
-					}
-				}
-			}
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					&mcp.TextContent{
-						Text: fmt.Sprintf("Schema introspection failed: %s", errorMsg),
-					},
+	return nil, SearchFieldsOutput{
+		Matches: matches,
+		Count:   len(matches),
+	}, nil
+}
+
+func (s *SDKServer) handleCompareTypes(ctx context.Context, req *mcp.CallToolRequest, input CompareTypesInput) (
+	*mcp.CallToolResult,
+	CompareTypesOutput,
+	error,
+) {
+	schemaData, err := s.cachedSchema(input.Endpoint, input.Headers, false)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: fmt.Sprintf("Schema introspection failed: %v", err),
 				},
-				IsError: true,
-			}, ListTypesOutput{TypeNames: []string{}, Count: 0}, nil
-		}
+			},
+			IsError: true,
+		}, CompareTypesOutput{}, nil
+	}
 
-		// Cache the schema
-		s.cacheMutex.Lock()
-		s.schemaCache[cacheKey] = result.Data
-		schemaData = result.Data
-		s.cacheMutex.Unlock()
+	output, err := s.compareTypes(schemaData, input.TypeA, input.TypeB)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: fmt.Sprintf("Failed to compare types: %v", err),
+				},
+			},
+			IsError: true,
+		}, CompareTypesOutput{}, nil
 	}
 
-	// Parse the schema to find matching types
-	typeNames, err := s.listMatchingTypes(schemaData, input.Filter, input.Kind)
+	return nil, output, nil
+}
+
+func (s *SDKServer) handleIntrospectSchema(ctx context.Context, req *mcp.CallToolRequest, input IntrospectSchemaInput) (
+	*mcp.CallToolResult,
+	IntrospectSchemaOutput,
+	error,
+) {
+	format := input.Format
+	if format == "" {
+		format = "sdl"
+	}
+	if format != "sdl" && format != "json" {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: fmt.Sprintf("Invalid format '%s': must be 'sdl' or 'json'", input.Format),
+				},
+			},
+			IsError: true,
+		}, IntrospectSchemaOutput{}, nil
+	}
+
+	schemaData, err := s.cachedSchema(input.Endpoint, input.Headers, false)
 	if err != nil {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
 				&mcp.TextContent{
-					Text: fmt.Sprintf("Failed to list types: %v", err),
+					Text: fmt.Sprintf("Schema introspection failed: %v", err),
 				},
 			},
 			IsError: true,
-		}, ListTypesOutput{TypeNames: []string{}, Count: 0}, nil
+		}, IntrospectSchemaOutput{}, nil
 	}
 
-	return nil, ListTypesOutput{
-		TypeNames: typeNames,
-		Count:     len(typeNames),
+	if format == "json" {
+		return nil, IntrospectSchemaOutput{
+			Format: format,
+			Schema: schemaData,
+		}, nil
+	}
+
+	sdl, err := convertIntrospectionToSDL(&Response{Data: schemaData})
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: fmt.Sprintf("Failed to convert schema to SDL: %v", err),
+				},
+			},
+			IsError: true,
+		}, IntrospectSchemaOutput{}, nil
+	}
+
+	return nil, IntrospectSchemaOutput{
+		Format: format,
+		SDL:    sdl,
 	}, nil
 }
 
 // extractTypeInfo parses the schema data to extract information about a specific type
 func (s *SDKServer) extractTypeInfo(schemaData interface{}, typeName, endpoint string) (string, error) {
+	targetType, err := s.findType(schemaData, typeName)
+	if err != nil {
+		return "", err
+	}
+
+	// Format the type information
+	return s.formatTypeDefinition(targetType, typeName, endpoint), nil
+}
+
+// findType returns the introspection definition of a type in the schema data
+func (s *SDKServer) findType(schemaData interface{}, typeName string) (map[string]interface{}, error) {
 	// Parse the schema structure
 	schemaMap, ok := schemaData.(map[string]interface{})
 	if !ok {
-		return "", fmt.Errorf("invalid schema data format")
+		return nil, fmt.Errorf("invalid schema data format")
 	}
 
 	// Navigate to the schema types
 	__schema, ok := schemaMap["__schema"].(map[string]interface{})
 	if !ok {
-		return "", fmt.Errorf("schema missing __schema field")
+		return nil, fmt.Errorf("schema missing __schema field")
 	}
 
 	types, ok := __schema["types"].([]interface{})
 	if !ok {
-		return "", fmt.Errorf("schema missing types array")
+		return nil, fmt.Errorf("schema missing types array")
 	}
 
 	// Find the specific type
@@ -614,11 +1082,73 @@ func (s *SDKServer) extractTypeInfo(schemaData interface{}, typeName, endpoint s
 	}
 
 	if targetType == nil {
-		return "", fmt.Errorf("type '%s' not found in schema", typeName)
+		return nil, fmt.Errorf("type '%s' not found in schema", typeName)
 	}
 
-	// Format the type information
-	return s.formatTypeDefinition(targetType, typeName, endpoint), nil
+	return targetType, nil
+}
+
+// compareTypes compares the fields (or input fields) of two types in the schema data
+func (s *SDKServer) compareTypes(schemaData interface{}, typeA, typeB string) (CompareTypesOutput, error) {
+	defA, err := s.findType(schemaData, typeA)
+	if err != nil {
+		return CompareTypesOutput{}, err
+	}
+	defB, err := s.findType(schemaData, typeB)
+	if err != nil {
+		return CompareTypesOutput{}, err
+	}
+
+	namesA, typesA := s.fieldTypes(defA)
+	namesB, typesB := s.fieldTypes(defB)
+
+	// Initialize as empty slices, not nil
+	output := CompareTypesOutput{
+		OnlyInA:   []string{},
+		OnlyInB:   []string{},
+		Differing: []FieldTypeDifference{},
+	}
+
+	for _, name := range namesA {
+		other, ok := typesB[name]
+		if !ok {
+			output.OnlyInA = append(output.OnlyInA, name)
+		} else if other != typesA[name] {
+			output.Differing = append(output.Differing, FieldTypeDifference{
+				FieldName: name,
+				TypeA:     typesA[name],
+				TypeB:     other,
+			})
+		}
+	}
+	for _, name := range namesB {
+		if _, ok := typesA[name]; !ok {
+			output.OnlyInB = append(output.OnlyInB, name)
+		}
+	}
+
+	return output, nil
+}
+
+// fieldTypes returns the field (or input field) names of a type definition in
+// declaration order, along with their formatted types
+func (s *SDKServer) fieldTypes(typeDef map[string]interface{}) ([]string, map[string]string) {
+	fields, ok := typeDef["fields"].([]interface{})
+	if !ok {
+		fields, _ = typeDef["inputFields"].([]interface{})
+	}
+
+	var names []string
+	types := make(map[string]string, len(fields))
+	for _, field := range fields {
+		if fieldDef, ok := field.(map[string]interface{}); ok {
+			name, _ := fieldDef["name"].(string)
+			typeInfo, _ := fieldDef["type"].(map[string]interface{})
+			names = append(names, name)
+			types[name] = s.formatType(typeInfo)
+		}
+	}
+	return names, types
 }
 
 // formatTypeDefinition formats a type definition into a readable string
@@ -824,6 +1354,69 @@ func (s *SDKServer) listMatchingTypes(schemaData interface{}, filter, kind strin
 	return matchingTypes, nil
 }
 
+// searchFields finds fields of object, interface, and input types whose name matches the pattern
+func (s *SDKServer) searchFields(schemaData interface{}, pattern string) ([]FieldMatch, error) {
+	// Parse the schema structure
+	schemaMap, ok := schemaData.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid schema data format")
+	}
+
+	// Navigate to the schema types
+	__schema, ok := schemaMap["__schema"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("schema missing __schema field")
+	}
+
+	types, ok := __schema["types"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("schema missing types array")
+	}
+
+	matches := []FieldMatch{} // Initialize as empty slice, not nil
+
+	for _, typeItem := range types {
+		typeDef, ok := typeItem.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		typeName, _ := typeDef["name"].(string)
+		// Skip introspection types
+		if typeName == "" || typeName[0] == '_' {
+			continue
+		}
+
+		var fields []interface{}
+		switch typeDef["kind"] {
+		case "OBJECT", "INTERFACE":
+			fields, _ = typeDef["fields"].([]interface{})
+		case "INPUT_OBJECT":
+			fields, _ = typeDef["inputFields"].([]interface{})
+		default:
+			continue
+		}
+
+		for _, field := range fields {
+			fieldDef, ok := field.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			fieldName, _ := fieldDef["name"].(string)
+			if !s.matchesRegex(fieldName, pattern) {
+				continue
+			}
+			fieldType, _ := fieldDef["type"].(map[string]interface{})
+			matches = append(matches, FieldMatch{
+				TypeName:  typeName,
+				FieldName: fieldName,
+				FieldType: s.formatType(fieldType),
+			})
+		}
+	}
+
+	return matches, nil
+}
+
 // matchesRegex performs regex pattern matching
 func (s *SDKServer) matchesRegex(name, pattern string) bool {
 	// Compile the regex pattern
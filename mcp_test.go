@@ -2,14 +2,21 @@ package gqlt
 
 import (
 	"context"
+	"encoding/json"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"slices"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/kluzzebass/gqlt/internal/mockserver/graph"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -703,3 +710,626 @@ func TestSDKServer_handleExecuteQuery_Subscription(t *testing.T) {
 		}
 	}
 }
+
+// newCountingSchemaServer serves an introspection result for the given SDL and counts requests
+func newCountingSchemaServer(t *testing.T, sdl string, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	introspection, err := SDLToIntrospection(sdl)
+	if err != nil {
+		t.Fatalf("SDLToIntrospection failed: %v", err)
+	}
+	body, err := json.Marshal(map[string]interface{}{"data": introspection})
+	if err != nil {
+		t.Fatalf("Failed to marshal introspection: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSDKServer_SchemaCacheTTL(t *testing.T) {
+	var requests atomic.Int32
+	graphqlServer := newCountingSchemaServer(t, `type Query { user: User } type User { id: ID! }`, &requests)
+
+	server, err := NewSDKServer()
+	if err != nil {
+		t.Fatalf("Failed to create SDK server: %v", err)
+	}
+	if server.cacheTTL != DefaultSchemaCacheTTL {
+		t.Errorf("Expected default TTL %v, got %v", DefaultSchemaCacheTTL, server.cacheTTL)
+	}
+
+	// Replace the clock so expiry can be simulated
+	clock := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	server.now = func() time.Time { return clock }
+	server.SetSchemaCacheTTL(time.Minute)
+
+	ctx := context.Background()
+	req := &mcp.CallToolRequest{}
+	describe := DescribeTypeInput{TypeName: "User", Endpoint: graphqlServer.URL}
+	list := ListTypesInput{Endpoint: graphqlServer.URL}
+
+	if result, _, _ := server.handleDescribeType(ctx, req, describe); result != nil {
+		t.Fatalf("handleDescribeType failed: %+v", result)
+	}
+	if requests.Load() != 1 {
+		t.Fatalf("Expected 1 introspection request, got %d", requests.Load())
+	}
+
+	// Within the TTL both tools are served from the cache
+	clock = clock.Add(30 * time.Second)
+	server.handleDescribeType(ctx, req, describe)
+	server.handleListTypes(ctx, req, list)
+	if requests.Load() != 1 {
+		t.Errorf("Expected cached schema within TTL, got %d requests", requests.Load())
+	}
+
+	// After the TTL the schema is introspected again
+	clock = clock.Add(time.Minute)
+	if result, _, _ := server.handleListTypes(ctx, req, list); result != nil {
+		t.Fatalf("handleListTypes failed: %+v", result)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("Expected re-introspection after TTL, got %d requests", requests.Load())
+	}
+
+	// The refreshed entry is cached again
+	server.handleDescribeType(ctx, req, describe)
+	if requests.Load() != 2 {
+		t.Errorf("Expected refreshed schema to be cached, got %d requests", requests.Load())
+	}
+}
+
+func TestSDKServer_SchemaCacheTTL_Disabled(t *testing.T) {
+	var requests atomic.Int32
+	graphqlServer := newCountingSchemaServer(t, `type Query { hello: String }`, &requests)
+
+	server, err := NewSDKServer()
+	if err != nil {
+		t.Fatalf("Failed to create SDK server: %v", err)
+	}
+	server.SetSchemaCacheTTL(0)
+
+	ctx := context.Background()
+	req := &mcp.CallToolRequest{}
+	input := ListTypesInput{Endpoint: graphqlServer.URL}
+
+	server.handleListTypes(ctx, req, input)
+	server.handleListTypes(ctx, req, input)
+	if requests.Load() != 2 {
+		t.Errorf("Expected every call to introspect with caching disabled, got %d requests", requests.Load())
+	}
+}
+
+// newMockGraphQLServer starts the gqlt mock GraphQL server for the duration of a test
+func newMockGraphQLServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: graph.NewResolver()}))
+	srv.AddTransport(transport.POST{})
+	srv.Use(extension.Introspection{})
+
+	server := httptest.NewServer(srv)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSDKServer_handleIntrospectSchema(t *testing.T) {
+	mockServer := newMockGraphQLServer(t)
+
+	server, err := NewSDKServer()
+	if err != nil {
+		t.Fatalf("Failed to create SDK server: %v", err)
+	}
+
+	ctx := context.Background()
+	req := &mcp.CallToolRequest{}
+
+	t.Run("sdl", func(t *testing.T) {
+		result, output, err := server.handleIntrospectSchema(ctx, req, IntrospectSchemaInput{
+			Endpoint: mockServer.URL,
+		})
+		if err != nil {
+			t.Fatalf("handleIntrospectSchema failed: %v", err)
+		}
+		if result != nil {
+			t.Fatalf("Result should be nil for successful execution: %+v", result)
+		}
+		if output.Format != "sdl" {
+			t.Errorf("Expected default format 'sdl', got %q", output.Format)
+		}
+		for _, s := range []string{"type User", "email: String!", "enum TodoStatus"} {
+			if !strings.Contains(output.SDL, s) {
+				t.Errorf("Expected SDL to contain %q", s)
+			}
+		}
+		if output.Schema != nil {
+			t.Error("Expected no introspection JSON for SDL format")
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		result, output, err := server.handleIntrospectSchema(ctx, req, IntrospectSchemaInput{
+			Endpoint: mockServer.URL,
+			Format:   "json",
+		})
+		if err != nil {
+			t.Fatalf("handleIntrospectSchema failed: %v", err)
+		}
+		if result != nil {
+			t.Fatalf("Result should be nil for successful execution: %+v", result)
+		}
+		analyzer, err := NewAnalyzer(&Response{Data: output.Schema})
+		if err != nil {
+			t.Fatalf("Expected introspection JSON, got error: %v", err)
+		}
+		if _, err := analyzer.FindType("User"); err != nil {
+			t.Errorf("Expected User type in introspection JSON: %v", err)
+		}
+		if output.SDL != "" {
+			t.Error("Expected no SDL for JSON format")
+		}
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		result, _, err := server.handleIntrospectSchema(ctx, req, IntrospectSchemaInput{
+			Endpoint: mockServer.URL,
+			Format:   "xml",
+		})
+		if err != nil {
+			t.Fatalf("handleIntrospectSchema failed: %v", err)
+		}
+		if result == nil || !result.IsError {
+			t.Error("Expected error result for invalid format")
+		}
+	})
+}
+
+func TestSDKServer_handleSearchFields(t *testing.T) {
+	mockServer := newMockGraphQLServer(t)
+
+	server, err := NewSDKServer()
+	if err != nil {
+		t.Fatalf("Failed to create SDK server: %v", err)
+	}
+
+	ctx := context.Background()
+	req := &mcp.CallToolRequest{}
+
+	result, output, err := server.handleSearchFields(ctx, req, SearchFieldsInput{
+		Endpoint:     mockServer.URL,
+		FieldPattern: "^email$",
+	})
+	if err != nil {
+		t.Fatalf("handleSearchFields failed: %v", err)
+	}
+	if result != nil {
+		t.Fatalf("Result should be nil for successful execution: %+v", result)
+	}
+	if output.Count != len(output.Matches) {
+		t.Errorf("Count %d does not match %d matches", output.Count, len(output.Matches))
+	}
+
+	want := FieldMatch{TypeName: "User", FieldName: "email", FieldType: "String!"}
+	if !slices.Contains(output.Matches, want) {
+		t.Errorf("Expected matches to contain %+v, got %+v", want, output.Matches)
+	}
+	for _, match := range output.Matches {
+		if match.FieldName != "email" {
+			t.Errorf("Unexpected match: %+v", match)
+		}
+		if strings.HasPrefix(match.TypeName, "__") {
+			t.Errorf("Introspection types should be skipped: %+v", match)
+		}
+	}
+
+	// No matches yields an empty list, not nil
+	_, output, _ = server.handleSearchFields(ctx, req, SearchFieldsInput{
+		Endpoint:     mockServer.URL,
+		FieldPattern: "^doesNotExist$",
+	})
+	if output.Matches == nil || output.Count != 0 {
+		t.Errorf("Expected empty matches, got %+v", output)
+	}
+}
+
+func TestSDKServer_handleExecuteQuery_Timeout(t *testing.T) {
+	// Server that never answers before the client gives up
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer slowServer.Close()
+
+	server, err := NewSDKServer()
+	if err != nil {
+		t.Fatalf("Failed to create SDK server: %v", err)
+	}
+
+	input := ExecuteQueryInput{
+		Query:     `query { hello }`,
+		Endpoint:  slowServer.URL,
+		TimeoutMs: 50,
+	}
+
+	start := time.Now()
+	result, _, err := server.handleExecuteQuery(context.Background(), &mcp.CallToolRequest{}, input)
+	if err != nil {
+		t.Fatalf("handleExecuteQuery should not return error: %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("Query should have been aborted by the timeout")
+	}
+
+	if result == nil || !result.IsError {
+		t.Fatal("Expected error result for timed out query")
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "timed out") {
+		t.Errorf("Expected timeout error, got: %s", text)
+	}
+}
+
+func TestSDKServer_StartHTTP(t *testing.T) {
+	server, err := NewSDKServer()
+	if err != nil {
+		t.Fatalf("Failed to create SDK server: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.serveHTTP(ctx, listener)
+	}()
+
+	// Connect a client over the network and call a tool
+	client := mcp.NewClient(&mcp.Implementation{Name: "gqlt-test-client", Version: "test"}, nil)
+	session, err := client.Connect(ctx, &mcp.SSEClientTransport{Endpoint: "http://" + listener.Addr().String()}, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to MCP server: %v", err)
+	}
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "version"})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("Expected successful tool call, got %+v", result)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, Version()) {
+		t.Errorf("Expected version %q in result, got: %s", Version(), text)
+	}
+
+	// Network clients can't make the server read local files
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{
+		Name: "execute_query",
+		Arguments: map[string]interface{}{
+			"query":    "mutation ($file: Upload!) { upload(file: $file) }",
+			"endpoint": "http://127.0.0.1:1/graphql",
+			"files":    map[string]interface{}{"file": "/etc/passwd"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if !result.IsError || !strings.Contains(result.Content[0].(*mcp.TextContent).Text, "File uploads are not available") {
+		t.Errorf("Expected file uploads to be rejected over HTTP, got %+v", result)
+	}
+	session.Close()
+
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), time.Second)
+	defer stopCancel()
+	if err := server.Stop(stopCtx); err != nil {
+		t.Errorf("Stop failed: %v", err)
+	}
+
+	select {
+	case err := <-serverErr:
+		if err != nil {
+			t.Errorf("serveHTTP returned error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("Server did not stop")
+	}
+}
+
+func TestSDKServer_StartHTTP_InvalidAddress(t *testing.T) {
+	server, err := NewSDKServer()
+	if err != nil {
+		t.Fatalf("Failed to create SDK server: %v", err)
+	}
+
+	if err := server.Start(context.Background(), "not-an-address"); err == nil {
+		t.Error("Expected error for invalid listen address")
+	}
+}
+
+func TestSDKServer_StartHTTP_RemoteAddress(t *testing.T) {
+	server, err := NewSDKServer()
+	if err != nil {
+		t.Fatalf("Failed to create SDK server: %v", err)
+	}
+
+	for _, address := range []string{":0", "0.0.0.0:0", "example.com:8091"} {
+		err := server.Start(context.Background(), address)
+		if err == nil || !strings.Contains(err.Error(), "refusing to listen") {
+			t.Errorf("Expected %s to be refused, got %v", address, err)
+		}
+	}
+}
+
+func TestIsLoopbackAddress(t *testing.T) {
+	tests := map[string]bool{
+		"localhost:8091": true,
+		"127.0.0.1:8091": true,
+		"[::1]:8091":     true,
+		":8091":          false,
+		"0.0.0.0:8091":   false,
+		"10.0.0.1:8091":  false,
+		"example.com:80": false,
+		"not-an-address": false,
+	}
+	for address, want := range tests {
+		if got := isLoopbackAddress(address); got != want {
+			t.Errorf("isLoopbackAddress(%q) = %v, want %v", address, got, want)
+		}
+	}
+}
+
+func TestSDKServer_SetCacheDir(t *testing.T) {
+	var requests atomic.Int32
+	graphqlServer := newCountingSchemaServer(t, `type Query { user: User } type User { id: ID! }`, &requests)
+	cacheDir := t.TempDir()
+
+	ctx := context.Background()
+	req := &mcp.CallToolRequest{}
+	input := DescribeTypeInput{TypeName: "User", Endpoint: graphqlServer.URL}
+
+	// The first server introspects and persists the schema
+	first, err := NewSDKServer()
+	if err != nil {
+		t.Fatalf("Failed to create SDK server: %v", err)
+	}
+	first.SetCacheDir(cacheDir)
+	if result, _, _ := first.handleDescribeType(ctx, req, input); result != nil {
+		t.Fatalf("handleDescribeType failed: %+v", result)
+	}
+	if requests.Load() != 1 {
+		t.Fatalf("Expected 1 introspection request, got %d", requests.Load())
+	}
+
+	files, err := os.ReadDir(cacheDir)
+	if err != nil || len(files) != 1 {
+		t.Fatalf("Expected one persisted schema, got %v (err: %v)", files, err)
+	}
+
+	// A new server loads the persisted schema without hitting the network
+	second, err := NewSDKServer()
+	if err != nil {
+		t.Fatalf("Failed to create SDK server: %v", err)
+	}
+	second.SetCacheDir(cacheDir)
+	result, output, _ := second.handleDescribeType(ctx, req, input)
+	if result != nil {
+		t.Fatalf("handleDescribeType failed: %+v", result)
+	}
+	if !strings.Contains(output.TypeInfo, "Type: User") {
+		t.Errorf("Expected User type info, got: %s", output.TypeInfo)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Expected persisted schema to be used, got %d requests", requests.Load())
+	}
+
+	// Persisted schemas older than the TTL are ignored
+	third, err := NewSDKServer()
+	if err != nil {
+		t.Fatalf("Failed to create SDK server: %v", err)
+	}
+	third.SetCacheDir(cacheDir)
+	third.now = func() time.Time { return time.Now().Add(DefaultSchemaCacheTTL + time.Minute) }
+	if result, _, _ := third.handleDescribeType(ctx, req, input); result != nil {
+		t.Fatalf("handleDescribeType failed: %+v", result)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("Expected expired schema to be re-introspected, got %d requests", requests.Load())
+	}
+}
+
+func TestSDKServer_handleCompareTypes(t *testing.T) {
+	var requests atomic.Int32
+	graphqlServer := newCountingSchemaServer(t, `
+		type Query { user: User admin: AdminUser }
+
+		type User {
+			id: ID!
+			name: String!
+			email: String
+			age: Int
+		}
+
+		type AdminUser {
+			id: ID!
+			name: String
+			email: String
+			permissions: [String!]!
+		}
+	`, &requests)
+
+	server, err := NewSDKServer()
+	if err != nil {
+		t.Fatalf("Failed to create SDK server: %v", err)
+	}
+
+	ctx := context.Background()
+	req := &mcp.CallToolRequest{}
+
+	result, output, err := server.handleCompareTypes(ctx, req, CompareTypesInput{
+		Endpoint: graphqlServer.URL,
+		TypeA:    "User",
+		TypeB:    "AdminUser",
+	})
+	if err != nil {
+		t.Fatalf("handleCompareTypes failed: %v", err)
+	}
+	if result != nil {
+		t.Fatalf("Result should be nil for successful execution: %+v", result)
+	}
+
+	if !slices.Equal(output.OnlyInA, []string{"age"}) {
+		t.Errorf("Expected onlyInA [age], got %v", output.OnlyInA)
+	}
+	if !slices.Equal(output.OnlyInB, []string{"permissions"}) {
+		t.Errorf("Expected onlyInB [permissions], got %v", output.OnlyInB)
+	}
+	want := []FieldTypeDifference{{FieldName: "name", TypeA: "String!", TypeB: "String"}}
+	if !slices.Equal(output.Differing, want) {
+		t.Errorf("Expected differing %+v, got %+v", want, output.Differing)
+	}
+
+	// Unknown types produce an error result
+	result, _, _ = server.handleCompareTypes(ctx, req, CompareTypesInput{
+		Endpoint: graphqlServer.URL,
+		TypeA:    "User",
+		TypeB:    "Missing",
+	})
+	if result == nil || !result.IsError {
+		t.Error("Expected error result for unknown type")
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Expected the schema to be introspected once, got %d requests", requests.Load())
+	}
+}
+
+func TestSDKServer_clientFor(t *testing.T) {
+	server, err := NewSDKServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	headers := map[string]string{"X-Tenant": "a", "Authorization": "Bearer t"}
+	a := server.clientFor("https://api.example.com/graphql", headers)
+	b := server.clientFor("https://api.example.com/graphql", headers)
+	if a.headers["X-Tenant"] != "a" || a.endpoint != "https://api.example.com/graphql" {
+		t.Errorf("Expected client to carry the endpoint and headers, got %s %v", a.endpoint, a.headers)
+	}
+
+	// Callers with the same headers share connections but not cookies
+	if a.httpClient.Transport != sharedTransport || b.httpClient.Transport != sharedTransport {
+		t.Error("Expected clients to use the shared transport")
+	}
+	if a.httpClient.Jar == b.httpClient.Jar {
+		t.Error("Expected clients not to share a cookie jar")
+	}
+}
+
+func TestSDKServer_clientForReusesConnections(t *testing.T) {
+	var connections atomic.Int32
+	mockServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"hello":"world"}}`))
+	}))
+	mockServer.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	mockServer.Start()
+	defer mockServer.Close()
+
+	server, err := NewSDKServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		result, _, err := server.handleExecuteQuery(context.Background(), &mcp.CallToolRequest{}, ExecuteQueryInput{
+			Query:    "{ hello }",
+			Endpoint: mockServer.URL,
+		})
+		if err != nil || (result != nil && result.IsError) {
+			t.Fatalf("handleExecuteQuery failed: %v %+v", err, result)
+		}
+	}
+	if got := connections.Load(); got != 1 {
+		t.Errorf("Expected sequential calls to reuse one connection, got %d", got)
+	}
+}
+
+func TestSDKServer_handleExecuteBatch(t *testing.T) {
+	mockServer := newMockGraphQLServer(t)
+
+	server, err := NewSDKServer()
+	if err != nil {
+		t.Fatalf("Failed to create SDK server: %v", err)
+	}
+
+	ctx := context.Background()
+	req := &mcp.CallToolRequest{}
+
+	input := ExecuteBatchInput{
+		Operations: []BatchOperation{
+			{Query: `query { hello }`},
+			{Query: `query Echo($message: String!) { echo(message: $message) }`, Variables: map[string]interface{}{"message": "hi"}, OperationName: "Echo"},
+		},
+		Endpoint: mockServer.URL,
+	}
+
+	result, output, err := server.handleExecuteBatch(ctx, req, input)
+	if err != nil {
+		t.Fatalf("handleExecuteBatch failed: %v", err)
+	}
+	if result != nil {
+		t.Fatalf("Result should be nil for successful execution, got: %v", result.Content)
+	}
+	if len(output.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(output.Results))
+	}
+	for i, r := range output.Results {
+		if errs, _ := r.Errors.([]interface{}); r.Error != "" || len(errs) > 0 {
+			t.Errorf("Result %d should have no errors, got %q %v", i, r.Error, r.Errors)
+		}
+		if r.ElapsedMs < 0 {
+			t.Errorf("Result %d has a negative elapsed time", i)
+		}
+	}
+	if data, _ := output.Results[0].Data.(map[string]interface{}); data["hello"] == nil {
+		t.Errorf("Expected hello in the first result, got %v", output.Results[0].Data)
+	}
+	if data, _ := output.Results[1].Data.(map[string]interface{}); data["echo"] != "hi" {
+		t.Errorf("Expected the echoed message in the second result, got %v", output.Results[1].Data)
+	}
+
+	// A failing operation gets an error without stopping the batch
+	input.Operations[0] = BatchOperation{Query: `subscription { tick }`}
+	_, output, err = server.handleExecuteBatch(ctx, req, input)
+	if err != nil {
+		t.Fatalf("handleExecuteBatch failed: %v", err)
+	}
+	if !strings.Contains(output.Results[0].Error, "not supported") {
+		t.Errorf("Expected the subscription to be rejected, got %q", output.Results[0].Error)
+	}
+	if data, _ := output.Results[1].Data.(map[string]interface{}); data["echo"] != "hi" {
+		t.Errorf("Expected the second operation to run anyway, got %v", output.Results[1].Data)
+	}
+
+	result, _, err = server.handleExecuteBatch(ctx, req, ExecuteBatchInput{Endpoint: mockServer.URL})
+	if err != nil {
+		t.Fatalf("handleExecuteBatch failed: %v", err)
+	}
+	if result == nil || !result.IsError {
+		t.Error("Expected an error result for an empty batch")
+	}
+}
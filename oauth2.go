@@ -0,0 +1,38 @@
+package gqlt
+
+import (
+	"context"
+	"net/http"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// SetOAuth2 authenticates requests with a bearer token obtained from tokenURL
+// with the OAuth2 client credentials flow. The token is fetched before the first
+// request, cached, and fetched again once it expires. It replaces any
+// Authorization header set by SetHeaders or SetAuth. Token requests use the
+// client's TLS and proxy settings.
+//
+// Example:
+//
+//	client.SetOAuth2("https://auth.example.com/oauth/token", "client-id", "client-secret", []string{"read:users"})
+func (c *Client) SetOAuth2(tokenURL, clientID, clientSecret string, scopes []string) {
+	c.oauth2 = &clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     tokenURL,
+		Scopes:       scopes,
+	}
+	c.updateTransport()
+}
+
+// oauth2Transport wraps base so requests carry a token from the client credentials
+// flow, fetching tokens through base as well
+func (c *Client) oauth2Transport(base http.RoundTripper) http.RoundTripper {
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: base})
+	return &oauth2.Transport{
+		Source: c.oauth2.TokenSource(ctx),
+		Base:   base,
+	}
+}
@@ -0,0 +1,107 @@
+package gqlt
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newTokenServer starts a client credentials token endpoint issuing numbered
+// tokens that expire after expiresIn seconds
+func newTokenServer(t *testing.T, expiresIn int) (*httptest.Server, *int64) {
+	t.Helper()
+	var issued int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("Failed to parse token request: %v", err)
+		}
+		if r.Form.Get("grant_type") != "client_credentials" {
+			t.Errorf("Expected the client_credentials grant, got %q", r.Form.Get("grant_type"))
+		}
+		if r.Form.Get("scope") != "read:users write:users" {
+			t.Errorf("Expected the requested scopes, got %q", r.Form.Get("scope"))
+		}
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok || clientID != "my-client" || clientSecret != "my-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		n := atomic.AddInt64(&issued, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d}`, n, expiresIn)
+	}))
+	t.Cleanup(server.Close)
+	return server, &issued
+}
+
+// newAuthorizationEchoServer starts a GraphQL server answering with the
+// Authorization header of each request
+func newAuthorizationEchoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"data":{"authorization":%q}}`, r.Header.Get("Authorization"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func executeAuthorization(t *testing.T, client *Client) string {
+	t.Helper()
+	response, err := client.Execute(`{ authorization }`, nil, "")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	return response.Data.(map[string]interface{})["authorization"].(string)
+}
+
+func TestClientSetOAuth2(t *testing.T) {
+	tokenServer, issued := newTokenServer(t, 3600)
+	api := newAuthorizationEchoServer(t)
+
+	client := NewClient(api.URL, map[string]string{"Authorization": "Bearer stale"})
+	client.SetOAuth2(tokenServer.URL, "my-client", "my-secret", []string{"read:users", "write:users"})
+
+	for i := 0; i < 3; i++ {
+		if got := executeAuthorization(t, client); got != "Bearer token-1" {
+			t.Errorf("Expected the fetched token, got %q", got)
+		}
+	}
+	if n := atomic.LoadInt64(issued); n != 1 {
+		t.Errorf("Expected the token to be cached, got %d token requests", n)
+	}
+}
+
+func TestClientSetOAuth2Refresh(t *testing.T) {
+	// Tokens expiring within seconds count as expired, so every request refreshes
+	tokenServer, issued := newTokenServer(t, 1)
+	api := newAuthorizationEchoServer(t)
+
+	client := NewClient(api.URL, nil)
+	client.SetOAuth2(tokenServer.URL, "my-client", "my-secret", []string{"read:users", "write:users"})
+
+	if got := executeAuthorization(t, client); got != "Bearer token-1" {
+		t.Errorf("Expected the first token, got %q", got)
+	}
+	if got := executeAuthorization(t, client); got != "Bearer token-2" {
+		t.Errorf("Expected a refreshed token, got %q", got)
+	}
+	if n := atomic.LoadInt64(issued); n != 2 {
+		t.Errorf("Expected 2 token requests, got %d", n)
+	}
+}
+
+func TestClientSetOAuth2InvalidCredentials(t *testing.T) {
+	tokenServer, _ := newTokenServer(t, 3600)
+	api := newAuthorizationEchoServer(t)
+
+	client := NewClient(api.URL, nil)
+	client.SetOAuth2(tokenServer.URL, "my-client", "wrong", []string{"read:users", "write:users"})
+	if _, err := client.Execute(`{ authorization }`, nil, ""); err == nil || !strings.Contains(err.Error(), "invalid_client") {
+		t.Errorf("Expected the token endpoint's error, got %v", err)
+	}
+}
@@ -1,9 +1,14 @@
 package gqlt
 
 import (
+	"bytes"
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 
 	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
 	"github.com/vektah/gqlparser/v2/parser"
 )
 
@@ -25,6 +30,8 @@ type OperationInfo struct {
 // DetectOperationType parses a GraphQL document and detects the operation type.
 // If operationName is provided, it finds that specific operation.
 // If operationName is empty and there's only one operation, it uses that one.
+// The document is parsed with the GraphQL parser rather than inspected textually,
+// so comments, shorthand queries and fragments don't affect detection.
 // Returns an error if the operation can't be determined or doesn't exist.
 func DetectOperationType(query string, operationName string) (*OperationInfo, error) {
 	// Parse the GraphQL document without schema validation (syntax only)
@@ -43,29 +50,21 @@ func DetectOperationType(query string, operationName string) (*OperationInfo, er
 		return nil, fmt.Errorf("no operations found in query")
 	}
 
-	var targetOp *ast.OperationDefinition
-
-	// If operation name is specified, find it
-	if operationName != "" {
-		for _, op := range doc.Operations {
-			if op.Name == operationName {
-				targetOp = op
-				break
-			}
-		}
-		if targetOp == nil {
-			return nil, fmt.Errorf("operation '%s' not found in query", operationName)
-		}
-	} else {
-		// No operation name specified
-		if len(doc.Operations) > 1 {
-			return nil, fmt.Errorf("query contains multiple operations, please specify --operation")
+	// Operation names must be unique within a document
+	seen := make(map[string]bool, len(doc.Operations))
+	for _, op := range doc.Operations {
+		if op.Name == "" {
+			continue
 		}
-		// Use the single operation
-		for _, op := range doc.Operations {
-			targetOp = op
-			break
+		if seen[op.Name] {
+			return nil, fmt.Errorf("query defines multiple operations named '%s'", op.Name)
 		}
+		seen[op.Name] = true
+	}
+
+	targetOp, err := selectOperation(doc, operationName)
+	if err != nil {
+		return nil, err
 	}
 
 	// Determine operation type
@@ -86,3 +85,238 @@ func DetectOperationType(query string, operationName string) (*OperationInfo, er
 		Name: targetOp.Name,
 	}, nil
 }
+
+// selectOperation returns the named operation of a document, or its only operation
+// if operationName is empty
+func selectOperation(doc *ast.QueryDocument, operationName string) (*ast.OperationDefinition, error) {
+	// If operation name is specified, find it
+	if operationName != "" {
+		op := doc.Operations.ForName(operationName)
+		if op == nil {
+			return nil, fmt.Errorf("operation '%s' not found in query (available operations: %s)", operationName, operationNames(doc.Operations))
+		}
+		return op, nil
+	}
+
+	// No operation name specified
+	if len(doc.Operations) > 1 {
+		return nil, fmt.Errorf("query contains multiple operations, please specify --operation (available operations: %s)", operationNames(doc.Operations))
+	}
+	// Use the single operation
+	return doc.Operations[0], nil
+}
+
+// ExtractOperation returns a document holding only one operation of query and
+// the fragments it uses, directly or through other fragments, so a single
+// operation of a file with several can be sent on its own. The operation is
+// selected as in DetectOperationType. The result is formatted as by FormatQuery,
+// with operations and fragments in their original order.
+//
+// Example:
+//
+//	query, err := gqlt.ExtractOperation(document, "GetUser")
+func ExtractOperation(query, operationName string) (string, error) {
+	doc, gqlErr := parser.ParseQuery(&ast.Source{Name: "query", Input: query})
+	if gqlErr != nil {
+		return "", fmt.Errorf("failed to parse GraphQL query: %w", gqlErr)
+	}
+	if len(doc.Operations) == 0 {
+		return "", fmt.Errorf("no operations found in query")
+	}
+
+	op, err := selectOperation(doc, operationName)
+	if err != nil {
+		return "", err
+	}
+
+	used := make(map[string]bool)
+	if err := collectFragments(doc, op.SelectionSet, used); err != nil {
+		return "", err
+	}
+
+	extracted := &ast.QueryDocument{Operations: ast.OperationList{op}}
+	for _, fragment := range doc.Fragments {
+		if used[fragment.Name] {
+			extracted.Fragments = append(extracted.Fragments, fragment)
+		}
+	}
+
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf, formatter.WithIndent(FormatIndent), formatter.WithComments()).FormatQueryDocument(extracted)
+	return buf.String(), nil
+}
+
+// collectFragments records the names of the fragments a selection set spreads,
+// following the fragments it finds
+func collectFragments(doc *ast.QueryDocument, set ast.SelectionSet, used map[string]bool) error {
+	for _, selection := range set {
+		switch sel := selection.(type) {
+		case *ast.Field:
+			if err := collectFragments(doc, sel.SelectionSet, used); err != nil {
+				return err
+			}
+		case *ast.InlineFragment:
+			if err := collectFragments(doc, sel.SelectionSet, used); err != nil {
+				return err
+			}
+		case *ast.FragmentSpread:
+			if used[sel.Name] {
+				continue
+			}
+			fragment := doc.Fragments.ForName(sel.Name)
+			if fragment == nil {
+				return fmt.Errorf("fragment '%s' is not defined", sel.Name)
+			}
+			used[sel.Name] = true
+			if err := collectFragments(doc, fragment.SelectionSet, used); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateVariables checks variables against the variable definitions of an
+// operation before it is sent. It reports required variables (non-null without a
+// default) that are missing or null, variables the operation does not declare, and
+// values that don't match a built-in scalar type (Int, Float, String, Boolean, ID).
+// Custom scalars, enums and input objects need the schema and are not checked.
+// The operation is selected as in DetectOperationType; an error is returned only if
+// it can't be determined.
+//
+// Example:
+//
+//	problems, err := gqlt.ValidateVariables(`query ($id: ID!) { user(id: $id) { name } }`, "", nil)
+//	// problems[0].Message == `missing required variable "$id" of type ID!`
+func ValidateVariables(query, operationName string, variables map[string]interface{}) ([]ValidationError, error) {
+	doc, gqlErr := parser.ParseQuery(&ast.Source{Name: "query", Input: query})
+	if gqlErr != nil {
+		return nil, fmt.Errorf("failed to parse GraphQL query: %w", gqlErr)
+	}
+	if len(doc.Operations) == 0 {
+		return nil, fmt.Errorf("no operations found in query")
+	}
+	op, err := selectOperation(doc, operationName)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := []ValidationError{}
+	add := func(pos *ast.Position, format string, args ...interface{}) {
+		problem := ValidationError{Message: fmt.Sprintf(format, args...), Rule: "VariablesMatchOperation"}
+		if pos != nil {
+			problem.Line = pos.Line
+			problem.Column = pos.Column
+		}
+		problems = append(problems, problem)
+	}
+
+	for _, def := range op.VariableDefinitions {
+		value, provided := variables[def.Variable]
+		if !provided {
+			if def.Type.NonNull && def.DefaultValue == nil {
+				add(def.Position, "missing required variable \"$%s\" of type %s", def.Variable, def.Type.String())
+			}
+			continue
+		}
+		if problem := checkVariableValue(value, def.Type); problem != "" {
+			add(def.Position, "variable \"$%s\" of type %s %s", def.Variable, def.Type.String(), problem)
+		}
+	}
+
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if op.VariableDefinitions.ForName(name) == nil {
+			add(op.Position, "unexpected variable \"$%s\" is not declared by the operation", name)
+		}
+	}
+
+	return problems, nil
+}
+
+// checkVariableValue checks a decoded JSON value against a variable type and
+// describes the mismatch, or returns an empty string if the value is acceptable
+func checkVariableValue(value interface{}, typ *ast.Type) string {
+	if value == nil {
+		if typ.NonNull {
+			return "must not be null"
+		}
+		return ""
+	}
+
+	if typ.Elem != nil {
+		list, ok := value.([]interface{})
+		if !ok {
+			// A single value is coerced to a list of one item
+			return checkVariableValue(value, typ.Elem)
+		}
+		for i, item := range list {
+			if problem := checkVariableValue(item, typ.Elem); problem != "" {
+				return fmt.Sprintf("has an item at index %d that %s", i, problem)
+			}
+		}
+		return ""
+	}
+
+	var ok bool
+	switch typ.NamedType {
+	case "Int":
+		var n float64
+		n, ok = value.(float64)
+		ok = ok && n == math.Trunc(n)
+	case "Float":
+		_, ok = value.(float64)
+	case "String":
+		_, ok = value.(string)
+	case "Boolean":
+		_, ok = value.(bool)
+	case "ID":
+		switch v := value.(type) {
+		case string:
+			ok = true
+		case float64:
+			ok = v == math.Trunc(v)
+		}
+	default:
+		return ""
+	}
+	if !ok {
+		return fmt.Sprintf("cannot be %s", jsonKind(value))
+	}
+	return ""
+}
+
+// jsonKind names the JSON type of a decoded value for error messages
+func jsonKind(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return fmt.Sprintf("string %q", v)
+	case float64:
+		return fmt.Sprintf("number %v", v)
+	case bool:
+		return fmt.Sprintf("boolean %v", v)
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// operationNames lists the names of the operations in a document for error messages
+func operationNames(ops ast.OperationList) string {
+	names := make([]string, 0, len(ops))
+	for _, op := range ops {
+		if op.Name == "" {
+			names = append(names, "(anonymous)")
+		} else {
+			names = append(names, op.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,291 @@
+package gqlt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectOperationType(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		operationName string
+		wantType      OperationType
+		wantName      string
+		wantErr       []string
+	}{
+		{
+			name:     "single anonymous query",
+			query:    `{ users { id } }`,
+			wantType: OperationTypeQuery,
+		},
+		{
+			name:     "shorthand query with leading whitespace",
+			query:    "\n\t  \n{ user(id: \"1\") { id name } }",
+			wantType: OperationTypeQuery,
+		},
+		{
+			name: "commented document",
+			query: `# mutation Fake { noop }
+# subscription Also { fake }
+query GetUser { # trailing comment
+  user(id: "1") { id } # mutation Inline { noop }
+}`,
+			wantType: OperationTypeQuery,
+			wantName: "GetUser",
+		},
+		{
+			name:     "subscription",
+			query:    `subscription OnMessage($room: ID!) { messageAdded(room: $room) { id text } }`,
+			wantType: OperationTypeSubscription,
+			wantName: "OnMessage",
+		},
+		{
+			name:     "anonymous mutation",
+			query:    `mutation { createUser(input: {name: "query { x }"}) { id } }`,
+			wantType: OperationTypeMutation,
+		},
+		{
+			name: "operation selected alongside fragments",
+			query: `fragment UserFields on User { id name }
+subscription OnUser { userAdded { ...UserFields } }
+query GetUsers { users { ...UserFields } }`,
+			operationName: "OnUser",
+			wantType:      OperationTypeSubscription,
+			wantName:      "OnUser",
+		},
+		{
+			name:    "fragments only",
+			query:   `fragment UserFields on User { id name }`,
+			wantErr: []string{"no operations found"},
+		},
+		{
+			name:          "named operation selected from several",
+			query:         `query GetUsers { users { id } } mutation CreateUser { createUser(input: {name: "a"}) { id } }`,
+			operationName: "CreateUser",
+			wantType:      OperationTypeMutation,
+			wantName:      "CreateUser",
+		},
+		{
+			name:    "ambiguous without operation name",
+			query:   `query GetUsers { users { id } } query GetUser { user(id: "1") { id } }`,
+			wantErr: []string{"multiple operations", "GetUsers, GetUser"},
+		},
+		{
+			name:          "operation name not found",
+			query:         `query GetUsers { users { id } } query GetUser { user(id: "1") { id } }`,
+			operationName: "Missing",
+			wantErr:       []string{"operation 'Missing' not found", "GetUsers, GetUser"},
+		},
+		{
+			name:          "duplicate operation names",
+			query:         `query GetUsers { users { id } } query GetUsers { users { name } }`,
+			operationName: "GetUsers",
+			wantErr:       []string{"multiple operations named 'GetUsers'"},
+		},
+		{
+			name:    "syntax error",
+			query:   `query {`,
+			wantErr: []string{"failed to parse GraphQL query"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := DetectOperationType(tt.query, tt.operationName)
+			if len(tt.wantErr) > 0 {
+				if err == nil {
+					t.Fatalf("Expected error, got %+v", info)
+				}
+				for _, s := range tt.wantErr {
+					if !strings.Contains(err.Error(), s) {
+						t.Errorf("Expected error to contain %q, got %q", s, err.Error())
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DetectOperationType failed: %v", err)
+			}
+			if info.Type != tt.wantType {
+				t.Errorf("Expected type %s, got %s", tt.wantType, info.Type)
+			}
+			if info.Name != tt.wantName {
+				t.Errorf("Expected name %q, got %q", tt.wantName, info.Name)
+			}
+		})
+	}
+}
+
+func TestValidateVariables(t *testing.T) {
+	query := `query Search($term: String!, $limit: Int = 10, $ids: [ID!], $filter: Filter) { search(term: $term, limit: $limit, ids: $ids, filter: $filter) { id } }`
+
+	tests := []struct {
+		name      string
+		variables map[string]interface{}
+		want      []string
+	}{
+		{
+			name:      "required only",
+			variables: map[string]interface{}{"term": "gql"},
+		},
+		{
+			name:      "all variables with custom input object",
+			variables: map[string]interface{}{"term": "gql", "limit": float64(5), "ids": []interface{}{"1", float64(2)}, "filter": map[string]interface{}{"any": true}},
+		},
+		{
+			name:      "single value coerced to list",
+			variables: map[string]interface{}{"term": "gql", "ids": "1"},
+		},
+		{
+			name:      "missing required",
+			variables: map[string]interface{}{"limit": float64(5)},
+			want:      []string{`missing required variable "$term" of type String!`},
+		},
+		{
+			name:      "null for non-null",
+			variables: map[string]interface{}{"term": nil},
+			want:      []string{`variable "$term" of type String! must not be null`},
+		},
+		{
+			name:      "wrong types",
+			variables: map[string]interface{}{"term": "gql", "limit": 2.5, "ids": []interface{}{"1", nil}},
+			want: []string{
+				`variable "$limit" of type Int cannot be number 2.5`,
+				`variable "$ids" of type [ID!] has an item at index 1 that must not be null`,
+			},
+		},
+		{
+			name:      "unexpected variables",
+			variables: map[string]interface{}{"term": "gql", "page": float64(2), "after": "x"},
+			want: []string{
+				`unexpected variable "$after" is not declared by the operation`,
+				`unexpected variable "$page" is not declared by the operation`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems, err := ValidateVariables(query, "", tt.variables)
+			if err != nil {
+				t.Fatalf("ValidateVariables failed: %v", err)
+			}
+			var got []string
+			for _, problem := range problems {
+				got = append(got, problem.Message)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Expected problems %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := ValidateVariables(`query A { a } query B { b }`, "", nil); err == nil {
+		t.Error("Expected error when the operation can't be determined")
+	}
+}
+
+func TestExtractOperation(t *testing.T) {
+	document := `query GetUser($id: ID!) {
+  user(id: $id) {
+    ...UserFields
+  }
+}
+
+mutation CreatePost($title: String!) {
+  createPost(title: $title) {
+    ...PostFields
+  }
+}
+
+fragment UserFields on User {
+  id
+  name
+}
+
+fragment PostFields on Post {
+  title
+  author {
+    ... on User {
+      ...UserFields
+    }
+  }
+}
+`
+
+	tests := []struct {
+		name          string
+		operationName string
+		expected      string
+		expectError   string
+	}{
+		{
+			name:          "operation with a fragment",
+			operationName: "GetUser",
+			expected: `query GetUser ($id: ID!) {
+  user(id: $id) {
+    ... UserFields
+  }
+}
+fragment UserFields on User {
+  id
+  name
+}
+`,
+		},
+		{
+			name:          "fragments used through other fragments",
+			operationName: "CreatePost",
+			expected: `mutation CreatePost ($title: String!) {
+  createPost(title: $title) {
+    ... PostFields
+  }
+}
+fragment UserFields on User {
+  id
+  name
+}
+fragment PostFields on Post {
+  title
+  author {
+    ... on User {
+      ... UserFields
+    }
+  }
+}
+`,
+		},
+		{
+			name:          "unknown operation",
+			operationName: "DeletePost",
+			expectError:   "operation 'DeletePost' not found",
+		},
+		{
+			name:        "no operation name",
+			expectError: "please specify --operation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractOperation(document, tt.operationName)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("Expected error containing %q, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExtractOperation failed: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := ExtractOperation(`{ ...Missing }`, ""); err == nil || !strings.Contains(err.Error(), "fragment 'Missing' is not defined") {
+		t.Errorf("Expected an error for an undefined fragment, got %v", err)
+	}
+}
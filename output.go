@@ -99,6 +99,19 @@ func GetAvailableFormatters() []string {
 type JSONFormatter struct {
 	output      io.Writer
 	errorOutput io.Writer
+	compact     bool
+}
+
+// Response modes accepted by FormatResponse
+const (
+	ResponseModeCompact = "compact" // One line per response (the default)
+	ResponseModePretty  = "pretty"  // Indented over multiple lines
+)
+
+// SetCompact makes structured output compact JSON on a single line instead of
+// indented JSON. Responses are indented only in ResponseModePretty.
+func (f *JSONFormatter) SetCompact(compact bool) {
+	f.compact = compact
 }
 
 // SetOutput sets the output writer for the formatter
@@ -131,6 +144,48 @@ func (f *JSONFormatter) getErrorOutput() io.Writer {
 type TableFormatter struct {
 	output      io.Writer
 	errorOutput io.Writer
+	color       *bool // nil detects whether to color each writer
+}
+
+// ANSI escape codes used by TableFormatter
+const (
+	ansiGreen = "\033[32m"
+	ansiRed   = "\033[31m"
+	ansiDim   = "\033[2m"
+	ansiReset = "\033[0m"
+)
+
+// SetColor forces colored output on or off. By default output is colored only
+// when written to a terminal and the NO_COLOR environment variable is not set.
+func (f *TableFormatter) SetColor(enabled bool) {
+	f.color = &enabled
+}
+
+// colorEnabled reports whether output written to w is colored
+func (f *TableFormatter) colorEnabled(w io.Writer) bool {
+	if f.color != nil {
+		return *f.color
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorize wraps text in an ANSI color when output written to w is colored
+func (f *TableFormatter) colorize(w io.Writer, color, text string) string {
+	if !f.colorEnabled(w) {
+		return text
+	}
+	return color + text + ansiReset
 }
 
 // SetOutput sets the output writer for the formatter
@@ -219,15 +274,25 @@ const (
 	ErrorCodeConfigValidate = "CONFIG_VALIDATE_ERROR"
 
 	// Input validation errors
-	ErrorCodeInputValidation = "INPUT_VALIDATION_ERROR"
-	ErrorCodeQueryLoad       = "QUERY_LOAD_ERROR"
-	ErrorCodeVariablesLoad   = "VARIABLES_LOAD_ERROR"
-	ErrorCodeFilesParse      = "FILES_PARSE_ERROR"
-	ErrorCodeFilesListParse  = "FILES_LIST_PARSE_ERROR"
+	ErrorCodeInputValidation     = "INPUT_VALIDATION_ERROR"
+	ErrorCodeQueryLoad           = "QUERY_LOAD_ERROR"
+	ErrorCodeQueryParse          = "QUERY_PARSE_ERROR"
+	ErrorCodeQueryValidation     = "QUERY_VALIDATION_ERROR"
+	ErrorCodeQueryComplexity     = "QUERY_COMPLEXITY_ERROR"
+	ErrorCodeQueryLint           = "QUERY_LINT_ERROR"
+	ErrorCodeVariablesLoad       = "VARIABLES_LOAD_ERROR"
+	ErrorCodeVariablesValidation = "VARIABLES_VALIDATION_ERROR"
+	ErrorCodeHeadersLoad         = "HEADERS_LOAD_ERROR"
+	ErrorCodeFilesParse          = "FILES_PARSE_ERROR"
+	ErrorCodeFilesListParse      = "FILES_LIST_PARSE_ERROR"
+	ErrorCodeSaveField           = "SAVE_FIELD_ERROR"
+	ErrorCodeSelectPath          = "SELECT_PATH_ERROR"
+	ErrorCodeScriptLoad          = "SCRIPT_LOAD_ERROR"
 
 	// GraphQL execution errors
 	ErrorCodeGraphQLExecution = "GRAPHQL_EXECUTION_ERROR"
 	ErrorCodeGraphQLErrors    = "GRAPHQL_ERRORS"
+	ErrorCodeScriptStep       = "SCRIPT_STEP_ERROR"
 	ErrorCodeNetworkError     = "NETWORK_ERROR"
 	ErrorCodeAuthError        = "AUTH_ERROR"
 
@@ -300,21 +365,30 @@ func (f *JSONFormatter) FormatStructuredErrorWithContext(err error, code string,
 	return f.formatStructuredJSONToError(output)
 }
 
-// FormatResponse formats a GraphQL response as compact JSON
+// FormatResponse formats a GraphQL response as JSON, indented in
+// ResponseModePretty and compact otherwise
 func (f *JSONFormatter) FormatResponse(response *Response, mode string) error {
 	encoder := json.NewEncoder(f.getOutput())
+	if mode == ResponseModePretty {
+		encoder.SetIndent("", "  ")
+	}
 	return encoder.Encode(response)
 }
 
 func (f *JSONFormatter) formatStructuredJSON(output *StructuredOutput) error {
-	encoder := json.NewEncoder(f.getOutput())
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(output)
+	return f.encodeStructured(f.getOutput(), output)
 }
 
 func (f *JSONFormatter) formatStructuredJSONToError(output *StructuredOutput) error {
-	encoder := json.NewEncoder(f.getErrorOutput())
-	encoder.SetIndent("", "  ")
+	return f.encodeStructured(f.getErrorOutput(), output)
+}
+
+// encodeStructured writes structured output as JSON, indented unless compact
+func (f *JSONFormatter) encodeStructured(w io.Writer, output *StructuredOutput) error {
+	encoder := json.NewEncoder(w)
+	if !f.compact {
+		encoder.SetIndent("", "  ")
+	}
 	return encoder.Encode(output)
 }
 
@@ -382,99 +456,91 @@ func (f *TableFormatter) formatStructuredTable(output *StructuredOutput, quiet b
 				fmt.Fprintln(f.getOutput(), output.Data)
 			}
 		} else {
-			fmt.Fprintf(f.getErrorOutput(), "Error: %s\n", output.Error.Message)
+			f.printQuietError(f.getErrorOutput(), output)
 		}
 		return nil
 	}
 
 	// Full table output
+	w := f.getOutput()
 	if output.Success {
-		fmt.Fprintln(f.getOutput(), "✓ Success")
+		fmt.Fprintln(w, f.colorize(w, ansiGreen, "✓ Success"))
 		if output.Data != nil {
-			fmt.Fprintf(f.getOutput(), "Data: %v\n", output.Data)
+			fmt.Fprintf(w, "Data: %v\n", output.Data)
 		}
 	} else {
-		fmt.Fprintln(f.getOutput(), "✗ Error")
-		fmt.Fprintf(f.getOutput(), "Code: %s\n", output.Error.Code)
-		if output.Error.Type != "" {
-			fmt.Fprintf(f.getOutput(), "Type: %s\n", output.Error.Type)
-		}
-		fmt.Fprintf(f.getOutput(), "Message: %s\n", output.Error.Message)
-		if output.Error.Details != "" {
-			fmt.Fprintf(f.getOutput(), "Details: %s\n", output.Error.Details)
-		}
-		if len(output.Error.Context) > 0 {
-			fmt.Fprintln(f.getOutput(), "Context:")
-			for key, value := range output.Error.Context {
-				fmt.Fprintf(f.getOutput(), "  %s: %v\n", key, value)
-			}
-		}
-	}
-
-	if output.Meta != nil {
-		fmt.Fprintln(f.getOutput(), "\nMetadata:")
-		if output.Meta.Command != "" {
-			fmt.Fprintf(f.getOutput(), "  Command: %s\n", output.Meta.Command)
-		}
-		if output.Meta.Config != "" {
-			fmt.Fprintf(f.getOutput(), "  Config: %s\n", output.Meta.Config)
-		}
-		if output.Meta.Endpoint != "" {
-			fmt.Fprintf(f.getOutput(), "  Endpoint: %s\n", output.Meta.Endpoint)
-		}
-		if output.Meta.Operation != "" {
-			fmt.Fprintf(f.getOutput(), "  Operation: %s\n", output.Meta.Operation)
-		}
+		f.printError(w, output)
 	}
+	f.printMeta(w, output)
 
 	return nil
 }
 
 func (f *TableFormatter) formatStructuredTableToError(output *StructuredOutput, quiet bool) error {
+	w := f.getErrorOutput()
 	if quiet {
 		// In quiet mode, just show the error message to error output
 		if !output.Success {
-			fmt.Fprintf(f.getErrorOutput(), "Error: %s\n", output.Error.Message)
+			f.printQuietError(w, output)
 		}
 		return nil
 	}
 
 	// Full table output to error stream
 	if !output.Success {
-		fmt.Fprintln(f.getErrorOutput(), "✗ Error")
-		fmt.Fprintf(f.getErrorOutput(), "Code: %s\n", output.Error.Code)
-		if output.Error.Type != "" {
-			fmt.Fprintf(f.getErrorOutput(), "Type: %s\n", output.Error.Type)
-		}
-		fmt.Fprintf(f.getErrorOutput(), "Message: %s\n", output.Error.Message)
-		if output.Error.Details != "" {
-			fmt.Fprintf(f.getErrorOutput(), "Details: %s\n", output.Error.Details)
-		}
-		if len(output.Error.Context) > 0 {
-			fmt.Fprintf(f.getErrorOutput(), "Context:\n")
-			for key, value := range output.Error.Context {
-				fmt.Fprintf(f.getErrorOutput(), "  %s: %v\n", key, value)
-			}
-		}
+		f.printError(w, output)
 	}
+	f.printMeta(w, output)
 
-	if output.Meta != nil {
-		fmt.Fprintln(f.getErrorOutput(), "\nMetadata:")
-		if output.Meta.Command != "" {
-			fmt.Fprintf(f.getErrorOutput(), "  Command: %s\n", output.Meta.Command)
-		}
-		if output.Meta.Config != "" {
-			fmt.Fprintf(f.getErrorOutput(), "  Config: %s\n", output.Meta.Config)
-		}
-		if output.Meta.Endpoint != "" {
-			fmt.Fprintf(f.getErrorOutput(), "  Endpoint: %s\n", output.Meta.Endpoint)
-		}
-		if output.Meta.Operation != "" {
-			fmt.Fprintf(f.getErrorOutput(), "  Operation: %s\n", output.Meta.Operation)
+	return nil
+}
+
+// printQuietError writes the one-line error message shown in quiet mode
+func (f *TableFormatter) printQuietError(w io.Writer, output *StructuredOutput) {
+	fmt.Fprintln(w, f.colorize(w, ansiRed, "Error: "+output.Error.Message))
+}
+
+// printError writes the details of a failed output
+func (f *TableFormatter) printError(w io.Writer, output *StructuredOutput) {
+	fmt.Fprintln(w, f.colorize(w, ansiRed, "✗ Error"))
+	fmt.Fprintf(w, "Code: %s\n", f.colorize(w, ansiRed, output.Error.Code))
+	if output.Error.Type != "" {
+		fmt.Fprintf(w, "Type: %s\n", output.Error.Type)
+	}
+	fmt.Fprintf(w, "Message: %s\n", f.colorize(w, ansiRed, output.Error.Message))
+	if output.Error.Details != "" {
+		fmt.Fprintf(w, "Details: %s\n", output.Error.Details)
+	}
+	if len(output.Error.Context) > 0 {
+		fmt.Fprintln(w, "Context:")
+		for key, value := range output.Error.Context {
+			fmt.Fprintf(w, "  %s: %v\n", key, value)
 		}
 	}
+}
 
-	return nil
+// printMeta writes the metadata of an output, if any, dimmed
+func (f *TableFormatter) printMeta(w io.Writer, output *StructuredOutput) {
+	if output.Meta == nil {
+		return
+	}
+	lines := []string{"Metadata:"}
+	if output.Meta.Command != "" {
+		lines = append(lines, "  Command: "+output.Meta.Command)
+	}
+	if output.Meta.Config != "" {
+		lines = append(lines, "  Config: "+output.Meta.Config)
+	}
+	if output.Meta.Endpoint != "" {
+		lines = append(lines, "  Endpoint: "+output.Meta.Endpoint)
+	}
+	if output.Meta.Operation != "" {
+		lines = append(lines, "  Operation: "+output.Meta.Operation)
+	}
+	fmt.Fprintln(w)
+	for _, line := range lines {
+		fmt.Fprintln(w, f.colorize(w, ansiDim, line))
+	}
 }
 
 // YAMLFormatter implementation
@@ -3,7 +3,9 @@ package gqlt
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -700,3 +702,72 @@ func TestFormatterPerformance(t *testing.T) {
 		}
 	})
 }
+
+func TestTableFormatterColor(t *testing.T) {
+	write := func(formatter *TableFormatter, quiet bool) string {
+		var out bytes.Buffer
+		formatter.SetOutput(&out)
+		formatter.SetErrorOutput(&out)
+		formatter.FormatStructured(map[string]string{"id": "1"}, quiet)
+		formatter.FormatStructuredError(errors.New("boom"), ErrorCodeNetworkError, quiet)
+		formatter.formatStructuredTable(&StructuredOutput{Success: true, Meta: &MetaInfo{Command: "run"}}, quiet)
+		return out.String()
+	}
+
+	// Buffers aren't terminals, so output isn't colored
+	for _, quiet := range []bool{false, true} {
+		if output := write(&TableFormatter{}, quiet); strings.Contains(output, "\033[") {
+			t.Errorf("Expected no escape codes with quiet=%v, got %q", quiet, output)
+		}
+	}
+
+	formatter := &TableFormatter{}
+	formatter.SetColor(true)
+	output := write(formatter, false)
+	for _, expected := range []string{
+		ansiGreen + "✓ Success" + ansiReset,
+		ansiRed + "✗ Error" + ansiReset,
+		"Message: " + ansiRed + "boom" + ansiReset,
+		ansiDim + "  Command: run" + ansiReset,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in %q", expected, output)
+		}
+	}
+
+	formatter.SetColor(false)
+	if output := write(formatter, false); strings.Contains(output, "\033[") {
+		t.Errorf("Expected no escape codes with color off, got %q", output)
+	}
+}
+
+func TestJSONFormatterIndentation(t *testing.T) {
+	response := &Response{Data: map[string]interface{}{"user": map[string]interface{}{"id": "1"}}}
+
+	var out bytes.Buffer
+	formatter := &JSONFormatter{}
+	formatter.SetOutput(&out)
+	formatter.FormatResponse(response, ResponseModeCompact)
+	if lines := strings.Count(out.String(), "\n"); lines != 1 {
+		t.Errorf("Expected a compact response on one line, got %q", out.String())
+	}
+
+	out.Reset()
+	formatter.FormatResponse(response, ResponseModePretty)
+	if lines := strings.Count(out.String(), "\n"); lines <= 1 {
+		t.Errorf("Expected an indented response, got %q", out.String())
+	}
+
+	out.Reset()
+	formatter.FormatStructured(response.Data, false)
+	if lines := strings.Count(out.String(), "\n"); lines <= 1 {
+		t.Errorf("Expected indented structured output by default, got %q", out.String())
+	}
+
+	out.Reset()
+	formatter.SetCompact(true)
+	formatter.FormatStructured(response.Data, false)
+	if lines := strings.Count(out.String(), "\n"); lines != 1 {
+		t.Errorf("Expected compact structured output on one line, got %q", out.String())
+	}
+}
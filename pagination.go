@@ -0,0 +1,92 @@
+package gqlt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// DefaultMaxPages is the number of pages ExecuteAllPages fetches at most unless
+// changed with SetMaxPages
+const DefaultMaxPages = 100
+
+// ErrMaxPagesReached is returned by ExecuteAllPages, along with the pages
+// fetched so far, when more pages remain after the maximum number of pages
+var ErrMaxPagesReached = errors.New("maximum number of pages reached")
+
+// SetMaxPages sets the number of pages ExecuteAllPages fetches at most
+// (DefaultMaxPages by default), guarding against servers that never report the
+// last page. Zero or less restores the default.
+func (c *Client) SetMaxPages(maxPages int) {
+	c.maxPages = maxPages
+}
+
+// ExecuteAllPages fetches every page of a cursor-paginated connection. The query
+// must take the cursor of the page to fetch as its $after variable. After each
+// page, the cursor and whether another page follows are read from the response
+// data at cursorPath and hasNextPath (dotted paths as for ExtractPath), and the
+// cursor is passed as "after" to fetch the next page. It stops when hasNextPath
+// is false, or early when a page has GraphQL errors; that page is the last one
+// returned. Fetching more pages than allowed by SetMaxPages returns the pages
+// so far and an error wrapping ErrMaxPagesReached.
+//
+// Example:
+//
+//	pages, err := client.ExecuteAllPages(
+//	    `query Users($after: String) { users(first: 50, after: $after) { nodes { id } pageInfo { endCursor hasNextPage } } }`,
+//	    nil, "Users", "users.pageInfo.endCursor", "users.pageInfo.hasNextPage",
+//	)
+func (c *Client) ExecuteAllPages(query string, variables map[string]interface{}, operationName, cursorPath, hasNextPath string) ([]*Response, error) {
+	return c.ExecuteAllPagesContext(context.Background(), query, variables, operationName, cursorPath, hasNextPath)
+}
+
+// ExecuteAllPagesContext works like ExecuteAllPages but stops fetching pages when
+// the context is canceled.
+func (c *Client) ExecuteAllPagesContext(ctx context.Context, query string, variables map[string]interface{}, operationName, cursorPath, hasNextPath string) ([]*Response, error) {
+	maxPages := c.maxPages
+	if maxPages <= 0 {
+		maxPages = DefaultMaxPages
+	}
+
+	pageVariables := make(map[string]interface{}, len(variables)+1)
+	for k, v := range variables {
+		pageVariables[k] = v
+	}
+
+	var pages []*Response
+	for {
+		if len(pages) == maxPages {
+			return pages, fmt.Errorf("%w (%d)", ErrMaxPagesReached, maxPages)
+		}
+
+		response, err := c.ExecuteContext(ctx, query, pageVariables, operationName)
+		if err != nil {
+			return pages, fmt.Errorf("failed to fetch page %d: %w", len(pages)+1, err)
+		}
+		pages = append(pages, response)
+		if len(response.Errors) > 0 {
+			return pages, nil
+		}
+
+		hasNext, err := ExtractPath(response.Data, hasNextPath)
+		if err != nil {
+			return pages, fmt.Errorf("failed to read hasNextPage of page %d: %w", len(pages), err)
+		}
+		next, ok := hasNext.(bool)
+		if !ok {
+			return pages, fmt.Errorf("hasNextPage of page %d at %s is %v, not a boolean", len(pages), hasNextPath, hasNext)
+		}
+		if !next {
+			return pages, nil
+		}
+
+		cursor, err := ExtractPath(response.Data, cursorPath)
+		if err != nil {
+			return pages, fmt.Errorf("failed to read the cursor of page %d: %w", len(pages), err)
+		}
+		if cursor == nil {
+			return pages, fmt.Errorf("page %d has a next page but no cursor at %s", len(pages), cursorPath)
+		}
+		pageVariables["after"] = cursor
+	}
+}
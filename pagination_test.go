@@ -0,0 +1,107 @@
+package gqlt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newPaginatedServer serves a users connection with the given number of pages,
+// recording the after variable of each request
+func newPaginatedServer(t *testing.T, pageCount int, afters *[]interface{}) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		after := request.Variables["after"]
+		*afters = append(*afters, after)
+
+		page := 1
+		if after != nil {
+			fmt.Sscanf(after.(string), "cursor-%d", &page)
+			page++
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"users": map[string]interface{}{
+					"nodes": []interface{}{map[string]interface{}{"id": fmt.Sprintf("user-%d", page)}},
+					"pageInfo": map[string]interface{}{
+						"endCursor":   fmt.Sprintf("cursor-%d", page),
+						"hasNextPage": page < pageCount,
+					},
+				},
+			},
+		})
+	}))
+}
+
+const paginationTestQuery = `query Users($first: Int, $after: String) { users(first: $first, after: $after) { nodes { id } pageInfo { endCursor hasNextPage } } }`
+
+func TestClient_ExecuteAllPages(t *testing.T) {
+	var afters []interface{}
+	server := newPaginatedServer(t, 3, &afters)
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	variables := map[string]interface{}{"first": 1}
+	pages, err := client.ExecuteAllPages(paginationTestQuery, variables, "Users", "users.pageInfo.endCursor", "users.pageInfo.hasNextPage")
+	if err != nil {
+		t.Fatalf("ExecuteAllPages failed: %v", err)
+	}
+
+	if len(pages) != 3 {
+		t.Fatalf("Expected 3 pages, got %d", len(pages))
+	}
+	for i, page := range pages {
+		id, err := ExtractPath(page.Data, "users.nodes.0.id")
+		if err != nil || id != fmt.Sprintf("user-%d", i+1) {
+			t.Errorf("Expected user-%d on page %d, got %v (%v)", i+1, i+1, id, err)
+		}
+	}
+
+	expectedAfters := []interface{}{nil, "cursor-1", "cursor-2"}
+	if fmt.Sprint(afters) != fmt.Sprint(expectedAfters) {
+		t.Errorf("Expected after variables %v, got %v", expectedAfters, afters)
+	}
+	if _, ok := variables["after"]; ok {
+		t.Error("Expected the caller's variables to be left unchanged")
+	}
+}
+
+func TestClient_ExecuteAllPagesMaxPages(t *testing.T) {
+	var afters []interface{}
+	server := newPaginatedServer(t, 3, &afters)
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	client.SetMaxPages(2)
+	pages, err := client.ExecuteAllPages(paginationTestQuery, nil, "Users", "users.pageInfo.endCursor", "users.pageInfo.hasNextPage")
+	if !errors.Is(err, ErrMaxPagesReached) {
+		t.Errorf("Expected ErrMaxPagesReached, got %v", err)
+	}
+	if len(pages) != 2 || len(afters) != 2 {
+		t.Errorf("Expected 2 pages from 2 requests, got %d pages from %d requests", len(pages), len(afters))
+	}
+}
+
+func TestClient_ExecuteAllPagesInvalidPath(t *testing.T) {
+	var afters []interface{}
+	server := newPaginatedServer(t, 3, &afters)
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	pages, err := client.ExecuteAllPages(paginationTestQuery, nil, "Users", "users.pageInfo.endCursor", "users.pageInfo.missing")
+	if err == nil {
+		t.Error("Expected an error for a missing hasNextPage path")
+	}
+	if len(pages) != 1 {
+		t.Errorf("Expected the first page to be returned, got %d pages", len(pages))
+	}
+}
@@ -0,0 +1,107 @@
+package gqlt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ExtractPath returns the value at a dotted path in decoded response data, such
+// as the Data of a Response. Each segment names an object field, or indexes a
+// list when the value at that point is a list (e.g. "users.0.avatar.url").
+// An empty path returns data itself.
+//
+// Example:
+//
+//	url, err := gqlt.ExtractPath(result.Data, "createExport.download.url")
+func ExtractPath(data interface{}, path string) (interface{}, error) {
+	if path == "" {
+		return data, nil
+	}
+	return walkPath(data, strings.Split(path, "."))
+}
+
+// SelectPath is like ExtractPath, but list indexes can also be written in
+// brackets (e.g. "users[0].name" or "matrix[1][2]").
+//
+// Example:
+//
+//	name, err := gqlt.SelectPath(map[string]interface{}{"data": result.Data}, "data.users[0].name")
+func SelectPath(data interface{}, path string) (interface{}, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return walkPath(data, segments)
+}
+
+// parsePath splits a dotted path with optional bracketed indexes into segments,
+// so "users[0].name" becomes "users", "0" and "name"
+func parsePath(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	var segments []string
+	for _, part := range strings.Split(path, ".") {
+		name, rest, _ := strings.Cut(part, "[")
+		if name == "" && rest == "" {
+			return nil, fmt.Errorf("invalid path %q: empty segment", path)
+		}
+		if name != "" {
+			segments = append(segments, name)
+		}
+		if rest == "" && !strings.Contains(part, "[") {
+			continue
+		}
+
+		// Every "[" must be followed by a numeric index and a closing "]"
+		for rest = "[" + rest; rest != ""; {
+			end := strings.Index(rest, "]")
+			if !strings.HasPrefix(rest, "[") || end < 0 {
+				return nil, fmt.Errorf("invalid path %q: malformed index in %q", path, part)
+			}
+			index := rest[1:end]
+			if _, err := strconv.Atoi(index); err != nil {
+				return nil, fmt.Errorf("invalid path %q: index %q is not a number", path, index)
+			}
+			segments = append(segments, index)
+			rest = rest[end+1:]
+		}
+	}
+	return segments, nil
+}
+
+// walkPath follows segments through decoded JSON objects and lists
+func walkPath(data interface{}, segments []string) (interface{}, error) {
+	current := data
+	for i, segment := range segments {
+		at := strings.Join(segments[:i+1], ".")
+		parent := "data"
+		if i > 0 {
+			parent = strconv.Quote(strings.Join(segments[:i], "."))
+		}
+		switch value := current.(type) {
+		case map[string]interface{}:
+			next, ok := value[segment]
+			if !ok {
+				return nil, fmt.Errorf("field %q not found", at)
+			}
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil {
+				return nil, fmt.Errorf("%s is a list, expected an index instead of %q", parent, segment)
+			}
+			if index < 0 || index >= len(value) {
+				return nil, fmt.Errorf("index %d out of range at %q (length %d)", index, at, len(value))
+			}
+			current = value[index]
+		case nil:
+			return nil, fmt.Errorf("cannot read %q: %s is null", at, parent)
+		default:
+			return nil, fmt.Errorf("cannot read %q: %s is a %s", at, parent, jsonKind(value))
+		}
+	}
+	return current, nil
+}
@@ -0,0 +1,105 @@
+package gqlt
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestExtractPath(t *testing.T) {
+	var data interface{}
+	err := json.Unmarshal([]byte(`{
+		"createExport": {"download": {"url": "https://example.com/export.csv", "size": 42}},
+		"users": [{"name": "Ada"}, {"name": "Grace", "avatar": null}]
+	}`), &data)
+	if err != nil {
+		t.Fatalf("Failed to decode data: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    interface{}
+		wantErr string
+	}{
+		{name: "nested field", path: "createExport.download.url", want: "https://example.com/export.csv"},
+		{name: "number", path: "createExport.download.size", want: float64(42)},
+		{name: "list index", path: "users.1.name", want: "Grace"},
+		{name: "null value", path: "users.1.avatar", want: nil},
+		{name: "missing field", path: "createExport.upload", wantErr: `field "createExport.upload" not found`},
+		{name: "index out of range", path: "users.2.name", wantErr: `index 2 out of range at "users.2" (length 2)`},
+		{name: "non-numeric index", path: "users.first", wantErr: `"users" is a list, expected an index instead of "first"`},
+		{name: "through null", path: "users.1.avatar.url", wantErr: `"users.1.avatar" is null`},
+		{name: "through scalar", path: "createExport.download.url.host", wantErr: `"createExport.download.url" is a string`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractPath(data, tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExtractPath failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if got, err := ExtractPath(data, ""); err != nil || got == nil {
+		t.Errorf("Expected an empty path to return the data, got %v, %v", got, err)
+	}
+}
+
+func TestSelectPath(t *testing.T) {
+	var data interface{}
+	err := json.Unmarshal([]byte(`{"data": {
+		"user": {"name": "Ada", "address": {"city": "London"}},
+		"users": [{"id": "1"}, {"id": "2", "tags": ["admin", "ops"]}],
+		"matrix": [[1, 2], [3, 4]]
+	}}`), &data)
+	if err != nil {
+		t.Fatalf("Failed to decode data: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    interface{}
+		wantErr string
+	}{
+		{name: "nested object", path: "data.user.address.city", want: "London"},
+		{name: "array index", path: "data.users[1].id", want: "2"},
+		{name: "index on nested array", path: "data.users[1].tags[0]", want: "admin"},
+		{name: "consecutive indexes", path: "data.matrix[1][0]", want: float64(3)},
+		{name: "dotted index", path: "data.users.0.id", want: "1"},
+		{name: "missing path", path: "data.user.email", wantErr: `field "data.user.email" not found`},
+		{name: "index out of range", path: "data.users[5].id", wantErr: `index 5 out of range at "data.users.5" (length 2)`},
+		{name: "non-numeric index", path: "data.users[first]", wantErr: `index "first" is not a number`},
+		{name: "unclosed bracket", path: "data.users[0", wantErr: `malformed index in "users[0"`},
+		{name: "empty segment", path: "data..user", wantErr: "empty segment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SelectPath(data, tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectPath failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package gqlt
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// DefaultRequestIDHeader is the header SetRequestIDHeader uses when given no name
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the context key of a caller-supplied request ID
+type requestIDKey struct{}
+
+// SetRequestIDHeader makes the client send a request ID with every HTTP request
+// under the given header (DefaultRequestIDHeader if empty), so client and server
+// logs can be correlated. A random UUID is generated for each request unless the
+// context carries an ID set with WithRequestID. The ID that was sent is reported
+// in the response's RequestID.
+//
+// Example:
+//
+//	client.SetRequestIDHeader("X-Correlation-ID")
+//	response, err := client.Execute(`query { users { name } }`, nil, "")
+//	log.Printf("request %s took %dms", response.RequestID, response.DurationMs)
+func (c *Client) SetRequestIDHeader(name string) {
+	if name == "" {
+		name = DefaultRequestIDHeader
+	}
+	c.requestIDHeader = name
+}
+
+// WithRequestID returns a context that makes the requests of a client with a
+// request ID header (see SetRequestIDHeader) use id instead of a generated one.
+//
+// Example:
+//
+//	ctx := gqlt.WithRequestID(context.Background(), incomingRequestID)
+//	response, err := client.ExecuteContext(ctx, `query { users { name } }`, nil, "")
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// setRequestID adds the request ID header to a request if one is configured
+func (c *Client) setRequestID(req *http.Request) {
+	if c.requestIDHeader == "" {
+		return
+	}
+	id, _ := req.Context().Value(requestIDKey{}).(string)
+	if id == "" {
+		id = newRequestID()
+	}
+	req.Header.Set(c.requestIDHeader, id)
+}
+
+// requestID returns the request ID sent with a request, if any
+func (c *Client) requestID(req *http.Request) string {
+	if c.requestIDHeader == "" {
+		return ""
+	}
+	return req.Header.Get(c.requestIDHeader)
+}
+
+// newRequestID generates a random (version 4) UUID
+func newRequestID() string {
+	var b [16]byte
+	// crypto/rand.Read never returns an error
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,78 @@
+package gqlt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestClient_RequestID(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("X-Request-ID"))
+		w.Write([]byte(`{"data": {"ok": true}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	client.SetRequestIDHeader("")
+
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	seen := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		result, err := client.Execute(`{ ok }`, nil, "")
+		if err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		id := received[len(received)-1]
+		if !uuid.MatchString(id) {
+			t.Errorf("Expected a UUID request ID, got %q", id)
+		}
+		if seen[id] {
+			t.Errorf("Expected a unique request ID per request, got %q twice", id)
+		}
+		seen[id] = true
+		if result.RequestID != id {
+			t.Errorf("Expected the response to report ID %q, got %q", id, result.RequestID)
+		}
+	}
+
+	// A caller-supplied ID is sent as is
+	ctx := WithRequestID(context.Background(), "checkout-42")
+	result, err := client.ExecuteContext(ctx, `{ ok }`, nil, "")
+	if err != nil {
+		t.Fatalf("ExecuteContext failed: %v", err)
+	}
+	if got := received[len(received)-1]; got != "checkout-42" || result.RequestID != "checkout-42" {
+		t.Errorf("Expected the supplied ID to be preserved, sent %q and reported %q", got, result.RequestID)
+	}
+}
+
+func TestClient_RequestIDHeaderName(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.Write([]byte(`{"data": {"ok": true}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	result, err := client.Execute(`{ ok }`, nil, "")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if header.Get(DefaultRequestIDHeader) != "" || result.RequestID != "" {
+		t.Error("Expected no request ID unless a header is configured")
+	}
+
+	client.SetRequestIDHeader("X-Correlation-ID")
+	result, err = client.Execute(`{ ok }`, nil, "")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if id := header.Get("X-Correlation-ID"); id == "" || id != result.RequestID {
+		t.Errorf("Expected the ID under the configured header, got %q (response %q)", id, result.RequestID)
+	}
+}
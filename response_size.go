@@ -0,0 +1,53 @@
+package gqlt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxResponseSize is the size in bytes a response body may have at most
+// unless changed with SetMaxResponseSize
+const DefaultMaxResponseSize = 256 << 20
+
+// ErrResponseTooLarge is wrapped by the error returned when a response body is
+// larger than allowed by SetMaxResponseSize
+var ErrResponseTooLarge = errors.New("response too large")
+
+// SetMaxResponseSize sets the size in bytes a response body may have at most
+// (DefaultMaxResponseSize by default), so a misbehaving server can't exhaust
+// memory. Compressed responses are limited by their decompressed size. Reading a
+// larger body fails with an error wrapping ErrResponseTooLarge. Zero or less
+// restores the default.
+//
+// Example:
+//
+//	client.SetMaxResponseSize(10 << 20) // 10 MiB
+func (c *Client) SetMaxResponseSize(bytes int64) {
+	c.maxResponse = bytes
+}
+
+// limitResponse limits how much of a response body can be read
+func (c *Client) limitResponse(body io.Reader) io.Reader {
+	limit := c.maxResponse
+	if limit <= 0 {
+		limit = DefaultMaxResponseSize
+	}
+	return &limitedReader{reader: io.LimitReader(body, limit+1), limit: limit}
+}
+
+// limitedReader reads up to limit bytes and fails if there are more
+type limitedReader struct {
+	reader io.Reader // Limited to one byte past the limit
+	limit  int64
+	n      int64
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.n += int64(n)
+	if r.n > r.limit {
+		return n - int(r.n-r.limit), fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, r.limit)
+	}
+	return n, err
+}
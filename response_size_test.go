@@ -0,0 +1,75 @@
+package gqlt
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_SetMaxResponseSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"padding": "`))
+		// Stream a megabyte, well past the limit
+		chunk := []byte(strings.Repeat("x", 1024))
+		for i := 0; i < 1024; i++ {
+			if _, err := w.Write(chunk); err != nil {
+				return
+			}
+		}
+		w.Write([]byte(`"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	client.SetMaxResponseSize(64 << 10)
+	_, err := client.Execute(`{ padding }`, nil, "")
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("Expected ErrResponseTooLarge, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "more than 65536 bytes") {
+		t.Errorf("Expected the limit in the error, got %v", err)
+	}
+
+	// The default limit allows the response
+	client.SetMaxResponseSize(0)
+	result, err := client.Execute(`{ padding }`, nil, "")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if result.Data == nil {
+		t.Errorf("Expected data, got %+v", result)
+	}
+}
+
+func TestLimitedReader(t *testing.T) {
+	client := &Client{}
+	client.SetMaxResponseSize(5)
+
+	var data []byte
+	buf := make([]byte, 2)
+	reader := client.limitResponse(strings.NewReader("12345"))
+	for {
+		n, err := reader.Read(buf)
+		data = append(data, buf[:n]...)
+		if err != nil {
+			if err != io.EOF {
+				t.Fatalf("Expected a body of exactly the limit to be read, got %v", err)
+			}
+			break
+		}
+	}
+	if string(data) != "12345" {
+		t.Errorf("Expected 12345, got %q", data)
+	}
+
+	reader = client.limitResponse(strings.NewReader("123456"))
+	buf = make([]byte, 10)
+	n, err := reader.Read(buf)
+	if !errors.Is(err, ErrResponseTooLarge) || n != 5 {
+		t.Errorf("Expected 5 bytes and ErrResponseTooLarge, got %d bytes and %v", n, err)
+	}
+}
@@ -0,0 +1,67 @@
+package gqlt
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultRetryMaxWait caps the wait between retries when SetRetry is given no maximum
+const DefaultRetryMaxWait = 30 * time.Second
+
+// SetRetry makes requests answered with 429 Too Many Requests or 503 Service
+// Unavailable be retried up to maxAttempts times. Before each retry the client
+// sleeps for as long as the response's Retry-After header asks (in seconds or as
+// an HTTP date), or backs off exponentially from one second if there is none,
+// but never longer than maxWait (DefaultRetryMaxWait if zero). Uploads sent by
+// ExecuteWithFiles are streamed and therefore not retried. A maxAttempts of zero
+// disables retries, which is the default.
+//
+// Example:
+//
+//	client.SetRetry(3, 10*time.Second)
+func (c *Client) SetRetry(maxAttempts int, maxWait time.Duration) {
+	if maxWait <= 0 {
+		maxWait = DefaultRetryMaxWait
+	}
+	c.retryAttempts = maxAttempts
+	c.retryMaxWait = maxWait
+}
+
+// isRetryableStatus reports whether a status code asks the client to try again later
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable
+}
+
+// retryDelay returns how long to wait before retrying after the given attempt
+// (counting from zero), honoring a Retry-After header value if there is one
+func (c *Client) retryDelay(retryAfter string, attempt int, now time.Time) time.Duration {
+	delay, ok := parseRetryAfter(retryAfter, now, c.retryMaxWait)
+	if !ok {
+		delay = time.Second << min(attempt, 16)
+	}
+	return min(delay, c.retryMaxWait)
+}
+
+// parseRetryAfter parses a Retry-After header value, which is either a number of
+// seconds or an HTTP date. Dates in the past give a zero delay, and delays are
+// capped at maxWait.
+func parseRetryAfter(value string, now time.Time, maxWait time.Duration) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		// Clamp before converting, as huge values would overflow time.Duration
+		seconds = min(seconds, int(maxWait/time.Second))
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return min(max(date.Sub(now), 0), maxWait), true
+	}
+	return 0, false
+}
@@ -0,0 +1,138 @@
+package gqlt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_RetryAfter(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte("slow down"))
+			return
+		}
+		w.Write([]byte(`{"data": {"ok": true}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	client.SetRetry(3, 5*time.Second)
+
+	start := time.Now()
+	result, err := client.Execute(`{ ok }`, map[string]interface{}{"id": 1}, "")
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected one retry, got %d requests", requests)
+	}
+	if result.Data == nil {
+		t.Error("Expected the data of the retried request")
+	}
+	if elapsed < 900*time.Millisecond || elapsed > 3*time.Second {
+		t.Errorf("Expected the client to wait about one second, took %v", elapsed)
+	}
+}
+
+func TestClient_RetryMaxWait(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= 2 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"data": {"ok": true}}`))
+	}))
+	defer server.Close()
+
+	var logged []int
+	client := NewClient(server.URL, nil)
+	client.SetRetry(2, 50*time.Millisecond)
+	client.SetLogger(func(info RequestLog) { logged = append(logged, info.StatusCode) })
+
+	start := time.Now()
+	if _, err := client.Execute(`{ ok }`, nil, ""); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected waits to be capped at 50ms, took %v", elapsed)
+	}
+	if len(logged) != 3 || logged[0] != 503 || logged[2] != 200 {
+		t.Errorf("Expected every attempt to be logged, got %v", logged)
+	}
+}
+
+func TestClient_RetryDisabledByDefault(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"errors": [{"message": "rate limited"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	result, err := client.Execute(`{ ok }`, nil, "")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if requests != 1 || len(result.Errors) != 1 {
+		t.Errorf("Expected the 429 response to be returned as is, got %d requests and %v", requests, result.Errors)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{value: "1", want: time.Second, ok: true},
+		{value: " 120 ", want: 2 * time.Minute, ok: true},
+		{value: "Wed, 01 Jan 2025 12:00:30 GMT", want: 30 * time.Second, ok: true},
+		{value: "Wed, 01 Jan 2025 11:00:00 GMT", want: 0, ok: true},
+		{value: "7200", want: time.Hour, ok: true},
+		{value: "9223372036854775807", want: time.Hour, ok: true},
+		{value: "Wed, 01 Jan 2025 14:00:00 GMT", want: time.Hour, ok: true},
+		{value: "", ok: false},
+		{value: "-5", ok: false},
+		{value: "soon", ok: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now, time.Hour)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestClient_RetryDelayBackoff(t *testing.T) {
+	client := NewClient("http://example.com", nil)
+	client.SetRetry(5, 0)
+
+	now := time.Now()
+	if got := client.retryDelay("", 0, now); got != time.Second {
+		t.Errorf("Expected a 1s first backoff, got %v", got)
+	}
+	if got := client.retryDelay("", 2, now); got != 4*time.Second {
+		t.Errorf("Expected a 4s third backoff, got %v", got)
+	}
+	if got := client.retryDelay("", 10, now); got != DefaultRetryMaxWait {
+		t.Errorf("Expected backoff to be capped at %v, got %v", DefaultRetryMaxWait, got)
+	}
+	if got := client.retryDelay("9223372036854775807", 0, now); got != DefaultRetryMaxWait {
+		t.Errorf("Expected a huge Retry-After to be capped at %v, got %v", DefaultRetryMaxWait, got)
+	}
+}
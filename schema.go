@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 )
 
@@ -40,6 +41,36 @@ func NewAnalyzer(schema *Response) (*Analyzer, error) {
 	}, nil
 }
 
+// NewAnalyzerFromSDL creates a new schema analyzer from GraphQL SDL. The SDL is converted
+// to the same introspection format a live endpoint returns, so all analysis works the
+// same as for an introspected schema.
+//
+// Example:
+//
+//	sdl, _ := os.ReadFile("schema.graphqls")
+//	analyzer, err := gqlt.NewAnalyzerFromSDL(string(sdl))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func NewAnalyzerFromSDL(sdl string) (*Analyzer, error) {
+	introspection, err := SDLToIntrospection(sdl)
+	if err != nil {
+		return nil, err
+	}
+
+	// Round-trip through JSON so the data has the exact shape of a decoded introspection response
+	data, err := json.Marshal(introspection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode introspection data: %w", err)
+	}
+	var result Response
+	if err := json.Unmarshal(data, &result.Data); err != nil {
+		return nil, fmt.Errorf("failed to decode introspection data: %w", err)
+	}
+
+	return NewAnalyzer(&result)
+}
+
 // LoadAnalyzerFromFile creates a new schema analyzer by loading a schema from a JSON file.
 // The file should contain a GraphQL introspection response in JSON format.
 //
@@ -50,6 +81,23 @@ func NewAnalyzer(schema *Response) (*Analyzer, error) {
 //	    log.Fatal(err)
 //	}
 func LoadAnalyzerFromFile(filePath string) (*Analyzer, error) {
+	result, err := LoadSchemaFromFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return NewAnalyzer(result)
+}
+
+// LoadSchemaFromFile loads a schema file as an introspection response.
+// The file may contain either an introspection result in JSON format or GraphQL SDL.
+//
+// Example:
+//
+//	schema, err := gqlt.LoadSchemaFromFile("schema.graphqls")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func LoadSchemaFromFile(filePath string) (*Response, error) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read schema file: %w", err)
@@ -58,7 +106,7 @@ func LoadAnalyzerFromFile(filePath string) (*Analyzer, error) {
 	// Try parsing as JSON first
 	var result Response
 	if err := json.Unmarshal(data, &result); err == nil {
-		return NewAnalyzer(&result)
+		return &result, nil
 	}
 
 	// If JSON parsing failed, try SDL
@@ -69,10 +117,9 @@ func LoadAnalyzerFromFile(filePath string) (*Analyzer, error) {
 	}
 
 	// Create Response from SDL introspection data
-	result = Response{
+	return &Response{
 		Data: introspectionData,
-	}
-	return NewAnalyzer(&result)
+	}, nil
 }
 
 // GetSummary returns a summary of the schema
@@ -131,6 +178,33 @@ func (a *Analyzer) FindType(typeName string) (*TypeDescription, error) {
 	return nil, fmt.Errorf("type '%s' not found in schema", typeName)
 }
 
+// TypeToSDL returns the SDL definition of a single type.
+//
+// Example:
+//
+//	sdl, err := analyzer.TypeToSDL("User")
+//	// type User {
+//	//   id: ID!
+//	//   name: String!
+//	// }
+func (a *Analyzer) TypeToSDL(typeName string) (string, error) {
+	types, err := a.typesByName()
+	if err != nil {
+		return "", err
+	}
+
+	typeObj, ok := types[typeName]
+	if !ok {
+		return "", fmt.Errorf("type '%s' not found in schema", typeName)
+	}
+
+	var sdl strings.Builder
+	if err := writeTypeSDL(&sdl, typeObj); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(sdl.String(), "\n"), nil
+}
+
 // FindField finds a field in a root type
 func (a *Analyzer) FindField(rootType, fieldName string) (*FieldDescription, error) {
 	// Find the root type
@@ -221,6 +295,112 @@ func (a *Analyzer) GetFieldDescription(rootType string, fieldObj map[string]inte
 	return desc, nil
 }
 
+// GetImplementors returns the names of the object types implementing an interface,
+// sorted by name. It uses the interface's possibleTypes and the interfaces listed
+// on each object type, so it works for schemas loaded from SDL as well.
+//
+// Example:
+//
+//	implementors, err := analyzer.GetImplementors("Node")
+//	// [Post User]
+func (a *Analyzer) GetImplementors(interfaceName string) ([]string, error) {
+	types, err := a.typesByName()
+	if err != nil {
+		return nil, err
+	}
+
+	iface, ok := types[interfaceName]
+	if !ok {
+		return nil, fmt.Errorf("type '%s' not found in schema", interfaceName)
+	}
+	if kind, _ := iface["kind"].(string); kind != "INTERFACE" {
+		return nil, fmt.Errorf("type '%s' is not an interface", interfaceName)
+	}
+
+	found := make(map[string]bool)
+	for _, name := range typeNames(iface["possibleTypes"]) {
+		found[name] = true
+	}
+	for name, typeObj := range types {
+		for _, implemented := range typeNames(typeObj["interfaces"]) {
+			if implemented == interfaceName {
+				found[name] = true
+			}
+		}
+	}
+
+	implementors := make([]string, 0, len(found))
+	for name := range found {
+		implementors = append(implementors, name)
+	}
+	sort.Strings(implementors)
+	return implementors, nil
+}
+
+// ListDeprecated returns all deprecated fields of OBJECT and INTERFACE types and all
+// deprecated ENUM values in the schema, along with their deprecation reasons.
+//
+// Example:
+//
+//	deprecated, err := analyzer.ListDeprecated()
+//	for _, d := range deprecated {
+//	    fmt.Printf("%s.%s: %s\n", d.TypeName, d.Name, d.DeprecationReason)
+//	}
+func (a *Analyzer) ListDeprecated() ([]DeprecatedField, error) {
+	types, ok := a.schemaData["types"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid types format")
+	}
+
+	deprecated := []DeprecatedField{}
+	for _, t := range types {
+		typeObj, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		typeName, _ := typeObj["name"].(string)
+		kind, _ := typeObj["kind"].(string)
+
+		// Skip introspection types
+		if strings.HasPrefix(typeName, "__") {
+			continue
+		}
+
+		var items []interface{}
+		var itemKind string
+		switch kind {
+		case "OBJECT", "INTERFACE":
+			items, _ = typeObj["fields"].([]interface{})
+			itemKind = "FIELD"
+		case "ENUM":
+			items, _ = typeObj["enumValues"].([]interface{})
+			itemKind = "ENUM_VALUE"
+		default:
+			continue
+		}
+
+		for _, item := range items {
+			itemObj, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if isDeprecated, _ := itemObj["isDeprecated"].(bool); !isDeprecated {
+				continue
+			}
+			name, _ := itemObj["name"].(string)
+			reason, _ := itemObj["deprecationReason"].(string)
+			deprecated = append(deprecated, DeprecatedField{
+				TypeName:          typeName,
+				Name:              name,
+				Kind:              itemKind,
+				DeprecationReason: reason,
+			})
+		}
+	}
+
+	return deprecated, nil
+}
+
 // formatTypeDescription formats a type description
 func (a *Analyzer) formatTypeDescription(typeObj map[string]interface{}) (*TypeDescription, error) {
 	name, _ := typeObj["name"].(string)
@@ -259,6 +439,15 @@ func (a *Analyzer) formatTypeDescription(typeObj map[string]interface{}) (*TypeD
 		}
 	}
 
+	// List implementing types for interfaces
+	if kind == "INTERFACE" {
+		implementors, err := a.GetImplementors(name)
+		if err != nil {
+			return nil, err
+		}
+		desc.Implementors = implementors
+	}
+
 	// Format enum values if available
 	if enumValues, ok := typeObj["enumValues"].([]interface{}); ok && len(enumValues) > 0 {
 		desc.EnumValues = make([]EnumValue, 0, len(enumValues))
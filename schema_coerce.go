@@ -0,0 +1,123 @@
+package gqlt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// CoerceVariables returns a copy of variables with values converted to the types
+// the operation declares for them, where a value has the wrong JSON type but an
+// obvious meaning: numeric strings become numbers for Int and Float, "true" and
+// "false" become booleans for Boolean, and strings are matched to the values of
+// enum types ignoring case. Lists are coerced item by item and input objects field
+// by field. Values that can't be converted are left as they are, for the server or
+// ValidateVariables to reject. The operation is selected as in DetectOperationType.
+//
+// Example:
+//
+//	variables, err := analyzer.CoerceVariables(
+//	    `query ($id: Int!, $status: Status) { orders(id: $id, status: $status) { id } }`,
+//	    "", map[string]interface{}{"id": "42", "status": "shipped"},
+//	)
+//	// variables: {"id": 42, "status": "SHIPPED"}
+func (a *Analyzer) CoerceVariables(query, operationName string, variables map[string]interface{}) (map[string]interface{}, error) {
+	schema, err := a.astSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	doc, gqlErr := parser.ParseQuery(&ast.Source{Name: "query", Input: query})
+	if gqlErr != nil {
+		return nil, fmt.Errorf("failed to parse GraphQL query: %w", gqlErr)
+	}
+	if len(doc.Operations) == 0 {
+		return nil, fmt.Errorf("no operations found in query")
+	}
+	op, err := selectOperation(doc, operationName)
+	if err != nil {
+		return nil, err
+	}
+
+	coerced := make(map[string]interface{}, len(variables))
+	for name, value := range variables {
+		if def := op.VariableDefinitions.ForName(name); def != nil {
+			value = coerceValue(schema, value, def.Type)
+		}
+		coerced[name] = value
+	}
+	return coerced, nil
+}
+
+// coerceValue converts a decoded JSON value to a type where it has an obvious
+// meaning as one, and otherwise returns it unchanged
+func coerceValue(schema *ast.Schema, value interface{}, typ *ast.Type) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	if typ.Elem != nil {
+		list, ok := value.([]interface{})
+		if !ok {
+			// A single value is coerced to a list of one item
+			return coerceValue(schema, value, typ.Elem)
+		}
+		coerced := make([]interface{}, len(list))
+		for i, item := range list {
+			coerced[i] = coerceValue(schema, item, typ.Elem)
+		}
+		return coerced
+	}
+
+	s, isString := value.(string)
+	switch typ.NamedType {
+	case "Int":
+		if n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 32); isString && err == nil {
+			return float64(n)
+		}
+		return value
+	case "Float":
+		if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); isString && err == nil {
+			return f
+		}
+		return value
+	case "Boolean":
+		switch {
+		case isString && strings.EqualFold(s, "true"):
+			return true
+		case isString && strings.EqualFold(s, "false"):
+			return false
+		}
+		return value
+	}
+
+	def := schema.Types[typ.NamedType]
+	if def == nil {
+		return value
+	}
+	switch def.Kind {
+	case ast.Enum:
+		if isString {
+			for _, enumValue := range def.EnumValues {
+				if strings.EqualFold(enumValue.Name, s) {
+					return enumValue.Name
+				}
+			}
+		}
+	case ast.InputObject:
+		if fields, ok := value.(map[string]interface{}); ok {
+			coerced := make(map[string]interface{}, len(fields))
+			for name, fieldValue := range fields {
+				if field := def.Fields.ForName(name); field != nil {
+					fieldValue = coerceValue(schema, fieldValue, field.Type)
+				}
+				coerced[name] = fieldValue
+			}
+			return coerced
+		}
+	}
+	return value
+}
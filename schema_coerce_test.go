@@ -0,0 +1,90 @@
+package gqlt
+
+import (
+	"reflect"
+	"testing"
+)
+
+const coerceTestSDL = `
+	type Query {
+		orders(filter: OrderFilter, limit: Int, ids: [Int!]): [Order!]!
+	}
+
+	type Order {
+		id: ID!
+	}
+
+	enum Status {
+		PENDING
+		SHIPPED
+	}
+
+	input OrderFilter {
+		status: Status
+		minTotal: Float
+		paid: Boolean
+		code: String
+	}
+`
+
+func TestAnalyzer_CoerceVariables(t *testing.T) {
+	analyzer, err := NewAnalyzerFromSDL(coerceTestSDL)
+	if err != nil {
+		t.Fatalf("NewAnalyzerFromSDL failed: %v", err)
+	}
+	query := `query Orders($filter: OrderFilter, $limit: Int, $ids: [Int!], $status: Status) {
+		orders(filter: $filter, limit: $limit, ids: $ids) { id }
+	}`
+
+	tests := []struct {
+		name      string
+		variables map[string]interface{}
+		expected  map[string]interface{}
+	}{
+		{
+			name:      "numeric string to Int",
+			variables: map[string]interface{}{"limit": "10", "ids": []interface{}{"1", 2.0}},
+			expected:  map[string]interface{}{"limit": 10.0, "ids": []interface{}{1.0, 2.0}},
+		},
+		{
+			name:      "enum casing",
+			variables: map[string]interface{}{"status": "shipped"},
+			expected:  map[string]interface{}{"status": "SHIPPED"},
+		},
+		{
+			name: "input object fields",
+			variables: map[string]interface{}{"filter": map[string]interface{}{
+				"status": "Pending", "minTotal": "9.5", "paid": "TRUE", "code": "007",
+			}},
+			expected: map[string]interface{}{"filter": map[string]interface{}{
+				"status": "PENDING", "minTotal": 9.5, "paid": true, "code": "007",
+			}},
+		},
+		{
+			name:      "single value for a list",
+			variables: map[string]interface{}{"ids": "3"},
+			expected:  map[string]interface{}{"ids": 3.0},
+		},
+		{
+			name:      "values without an obvious meaning are kept",
+			variables: map[string]interface{}{"limit": "ten", "status": "lost", "other": "1"},
+			expected:  map[string]interface{}{"limit": "ten", "status": "lost", "other": "1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coerced, err := analyzer.CoerceVariables(query, "", tt.variables)
+			if err != nil {
+				t.Fatalf("CoerceVariables failed: %v", err)
+			}
+			if !reflect.DeepEqual(coerced, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, coerced)
+			}
+		})
+	}
+
+	if _, err := analyzer.CoerceVariables(query, "Missing", nil); err == nil {
+		t.Error("Expected an error for an unknown operation")
+	}
+}
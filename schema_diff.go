@@ -0,0 +1,257 @@
+package gqlt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SchemaDiff compares two introspection results and reports the differences between them.
+// Removed types, fields and enum values are marked as breaking, as are field type changes
+// that narrow what clients can rely on (e.g. an output field becoming nullable, or an
+// input field becoming required).
+//
+// Example:
+//
+//	report, err := gqlt.SchemaDiff(oldSchema, newSchema)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if report.HasBreakingChanges() {
+//	    fmt.Println("Schema contains breaking changes")
+//	}
+func SchemaDiff(oldSchema, newSchema *Response) (*DiffReport, error) {
+	oldAnalyzer, err := NewAnalyzer(oldSchema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid old schema: %w", err)
+	}
+	newAnalyzer, err := NewAnalyzer(newSchema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid new schema: %w", err)
+	}
+
+	oldTypes, err := oldAnalyzer.typesByName()
+	if err != nil {
+		return nil, fmt.Errorf("invalid old schema: %w", err)
+	}
+	newTypes, err := newAnalyzer.typesByName()
+	if err != nil {
+		return nil, fmt.Errorf("invalid new schema: %w", err)
+	}
+
+	// Compare what matters to clients of each type, and classify the differences
+	changes, err := DiffJSON(oldAnalyzer.schemaShape(oldTypes), newAnalyzer.schemaShape(newTypes))
+	if err != nil {
+		return nil, err
+	}
+
+	// The members of a type that changed kind aren't compared
+	kindChanged := make(map[string]bool)
+	for _, change := range changes {
+		if typeName, ok := strings.CutSuffix(change.Path, ".kind"); ok {
+			kindChanged[typeName] = true
+		}
+	}
+
+	report := &DiffReport{
+		Changes: []SchemaChange{},
+	}
+	for _, change := range changes {
+		parts := strings.SplitN(change.Path, ".", 3)
+		typeName := parts[0]
+		switch {
+		case len(parts) == 1 && change.Kind == ChangeValueAdded:
+			kind, _ := change.New.(map[string]interface{})["kind"].(string)
+			report.add(SchemaChange{
+				Change:      ChangeTypeAdded,
+				Path:        typeName,
+				Description: fmt.Sprintf("Type '%s' (%s) was added", typeName, kind),
+			})
+		case len(parts) == 1 && change.Kind == ChangeValueRemoved:
+			kind, _ := change.Old.(map[string]interface{})["kind"].(string)
+			report.add(SchemaChange{
+				Change:      ChangeTypeRemoved,
+				Path:        typeName,
+				Description: fmt.Sprintf("Type '%s' (%s) was removed", typeName, kind),
+				Breaking:    true,
+			})
+		case len(parts) == 2:
+			report.add(SchemaChange{
+				Change:      ChangeTypeKindChanged,
+				Path:        typeName,
+				Description: fmt.Sprintf("Type '%s' changed kind from %s to %s", typeName, change.Old, change.New),
+				Breaking:    true,
+			})
+		case len(parts) == 3 && !kindChanged[typeName]:
+			path := typeName + "." + parts[2]
+			if parts[1] == "enumValues" {
+				addEnumValueChange(report, path, change)
+			} else {
+				input := parts[1] == "inputFields"
+				newField := namedObjects(newTypes[typeName][parts[1]])[parts[2]]
+				addFieldChange(report, path, change, newField, input)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// add appends a change to the report and keeps the breaking count in sync
+func (r *DiffReport) add(change SchemaChange) {
+	r.Changes = append(r.Changes, change)
+	if change.Breaking {
+		r.BreakingCount++
+	}
+}
+
+// typesByName indexes the schema types by name
+func (a *Analyzer) typesByName() (map[string]map[string]interface{}, error) {
+	types, ok := a.schemaData["types"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid types format")
+	}
+
+	result := make(map[string]map[string]interface{}, len(types))
+	for _, t := range types {
+		typeObj, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, ok := typeObj["name"].(string); ok && name != "" {
+			result[name] = typeObj
+		}
+	}
+	return result, nil
+}
+
+// schemaShape reduces the types of a schema to what SchemaDiff compares: the kind
+// of each type, the type of each of its fields and input fields, and its enum values
+func (a *Analyzer) schemaShape(types map[string]map[string]interface{}) map[string]interface{} {
+	fieldTypes := func(list interface{}) map[string]interface{} {
+		result := make(map[string]interface{})
+		for name, field := range namedObjects(list) {
+			typeMap, _ := field["type"].(map[string]interface{})
+			result[name] = a.formatTypeString(typeMap)
+		}
+		return result
+	}
+
+	shape := make(map[string]interface{}, len(types))
+	for name, typeObj := range types {
+		// Skip introspection types
+		if strings.HasPrefix(name, "__") {
+			continue
+		}
+		kind, _ := typeObj["kind"].(string)
+		enumValues := make(map[string]interface{})
+		for value := range namedObjects(typeObj["enumValues"]) {
+			enumValues[value] = true
+		}
+		shape[name] = map[string]interface{}{
+			"kind":        kind,
+			"fields":      fieldTypes(typeObj["fields"]),
+			"inputFields": fieldTypes(typeObj["inputFields"]),
+			"enumValues":  enumValues,
+		}
+	}
+	return shape
+}
+
+// addFieldChange reports a difference in a field (or input field) of a type present
+// in both schemas. newField is the field in the new schema, if it is there.
+func addFieldChange(report *DiffReport, path string, change Change, newField map[string]interface{}, input bool) {
+	switch change.Kind {
+	case ChangeValueAdded:
+		newTypeStr, _ := change.New.(string)
+		_, hasDefault := newField["defaultValue"].(string)
+		report.add(SchemaChange{
+			Change:      ChangeFieldAdded,
+			Path:        path,
+			Description: fmt.Sprintf("Field '%s' was added with type %s", path, newTypeStr),
+			// A new required input field breaks existing clients that don't send it
+			Breaking: input && strings.HasSuffix(newTypeStr, "!") && !hasDefault,
+		})
+	case ChangeValueRemoved:
+		report.add(SchemaChange{
+			Change:      ChangeFieldRemoved,
+			Path:        path,
+			Description: fmt.Sprintf("Field '%s' was removed", path),
+			Breaking:    true,
+		})
+	case ChangeValueChanged:
+		oldTypeStr, _ := change.Old.(string)
+		newTypeStr, _ := change.New.(string)
+		report.add(SchemaChange{
+			Change:      ChangeFieldTypeChanged,
+			Path:        path,
+			Description: fmt.Sprintf("Field '%s' changed type from %s to %s", path, oldTypeStr, newTypeStr),
+			Breaking:    !isSafeTypeChange(oldTypeStr, newTypeStr, input),
+		})
+	}
+}
+
+// addEnumValueChange reports an enum value added to or removed from an enum present
+// in both schemas
+func addEnumValueChange(report *DiffReport, path string, change Change) {
+	switch change.Kind {
+	case ChangeValueAdded:
+		report.add(SchemaChange{
+			Change:      ChangeEnumValueAdded,
+			Path:        path,
+			Description: fmt.Sprintf("Enum value '%s' was added", path),
+		})
+	case ChangeValueRemoved:
+		report.add(SchemaChange{
+			Change:      ChangeEnumValueRemoved,
+			Path:        path,
+			Description: fmt.Sprintf("Enum value '%s' was removed", path),
+			Breaking:    true,
+		})
+	}
+}
+
+// isSafeTypeChange reports whether a field type change keeps existing clients working.
+// Output fields may become non-null, and input fields may become nullable; anything
+// else narrows the contract.
+func isSafeTypeChange(oldType, newType string, input bool) bool {
+	if input {
+		return strings.HasSuffix(oldType, "!") && strings.TrimSuffix(oldType, "!") == newType
+	}
+	return strings.HasSuffix(newType, "!") && strings.TrimSuffix(newType, "!") == oldType
+}
+
+// namedObjects indexes a list of introspection objects (fields, enum values) by name
+func namedObjects(list interface{}) map[string]map[string]interface{} {
+	result := make(map[string]map[string]interface{})
+	items, ok := list.([]interface{})
+	if !ok {
+		return result
+	}
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, ok := obj["name"].(string); ok {
+			result[name] = obj
+		}
+	}
+	return result
+}
+
+// unionKeys returns the sorted union of the keys of both maps
+func unionKeys[V any](a, b map[string]V) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	keys := make([]string, 0, len(a)+len(b))
+	for _, m := range []map[string]V{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,184 @@
+package gqlt
+
+import (
+	"testing"
+)
+
+func loadTestSchema(t *testing.T, sdl string) *Response {
+	t.Helper()
+	data, err := SDLToIntrospection(sdl)
+	if err != nil {
+		t.Fatalf("Failed to parse SDL: %v", err)
+	}
+	return &Response{Data: data}
+}
+
+func TestSchemaDiff(t *testing.T) {
+	oldSchema := loadTestSchema(t, `
+		type Query {
+			user(id: ID!): User
+			legacy: String
+		}
+
+		type User {
+			id: ID!
+			name: String!
+			email: String
+			age: Int
+		}
+
+		type Obsolete {
+			id: ID!
+		}
+
+		enum Role {
+			ADMIN
+			GUEST
+		}
+
+		input UserInput {
+			name: String!
+			email: String
+		}
+	`)
+
+	newSchema := loadTestSchema(t, `
+		type Query {
+			user(id: ID!): User
+			users: [User!]!
+		}
+
+		type User {
+			id: ID!
+			name: String
+			email: String!
+			age: Float
+		}
+
+		type Post {
+			id: ID!
+		}
+
+		enum Role {
+			ADMIN
+			EDITOR
+		}
+
+		input UserInput {
+			name: String
+			email: String
+			password: String!
+			limit: Int! = 10
+		}
+	`)
+
+	report, err := SchemaDiff(oldSchema, newSchema)
+	if err != nil {
+		t.Fatalf("SchemaDiff failed: %v", err)
+	}
+
+	expected := map[string]struct {
+		change   string
+		breaking bool
+	}{
+		"Post":               {ChangeTypeAdded, false},
+		"Obsolete":           {ChangeTypeRemoved, true},
+		"Query.users":        {ChangeFieldAdded, false},
+		"Query.legacy":       {ChangeFieldRemoved, true},
+		"User.name":          {ChangeFieldTypeChanged, true},  // output narrowed to nullable
+		"User.email":         {ChangeFieldTypeChanged, false}, // output widened to non-null
+		"User.age":           {ChangeFieldTypeChanged, true},
+		"UserInput.name":     {ChangeFieldTypeChanged, false}, // input relaxed to nullable
+		"UserInput.password": {ChangeFieldAdded, true},        // new required input field
+		"UserInput.limit":    {ChangeFieldAdded, false},       // new required input field with a default
+		"Role.EDITOR":        {ChangeEnumValueAdded, false},
+		"Role.GUEST":         {ChangeEnumValueRemoved, true},
+	}
+
+	if len(report.Changes) != len(expected) {
+		t.Errorf("Expected %d changes, got %d: %+v", len(expected), len(report.Changes), report.Changes)
+	}
+
+	breaking := 0
+	for _, change := range report.Changes {
+		want, ok := expected[change.Path]
+		if !ok {
+			t.Errorf("Unexpected change: %+v", change)
+			continue
+		}
+		if change.Change != want.change {
+			t.Errorf("Expected %s to be %s, got %s", change.Path, want.change, change.Change)
+		}
+		if change.Breaking != want.breaking {
+			t.Errorf("Expected %s breaking=%v, got %v", change.Path, want.breaking, change.Breaking)
+		}
+		if change.Breaking {
+			breaking++
+		}
+	}
+
+	if report.BreakingCount != breaking {
+		t.Errorf("Expected breaking count %d, got %d", breaking, report.BreakingCount)
+	}
+	if !report.HasBreakingChanges() {
+		t.Error("Expected report to have breaking changes")
+	}
+}
+
+func TestSchemaDiff_KindChanged(t *testing.T) {
+	oldSchema := loadTestSchema(t, `
+		type Query { status: Status }
+		type Status { id: ID! }
+	`)
+	newSchema := loadTestSchema(t, `
+		type Query { status: Status }
+		enum Status { ACTIVE }
+	`)
+
+	report, err := SchemaDiff(oldSchema, newSchema)
+	if err != nil {
+		t.Fatalf("SchemaDiff failed: %v", err)
+	}
+
+	// The kind change is reported without the fields and values that came and went with it
+	if len(report.Changes) != 1 {
+		t.Fatalf("Expected a single change, got %+v", report.Changes)
+	}
+	change := report.Changes[0]
+	if change.Change != ChangeTypeKindChanged || change.Path != "Status" || !change.Breaking {
+		t.Errorf("Expected a breaking kind change of Status, got %+v", change)
+	}
+	if change.Description != "Type 'Status' changed kind from OBJECT to ENUM" {
+		t.Errorf("Unexpected description: %s", change.Description)
+	}
+}
+
+func TestSchemaDiff_Identical(t *testing.T) {
+	sdl := `
+		type Query {
+			hello: String
+		}
+	`
+	report, err := SchemaDiff(loadTestSchema(t, sdl), loadTestSchema(t, sdl))
+	if err != nil {
+		t.Fatalf("SchemaDiff failed: %v", err)
+	}
+	if len(report.Changes) != 0 {
+		t.Errorf("Expected no changes, got %+v", report.Changes)
+	}
+	if report.HasBreakingChanges() {
+		t.Error("Expected no breaking changes")
+	}
+}
+
+func TestSchemaDiff_InvalidSchema(t *testing.T) {
+	valid := loadTestSchema(t, `type Query { hello: String }`)
+	invalid := &Response{Data: "not a schema"}
+
+	if _, err := SchemaDiff(invalid, valid); err == nil {
+		t.Error("Expected error for invalid old schema")
+	}
+	if _, err := SchemaDiff(valid, invalid); err == nil {
+		t.Error("Expected error for invalid new schema")
+	}
+}
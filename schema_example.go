@@ -0,0 +1,187 @@
+package gqlt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultExampleDepth is the number of object levels expanded by GenerateExampleQuery
+const DefaultExampleDepth = 1
+
+// GenerateExampleQuery generates a sample query for a field of the schema's query type.
+// Scalar and enum fields of the returned type are selected; nested object fields are
+// expanded up to DefaultExampleDepth levels. Required arguments of the root field are
+// stubbed with operation variables of the matching type.
+//
+// Example:
+//
+//	query, err := analyzer.GenerateExampleQuery("user")
+//	// query ExampleUser($id: ID!) {
+//	//   user(id: $id) {
+//	//     id
+//	//     name
+//	//   }
+//	// }
+func (a *Analyzer) GenerateExampleQuery(rootField string) (string, error) {
+	return a.GenerateExampleQueryWithDepth(rootField, DefaultExampleDepth)
+}
+
+// GenerateExampleQueryWithDepth works like GenerateExampleQuery but expands nested
+// object fields up to the given depth. A depth of 1 selects only the scalar fields
+// of the root field's type.
+func (a *Analyzer) GenerateExampleQueryWithDepth(rootField string, depth int) (string, error) {
+	if depth < 1 {
+		return "", fmt.Errorf("depth must be at least 1")
+	}
+
+	summary, err := a.GetSummary()
+	if err != nil {
+		return "", err
+	}
+	if summary.QueryType == "" {
+		return "", fmt.Errorf("schema has no query type")
+	}
+
+	types, err := a.typesByName()
+	if err != nil {
+		return "", err
+	}
+
+	queryType, ok := types[summary.QueryType]
+	if !ok {
+		return "", fmt.Errorf("type '%s' not found in schema", summary.QueryType)
+	}
+
+	field, ok := namedObjects(queryType["fields"])[rootField]
+	if !ok {
+		return "", fmt.Errorf("field '%s' not found in type '%s'", rootField, summary.QueryType)
+	}
+
+	// Stub required arguments with variables
+	var varDefs, argUses []string
+	if args, ok := field["args"].([]interface{}); ok {
+		for _, arg := range args {
+			argObj, ok := arg.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			argName, _ := argObj["name"].(string)
+			argType, _ := argObj["type"].(map[string]interface{})
+			argTypeStr := a.formatTypeString(argType)
+			if !isRequiredInput(argObj, argTypeStr) {
+				continue
+			}
+			varDefs = append(varDefs, fmt.Sprintf("$%s: %s", argName, argTypeStr))
+			argUses = append(argUses, fmt.Sprintf("%s: $%s", argName, argName))
+		}
+	}
+
+	var query strings.Builder
+	query.WriteString("query Example" + strings.ToUpper(rootField[:1]) + rootField[1:])
+	if len(varDefs) > 0 {
+		query.WriteString("(" + strings.Join(varDefs, ", ") + ")")
+	}
+	query.WriteString(" {\n  " + rootField)
+	if len(argUses) > 0 {
+		query.WriteString("(" + strings.Join(argUses, ", ") + ")")
+	}
+
+	fieldType, _ := field["type"].(map[string]interface{})
+	a.writeExampleSelection(&query, types, fieldType, depth, "  ")
+	query.WriteString("\n}\n")
+
+	return query.String(), nil
+}
+
+// writeExampleSelection writes the selection set for a field of the given type, if it needs one
+func (a *Analyzer) writeExampleSelection(query *strings.Builder, types map[string]map[string]interface{}, typeRef map[string]interface{}, depth int, indent string) {
+	typeName := namedTypeName(typeRef)
+	typeObj, ok := types[typeName]
+	if !ok {
+		return
+	}
+
+	kind, _ := typeObj["kind"].(string)
+	switch kind {
+	case "OBJECT", "INTERFACE":
+	case "UNION":
+		query.WriteString(" {\n" + indent + "  __typename\n" + indent + "}")
+		return
+	default:
+		// Scalars and enums are leaves
+		return
+	}
+
+	var selection strings.Builder
+	fields, _ := typeObj["fields"].([]interface{})
+	for _, f := range fields {
+		fieldObj, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := fieldObj["name"].(string)
+		if name == "" || strings.HasPrefix(name, "__") || a.hasRequiredArgs(fieldObj) {
+			continue
+		}
+
+		fieldType, _ := fieldObj["type"].(map[string]interface{})
+		childType, ok := types[namedTypeName(fieldType)]
+		if !ok {
+			continue
+		}
+		childKind, _ := childType["kind"].(string)
+
+		switch childKind {
+		case "SCALAR", "ENUM":
+			selection.WriteString(indent + "  " + name + "\n")
+		case "OBJECT", "INTERFACE", "UNION":
+			// Stop expanding object fields once the depth limit is reached
+			if depth <= 1 {
+				continue
+			}
+			selection.WriteString(indent + "  " + name)
+			a.writeExampleSelection(&selection, types, fieldType, depth-1, indent+"  ")
+			selection.WriteString("\n")
+		}
+	}
+
+	// A selection set may not be empty
+	if selection.Len() == 0 {
+		selection.WriteString(indent + "  __typename\n")
+	}
+
+	query.WriteString(" {\n" + selection.String() + indent + "}")
+}
+
+// hasRequiredArgs reports whether a field has arguments that must be provided
+func (a *Analyzer) hasRequiredArgs(fieldObj map[string]interface{}) bool {
+	args, _ := fieldObj["args"].([]interface{})
+	for _, arg := range args {
+		argObj, ok := arg.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		argType, _ := argObj["type"].(map[string]interface{})
+		if isRequiredInput(argObj, a.formatTypeString(argType)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRequiredInput reports whether an argument or input field is non-null without a default
+func isRequiredInput(inputObj map[string]interface{}, typeStr string) bool {
+	defaultValue, _ := inputObj["defaultValue"].(string)
+	return strings.HasSuffix(typeStr, "!") && defaultValue == ""
+}
+
+// namedTypeName unwraps NON_NULL and LIST wrappers and returns the named type
+func namedTypeName(typeRef map[string]interface{}) string {
+	for typeRef != nil {
+		if name, ok := typeRef["name"].(string); ok && name != "" {
+			return name
+		}
+		typeRef, _ = typeRef["ofType"].(map[string]interface{})
+	}
+	return ""
+}
@@ -0,0 +1,151 @@
+package gqlt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const exampleTestSDL = `
+	type Query {
+		user(id: ID!, verbose: Boolean): User
+		users(first: Int = 10): [User!]!
+		search(term: String!): [SearchResult!]!
+		version: String!
+	}
+
+	type User {
+		id: ID!
+		name: String!
+		role: Role!
+		friends: [User!]!
+		posts(first: Int!): [Post!]!
+	}
+
+	type Post {
+		id: ID!
+		title: String!
+		author: User!
+	}
+
+	enum Role {
+		ADMIN
+		USER
+	}
+
+	union SearchResult = User | Post
+`
+
+func newExampleTestAnalyzer(t *testing.T) (*Analyzer, *ast.Schema) {
+	t.Helper()
+	introspection, err := SDLToIntrospection(exampleTestSDL)
+	if err != nil {
+		t.Fatalf("SDLToIntrospection failed: %v", err)
+	}
+	analyzer, err := NewAnalyzer(&Response{Data: introspection})
+	if err != nil {
+		t.Fatalf("NewAnalyzer failed: %v", err)
+	}
+	schema, gqlErr := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: exampleTestSDL})
+	if gqlErr != nil {
+		t.Fatalf("LoadSchema failed: %v", gqlErr)
+	}
+	return analyzer, schema
+}
+
+func TestAnalyzer_GenerateExampleQuery(t *testing.T) {
+	analyzer, schema := newExampleTestAnalyzer(t)
+
+	tests := []struct {
+		name        string
+		field       string
+		depth       int
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:        "required arguments become variables",
+			field:       "user",
+			depth:       1,
+			contains:    []string{"query ExampleUser($id: ID!)", "user(id: $id)", "id", "name", "role"},
+			notContains: []string{"verbose", "friends", "posts"},
+		},
+		{
+			name:        "optional arguments are omitted",
+			field:       "users",
+			depth:       1,
+			contains:    []string{"query ExampleUsers {", "users {"},
+			notContains: []string{"first"},
+		},
+		{
+			name:        "nested objects expand with depth",
+			field:       "user",
+			depth:       2,
+			contains:    []string{"friends {"},
+			notContains: []string{"posts"}, // requires arguments
+		},
+		{
+			name:     "unions select __typename",
+			field:    "search",
+			depth:    1,
+			contains: []string{"search(term: $term) {", "__typename"},
+		},
+		{
+			name:        "scalar root field has no selection",
+			field:       "version",
+			depth:       1,
+			contains:    []string{"version"},
+			notContains: []string{"version {"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := analyzer.GenerateExampleQueryWithDepth(tt.field, tt.depth)
+			if err != nil {
+				t.Fatalf("GenerateExampleQueryWithDepth failed: %v", err)
+			}
+
+			// The generated query must be valid against the schema
+			if _, errs := gqlparser.LoadQuery(schema, query); errs != nil {
+				t.Fatalf("Generated query is invalid: %v\n%s", errs, query)
+			}
+
+			for _, s := range tt.contains {
+				if !strings.Contains(query, s) {
+					t.Errorf("Expected query to contain %q:\n%s", s, query)
+				}
+			}
+			for _, s := range tt.notContains {
+				if strings.Contains(query, s) {
+					t.Errorf("Expected query not to contain %q:\n%s", s, query)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzer_GenerateExampleQuery_DefaultDepth(t *testing.T) {
+	analyzer, _ := newExampleTestAnalyzer(t)
+
+	query, err := analyzer.GenerateExampleQuery("user")
+	if err != nil {
+		t.Fatalf("GenerateExampleQuery failed: %v", err)
+	}
+	if strings.Contains(query, "friends") {
+		t.Errorf("Expected default depth to skip nested objects:\n%s", query)
+	}
+}
+
+func TestAnalyzer_GenerateExampleQuery_Errors(t *testing.T) {
+	analyzer, _ := newExampleTestAnalyzer(t)
+
+	if _, err := analyzer.GenerateExampleQuery("missing"); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+	if _, err := analyzer.GenerateExampleQueryWithDepth("user", 0); err == nil {
+		t.Error("Expected error for invalid depth")
+	}
+}
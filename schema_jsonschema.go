@@ -0,0 +1,208 @@
+package gqlt
+
+import "fmt"
+
+// JSONSchemaDraft is the JSON Schema dialect of the documents generated by
+// InputTypeToJSONSchema
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// scalarJSONSchemas maps built-in and commonly used custom scalars to JSON Schema.
+// Other custom scalars accept any value.
+var scalarJSONSchemas = map[string]map[string]interface{}{
+	"Int":          {"type": "integer"},
+	"Float":        {"type": "number"},
+	"String":       {"type": "string"},
+	"ID":           {"type": "string"},
+	"Boolean":      {"type": "boolean"},
+	"BigInt":       {"type": "integer"},
+	"Long":         {"type": "integer"},
+	"DateTime":     {"type": "string", "format": "date-time"},
+	"Date":         {"type": "string", "format": "date"},
+	"Time":         {"type": "string", "format": "time"},
+	"UUID":         {"type": "string", "format": "uuid"},
+	"Email":        {"type": "string", "format": "email"},
+	"EmailAddress": {"type": "string", "format": "email"},
+	"URL":          {"type": "string", "format": "uri"},
+	"URI":          {"type": "string", "format": "uri"},
+}
+
+// InputTypeToJSONSchema converts an input object type to a JSON Schema document
+// describing the values it accepts, e.g. to drive form generation or to validate
+// variables before sending them. Non-null input fields without a default value
+// are required, nullable fields also accept null, enums become string enums and
+// scalars are mapped to the closest JSON type (with a format for well-known
+// custom scalars such as DateTime or UUID). Nested input types are placed in
+// "$defs" and referenced, so recursive input types are supported.
+//
+// Example:
+//
+//	schema, err := analyzer.InputTypeToJSONSchema("CreateUserInput")
+//	// {"$schema": "...", "title": "CreateUserInput", "type": "object",
+//	//  "properties": {"name": {"type": "string"}, ...}, "required": ["name"]}
+func (a *Analyzer) InputTypeToJSONSchema(typeName string) (map[string]interface{}, error) {
+	types, err := a.typesByName()
+	if err != nil {
+		return nil, err
+	}
+
+	typeObj, ok := types[typeName]
+	if !ok {
+		return nil, fmt.Errorf("type '%s' not found in schema", typeName)
+	}
+	if kind, _ := typeObj["kind"].(string); kind != "INPUT_OBJECT" {
+		return nil, fmt.Errorf("type '%s' is a %s, not an input type", typeName, kind)
+	}
+
+	defs := map[string]interface{}{}
+	document := map[string]interface{}{
+		"$schema": JSONSchemaDraft,
+		"title":   typeName,
+	}
+	for key, value := range inputObjectJSONSchema(types, typeObj, defs) {
+		document[key] = value
+	}
+
+	// The root type is the document itself, so it is never referenced through $defs
+	delete(defs, typeName)
+	if len(defs) > 0 {
+		document["$defs"] = defs
+	}
+	rootRefs(document, typeName)
+
+	return document, nil
+}
+
+// inputObjectJSONSchema converts the input fields of an input object type
+func inputObjectJSONSchema(types map[string]map[string]interface{}, typeObj map[string]interface{}, defs map[string]interface{}) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+
+	fields, _ := typeObj["inputFields"].([]interface{})
+	for _, f := range fields {
+		fieldObj, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := fieldObj["name"].(string)
+		if name == "" {
+			continue
+		}
+		typeRef, _ := fieldObj["type"].(map[string]interface{})
+
+		property := typeRefJSONSchema(types, typeRef, defs)
+		if description, _ := fieldObj["description"].(string); description != "" {
+			property["description"] = description
+		}
+		if defaultValue := parseDefaultValue(fieldObj["defaultValue"]); defaultValue != nil {
+			if value, err := defaultValue.Value(nil); err == nil {
+				property["default"] = value
+			}
+		} else if kind, _ := typeRef["kind"].(string); kind == "NON_NULL" {
+			required = append(required, name)
+		}
+		if deprecated, _ := fieldObj["isDeprecated"].(bool); deprecated {
+			property["deprecated"] = true
+		}
+		properties[name] = property
+	}
+
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	if description, _ := typeObj["description"].(string); description != "" {
+		schema["description"] = description
+	}
+	return schema
+}
+
+// typeRefJSONSchema converts a (possibly wrapped) type reference. Types are
+// nullable unless wrapped in NON_NULL, in which case null is also allowed.
+func typeRefJSONSchema(types map[string]map[string]interface{}, typeRef map[string]interface{}, defs map[string]interface{}) map[string]interface{} {
+	kind, _ := typeRef["kind"].(string)
+	if kind == "NON_NULL" {
+		ofType, _ := typeRef["ofType"].(map[string]interface{})
+		return nonNullJSONSchema(types, ofType, defs)
+	}
+	return nullableJSONSchema(nonNullJSONSchema(types, typeRef, defs))
+}
+
+// nonNullJSONSchema converts a type reference whose value can't be null
+func nonNullJSONSchema(types map[string]map[string]interface{}, typeRef map[string]interface{}, defs map[string]interface{}) map[string]interface{} {
+	kind, _ := typeRef["kind"].(string)
+	name, _ := typeRef["name"].(string)
+	if typeObj, ok := types[name]; ok {
+		// Named references don't always carry the kind of the type they name
+		kind, _ = typeObj["kind"].(string)
+	}
+	switch kind {
+	case "LIST":
+		ofType, _ := typeRef["ofType"].(map[string]interface{})
+		return map[string]interface{}{"type": "array", "items": typeRefJSONSchema(types, ofType, defs)}
+	case "ENUM":
+		values := []interface{}{}
+		enumValues, _ := types[name]["enumValues"].([]interface{})
+		for _, v := range enumValues {
+			valueObj, _ := v.(map[string]interface{})
+			if valueName, _ := valueObj["name"].(string); valueName != "" {
+				values = append(values, valueName)
+			}
+		}
+		return map[string]interface{}{"type": "string", "enum": values}
+	case "INPUT_OBJECT":
+		// Add the definition before converting it, so recursive references end
+		if _, ok := defs[name]; !ok {
+			defs[name] = map[string]interface{}{}
+			if typeObj, ok := types[name]; ok {
+				defs[name] = inputObjectJSONSchema(types, typeObj, defs)
+			}
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + name}
+	case "SCALAR":
+		schema := map[string]interface{}{}
+		for key, value := range scalarJSONSchemas[name] {
+			schema[key] = value
+		}
+		if _, ok := scalarJSONSchemas[name]; !ok {
+			schema["description"] = fmt.Sprintf("Custom scalar %s", name)
+		}
+		return schema
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// nullableJSONSchema extends a schema to also accept null
+func nullableJSONSchema(schema map[string]interface{}) map[string]interface{} {
+	if _, ok := schema["$ref"]; ok {
+		return map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}
+	}
+	if t, ok := schema["type"].(string); ok {
+		schema["type"] = []interface{}{t, "null"}
+	}
+	if values, ok := schema["enum"].([]interface{}); ok {
+		schema["enum"] = append(values, nil)
+	}
+	return schema
+}
+
+// rootRefs points references to the root type at the document itself
+func rootRefs(value interface{}, typeName string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if v["$ref"] == "#/$defs/"+typeName {
+			v["$ref"] = "#"
+		}
+		for _, item := range v {
+			rootRefs(item, typeName)
+		}
+	case []interface{}:
+		for _, item := range v {
+			rootRefs(item, typeName)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package gqlt
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const jsonSchemaTestSDL = `
+	type Query {
+		user(id: ID!): User
+	}
+
+	type Mutation {
+		createUser(input: CreateUserInput!): User
+	}
+
+	type User {
+		id: ID!
+	}
+
+	"Fields of a new user"
+	input CreateUserInput {
+		"Display name"
+		name: String!
+		email: String!
+		age: Int
+		score: Float
+		active: Boolean = true
+		role: Role! = USER
+		tags: [String!]
+		joined: DateTime
+		avatar: Upload
+		address: AddressInput
+		manager: CreateUserInput
+	}
+
+	input AddressInput {
+		street: String!
+		city: String
+	}
+
+	enum Role {
+		ADMIN
+		USER
+	}
+
+	scalar DateTime
+	scalar Upload
+`
+
+func TestAnalyzer_InputTypeToJSONSchema(t *testing.T) {
+	analyzer, err := NewAnalyzerFromSDL(jsonSchemaTestSDL)
+	if err != nil {
+		t.Fatalf("NewAnalyzerFromSDL failed: %v", err)
+	}
+
+	schema, err := analyzer.InputTypeToJSONSchema("CreateUserInput")
+	if err != nil {
+		t.Fatalf("InputTypeToJSONSchema failed: %v", err)
+	}
+
+	// Compare through JSON, the form the schema is consumed in
+	encoded, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("Failed to encode schema: %v", err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(encoded, &got); err != nil {
+		t.Fatalf("Failed to decode schema: %v", err)
+	}
+
+	var want map[string]interface{}
+	err = json.Unmarshal([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "CreateUserInput",
+		"description": "Fields of a new user",
+		"type": "object",
+		"additionalProperties": false,
+		"required": ["name", "email"],
+		"properties": {
+			"name": {"type": "string", "description": "Display name"},
+			"email": {"type": "string"},
+			"age": {"type": ["integer", "null"]},
+			"score": {"type": ["number", "null"]},
+			"active": {"type": ["boolean", "null"], "default": true},
+			"role": {"type": "string", "enum": ["ADMIN", "USER"], "default": "USER"},
+			"tags": {"type": ["array", "null"], "items": {"type": "string"}},
+			"joined": {"type": ["string", "null"], "format": "date-time"},
+			"avatar": {"description": "Custom scalar Upload"},
+			"address": {"anyOf": [{"$ref": "#/$defs/AddressInput"}, {"type": "null"}]},
+			"manager": {"anyOf": [{"$ref": "#"}, {"type": "null"}]}
+		},
+		"$defs": {
+			"AddressInput": {
+				"type": "object",
+				"additionalProperties": false,
+				"required": ["street"],
+				"properties": {
+					"street": {"type": "string"},
+					"city": {"type": ["string", "null"]}
+				}
+			}
+		}
+	}`), &want)
+	if err != nil {
+		t.Fatalf("Failed to decode expected schema: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected schema:\n%s", encoded)
+	}
+}
+
+func TestAnalyzer_InputTypeToJSONSchemaErrors(t *testing.T) {
+	analyzer, err := NewAnalyzerFromSDL(jsonSchemaTestSDL)
+	if err != nil {
+		t.Fatalf("NewAnalyzerFromSDL failed: %v", err)
+	}
+
+	if _, err := analyzer.InputTypeToJSONSchema("Missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+	if _, err := analyzer.InputTypeToJSONSchema("User"); err == nil || !strings.Contains(err.Error(), "not an input type") {
+		t.Errorf("Expected a not an input type error, got %v", err)
+	}
+}
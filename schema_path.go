@@ -0,0 +1,86 @@
+package gqlt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FindPath finds the ways to navigate from one type to another through fields. It does a
+// breadth-first search over the fields of object and interface types and returns each
+// path as the list of field names to follow, shortest paths first. Paths are at most
+// maxDepth fields long and never visit the same type twice.
+//
+// Example:
+//
+//	paths, err := analyzer.FindPath("Query", "Comment", 4)
+//	// [[user posts comments]]
+func (a *Analyzer) FindPath(fromType, toType string, maxDepth int) ([][]string, error) {
+	if maxDepth < 1 {
+		return nil, fmt.Errorf("max depth must be at least 1")
+	}
+
+	types, err := a.typesByName()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := types[fromType]; !ok {
+		return nil, fmt.Errorf("type '%s' not found in schema", fromType)
+	}
+	if _, ok := types[toType]; !ok {
+		return nil, fmt.Errorf("type '%s' not found in schema", toType)
+	}
+
+	type searchState struct {
+		typeName string
+		fields   []string
+		visited  map[string]bool
+	}
+
+	paths := [][]string{}
+	queue := []searchState{{typeName: fromType, visited: map[string]bool{fromType: true}}}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+
+		typeObj := types[state.typeName]
+		kind, _ := typeObj["kind"].(string)
+		if kind != "OBJECT" && kind != "INTERFACE" {
+			continue
+		}
+
+		fieldList, _ := typeObj["fields"].([]interface{})
+		for _, f := range fieldList {
+			field, ok := f.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			fieldName, _ := field["name"].(string)
+			if strings.HasPrefix(fieldName, "__") {
+				continue
+			}
+			typeRef, _ := field["type"].(map[string]interface{})
+			next := namedTypeName(typeRef)
+			if next == "" || state.visited[next] {
+				continue
+			}
+
+			fields := append(append([]string{}, state.fields...), fieldName)
+			if next == toType {
+				paths = append(paths, fields)
+				continue
+			}
+			if len(fields) >= maxDepth {
+				continue
+			}
+
+			visited := make(map[string]bool, len(state.visited)+1)
+			for name := range state.visited {
+				visited[name] = true
+			}
+			visited[next] = true
+			queue = append(queue, searchState{typeName: next, fields: fields, visited: visited})
+		}
+	}
+
+	return paths, nil
+}
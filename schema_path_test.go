@@ -0,0 +1,109 @@
+package gqlt
+
+import (
+	"reflect"
+	"testing"
+)
+
+const pathTestSDL = `
+	type Query {
+		user(id: ID!): User
+		post(id: ID!): Post
+		version: String!
+	}
+
+	type User {
+		id: ID!
+		posts: [Post!]!
+		friends: [User!]!
+	}
+
+	type Post {
+		id: ID!
+		author: User!
+		comments: [Comment!]!
+	}
+
+	type Comment {
+		id: ID!
+		text: String!
+		post: Post!
+	}
+`
+
+func TestAnalyzer_FindPath(t *testing.T) {
+	analyzer, err := NewAnalyzerFromSDL(pathTestSDL)
+	if err != nil {
+		t.Fatalf("NewAnalyzerFromSDL failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		maxDepth int
+		want     [][]string
+		wantErr  bool
+	}{
+		{
+			name:     "shortest paths first",
+			from:     "Query",
+			to:       "Comment",
+			maxDepth: 3,
+			want: [][]string{
+				{"post", "comments"},
+				{"user", "posts", "comments"},
+			},
+		},
+		{
+			name:     "max depth limits path length",
+			from:     "Query",
+			to:       "Comment",
+			maxDepth: 2,
+			want:     [][]string{{"post", "comments"}},
+		},
+		{
+			name:     "cycles are not followed",
+			from:     "Comment",
+			to:       "User",
+			maxDepth: 5,
+			want:     [][]string{{"post", "author"}},
+		},
+		{
+			name:     "unreachable type",
+			from:     "Comment",
+			to:       "Query",
+			maxDepth: 5,
+			want:     [][]string{},
+		},
+		{
+			name:     "unknown type",
+			from:     "Query",
+			to:       "Missing",
+			maxDepth: 3,
+			wantErr:  true,
+		},
+		{
+			name:     "invalid max depth",
+			from:     "Query",
+			to:       "Comment",
+			maxDepth: 0,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := analyzer.FindPath(tt.from, tt.to, tt.maxDepth)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FindPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
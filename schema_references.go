@@ -0,0 +1,65 @@
+package gqlt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FindReferences returns every field, argument and input field in the schema whose
+// type is typeName, ignoring list and non-null wrappers. It shows what a change to
+// the type would affect. References are sorted by the declaring type, field and
+// argument names.
+//
+// Example:
+//
+//	references, err := analyzer.FindReferences("User")
+//	for _, r := range references {
+//	    fmt.Printf("%s.%s: %s\n", r.TypeName, r.Field, r.Type)
+//	}
+func (a *Analyzer) FindReferences(typeName string) ([]Reference, error) {
+	types, err := a.typesByName()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := types[typeName]; !ok {
+		return nil, fmt.Errorf("type '%s' not found in schema", typeName)
+	}
+
+	references := []Reference{}
+	addReference := func(reference Reference, item map[string]interface{}) {
+		typeRef, _ := item["type"].(map[string]interface{})
+		if namedTypeName(typeRef) == typeName {
+			reference.Type = a.formatTypeString(typeRef)
+			references = append(references, reference)
+		}
+	}
+
+	for name, typeObj := range types {
+		if strings.HasPrefix(name, "__") {
+			continue
+		}
+
+		for fieldName, field := range schemaFields(typeObj["fields"]) {
+			addReference(Reference{TypeName: name, Field: fieldName, Kind: "FIELD"}, field)
+			for argName, arg := range namedObjects(field["args"]) {
+				addReference(Reference{TypeName: name, Field: fieldName, Argument: argName, Kind: "ARGUMENT"}, arg)
+			}
+		}
+		for fieldName, field := range namedObjects(typeObj["inputFields"]) {
+			addReference(Reference{TypeName: name, Field: fieldName, Kind: "INPUT_FIELD"}, field)
+		}
+	}
+
+	sort.Slice(references, func(i, j int) bool {
+		ri, rj := references[i], references[j]
+		if ri.TypeName != rj.TypeName {
+			return ri.TypeName < rj.TypeName
+		}
+		if ri.Field != rj.Field {
+			return ri.Field < rj.Field
+		}
+		return ri.Argument < rj.Argument
+	})
+	return references, nil
+}
@@ -0,0 +1,91 @@
+package gqlt
+
+import (
+	"reflect"
+	"testing"
+)
+
+const referencesTestSDL = `
+	type Query {
+		me: User
+		user(id: ID!): User
+		users(filter: UserFilter): [User!]!
+	}
+
+	type Mutation {
+		createUser(input: CreateUserInput!): User!
+		createUsers(inputs: [CreateUserInput!]!): [User]
+	}
+
+	type Post {
+		id: ID!
+		author: User!
+	}
+
+	type User {
+		id: ID!
+		name: String!
+		posts: [Post!]!
+	}
+
+	input CreateUserInput {
+		name: String!
+	}
+
+	input UserFilter {
+		name: String
+		createdLike: CreateUserInput
+	}
+`
+
+func TestAnalyzer_FindReferences(t *testing.T) {
+	analyzer, err := NewAnalyzerFromSDL(referencesTestSDL)
+	if err != nil {
+		t.Fatalf("NewAnalyzerFromSDL failed: %v", err)
+	}
+
+	tests := []struct {
+		typeName string
+		expected []Reference
+	}{
+		{
+			typeName: "User",
+			expected: []Reference{
+				{TypeName: "Mutation", Field: "createUser", Kind: "FIELD", Type: "User!"},
+				{TypeName: "Mutation", Field: "createUsers", Kind: "FIELD", Type: "[User]"},
+				{TypeName: "Post", Field: "author", Kind: "FIELD", Type: "User!"},
+				{TypeName: "Query", Field: "me", Kind: "FIELD", Type: "User"},
+				{TypeName: "Query", Field: "user", Kind: "FIELD", Type: "User"},
+				{TypeName: "Query", Field: "users", Kind: "FIELD", Type: "[User!]!"},
+			},
+		},
+		{
+			typeName: "CreateUserInput",
+			expected: []Reference{
+				{TypeName: "Mutation", Field: "createUser", Argument: "input", Kind: "ARGUMENT", Type: "CreateUserInput!"},
+				{TypeName: "Mutation", Field: "createUsers", Argument: "inputs", Kind: "ARGUMENT", Type: "[CreateUserInput!]!"},
+				{TypeName: "UserFilter", Field: "createdLike", Kind: "INPUT_FIELD", Type: "CreateUserInput"},
+			},
+		},
+		{
+			typeName: "Mutation",
+			expected: []Reference{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typeName, func(t *testing.T) {
+			references, err := analyzer.FindReferences(tt.typeName)
+			if err != nil {
+				t.Fatalf("FindReferences failed: %v", err)
+			}
+			if !reflect.DeepEqual(references, tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, references)
+			}
+		})
+	}
+
+	if _, err := analyzer.FindReferences("Missing"); err == nil {
+		t.Error("Expected an error for an unknown type")
+	}
+}
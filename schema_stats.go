@@ -0,0 +1,106 @@
+package gqlt
+
+import (
+	"sort"
+	"strings"
+)
+
+// MostReferencedTypesLimit is the number of types reported in SchemaStats.MostReferencedTypes
+const MostReferencedTypesLimit = 10
+
+// Stats computes aggregate statistics about the schema: the number of types per kind,
+// the average number of fields per object type, the types referenced most often by
+// fields, arguments and input fields, and the number of deprecated fields and enum
+// values. Introspection types (those starting with "__") are ignored.
+//
+// Example:
+//
+//	stats, err := analyzer.Stats()
+//	fmt.Printf("%d objects, %.1f fields per object\n", stats.Objects, stats.AverageFieldsPerObject)
+func (a *Analyzer) Stats() (*SchemaStats, error) {
+	types, err := a.typesByName()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &SchemaStats{MostReferencedTypes: []TypeReferenceCount{}}
+	references := map[string]int{}
+	objectFields := 0
+
+	countReferences := func(items map[string]map[string]interface{}) {
+		for _, item := range items {
+			typeRef, _ := item["type"].(map[string]interface{})
+			if name := namedTypeName(typeRef); name != "" && !strings.HasPrefix(name, "__") {
+				references[name]++
+			}
+		}
+	}
+
+	for name, typeObj := range types {
+		if strings.HasPrefix(name, "__") {
+			continue
+		}
+
+		fields := schemaFields(typeObj["fields"])
+		kind, _ := typeObj["kind"].(string)
+		switch kind {
+		case "OBJECT":
+			stats.Objects++
+			objectFields += len(fields)
+		case "INTERFACE":
+			stats.Interfaces++
+		case "UNION":
+			stats.Unions++
+		case "ENUM":
+			stats.Enums++
+		case "INPUT_OBJECT":
+			stats.InputObjects++
+		case "SCALAR":
+			stats.Scalars++
+		}
+
+		for _, field := range fields {
+			countReferences(namedObjects(field["args"]))
+		}
+		countReferences(fields)
+		countReferences(namedObjects(typeObj["inputFields"]))
+	}
+
+	if stats.Objects > 0 {
+		stats.AverageFieldsPerObject = float64(objectFields) / float64(stats.Objects)
+	}
+
+	for name, count := range references {
+		stats.MostReferencedTypes = append(stats.MostReferencedTypes, TypeReferenceCount{Name: name, References: count})
+	}
+	sort.Slice(stats.MostReferencedTypes, func(i, j int) bool {
+		ti, tj := stats.MostReferencedTypes[i], stats.MostReferencedTypes[j]
+		if ti.References != tj.References {
+			return ti.References > tj.References
+		}
+		return ti.Name < tj.Name
+	})
+	if len(stats.MostReferencedTypes) > MostReferencedTypesLimit {
+		stats.MostReferencedTypes = stats.MostReferencedTypes[:MostReferencedTypesLimit]
+	}
+
+	deprecated, err := a.ListDeprecated()
+	if err != nil {
+		return nil, err
+	}
+	stats.Deprecated = len(deprecated)
+
+	return stats, nil
+}
+
+// schemaFields indexes the fields of a type by name, leaving out the introspection
+// meta-fields (__schema, __type) that some servers list on the query type
+func schemaFields(list interface{}) map[string]map[string]interface{} {
+	fields := namedObjects(list)
+	for name := range fields {
+		if strings.HasPrefix(name, "__") {
+			delete(fields, name)
+		}
+	}
+	return fields
+}
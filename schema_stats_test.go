@@ -0,0 +1,106 @@
+package gqlt
+
+import (
+	"testing"
+)
+
+const statsTestSDL = `
+	scalar DateTime
+
+	interface Node {
+		id: ID!
+	}
+
+	type Query {
+		user(id: ID!): User
+		users(filter: UserFilter): [User!]!
+		node(id: ID!): Node
+	}
+
+	type User implements Node {
+		id: ID!
+		name: String!
+		createdAt: DateTime
+		role: Role @deprecated(reason: "Use roles instead")
+	}
+
+	type Post implements Node {
+		id: ID!
+		title: String!
+		author: User!
+	}
+
+	enum Role {
+		ADMIN
+		GUEST @deprecated
+	}
+
+	input UserFilter {
+		role: Role
+		name: String
+	}
+
+	union SearchResult = User | Post
+`
+
+func TestAnalyzer_Stats(t *testing.T) {
+	introspection, err := SDLToIntrospection(statsTestSDL)
+	if err != nil {
+		t.Fatalf("SDLToIntrospection failed: %v", err)
+	}
+	analyzer, err := NewAnalyzer(&Response{Data: introspection})
+	if err != nil {
+		t.Fatalf("NewAnalyzer failed: %v", err)
+	}
+
+	stats, err := analyzer.Stats()
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+
+	counts := []struct {
+		name string
+		got  int
+		want int
+	}{
+		{"objects", stats.Objects, 3},
+		{"interfaces", stats.Interfaces, 1},
+		{"unions", stats.Unions, 1},
+		{"enums", stats.Enums, 1},
+		{"input objects", stats.InputObjects, 1},
+		{"scalars", stats.Scalars, 6}, // DateTime plus the five built-in scalars
+		{"deprecated", stats.Deprecated, 2},
+	}
+	for _, c := range counts {
+		if c.got != c.want {
+			t.Errorf("%s = %d, want %d", c.name, c.got, c.want)
+		}
+	}
+
+	// Query, User and Post have 10 fields between them
+	if want := 10.0 / 3.0; stats.AverageFieldsPerObject != want {
+		t.Errorf("AverageFieldsPerObject = %v, want %v", stats.AverageFieldsPerObject, want)
+	}
+
+	wantTop := []TypeReferenceCount{
+		{Name: "ID", References: 5},
+		{Name: "String", References: 3},
+		{Name: "User", References: 3},
+		{Name: "Role", References: 2},
+	}
+	if len(stats.MostReferencedTypes) < len(wantTop) {
+		t.Fatalf("MostReferencedTypes = %v, want at least %d entries", stats.MostReferencedTypes, len(wantTop))
+	}
+	for i, want := range wantTop {
+		if got := stats.MostReferencedTypes[i]; got != want {
+			t.Errorf("MostReferencedTypes[%d] = %+v, want %+v", i, got, want)
+		}
+	}
+}
+
+func TestAnalyzer_StatsInvalidSchema(t *testing.T) {
+	analyzer := &Analyzer{schemaData: map[string]interface{}{}}
+	if _, err := analyzer.Stats(); err == nil {
+		t.Error("Expected error for schema without types")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -529,3 +530,322 @@ func TestAnalyzer_FindField_EdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestAnalyzer_ListDeprecated(t *testing.T) {
+	schemaData := map[string]interface{}{
+		"__schema": map[string]interface{}{
+			"types": []interface{}{
+				map[string]interface{}{
+					"name": "User",
+					"kind": "OBJECT",
+					"fields": []interface{}{
+						map[string]interface{}{
+							"name":         "id",
+							"isDeprecated": false,
+						},
+						map[string]interface{}{
+							"name":              "username",
+							"isDeprecated":      true,
+							"deprecationReason": "Use handle instead",
+						},
+					},
+				},
+				map[string]interface{}{
+					"name": "Role",
+					"kind": "ENUM",
+					"enumValues": []interface{}{
+						map[string]interface{}{
+							"name":         "ADMIN",
+							"isDeprecated": false,
+						},
+						map[string]interface{}{
+							"name":              "SUPERUSER",
+							"isDeprecated":      true,
+							"deprecationReason": "Merged into ADMIN",
+						},
+					},
+				},
+				map[string]interface{}{
+					"name": "__Type",
+					"kind": "OBJECT",
+					"fields": []interface{}{
+						map[string]interface{}{
+							"name":         "internal",
+							"isDeprecated": true,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	analyzer, err := NewAnalyzer(&Response{Data: schemaData})
+	if err != nil {
+		t.Fatalf("NewAnalyzer failed: %v", err)
+	}
+
+	deprecated, err := analyzer.ListDeprecated()
+	if err != nil {
+		t.Fatalf("ListDeprecated failed: %v", err)
+	}
+
+	expected := []DeprecatedField{
+		{TypeName: "User", Name: "username", Kind: "FIELD", DeprecationReason: "Use handle instead"},
+		{TypeName: "Role", Name: "SUPERUSER", Kind: "ENUM_VALUE", DeprecationReason: "Merged into ADMIN"},
+	}
+
+	if len(deprecated) != len(expected) {
+		t.Fatalf("Expected %d deprecated entries, got %d: %+v", len(expected), len(deprecated), deprecated)
+	}
+	for i, want := range expected {
+		if deprecated[i] != want {
+			t.Errorf("Expected %+v, got %+v", want, deprecated[i])
+		}
+	}
+}
+
+func TestAnalyzer_ListDeprecated_FromSDL(t *testing.T) {
+	introspection, err := SDLToIntrospection(`
+		type Query {
+			user: String
+			oldUser: String @deprecated(reason: "Use user")
+			legacy: String @deprecated
+		}
+	`)
+	if err != nil {
+		t.Fatalf("SDLToIntrospection failed: %v", err)
+	}
+
+	analyzer, err := NewAnalyzer(&Response{Data: introspection})
+	if err != nil {
+		t.Fatalf("NewAnalyzer failed: %v", err)
+	}
+
+	deprecated, err := analyzer.ListDeprecated()
+	if err != nil {
+		t.Fatalf("ListDeprecated failed: %v", err)
+	}
+
+	reasons := make(map[string]string)
+	for _, d := range deprecated {
+		reasons[d.TypeName+"."+d.Name] = d.DeprecationReason
+	}
+
+	if len(reasons) != 2 {
+		t.Fatalf("Expected 2 deprecated fields, got %+v", deprecated)
+	}
+	if reasons["Query.oldUser"] != "Use user" {
+		t.Errorf("Expected reason 'Use user', got %q", reasons["Query.oldUser"])
+	}
+	if reasons["Query.legacy"] != "No longer supported" {
+		t.Errorf("Expected default reason, got %q", reasons["Query.legacy"])
+	}
+}
+
+func TestAnalyzer_TypeToSDL(t *testing.T) {
+	introspection, err := SDLToIntrospection(`
+		type Query {
+			user(id: ID!): User
+			search(term: String!): [SearchResult!]!
+		}
+
+		type User {
+			id: ID!
+			name: String!
+			tags: [String!]
+			role: Role
+		}
+
+		type Post {
+			title: String
+		}
+
+		enum Role {
+			ADMIN
+			USER
+		}
+
+		input UserInput {
+			name: String!
+			ids: [ID!]!
+		}
+
+		union SearchResult = User | Post
+	`)
+	if err != nil {
+		t.Fatalf("SDLToIntrospection failed: %v", err)
+	}
+
+	analyzer, err := NewAnalyzer(&Response{Data: introspection})
+	if err != nil {
+		t.Fatalf("NewAnalyzer failed: %v", err)
+	}
+
+	tests := []struct {
+		typeName string
+		expected string
+	}{
+		{
+			typeName: "User",
+			expected: "type User {\n  id: ID!\n  name: String!\n  tags: [String!]\n  role: Role\n}\n",
+		},
+		{
+			typeName: "Role",
+			expected: "enum Role {\n  ADMIN\n  USER\n}\n",
+		},
+		{
+			typeName: "UserInput",
+			expected: "input UserInput {\n  name: String!\n  ids: [ID!]!\n}\n",
+		},
+		{
+			typeName: "SearchResult",
+			expected: "union SearchResult = User | Post\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typeName, func(t *testing.T) {
+			sdl, err := analyzer.TypeToSDL(tt.typeName)
+			if err != nil {
+				t.Fatalf("TypeToSDL failed: %v", err)
+			}
+			if sdl != tt.expected {
+				t.Errorf("Expected SDL:\n%s\ngot:\n%s", tt.expected, sdl)
+			}
+		})
+	}
+
+	if _, err := analyzer.TypeToSDL("Missing"); err == nil {
+		t.Error("Expected error for unknown type")
+	}
+}
+
+func TestNewAnalyzerFromSDL(t *testing.T) {
+	sdl := `
+		type Query {
+			"Look up a user"
+			user(id: ID!): User
+		}
+
+		type User {
+			id: ID!
+			name: String!
+			friends: [User!]!
+		}
+	`
+
+	analyzer, err := NewAnalyzerFromSDL(sdl)
+	if err != nil {
+		t.Fatalf("NewAnalyzerFromSDL failed: %v", err)
+	}
+
+	typeDesc, err := analyzer.FindType("User")
+	if err != nil {
+		t.Fatalf("FindType failed: %v", err)
+	}
+	if typeDesc.Kind != "OBJECT" || len(typeDesc.Fields) != 3 {
+		t.Errorf("Expected OBJECT User with 3 fields, got %+v", typeDesc)
+	}
+
+	fieldDesc, err := analyzer.FindField("Query", "user")
+	if err != nil {
+		t.Fatalf("FindField failed: %v", err)
+	}
+	if fieldDesc.Type != "User" || fieldDesc.Description != "Look up a user" {
+		t.Errorf("Unexpected field description: %+v", fieldDesc)
+	}
+	if len(fieldDesc.Arguments) != 1 || fieldDesc.Arguments[0].Signature != "id: ID!" {
+		t.Errorf("Expected argument 'id: ID!', got %+v", fieldDesc.Arguments)
+	}
+
+	// Converting back to SDL yields the original type definition
+	userSDL, err := analyzer.TypeToSDL("User")
+	if err != nil {
+		t.Fatalf("TypeToSDL failed: %v", err)
+	}
+	if want := "type User {\n  id: ID!\n  name: String!\n  friends: [User!]!\n}\n"; userSDL != want {
+		t.Errorf("TypeToSDL() = %q, want %q", userSDL, want)
+	}
+
+	if _, err := NewAnalyzerFromSDL("type Query {"); err == nil {
+		t.Error("Expected error for invalid SDL")
+	}
+}
+
+func TestAnalyzer_GetImplementors(t *testing.T) {
+	analyzer, err := NewAnalyzerFromSDL(`
+		interface Node {
+			id: ID!
+		}
+
+		type Query {
+			node(id: ID!): Node
+		}
+
+		type User implements Node {
+			id: ID!
+		}
+
+		type Post implements Node {
+			id: ID!
+		}
+
+		type Tag {
+			name: String!
+		}
+	`)
+	if err != nil {
+		t.Fatalf("NewAnalyzerFromSDL failed: %v", err)
+	}
+
+	implementors, err := analyzer.GetImplementors("Node")
+	if err != nil {
+		t.Fatalf("GetImplementors failed: %v", err)
+	}
+	if want := []string{"Post", "User"}; !reflect.DeepEqual(implementors, want) {
+		t.Errorf("GetImplementors() = %v, want %v", implementors, want)
+	}
+
+	desc, err := analyzer.GetTypeDescription("Node")
+	if err != nil {
+		t.Fatalf("GetTypeDescription failed: %v", err)
+	}
+	if !reflect.DeepEqual(desc.Implementors, implementors) {
+		t.Errorf("Expected type description to list implementors %v, got %v", implementors, desc.Implementors)
+	}
+
+	if _, err := analyzer.GetImplementors("Tag"); err == nil {
+		t.Error("Expected error for non-interface type")
+	}
+	if _, err := analyzer.GetImplementors("Missing"); err == nil {
+		t.Error("Expected error for unknown type")
+	}
+}
+
+func TestAnalyzer_GetImplementors_PossibleTypes(t *testing.T) {
+	// Introspection results from a live endpoint list implementors as possibleTypes
+	analyzer, err := NewAnalyzer(&Response{Data: map[string]interface{}{
+		"__schema": map[string]interface{}{
+			"types": []interface{}{
+				map[string]interface{}{
+					"kind": "INTERFACE",
+					"name": "Node",
+					"possibleTypes": []interface{}{
+						map[string]interface{}{"kind": "OBJECT", "name": "User"},
+					},
+				},
+			},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("NewAnalyzer failed: %v", err)
+	}
+
+	implementors, err := analyzer.GetImplementors("Node")
+	if err != nil {
+		t.Fatalf("GetImplementors failed: %v", err)
+	}
+	if want := []string{"User"}; !reflect.DeepEqual(implementors, want) {
+		t.Errorf("GetImplementors() = %v, want %v", implementors, want)
+	}
+}
@@ -0,0 +1,233 @@
+package gqlt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ScriptStep is one operation of a script run by RunScript. Its variables may
+// reference the responses of earlier steps (see ResolveReferences).
+type ScriptStep struct {
+	Name          string                 `json:"name,omitempty"`
+	Query         string                 `json:"query,omitempty"`
+	QueryFile     string                 `json:"queryFile,omitempty"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// LoadScript reads a script of operations from a JSON file, either a list of
+// steps or an object with a "steps" list. Steps without a name are named after
+// their position ("step1", "step2", ...), and query files are read relative to
+// the script.
+//
+// Example:
+//
+//	steps, err := gqlt.LoadScript("steps.json")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func LoadScript(path string) ([]ScriptStep, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read script: %w", err)
+	}
+
+	var steps []ScriptStep
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] == '{' {
+		var script struct {
+			Steps []ScriptStep `json:"steps"`
+		}
+		err = json.Unmarshal(content, &script)
+		steps = script.Steps
+	} else {
+		err = json.Unmarshal(content, &steps)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse script: %w", err)
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("script has no steps")
+	}
+
+	input := NewInput()
+	names := make(map[string]bool, len(steps))
+	for i := range steps {
+		step := &steps[i]
+		if step.Name == "" {
+			step.Name = fmt.Sprintf("step%d", i+1)
+		}
+		if strings.ContainsAny(step.Name, ".[") {
+			return nil, fmt.Errorf("step %d: name %q cannot contain '.' or '['", i+1, step.Name)
+		}
+		if names[step.Name] {
+			return nil, fmt.Errorf("step %d: duplicate name %q", i+1, step.Name)
+		}
+		names[step.Name] = true
+
+		if step.Query != "" && step.QueryFile != "" {
+			return nil, fmt.Errorf("step %q: cannot specify both query and queryFile", step.Name)
+		}
+		if step.QueryFile != "" {
+			queryFile := step.QueryFile
+			if !filepath.IsAbs(queryFile) {
+				queryFile = filepath.Join(filepath.Dir(path), queryFile)
+			}
+			step.Query, err = input.LoadQuery("", queryFile)
+			if err != nil {
+				return nil, fmt.Errorf("step %q: failed to load query: %w", step.Name, err)
+			}
+		}
+		if step.Query == "" {
+			return nil, fmt.Errorf("step %q: no query", step.Name)
+		}
+	}
+	return steps, nil
+}
+
+// referencePattern matches ${step.path} references
+var referencePattern = regexp.MustCompile(`\$\{([^{}]+)\}`)
+
+// ResolveReferences replaces ${name.path} references in the strings of value,
+// which may be nested in objects and lists, with values from results. The name
+// selects an entry of results and the path is read from it as with SelectPath
+// (e.g. "${step1.data.createUser.id}" or "${users.data.users[0].id}"). A string
+// that is a single reference is replaced by the referenced value itself, keeping
+// its type; references embedded in longer strings are interpolated, as-is for
+// strings and as JSON otherwise. The value passed in is not modified.
+//
+// Example:
+//
+//	results := map[string]interface{}{"create": map[string]interface{}{"data": response.Data}}
+//	vars, err := gqlt.ResolveReferences(map[string]interface{}{"id": "${create.data.createUser.id}"}, results)
+func ResolveReferences(value interface{}, results map[string]interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return resolveString(v, results)
+	case map[string]interface{}:
+		resolved := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			r, err := ResolveReferences(item, results)
+			if err != nil {
+				return nil, err
+			}
+			resolved[key] = r
+		}
+		return resolved, nil
+	case []interface{}:
+		resolved := make([]interface{}, len(v))
+		for i, item := range v {
+			r, err := ResolveReferences(item, results)
+			if err != nil {
+				return nil, err
+			}
+			resolved[i] = r
+		}
+		return resolved, nil
+	default:
+		return v, nil
+	}
+}
+
+// resolveString resolves the references in a single string
+func resolveString(s string, results map[string]interface{}) (interface{}, error) {
+	matches := referencePattern.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, nil
+	}
+	if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(s) {
+		return lookupReference(s[matches[0][2]:matches[0][3]], results)
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, match := range matches {
+		sb.WriteString(s[last:match[0]])
+		value, err := lookupReference(s[match[2]:match[3]], results)
+		if err != nil {
+			return nil, err
+		}
+		if str, ok := value.(string); ok {
+			sb.WriteString(str)
+		} else {
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode ${%s}: %w", s[match[2]:match[3]], err)
+			}
+			sb.Write(encoded)
+		}
+		last = match[1]
+	}
+	sb.WriteString(s[last:])
+	return sb.String(), nil
+}
+
+// lookupReference reads a "name.path" reference from results
+func lookupReference(ref string, results map[string]interface{}) (interface{}, error) {
+	ref = strings.TrimSpace(ref)
+	name, path, _ := strings.Cut(ref, ".")
+	if i := strings.Index(name, "["); i >= 0 {
+		name, path = name[:i], strings.TrimPrefix(ref[i:], ".")
+	}
+	result, ok := results[name]
+	if !ok {
+		return nil, fmt.Errorf("${%s}: unknown step %q", ref, name)
+	}
+	value, err := SelectPath(result, path)
+	if err != nil {
+		return nil, fmt.Errorf("${%s}: %w", ref, err)
+	}
+	return value, nil
+}
+
+// RunScript executes the steps of a script in order. Before each step its
+// variables are resolved against the responses of the steps before it, which
+// are available by step name as {"data": ..., "errors": ..., "extensions": ...}.
+// onStep (if set) is called with every step, its variables resolved, and its
+// response. The script stops at the first step that fails, returns GraphQL
+// errors or whose variables can't be resolved, or when onStep returns an error,
+// with an error naming that step.
+//
+// Example:
+//
+//	err := client.RunScript(ctx, steps, func(step gqlt.ScriptStep, response *gqlt.Response) error {
+//	    fmt.Printf("%s: %v\n", step.Name, response.Data)
+//	    return nil
+//	})
+func (c *Client) RunScript(ctx context.Context, steps []ScriptStep, onStep func(ScriptStep, *Response) error) error {
+	results := make(map[string]interface{}, len(steps))
+	for _, step := range steps {
+		if step.Variables != nil {
+			resolved, err := ResolveReferences(step.Variables, results)
+			if err != nil {
+				return fmt.Errorf("step %q: %w", step.Name, err)
+			}
+			step.Variables = resolved.(map[string]interface{})
+		}
+
+		response, err := c.ExecuteContext(ctx, step.Query, step.Variables, step.OperationName)
+		if err != nil {
+			return fmt.Errorf("step %q: %w", step.Name, err)
+		}
+		if onStep != nil {
+			if err := onStep(step, response); err != nil {
+				return fmt.Errorf("step %q: %w", step.Name, err)
+			}
+		}
+		if len(response.Errors) > 0 {
+			return fmt.Errorf("step %q: operation returned %d GraphQL error(s)", step.Name, len(response.Errors))
+		}
+
+		results[step.Name] = map[string]interface{}{
+			"data":       response.Data,
+			"errors":     response.Errors,
+			"extensions": response.Extensions,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package gqlt
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadScript(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "user.graphql"), []byte("query User($id: ID!) { user(id: $id) { name } }"), 0644); err != nil {
+		t.Fatalf("Failed to write query file: %v", err)
+	}
+	script := filepath.Join(dir, "steps.json")
+	err := os.WriteFile(script, []byte(`[
+		{"name": "create", "query": "mutation { createUser(name: \"Ada\") { id } }"},
+		{"queryFile": "user.graphql", "variables": {"id": "${create.data.createUser.id}"}}
+	]`), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write script: %v", err)
+	}
+
+	steps, err := LoadScript(script)
+	if err != nil {
+		t.Fatalf("LoadScript failed: %v", err)
+	}
+	if len(steps) != 2 || steps[0].Name != "create" || steps[1].Name != "step2" {
+		t.Fatalf("Expected steps create and step2, got %+v", steps)
+	}
+	if !strings.Contains(steps[1].Query, "user(id: $id)") {
+		t.Errorf("Expected the query file to be loaded relative to the script, got %q", steps[1].Query)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "steps object", content: `{"steps": [{"query": "{ a }"}]}`},
+		{name: "no steps", content: `[]`, wantErr: "no steps"},
+		{name: "no query", content: `[{"name": "a"}]`, wantErr: `step "a": no query`},
+		{name: "duplicate name", content: `[{"name": "a", "query": "{ a }"}, {"name": "a", "query": "{ b }"}]`, wantErr: `duplicate name "a"`},
+		{name: "dotted name", content: `[{"name": "a.b", "query": "{ a }"}]`, wantErr: "cannot contain"},
+		{name: "invalid JSON", content: `[{`, wantErr: "failed to parse script"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "script.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write script: %v", err)
+			}
+			_, err := LoadScript(path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("LoadScript failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestResolveReferences(t *testing.T) {
+	var results map[string]interface{}
+	err := json.Unmarshal([]byte(`{
+		"create": {"data": {"createUser": {"id": "42", "tags": ["a", "b"], "age": 36}}},
+		"list": {"data": {"users": [{"id": "1"}, {"id": "2"}]}}
+	}`), &results)
+	if err != nil {
+		t.Fatalf("Failed to decode results: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    interface{}
+		wantErr string
+	}{
+		{name: "whole string", value: "${create.data.createUser.id}", want: "42"},
+		{name: "keeps type", value: "${create.data.createUser.age}", want: float64(36)},
+		{name: "list value", value: "${create.data.createUser.tags}", want: []interface{}{"a", "b"}},
+		{name: "bracket index", value: "${list.data.users[1].id}", want: "2"},
+		{name: "embedded", value: "user-${create.data.createUser.id}-${create.data.createUser.age}", want: "user-42-36"},
+		{name: "embedded JSON", value: "tags=${create.data.createUser.tags}", want: `tags=["a","b"]`},
+		{name: "no reference", value: "plain", want: "plain"},
+		{name: "nested", value: map[string]interface{}{"input": []interface{}{"${create.data.createUser.id}", true}},
+			want: map[string]interface{}{"input": []interface{}{"42", true}}},
+		{name: "unknown step", value: "${missing.data.id}", wantErr: `unknown step "missing"`},
+		{name: "missing field", value: "${create.data.deleteUser}", wantErr: `field "data.deleteUser" not found`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveReferences(tt.value, results)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveReferences failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %#v, got %#v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestClient_RunScript(t *testing.T) {
+	var received []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		received = append(received, body.Variables)
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(body.Query, "createUser"):
+			w.Write([]byte(`{"data":{"createUser":{"id":"42"}}}`))
+		case strings.Contains(body.Query, "failing"):
+			w.Write([]byte(`{"data":null,"errors":[{"message":"boom"}]}`))
+		default:
+			w.Write([]byte(`{"data":{"user":{"id":"42","name":"Ada"}}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+
+	t.Run("create then fetch", func(t *testing.T) {
+		received = nil
+		steps := []ScriptStep{
+			{Name: "step1", Query: `mutation { createUser(name: "Ada") { id } }`},
+			{Name: "step2", Query: `query ($id: ID!) { user(id: $id) { id name } }`,
+				Variables: map[string]interface{}{"id": "${step1.data.createUser.id}"}},
+		}
+		var names []string
+		err := client.RunScript(context.Background(), steps, func(step ScriptStep, response *Response) error {
+			names = append(names, step.Name)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("RunScript failed: %v", err)
+		}
+		if !reflect.DeepEqual(names, []string{"step1", "step2"}) {
+			t.Errorf("Expected both steps to run, got %v", names)
+		}
+		if len(received) != 2 || received[1]["id"] != "42" {
+			t.Errorf("Expected the second step to get the created ID, got %v", received)
+		}
+		if steps[1].Variables["id"] != "${step1.data.createUser.id}" {
+			t.Errorf("Expected the script's variables to be left unchanged, got %v", steps[1].Variables)
+		}
+	})
+
+	t.Run("failing first step", func(t *testing.T) {
+		received = nil
+		steps := []ScriptStep{
+			{Name: "step1", Query: `query failing { failing }`},
+			{Name: "step2", Query: `query { user(id: 1) { id } }`},
+		}
+		calls := 0
+		err := client.RunScript(context.Background(), steps, func(step ScriptStep, response *Response) error {
+			calls++
+			return nil
+		})
+		if err == nil || !strings.Contains(err.Error(), `step "step1"`) {
+			t.Fatalf("Expected an error naming step1, got %v", err)
+		}
+		if calls != 1 || len(received) != 1 {
+			t.Errorf("Expected the script to stop after the first step, got %d callbacks and %d requests", calls, len(received))
+		}
+	})
+
+	t.Run("unresolved reference", func(t *testing.T) {
+		received = nil
+		steps := []ScriptStep{
+			{Name: "fetch", Query: `query ($id: ID!) { user(id: $id) { id } }`,
+				Variables: map[string]interface{}{"id": "${create.data.createUser.id}"}},
+		}
+		err := client.RunScript(context.Background(), steps, nil)
+		if err == nil || !strings.Contains(err.Error(), `unknown step "create"`) {
+			t.Fatalf("Expected an unknown step error, got %v", err)
+		}
+		if len(received) != 0 {
+			t.Errorf("Expected no request to be sent, got %d", len(received))
+		}
+	})
+}
@@ -38,8 +38,8 @@ func (c *Client) FetchSDL() (string, error) {
 		}
 
 		// Add headers
-		for k, v := range c.headers {
-			req.Header.Set(k, v)
+		if err := c.setHeaders(req, nil); err != nil {
+			return "", err
 		}
 
 		resp, err := c.httpClient.Do(req)
@@ -49,7 +49,7 @@ func (c *Client) FetchSDL() (string, error) {
 		defer resp.Body.Close()
 
 		if resp.StatusCode == http.StatusOK {
-			body, err := io.ReadAll(resp.Body)
+			body, err := io.ReadAll(c.limitResponse(resp.Body))
 			if err != nil {
 				continue
 			}
@@ -305,5 +305,5 @@ func getDeprecationReason(directives ast.DirectiveList) string {
 		return "No longer supported"
 	}
 
-	return reason.Value.String()
+	return reason.Value.Raw
 }
@@ -0,0 +1,88 @@
+package gqlt
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestSigV4Sign(t *testing.T) {
+	// The get-vanilla case of the AWS Signature Version 4 test suite
+	req, _ := http.NewRequest("GET", "https://example.amazonaws.com/", nil)
+	signer := &sigV4Transport{
+		region:  "us-east-1",
+		service: "service",
+		creds: AWSCredentials{
+			AccessKeyID:     "AKIDEXAMPLE",
+			SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		},
+	}
+	signer.sign(req, nil, time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	expected := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, " +
+		"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if auth := req.Header.Get("Authorization"); auth != expected {
+		t.Errorf("Expected Authorization %q, got %q", expected, auth)
+	}
+	if date := req.Header.Get("X-Amz-Date"); date != "20150830T123600Z" {
+		t.Errorf("Expected X-Amz-Date 20150830T123600Z, got %q", date)
+	}
+}
+
+func TestClientSetAWSSigV4(t *testing.T) {
+	var received http.Header
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.Write([]byte(`{"data": {"ok": true}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, map[string]string{"Authorization": "Bearer replaced"})
+	client.SetAWSSigV4("eu-west-1", "appsync", AWSCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+		SessionToken:    "session-token",
+	})
+
+	result, err := client.Execute(`{ ok }`, nil, "")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if result.Data == nil {
+		t.Errorf("Expected data, got %+v", result)
+	}
+	if body != `{"query":"{ ok }"}` {
+		t.Errorf("Expected the request body to be sent unchanged, got %q", body)
+	}
+
+	authorization := regexp.MustCompile(`^AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/(\d{8})/eu-west-1/appsync/aws4_request, SignedHeaders=([a-z0-9;-]+), Signature=[0-9a-f]{64}$`)
+	match := authorization.FindStringSubmatch(received.Get("Authorization"))
+	if match == nil {
+		t.Fatalf("Expected a SigV4 Authorization header, got %q", received.Get("Authorization"))
+	}
+	if date := received.Get("X-Amz-Date"); len(date) != 16 || date[:8] != match[1] {
+		t.Errorf("Expected X-Amz-Date on the credential date %s, got %q", match[1], date)
+	}
+	if signedHeaders := match[2]; signedHeaders != "content-type;host;x-amz-date;x-amz-security-token" {
+		t.Errorf("Unexpected signed headers %q", signedHeaders)
+	}
+	if token := received.Get("X-Amz-Security-Token"); token != "session-token" {
+		t.Errorf("Expected the session token header, got %q", token)
+	}
+
+	// Empty credentials turn signing off
+	client.SetAWSSigV4("", "", AWSCredentials{})
+	if _, err := client.Execute(`{ ok }`, nil, ""); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if auth := received.Get("Authorization"); auth != "Bearer replaced" {
+		t.Errorf("Expected the unsigned Authorization header, got %q", auth)
+	}
+}
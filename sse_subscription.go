@@ -1,6 +1,7 @@
 package gqlt
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -67,7 +68,11 @@ func (c *SSESubscriptionClient) Subscribe(ctx context.Context, query string, var
 	req.Header.Set("Connection", "keep-alive")
 
 	// Add custom headers
-	for k, v := range c.headers {
+	headers, err := expandHeaders(c.headers, payloadJSON)
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range headers {
 		req.Header.Set(k, v)
 	}
 
@@ -120,6 +125,7 @@ func (c *SSESubscriptionClient) Subscribe(ctx context.Context, query string, var
 
 					// Extract GraphQL response
 					subMsg := &SubscriptionMessage{
+						Type:   SubscriptionMessageTypeData,
 						Data:   graphqlResponse["data"],
 						Errors: []interface{}{},
 					}
@@ -139,8 +145,7 @@ func (c *SSESubscriptionClient) Subscribe(ctx context.Context, query string, var
 
 // SSEReader reads Server-Sent Events from an io.Reader
 type SSEReader struct {
-	reader io.Reader
-	buffer []byte
+	reader *bufio.Reader
 }
 
 // SSEEvent represents a parsed SSE event
@@ -153,64 +158,52 @@ type SSEEvent struct {
 // NewSSEReader creates a new SSE reader
 func NewSSEReader(reader io.Reader) *SSEReader {
 	return &SSEReader{
-		reader: reader,
-		buffer: make([]byte, 4096),
+		reader: bufio.NewReader(reader),
 	}
 }
 
-// ReadEvent reads the next SSE event
+// ReadEvent reads the next SSE event. Events are terminated by a blank line and may
+// span several reads of the underlying reader. Returns io.EOF when the stream ends.
 func (r *SSEReader) ReadEvent() (*SSEEvent, error) {
 	var event SSEEvent
+	var dataLines []string
+	hasFields := false
 
 	for {
-		n, err := r.reader.Read(r.buffer)
-		if err != nil {
+		line, err := r.reader.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			// A partial event at the end of the stream is discarded
 			return nil, err
 		}
+		line = strings.TrimRight(line, "\r\n")
 
-		lines := strings.Split(string(r.buffer[:n]), "\n")
-
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-
-			if line == "" {
-				// Empty line indicates end of event
-				if event.Type != "" || event.Data != "" {
-					return &event, nil
-				}
-				continue
-			}
-
-			if strings.HasPrefix(line, ":") {
-				// Comment line, ignore
-				continue
-			}
-
-			colonIndex := strings.Index(line, ":")
-			if colonIndex == -1 {
-				continue
+		if line == "" {
+			// Empty line indicates end of event
+			if hasFields {
+				event.Data = strings.Join(dataLines, "\n")
+				return &event, nil
 			}
+			continue
+		}
 
-			field := line[:colonIndex]
-			value := strings.TrimSpace(line[colonIndex+1:])
-
-			switch field {
-			case "event":
-				event.Type = value
-			case "data":
-				if event.Data != "" {
-					event.Data += "\n" + value
-				} else {
-					event.Data = value
-				}
-			case "id":
-				event.ID = value
-			}
+		if strings.HasPrefix(line, ":") {
+			// Comment line, ignore
+			continue
 		}
 
-		// If we have a complete event, return it
-		if event.Type != "" || event.Data != "" {
-			return &event, nil
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "event":
+			event.Type = value
+		case "data":
+			dataLines = append(dataLines, value)
+		case "id":
+			event.ID = value
+		default:
+			continue
 		}
+		hasFields = true
 	}
 }
@@ -0,0 +1,117 @@
+package gqlt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestSSEReader_ReadEvent(t *testing.T) {
+	stream := ": keep-alive\n\n" +
+		"event: next\ndata: {\"data\":{\"n\":1}}\n\n" +
+		"event: next\r\nid: 2\r\ndata: {\"data\":\r\ndata: {\"n\":2}}\r\n\r\n" +
+		"event: complete\ndata:\n\n"
+
+	// Reading one byte at a time splits events across reads
+	for name, reader := range map[string]io.Reader{
+		"single read": strings.NewReader(stream),
+		"one byte":    iotest.OneByteReader(strings.NewReader(stream)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			sseReader := NewSSEReader(reader)
+
+			want := []SSEEvent{
+				{Type: "next", Data: `{"data":{"n":1}}`},
+				{Type: "next", Data: "{\"data\":\n{\"n\":2}}", ID: "2"},
+				{Type: "complete"},
+			}
+			for i, w := range want {
+				event, err := sseReader.ReadEvent()
+				if err != nil {
+					t.Fatalf("ReadEvent %d failed: %v", i, err)
+				}
+				if *event != w {
+					t.Errorf("Event %d = %+v, want %+v", i, *event, w)
+				}
+			}
+
+			if _, err := sseReader.ReadEvent(); err != io.EOF {
+				t.Errorf("Expected io.EOF at end of stream, got %v", err)
+			}
+		})
+	}
+}
+
+func TestClient_Subscribe_GraphQLSSE(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/event-stream" {
+			http.Error(w, "expected text/event-stream", http.StatusNotAcceptable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+
+		// Several events in a single write
+		var events strings.Builder
+		for i := 1; i <= 3; i++ {
+			fmt.Fprintf(&events, "event: next\ndata: {\"data\":{\"count\":%d}}\n\n", i)
+		}
+		events.WriteString("event: complete\ndata:\n\n")
+		w.Write([]byte(events.String()))
+		w.(http.Flusher).Flush()
+
+		// The client must stop reading at complete, not at the end of the stream
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	if err := client.SetSubscriptionProtocol(SubscriptionProtocolSSE); err != nil {
+		t.Fatalf("SetSubscriptionProtocol failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	messages, errs, err := client.Subscribe(ctx, `subscription { count }`, nil, "")
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	var counts []float64
+	for msg := range messages {
+		data, _ := msg.Data.(map[string]interface{})
+		count, _ := data["count"].(float64)
+		counts = append(counts, count)
+	}
+	for err := range errs {
+		t.Errorf("Unexpected subscription error: %v", err)
+	}
+
+	if ctx.Err() != nil {
+		t.Fatal("Expected subscription to end at the complete event")
+	}
+	if len(counts) != 3 || counts[0] != 1 || counts[2] != 3 {
+		t.Errorf("Expected counts 1..3, got %v", counts)
+	}
+}
+
+func TestClient_SetSubscriptionProtocol(t *testing.T) {
+	client := NewClient("ws://api.example.com/graphql", nil)
+	if err := client.SetSubscriptionProtocol("carrier-pigeon"); err == nil {
+		t.Error("Expected error for unknown protocol")
+	}
+
+	if err := client.SetSubscriptionProtocol(SubscriptionProtocolSSE); err != nil {
+		t.Fatalf("SetSubscriptionProtocol failed: %v", err)
+	}
+	if _, _, err := client.Subscribe(context.Background(), `subscription { count }`, nil, ""); err == nil {
+		t.Error("Expected graphql-sse to reject a WebSocket endpoint")
+	}
+}
@@ -0,0 +1,149 @@
+package gqlt
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// streamResponse copies the data field of a GraphQL response from body to w token
+// by token, wrapped in the same envelope the JSON formatter writes for a Response.
+// Errors and extensions are decoded into result and written after data.
+func streamResponse(body io.Reader, w io.Writer, result *Response) error {
+	dec := json.NewDecoder(body)
+	dec.UseNumber()
+	out := bufio.NewWriter(w)
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return fmt.Errorf("failed to parse GraphQL response: %w", err)
+	}
+
+	wroteData := false
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("failed to parse GraphQL response: %w", err)
+		}
+		key, _ := tok.(string)
+
+		switch key {
+		case "data":
+			out.WriteString(`{"data":`)
+			wroteData = true
+			err = copyJSONValue(dec, out)
+		case "errors":
+			err = decodeJSONValue(dec, &result.Errors)
+		case "extensions":
+			err = decodeJSONValue(dec, &result.Extensions)
+		default:
+			var ignored json.RawMessage
+			err = dec.Decode(&ignored)
+		}
+		if err != nil {
+			out.Flush()
+			return fmt.Errorf("failed to parse GraphQL response: %w", err)
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		out.Flush()
+		return fmt.Errorf("failed to parse GraphQL response: %w", err)
+	}
+
+	if !wroteData {
+		out.WriteString(`{"data":null`)
+	}
+	if len(result.Errors) > 0 {
+		out.WriteString(`,"errors":`)
+		if err := writeJSON(out, result.Errors); err != nil {
+			return err
+		}
+	}
+	if len(result.Extensions) > 0 {
+		out.WriteString(`,"extensions":`)
+		if err := writeJSON(out, result.Extensions); err != nil {
+			return err
+		}
+	}
+	out.WriteString("}\n")
+	return out.Flush()
+}
+
+// copyJSONValue writes the next JSON value from dec to out without decoding it
+// into memory as a whole
+func copyJSONValue(dec *json.Decoder, out *bufio.Writer) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		out.WriteRune(rune(v))
+		closing := json.Delim('}')
+		if v == '[' {
+			closing = ']'
+		}
+		for first := true; dec.More(); first = false {
+			if !first {
+				out.WriteByte(',')
+			}
+			if v == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				if err := writeJSON(out, key); err != nil {
+					return err
+				}
+				out.WriteByte(':')
+			}
+			if err := copyJSONValue(dec, out); err != nil {
+				return err
+			}
+		}
+		if err := expectDelim(dec, closing); err != nil {
+			return err
+		}
+		out.WriteRune(rune(closing))
+		return nil
+	case json.Number:
+		_, err := out.WriteString(v.String())
+		return err
+	default:
+		// Strings, booleans and null
+		return writeJSON(out, v)
+	}
+}
+
+// decodeJSONValue decodes the next value from dec into v with plain float64
+// numbers, as json.Unmarshal would, although dec is set to use json.Number
+func decodeJSONValue(dec *json.Decoder, v interface{}) error {
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// writeJSON writes the compact JSON encoding of v, escaped like json.Encoder does
+func writeJSON(out io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(data)
+	return err
+}
+
+// expectDelim reads the next token and checks that it is the given delimiter
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("expected %q, got %v", delim, tok)
+	}
+	return nil
+}
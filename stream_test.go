@@ -0,0 +1,145 @@
+package gqlt
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestClient_ExecuteStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// Errors before data, unknown keys and unusual numbers must all survive
+		w.Write([]byte(`{"errors":[{"message":"partial failure","path":["users",1]}],
+			"data":{"users":[{"id":"1","score":1e3,"tags":["a","<b>"],"meta":null},{"id":"2","active":false,"nested":{"empty":{},"list":[]}}]},
+			"unknown":{"ignored":true},
+			"extensions":{"cost":12}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	var out bytes.Buffer
+	result, err := client.ExecuteStream(`{ users { id } }`, nil, "", &out)
+	if err != nil {
+		t.Fatalf("ExecuteStream failed: %v", err)
+	}
+
+	want := `{"data":{"users":[{"id":"1","score":1e3,"tags":["a","\u003cb\u003e"],"meta":null},{"id":"2","active":false,"nested":{"empty":{},"list":[]}}]},` +
+		`"errors":[{"message":"partial failure","path":["users",1]}],"extensions":{"cost":12}}` + "\n"
+	if out.String() != want {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", out.String(), want)
+	}
+	if result.Data != nil || len(result.Errors) != 1 || result.Extensions["cost"] != float64(12) {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
+
+func TestClient_ExecuteStreamMatchesFormatter(t *testing.T) {
+	body := `{"data":{"a":{"b":[1,2.5,"x"],"c":true}}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	var streamed bytes.Buffer
+	if _, err := client.ExecuteStream(`{ a { b c } }`, nil, "", &streamed); err != nil {
+		t.Fatalf("ExecuteStream failed: %v", err)
+	}
+
+	response, err := client.Execute(`{ a { b c } }`, nil, "")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	var formatted bytes.Buffer
+	formatter := NewFormatter("json")
+	formatter.SetOutput(&formatted)
+	if err := formatter.FormatResponse(response, "compact"); err != nil {
+		t.Fatalf("FormatResponse failed: %v", err)
+	}
+
+	if streamed.String() != formatted.String() {
+		t.Errorf("Expected streamed output %q to match formatter output %q", streamed.String(), formatted.String())
+	}
+}
+
+func TestClient_ExecuteStreamMalformed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"users":[{"id":"1"`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	if _, err := client.ExecuteStream(`{ users { id } }`, nil, "", &bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "failed to parse GraphQL response") {
+		t.Errorf("Expected parse error, got %v", err)
+	}
+}
+
+// countingWriter counts the bytes written to it and periodically samples the live
+// heap while doing so
+type countingWriter struct {
+	bytes    int
+	writes   int
+	peakHeap uint64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.bytes += len(p)
+	w.writes++
+	if w.writes%256 == 0 {
+		runtime.GC()
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		if stats.HeapAlloc > w.peakHeap {
+			w.peakHeap = stats.HeapAlloc
+		}
+	}
+	return len(p), nil
+}
+
+func TestClient_ExecuteStreamLargeResponse(t *testing.T) {
+	// Build a response of about 16MB with many small objects
+	var body bytes.Buffer
+	body.WriteString(`{"data":{"items":[`)
+	for i := 0; body.Len() < 16<<20; i++ {
+		if i > 0 {
+			body.WriteByte(',')
+		}
+		fmt.Fprintf(&body, `{"id":"%d","name":"item number %d","value":%d}`, i, i, i*7)
+	}
+	body.WriteString(`]}}`)
+	payload := body.Bytes()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(payload)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+
+	runtime.GC()
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	out := &countingWriter{}
+	if _, err := client.ExecuteStream(`{ items { id name value } }`, nil, "", out); err != nil {
+		t.Fatalf("ExecuteStream failed: %v", err)
+	}
+
+	// The envelope wraps the same data, so the output is the size of the response plus the trailing newline
+	if out.bytes != len(payload)+1 {
+		t.Errorf("Expected %d bytes written, got %d", len(payload)+1, out.bytes)
+	}
+
+	// Decoding the whole response would keep well over its own size live on the heap
+	if out.writes < 256 {
+		t.Fatalf("Expected the output to be written in many small writes, got %d", out.writes)
+	}
+	if growth := int64(out.peakHeap) - int64(before.HeapAlloc); growth > int64(len(payload))/2 {
+		t.Errorf("Expected heap growth below %d bytes while streaming, peaked at %d", len(payload)/2, growth)
+	}
+}
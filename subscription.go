@@ -2,6 +2,7 @@ package gqlt
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"sync"
@@ -13,11 +14,16 @@ import (
 
 // SubscriptionClient handles GraphQL subscriptions over WebSocket
 type SubscriptionClient struct {
-	url        string
-	headers    map[string]string
-	conn       *websocket.Conn
-	protocol   string // negotiated subprotocol
-	mu         sync.Mutex
+	url      string
+	headers  map[string]string
+	conn     *websocket.Conn
+	pinned   SubscriptionProtocol // required subprotocol, or empty to accept any
+	protocol string               // negotiated subprotocol
+	mu       sync.Mutex
+
+	connectionParams map[string]interface{}
+	lifecycleEvents  bool
+	httpClient       *http.Client // client for the handshake, or nil for the default
 }
 
 // GraphQL WebSocket Protocol Messages (graphql-transport-ws)
@@ -32,19 +38,50 @@ const (
 	MessageTypeComplete       = "complete"
 )
 
+// Legacy GraphQL WebSocket Protocol Messages (graphql-ws, from subscriptions-transport-ws)
+const (
+	MessageTypeStart           = "start"
+	MessageTypeData            = "data"
+	MessageTypeStop            = "stop"
+	MessageTypeKeepAlive       = "ka"
+	MessageTypeConnectionError = "connection_error"
+)
+
+// webSocketSubprotocols are the subprotocols offered when no protocol is pinned, in order of preference
+var webSocketSubprotocols = []string{"graphql-transport-ws", "graphql-ws", "apollo-ws"}
+
 // WebSocket message structure
 type wsMessage struct {
-	ID      string                 `json:"id,omitempty"`
-	Type    string                 `json:"type"`
-	Payload map[string]interface{} `json:"payload,omitempty"`
+	ID      string      `json:"id,omitempty"`
+	Type    string      `json:"type"`
+	Payload interface{} `json:"payload,omitempty"` // an array of errors for graphql-transport-ws error messages
 }
 
+// Subscription message types
+const (
+	SubscriptionMessageTypeData        = "data"
+	SubscriptionMessageTypeKeepAlive   = "keepalive"
+	SubscriptionMessageTypeComplete    = "complete"
+	SubscriptionMessageTypeError       = "error"
+	SubscriptionMessageTypeReconnected = "reconnected"
+)
+
 // SubscriptionMessage represents a message received from a subscription
 type SubscriptionMessage struct {
+	// Type is one of the SubscriptionMessageType constants. Keep-alive, complete and
+	// error messages are only sent if lifecycle events are enabled with SetLifecycleEvents.
+	Type   string        `json:"type,omitempty"`
 	Data   interface{}   `json:"data,omitempty"`
 	Errors []interface{} `json:"errors,omitempty"`
+
+	// Reconnected marks a message without data, sent after the subscription was
+	// re-established following a dropped connection (see Client.SetReconnect)
+	Reconnected bool `json:"reconnected,omitempty"`
 }
 
+// ErrConnectionLost is wrapped by subscription errors caused by the connection dropping
+var ErrConnectionLost = errors.New("subscription connection lost")
+
 // NewSubscriptionClient creates a new WebSocket subscription client
 func NewSubscriptionClient(url string, headers map[string]string) *SubscriptionClient {
 	return &SubscriptionClient{
@@ -53,19 +90,69 @@ func NewSubscriptionClient(url string, headers map[string]string) *SubscriptionC
 	}
 }
 
+// SetProtocol pins the WebSocket subprotocol to SubscriptionProtocolGraphQLTransportWS or
+// SubscriptionProtocolGraphQLWS. Connect fails if the server doesn't accept it. By default
+// the subprotocol chosen by the server is used.
+func (c *SubscriptionClient) SetProtocol(protocol SubscriptionProtocol) error {
+	switch protocol {
+	case SubscriptionProtocolAuto, SubscriptionProtocolGraphQLTransportWS, SubscriptionProtocolGraphQLWS:
+		c.pinned = protocol
+		return nil
+	default:
+		return fmt.Errorf("unsupported WebSocket subprotocol: %s", protocol)
+	}
+}
+
+// SetConnectionParams sets parameters sent in the connection_init payload, such as
+// auth tokens for servers that don't authenticate WebSocket connections by headers.
+// They are sent alongside the client's headers, which are included under "headers".
+func (c *SubscriptionClient) SetConnectionParams(params map[string]interface{}) {
+	c.connectionParams = params
+}
+
+// SetLifecycleEvents makes the subscription deliver keep-alive, complete and error
+// events as messages of the corresponding type, rather than swallowing keep-alives and
+// closing the channels silently on completion. Subscription errors sent by the server
+// are then delivered as error messages instead of on the error channel.
+func (c *SubscriptionClient) SetLifecycleEvents(enabled bool) {
+	c.lifecycleEvents = enabled
+}
+
+// Protocol returns the subprotocol negotiated by Connect
+func (c *SubscriptionClient) Protocol() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.protocol
+}
+
+// isLegacyProtocol reports whether the connection speaks the legacy graphql-ws protocol
+func (c *SubscriptionClient) isLegacyProtocol() bool {
+	return c.protocol == "graphql-ws" || c.protocol == "apollo-ws"
+}
+
 // Connect establishes a WebSocket connection and performs the connection handshake
 func (c *SubscriptionClient) Connect(ctx context.Context) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	subprotocols := webSocketSubprotocols
+	if c.pinned != SubscriptionProtocolAuto {
+		subprotocols = []string{string(c.pinned)}
+	}
+
 	// Set up WebSocket dial options with headers
 	opts := &websocket.DialOptions{
 		HTTPHeader:   http.Header{},
-		Subprotocols: []string{"graphql-transport-ws", "graphql-ws", "apollo-ws"},
+		Subprotocols: subprotocols,
+		HTTPClient:   c.httpClient,
 	}
 
 	// Add custom headers
-	for k, v := range c.headers {
+	headers, err := expandHeaders(c.headers, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
 		opts.HTTPHeader.Set(k, v)
 	}
 
@@ -77,19 +164,29 @@ func (c *SubscriptionClient) Connect(ctx context.Context) error {
 
 	c.conn = conn
 	c.protocol = conn.Subprotocol()
-	
+
 	// If server didn't specify, check response header
 	if c.protocol == "" {
 		c.protocol = resp.Header.Get("Sec-WebSocket-Protocol")
 	}
 
+	if c.pinned != SubscriptionProtocolAuto && c.protocol != string(c.pinned) {
+		c.conn.Close(websocket.StatusPolicyViolation, "Unsupported subprotocol")
+		c.conn = nil
+		return fmt.Errorf("server did not accept WebSocket subprotocol %s", c.pinned)
+	}
+
 	// Send connection_init message
+	initPayload := map[string]interface{}{
+		// Include headers as connection params for auth
+		"headers": headers,
+	}
+	for k, v := range c.connectionParams {
+		initPayload[k] = v
+	}
 	initMsg := wsMessage{
-		Type: MessageTypeConnectionInit,
-		Payload: map[string]interface{}{
-			// Include headers as connection params for auth
-			"headers": c.headers,
-		},
+		Type:    MessageTypeConnectionInit,
+		Payload: initPayload,
 	}
 
 	if err := wsjson.Write(ctx, c.conn, initMsg); err != nil {
@@ -98,18 +195,29 @@ func (c *SubscriptionClient) Connect(ctx context.Context) error {
 	}
 
 	// Wait for connection_ack
-	var ackMsg wsMessage
-	if err := wsjson.Read(ctx, c.conn, &ackMsg); err != nil {
-		c.conn.Close(websocket.StatusInternalError, "Failed to receive connection_ack")
-		return fmt.Errorf("failed to receive connection_ack: %w", err)
-	}
+	for {
+		var ackMsg wsMessage
+		if err := wsjson.Read(ctx, c.conn, &ackMsg); err != nil {
+			c.conn.Close(websocket.StatusInternalError, "Failed to receive connection_ack")
+			return fmt.Errorf("failed to receive connection_ack: %w", err)
+		}
 
-	if ackMsg.Type != MessageTypeConnectionAck {
-		c.conn.Close(websocket.StatusPolicyViolation, "Expected connection_ack")
-		return fmt.Errorf("expected connection_ack, got %s", ackMsg.Type)
+		switch ackMsg.Type {
+		case MessageTypeConnectionAck:
+			return nil
+		case MessageTypePing:
+			// graphql-transport-ws servers may ping before acknowledging
+			wsjson.Write(ctx, c.conn, wsMessage{Type: MessageTypePong})
+		case MessageTypePong, MessageTypeKeepAlive:
+			continue
+		case MessageTypeConnectionError:
+			c.conn.Close(websocket.StatusPolicyViolation, "Connection rejected")
+			return fmt.Errorf("connection rejected: %v", ackMsg.Payload)
+		default:
+			c.conn.Close(websocket.StatusPolicyViolation, "Expected connection_ack")
+			return fmt.Errorf("expected connection_ack, got %s", ackMsg.Type)
+		}
 	}
-
-	return nil
 }
 
 // Subscribe sends a subscription request and returns a channel of messages
@@ -127,25 +235,25 @@ func (c *SubscriptionClient) Subscribe(ctx context.Context, query string, variab
 	// Determine message type based on protocol
 	// graphql-ws uses "start", graphql-transport-ws uses "subscribe"
 	messageType := MessageTypeSubscribe
-	if c.protocol == "graphql-ws" || c.protocol == "apollo-ws" {
-		messageType = "start"
+	if c.isLegacyProtocol() {
+		messageType = MessageTypeStart
 	}
 
-	// Send subscribe/start message
-	subscribeMsg := wsMessage{
-		ID:   subscriptionID,
-		Type: messageType,
-		Payload: map[string]interface{}{
-			"query": query,
-		},
+	payload := map[string]interface{}{
+		"query": query,
 	}
-
 	if operationName != "" {
-		subscribeMsg.Payload["operationName"] = operationName
+		payload["operationName"] = operationName
 	}
-
 	if variables != nil {
-		subscribeMsg.Payload["variables"] = variables
+		payload["variables"] = variables
+	}
+
+	// Send subscribe/start message
+	subscribeMsg := wsMessage{
+		ID:      subscriptionID,
+		Type:    messageType,
+		Payload: payload,
 	}
 
 	if err := wsjson.Write(ctx, c.conn, subscribeMsg); err != nil {
@@ -174,56 +282,61 @@ func (c *SubscriptionClient) receiveMessages(ctx context.Context, subscriptionID
 			c.Unsubscribe(context.Background(), subscriptionID)
 			return
 		default:
-			// Try to read a message with a timeout to check context periodically
-			readCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			// Wait for the next message for as long as it takes; an expired read
+			// context would close the connection, so quiet subscriptions must not
+			// have one
 			var msg wsMessage
-			err := wsjson.Read(readCtx, c.conn, &msg)
-			cancel()
+			err := wsjson.Read(ctx, c.conn, &msg)
 
 			if err != nil {
 				// Check if context was cancelled
 				if ctx.Err() != nil {
 					return
 				}
-				// Check if it's just a timeout (continue reading)
-				if readCtx.Err() == context.DeadlineExceeded {
-					continue
-				}
-				// Real error
-				errors <- fmt.Errorf("failed to read message: %w", err)
+				// The connection was closed or broke
+				errors <- fmt.Errorf("failed to read message: %w: %w", ErrConnectionLost, err)
 				return
 			}
 
 			// Handle message based on type
 			switch msg.Type {
-			case MessageTypeNext, "data": // "next" for graphql-transport-ws, "data" for graphql-ws
+			case MessageTypeNext, MessageTypeData: // "next" for graphql-transport-ws, "data" for graphql-ws
 				// Subscription data message
 				if msg.ID == subscriptionID {
-					if payload, ok := msg.Payload["data"].(map[string]interface{}); ok {
+					if payload, ok := msg.Payload.(map[string]interface{}); ok {
 						subMsg := &SubscriptionMessage{
-							Data: payload,
+							Type: SubscriptionMessageTypeData,
+							Data: payload["data"],
 						}
-						if errs, ok := msg.Payload["errors"].([]interface{}); ok {
+						if errs, ok := payload["errors"].([]interface{}); ok {
 							subMsg.Errors = errs
 						}
 						messages <- subMsg
 					}
 				}
 
-			case "ka": // keep-alive for graphql-ws
-				// Ignore keep-alive messages
-				continue
+			case MessageTypeKeepAlive: // keep-alive for graphql-ws
+				if c.lifecycleEvents {
+					messages <- &SubscriptionMessage{Type: SubscriptionMessageTypeKeepAlive}
+				}
 
 			case MessageTypeError:
 				// Subscription error
 				if msg.ID == subscriptionID {
-					errors <- fmt.Errorf("subscription error: %v", msg.Payload)
+					if c.lifecycleEvents {
+						messages <- &SubscriptionMessage{Type: SubscriptionMessageTypeError, Errors: errorList(msg.Payload)}
+					} else {
+						errors <- fmt.Errorf("subscription error: %v", msg.Payload)
+					}
 					return
 				}
 
 			case MessageTypeComplete:
 				// Subscription completed
 				if msg.ID == subscriptionID {
+					if c.lifecycleEvents {
+						messages <- &SubscriptionMessage{Type: SubscriptionMessageTypeComplete}
+					}
 					return
 				}
 
@@ -231,12 +344,24 @@ func (c *SubscriptionClient) receiveMessages(ctx context.Context, subscriptionID
 				// Respond to ping with pong
 				pongMsg := wsMessage{Type: MessageTypePong}
 				wsjson.Write(ctx, c.conn, pongMsg)
+				if c.lifecycleEvents {
+					messages <- &SubscriptionMessage{Type: SubscriptionMessageTypeKeepAlive}
+				}
 			}
 		}
 	}
 }
 
-// Unsubscribe sends a complete message to stop the subscription
+// errorList converts an error message payload, a list for graphql-transport-ws and a
+// single error for graphql-ws, into a list of errors
+func errorList(payload interface{}) []interface{} {
+	if list, ok := payload.([]interface{}); ok {
+		return list
+	}
+	return []interface{}{payload}
+}
+
+// Unsubscribe sends a complete (or, for graphql-ws, stop) message to stop the subscription
 func (c *SubscriptionClient) Unsubscribe(ctx context.Context, subscriptionID string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -245,9 +370,14 @@ func (c *SubscriptionClient) Unsubscribe(ctx context.Context, subscriptionID str
 		return nil
 	}
 
+	messageType := MessageTypeComplete
+	if c.isLegacyProtocol() {
+		messageType = MessageTypeStop
+	}
+
 	completeMsg := wsMessage{
 		ID:   subscriptionID,
-		Type: MessageTypeComplete,
+		Type: messageType,
 	}
 
 	return wsjson.Write(ctx, c.conn, completeMsg)
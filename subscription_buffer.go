@@ -0,0 +1,112 @@
+package gqlt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// SetSubscriptionBuffer makes Subscribe read messages from the server as soon as they
+// arrive and queue up to size of them until the caller receives them, so a slow
+// consumer doesn't stall the connection. When the queue is full, reading from the
+// server pauses until the caller catches up; messages are never dropped. If replay is
+// not nil, every message is also written to it as a line of JSON when it arrives,
+// before it is queued, so a consumer that crashes can resume from the replay. A size of
+// zero or less with a nil replay turns buffering off, which is the default.
+//
+// Example:
+//
+//	replay, err := os.OpenFile("replay.jsonl", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer replay.Close()
+//	client.SetSubscriptionBuffer(1000, replay)
+func (c *Client) SetSubscriptionBuffer(size int, replay io.Writer) {
+	c.subscriptionBuffer = size
+	c.subscriptionReplay = replay
+}
+
+// bufferSubscription relays the messages of a subscription through a queue of up to
+// size messages, writing each one to replay if it is not nil. Errors are relayed after
+// the messages that arrived before the subscription ended.
+func bufferSubscription(ctx context.Context, messages <-chan *SubscriptionMessage, errs <-chan error, size int, replay io.Writer) (<-chan *SubscriptionMessage, <-chan error) {
+	if size < 1 {
+		size = 1
+	}
+	out := make(chan *SubscriptionMessage)
+	outErrs := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(outErrs)
+
+		var encoder *json.Encoder
+		if replay != nil {
+			encoder = json.NewEncoder(replay)
+		}
+
+		var queue []*SubscriptionMessage
+		in := messages
+		for in != nil || len(queue) > 0 {
+			// Stop reading while the queue is full, and send only when it has messages
+			receive := in
+			if len(queue) >= size {
+				receive = nil
+			}
+			var send chan<- *SubscriptionMessage
+			var next *SubscriptionMessage
+			if len(queue) > 0 {
+				send, next = out, queue[0]
+			}
+
+			select {
+			case msg, ok := <-receive:
+				if !ok {
+					in = nil
+					continue
+				}
+				if encoder != nil {
+					if err := encoder.Encode(msg); err != nil {
+						drainSubscription(messages, errs)
+						outErrs <- fmt.Errorf("failed to write subscription replay: %w", err)
+						return
+					}
+				}
+				queue = append(queue, msg)
+			case send <- next:
+				queue[0] = nil
+				queue = queue[1:]
+			case <-ctx.Done():
+				drainSubscription(in, errs)
+				return
+			}
+		}
+
+		for err := range errs {
+			select {
+			case outErrs <- err:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, outErrs
+}
+
+// drainSubscription discards whatever a subscription still sends, so its reader
+// doesn't block on a channel nobody receives from
+func drainSubscription(messages <-chan *SubscriptionMessage, errs <-chan error) {
+	if messages != nil {
+		go func() {
+			for range messages {
+			}
+		}()
+	}
+	go func() {
+		for range errs {
+		}
+	}()
+}
@@ -0,0 +1,116 @@
+package gqlt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_SetSubscriptionBuffer(t *testing.T) {
+	const total = 50
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		for i := 1; i <= total; i++ {
+			fmt.Fprintf(w, "event: next\ndata: {\"data\":{\"count\":%d}}\n\n", i)
+			w.(http.Flusher).Flush()
+		}
+		fmt.Fprint(w, "event: complete\ndata:\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	for _, size := range []int{0, 2, 100} {
+		t.Run(fmt.Sprintf("size %d", size), func(t *testing.T) {
+			var replay bytes.Buffer
+			client := NewClient(server.URL, nil)
+			if err := client.SetSubscriptionProtocol(SubscriptionProtocolSSE); err != nil {
+				t.Fatalf("SetSubscriptionProtocol failed: %v", err)
+			}
+			client.SetSubscriptionBuffer(size, &replay)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			messages, errs, err := client.Subscribe(ctx, "subscription { count }", nil, "")
+			if err != nil {
+				t.Fatalf("Subscribe failed: %v", err)
+			}
+
+			// A slow reader still gets every message, in order
+			received := 0
+			for msg := range messages {
+				time.Sleep(time.Millisecond)
+				received++
+				count := msg.Data.(map[string]interface{})["count"]
+				if count != float64(received) {
+					t.Fatalf("Message %d has count %v", received, count)
+				}
+			}
+			if received != total {
+				t.Errorf("Expected %d messages, got %d", total, received)
+			}
+			if err := <-errs; err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			lines := strings.Split(strings.TrimSpace(replay.String()), "\n")
+			if len(lines) != total {
+				t.Fatalf("Expected %d replay lines, got %d", total, len(lines))
+			}
+			var last SubscriptionMessage
+			if err := json.Unmarshal([]byte(lines[total-1]), &last); err != nil {
+				t.Fatalf("Replay line is not JSON: %v", err)
+			}
+			if count := last.Data.(map[string]interface{})["count"]; count != float64(total) {
+				t.Errorf("Expected the last replay line to have count %d, got %v", total, count)
+			}
+		})
+	}
+}
+
+func TestBufferSubscription_ReadsAhead(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	messages := make(chan *SubscriptionMessage)
+	errs := make(chan error)
+	buffered, _ := bufferSubscription(ctx, messages, errs, 3, nil)
+
+	// The sender isn't held up by a reader that hasn't received anything yet
+	for i := 0; i < 3; i++ {
+		select {
+		case messages <- &SubscriptionMessage{Data: i}:
+		case <-time.After(time.Second):
+			t.Fatalf("Message %d was not buffered", i)
+		}
+	}
+	// With the buffer full, the sender has to wait
+	select {
+	case messages <- &SubscriptionMessage{Data: 3}:
+		t.Fatal("Expected the full buffer to hold up the sender")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	go func() {
+		messages <- &SubscriptionMessage{Data: 3}
+		close(messages)
+		close(errs)
+	}()
+	for i := 0; i < 4; i++ {
+		if msg := <-buffered; msg.Data != i {
+			t.Errorf("Expected message %d, got %v", i, msg.Data)
+		}
+	}
+	if _, ok := <-buffered; ok {
+		t.Error("Expected the buffered channel to be closed")
+	}
+}
@@ -0,0 +1,631 @@
+package gqlt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+// newWebSocketServer serves a subscription emitting two messages over whichever of the given
+// subprotocols the client negotiates. The type of the client's subscribe message is sent on started.
+func newWebSocketServer(t *testing.T, subprotocols []string, started chan<- string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{Subprotocols: subprotocols})
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+
+		ctx := r.Context()
+		legacy := conn.Subprotocol() == "graphql-ws"
+		if !legacy && conn.Subprotocol() != "graphql-transport-ws" {
+			conn.Close(websocket.StatusPolicyViolation, "unsupported subprotocol")
+			return
+		}
+
+		var msg wsMessage
+		if err := wsjson.Read(ctx, conn, &msg); err != nil || msg.Type != MessageTypeConnectionInit {
+			t.Errorf("Expected connection_init, got %+v (%v)", msg, err)
+			return
+		}
+
+		// graphql-transport-ws servers may ping before acknowledging
+		if !legacy {
+			wsjson.Write(ctx, conn, wsMessage{Type: MessageTypePing})
+			if err := wsjson.Read(ctx, conn, &msg); err != nil || msg.Type != MessageTypePong {
+				t.Errorf("Expected pong, got %+v (%v)", msg, err)
+				return
+			}
+		}
+		wsjson.Write(ctx, conn, wsMessage{Type: MessageTypeConnectionAck})
+		if legacy {
+			wsjson.Write(ctx, conn, wsMessage{Type: MessageTypeKeepAlive})
+		}
+
+		if err := wsjson.Read(ctx, conn, &msg); err != nil {
+			t.Errorf("Failed to read subscribe message: %v", err)
+			return
+		}
+		started <- msg.Type
+
+		dataType := MessageTypeNext
+		if legacy {
+			dataType = MessageTypeData
+		}
+		for i := 1; i <= 2; i++ {
+			wsjson.Write(ctx, conn, wsMessage{
+				ID:      msg.ID,
+				Type:    dataType,
+				Payload: map[string]interface{}{"data": map[string]interface{}{"count": i}},
+			})
+		}
+		wsjson.Write(ctx, conn, wsMessage{ID: msg.ID, Type: MessageTypeComplete})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// collectCounts subscribes and returns the counts received until the subscription completes
+func collectCounts(t *testing.T, client *Client) []float64 {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	messages, errs, err := client.Subscribe(ctx, `subscription { count }`, nil, "")
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	var counts []float64
+	for msg := range messages {
+		data, _ := msg.Data.(map[string]interface{})
+		count, _ := data["count"].(float64)
+		counts = append(counts, count)
+	}
+	for err := range errs {
+		t.Errorf("Unexpected subscription error: %v", err)
+	}
+	return counts
+}
+
+func TestClient_Subscribe_WebSocketProtocols(t *testing.T) {
+	tests := []struct {
+		name          string
+		serverOffers  []string
+		pinned        SubscriptionProtocol
+		wantSubscribe string
+	}{
+		{
+			name:          "auto-detects graphql-transport-ws",
+			serverOffers:  []string{"graphql-transport-ws"},
+			wantSubscribe: MessageTypeSubscribe,
+		},
+		{
+			name:          "auto-detects legacy graphql-ws",
+			serverOffers:  []string{"graphql-ws"},
+			wantSubscribe: MessageTypeStart,
+		},
+		{
+			name:          "pinned graphql-ws",
+			serverOffers:  []string{"graphql-transport-ws", "graphql-ws"},
+			pinned:        SubscriptionProtocolGraphQLWS,
+			wantSubscribe: MessageTypeStart,
+		},
+		{
+			name:          "pinned graphql-transport-ws",
+			serverOffers:  []string{"graphql-ws", "graphql-transport-ws"},
+			pinned:        SubscriptionProtocolGraphQLTransportWS,
+			wantSubscribe: MessageTypeSubscribe,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started := make(chan string, 1)
+			server := newWebSocketServer(t, tt.serverOffers, started)
+
+			client := NewClient("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+			if err := client.SetSubscriptionProtocol(tt.pinned); err != nil {
+				t.Fatalf("SetSubscriptionProtocol failed: %v", err)
+			}
+
+			counts := collectCounts(t, client)
+			if len(counts) != 2 || counts[0] != 1 || counts[1] != 2 {
+				t.Errorf("Expected counts [1 2], got %v", counts)
+			}
+			if got := <-started; got != tt.wantSubscribe {
+				t.Errorf("Expected %s message, got %s", tt.wantSubscribe, got)
+			}
+		})
+	}
+}
+
+func TestClient_Subscribe_PinnedProtocolRejected(t *testing.T) {
+	server := newWebSocketServer(t, []string{"graphql-transport-ws"}, make(chan string, 1))
+
+	// A pinned subprotocol must not fall back to another protocol or to SSE
+	client := NewClient(server.URL, nil)
+	if err := client.SetSubscriptionProtocol(SubscriptionProtocolGraphQLWS); err != nil {
+		t.Fatalf("SetSubscriptionProtocol failed: %v", err)
+	}
+
+	_, _, err := client.Subscribe(context.Background(), `subscription { count }`, nil, "")
+	if err == nil || !strings.Contains(err.Error(), "graphql-ws") {
+		t.Errorf("Expected error about the rejected subprotocol, got %v", err)
+	}
+}
+
+func TestClient_Subscribe_ConnectionParams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{Subprotocols: []string{"graphql-transport-ws"}})
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		ctx := r.Context()
+
+		var msg wsMessage
+		if err := wsjson.Read(ctx, conn, &msg); err != nil || msg.Type != MessageTypeConnectionInit {
+			return
+		}
+
+		// Reject the connection unless the auth token is in the connection params
+		payload, _ := msg.Payload.(map[string]interface{})
+		if payload["authToken"] != "secret" {
+			conn.Close(4403, "Forbidden")
+			return
+		}
+		// Headers are still sent alongside the params
+		if headers, _ := payload["headers"].(map[string]interface{}); headers["X-Client"] != "gqlt" {
+			conn.Close(4400, "Missing headers")
+			return
+		}
+		wsjson.Write(ctx, conn, wsMessage{Type: MessageTypeConnectionAck})
+
+		if err := wsjson.Read(ctx, conn, &msg); err != nil {
+			return
+		}
+		wsjson.Write(ctx, conn, wsMessage{
+			ID:      msg.ID,
+			Type:    MessageTypeNext,
+			Payload: map[string]interface{}{"data": map[string]interface{}{"count": 1}},
+		})
+		wsjson.Write(ctx, conn, wsMessage{ID: msg.ID, Type: MessageTypeComplete})
+	}))
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	client := NewClient(wsURL, map[string]string{"X-Client": "gqlt"})
+	if _, _, err := client.Subscribe(context.Background(), `subscription { count }`, nil, ""); err == nil {
+		t.Fatal("Expected subscription without connection params to be rejected")
+	}
+
+	client.SetConnectionParams(map[string]interface{}{"authToken": "secret"})
+	counts := collectCounts(t, client)
+	if len(counts) != 1 || counts[0] != 1 {
+		t.Errorf("Expected counts [1], got %v", counts)
+	}
+}
+
+func TestClient_Subscribe_Reconnect(t *testing.T) {
+	var connections atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{Subprotocols: []string{"graphql-transport-ws"}})
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		ctx := r.Context()
+		n := connections.Add(1)
+
+		var msg wsMessage
+		if err := wsjson.Read(ctx, conn, &msg); err != nil {
+			return
+		}
+		wsjson.Write(ctx, conn, wsMessage{Type: MessageTypeConnectionAck})
+		if err := wsjson.Read(ctx, conn, &msg); err != nil {
+			return
+		}
+		wsjson.Write(ctx, conn, wsMessage{
+			ID:      msg.ID,
+			Type:    MessageTypeNext,
+			Payload: map[string]interface{}{"data": map[string]interface{}{"count": n}},
+		})
+
+		// Drop the first connection mid-stream; complete the second normally
+		if n == 1 {
+			conn.Close(websocket.StatusGoingAway, "restarting")
+			return
+		}
+		wsjson.Write(ctx, conn, wsMessage{ID: msg.ID, Type: MessageTypeComplete})
+	}))
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	t.Run("without reconnect", func(t *testing.T) {
+		connections.Store(0)
+		client := NewClient(wsURL, nil)
+
+		messages, errs, err := client.Subscribe(context.Background(), `subscription { count }`, nil, "")
+		if err != nil {
+			t.Fatalf("Subscribe failed: %v", err)
+		}
+		for range messages {
+		}
+		if err := <-errs; !errors.Is(err, ErrConnectionLost) {
+			t.Errorf("Expected ErrConnectionLost, got %v", err)
+		}
+	})
+
+	t.Run("with reconnect", func(t *testing.T) {
+		connections.Store(0)
+		client := NewClient(wsURL, nil)
+		client.SetReconnect(3, 10*time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		messages, errs, err := client.Subscribe(ctx, `subscription { count }`, nil, "")
+		if err != nil {
+			t.Fatalf("Subscribe failed: %v", err)
+		}
+
+		var events []string
+		for msg := range messages {
+			if msg.Reconnected {
+				events = append(events, "reconnected")
+				continue
+			}
+			data, _ := msg.Data.(map[string]interface{})
+			events = append(events, fmt.Sprintf("count %v", data["count"]))
+		}
+		for err := range errs {
+			t.Errorf("Unexpected subscription error: %v", err)
+		}
+
+		want := []string{"count 1", "reconnected", "count 2"}
+		if strings.Join(events, ", ") != strings.Join(want, ", ") {
+			t.Errorf("Expected events %v, got %v", want, events)
+		}
+		// Normal completion must not trigger another reconnect
+		if got := connections.Load(); got != 2 {
+			t.Errorf("Expected 2 connections, got %d", got)
+		}
+	})
+}
+
+func TestClient_Subscribe_ReconnectGivesUp(t *testing.T) {
+	var connections atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{Subprotocols: []string{"graphql-transport-ws"}})
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+
+		// Only the first connection is accepted; later ones are dropped during the handshake
+		if connections.Add(1) > 1 {
+			return
+		}
+		var msg wsMessage
+		wsjson.Read(r.Context(), conn, &msg)
+		wsjson.Write(r.Context(), conn, wsMessage{Type: MessageTypeConnectionAck})
+		wsjson.Read(r.Context(), conn, &msg)
+		conn.Close(websocket.StatusGoingAway, "restarting")
+	}))
+	defer server.Close()
+
+	client := NewClient("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	client.SetReconnect(2, time.Millisecond)
+
+	messages, errs, err := client.Subscribe(context.Background(), `subscription { count }`, nil, "")
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	for range messages {
+	}
+	if err := <-errs; err == nil || !strings.Contains(err.Error(), "after 2 attempts") {
+		t.Errorf("Expected reconnect to give up after 2 attempts, got %v", err)
+	}
+	if got := connections.Load(); got != 3 {
+		t.Errorf("Expected 3 connections, got %d", got)
+	}
+}
+
+func TestClient_Subscribe_ReconnectIdle(t *testing.T) {
+	var connections atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{Subprotocols: []string{"graphql-transport-ws"}})
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		ctx := r.Context()
+		connections.Add(1)
+
+		var msg wsMessage
+		if err := wsjson.Read(ctx, conn, &msg); err != nil {
+			return
+		}
+		wsjson.Write(ctx, conn, wsMessage{Type: MessageTypeConnectionAck})
+		if err := wsjson.Read(ctx, conn, &msg); err != nil {
+			return
+		}
+
+		// Stay quiet for longer than any read timeout before the first event
+		select {
+		case <-time.After(6 * time.Second):
+		case <-ctx.Done():
+			return
+		}
+		wsjson.Write(ctx, conn, wsMessage{
+			ID:      msg.ID,
+			Type:    MessageTypeNext,
+			Payload: map[string]interface{}{"data": map[string]interface{}{"count": 1}},
+		})
+		wsjson.Write(ctx, conn, wsMessage{ID: msg.ID, Type: MessageTypeComplete})
+	}))
+	defer server.Close()
+
+	client := NewClient("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	client.SetReconnect(3, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	messages, errs, err := client.Subscribe(ctx, `subscription { count }`, nil, "")
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	var events []string
+	for msg := range messages {
+		if msg.Reconnected {
+			events = append(events, "reconnected")
+			continue
+		}
+		data, _ := msg.Data.(map[string]interface{})
+		events = append(events, fmt.Sprintf("count %v", data["count"]))
+	}
+	for err := range errs {
+		t.Errorf("Unexpected subscription error: %v", err)
+	}
+
+	// An idle connection is not a lost one
+	if strings.Join(events, ", ") != "count 1" {
+		t.Errorf("Expected only the event sent after the idle period, got %v", events)
+	}
+	if got := connections.Load(); got != 1 {
+		t.Errorf("Expected 1 connection, got %d", got)
+	}
+}
+
+func TestClient_Subscribe_ReconnectCancelWithoutReading(t *testing.T) {
+	closed := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{Subprotocols: []string{"graphql-transport-ws"}})
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		ctx := r.Context()
+
+		var msg wsMessage
+		if err := wsjson.Read(ctx, conn, &msg); err != nil {
+			return
+		}
+		wsjson.Write(ctx, conn, wsMessage{Type: MessageTypeConnectionAck})
+		if err := wsjson.Read(ctx, conn, &msg); err != nil {
+			return
+		}
+		for i := 0; i < 100; i++ {
+			wsjson.Write(ctx, conn, wsMessage{
+				ID:      msg.ID,
+				Type:    MessageTypeNext,
+				Payload: map[string]interface{}{"data": map[string]interface{}{"count": i}},
+			})
+		}
+
+		// Wait for the client to go away
+		wsjson.Read(ctx, conn, &msg)
+		close(closed)
+	}))
+	defer server.Close()
+
+	client := NewClient("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	client.SetReconnect(3, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	messages, _, err := client.Subscribe(ctx, `subscription { count }`, nil, "")
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	// Stop reading once the buffer is full, then cancel
+	deadline := time.Now().Add(5 * time.Second)
+	for len(messages) < cap(messages) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+
+	select {
+	case <-closed:
+	case <-time.After(3 * time.Second):
+		t.Error("Expected the connection to be closed after cancelling without reading")
+	}
+}
+
+func TestClient_Subscribe_LifecycleEvents(t *testing.T) {
+	// The legacy graphql-ws server sends a keep-alive right after acknowledging
+	server := newWebSocketServer(t, []string{"graphql-ws"}, make(chan string, 2))
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	subscribeTypes := func(client *Client) []string {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		messages, errs, err := client.Subscribe(ctx, `subscription { count }`, nil, "")
+		if err != nil {
+			t.Fatalf("Subscribe failed: %v", err)
+		}
+		var types []string
+		for msg := range messages {
+			types = append(types, msg.Type)
+		}
+		for err := range errs {
+			t.Errorf("Unexpected subscription error: %v", err)
+		}
+		return types
+	}
+
+	client := NewClient(wsURL, nil)
+	if got := strings.Join(subscribeTypes(client), ","); got != "data,data" {
+		t.Errorf("Expected only data messages by default, got %s", got)
+	}
+
+	client.SetLifecycleEvents(true)
+	if got := strings.Join(subscribeTypes(client), ","); got != "keepalive,data,data,complete" {
+		t.Errorf("Expected keep-alive and complete events, got %s", got)
+	}
+}
+
+func TestClient_Subscribe_LifecycleEvents_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{Subprotocols: []string{"graphql-transport-ws"}})
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		ctx := r.Context()
+
+		var msg wsMessage
+		wsjson.Read(ctx, conn, &msg)
+		wsjson.Write(ctx, conn, wsMessage{Type: MessageTypeConnectionAck})
+		wsjson.Read(ctx, conn, &msg)
+		wsjson.Write(ctx, conn, wsMessage{
+			ID:      msg.ID,
+			Type:    MessageTypeError,
+			Payload: []interface{}{map[string]interface{}{"message": "unknown field"}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	client.SetLifecycleEvents(true)
+
+	messages, errs, err := client.Subscribe(context.Background(), `subscription { missing }`, nil, "")
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	var received []*SubscriptionMessage
+	for msg := range messages {
+		received = append(received, msg)
+	}
+	for err := range errs {
+		t.Errorf("Expected error to be delivered as a message, got %v", err)
+	}
+
+	if len(received) != 1 || received[0].Type != SubscriptionMessageTypeError || len(received[0].Errors) != 1 {
+		t.Fatalf("Expected a single error message, got %+v", received)
+	}
+	if e, _ := received[0].Errors[0].(map[string]interface{}); e["message"] != "unknown field" {
+		t.Errorf("Expected error payload to be delivered, got %v", received[0].Errors)
+	}
+}
+
+func TestClient_Subscribe_TLS(t *testing.T) {
+	// The test server uses a self-signed certificate
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") == "text/event-stream" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "event: next\ndata: {\"data\":{\"count\":1}}\n\n")
+			fmt.Fprint(w, "event: complete\ndata:\n\n")
+			return
+		}
+
+		conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{Subprotocols: []string{"graphql-transport-ws"}})
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		ctx := r.Context()
+
+		var msg wsMessage
+		if err := wsjson.Read(ctx, conn, &msg); err != nil {
+			return
+		}
+		wsjson.Write(ctx, conn, wsMessage{Type: MessageTypeConnectionAck})
+		if err := wsjson.Read(ctx, conn, &msg); err != nil {
+			return
+		}
+		wsjson.Write(ctx, conn, wsMessage{
+			ID:      msg.ID,
+			Type:    MessageTypeNext,
+			Payload: map[string]interface{}{"data": map[string]interface{}{"count": 1}},
+		})
+		wsjson.Write(ctx, conn, wsMessage{ID: msg.ID, Type: MessageTypeComplete})
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		endpoint string
+		protocol SubscriptionProtocol
+	}{
+		{name: "websocket", endpoint: "wss" + strings.TrimPrefix(server.URL, "https")},
+		{name: "sse", endpoint: server.URL, protocol: SubscriptionProtocolSSE},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscribe := func(insecure bool) error {
+				client := NewClient(tt.endpoint, nil)
+				if tt.protocol != "" {
+					if err := client.SetSubscriptionProtocol(tt.protocol); err != nil {
+						t.Fatalf("SetSubscriptionProtocol failed: %v", err)
+					}
+				}
+				client.SetInsecureSkipVerify(insecure)
+
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				messages, errs, err := client.Subscribe(ctx, `subscription { count }`, nil, "")
+				if err != nil {
+					return err
+				}
+				count := 0
+				for range messages {
+					count++
+				}
+				if err := <-errs; err != nil {
+					return err
+				}
+				if count != 1 {
+					t.Errorf("Expected 1 message, got %d", count)
+				}
+				return nil
+			}
+
+			if err := subscribe(false); err == nil {
+				t.Error("Expected self-signed certificate to be rejected")
+			}
+			if err := subscribe(true); err != nil {
+				t.Errorf("Expected subscription to succeed with verification disabled: %v", err)
+			}
+		})
+	}
+}
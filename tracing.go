@@ -0,0 +1,175 @@
+package gqlt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FormatTracing renders the extensions of a response as a readable tree. Apollo
+// tracing data (the "tracing" extension) is shown as the total duration, the
+// parsing and validation phases, and one line per resolver nested by its path,
+// with its parent type, return type and duration. Any other extensions (such as a
+// query plan or cost report) follow as indented key/value trees, in key order.
+// Returns an empty string if there are no extensions.
+//
+// Example:
+//
+//	fmt.Print(gqlt.FormatTracing(response.Extensions))
+func FormatTracing(extensions map[string]interface{}) string {
+	var sb strings.Builder
+
+	if tracing, ok := extensions["tracing"].(map[string]interface{}); ok {
+		writeTracing(&sb, tracing)
+	}
+
+	keys := make([]string, 0, len(extensions))
+	for key := range extensions {
+		if key == "tracing" {
+			if _, ok := extensions[key].(map[string]interface{}); ok {
+				continue
+			}
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		writeExtensionValue(&sb, key, extensions[key], 0)
+	}
+
+	return sb.String()
+}
+
+// tracedResolver is a resolver entry of Apollo tracing data
+type tracedResolver struct {
+	path       []interface{}
+	parentType string
+	fieldName  string
+	returnType string
+	duration   time.Duration
+}
+
+// writeTracing renders Apollo tracing data: phases first, then resolvers
+func writeTracing(sb *strings.Builder, tracing map[string]interface{}) {
+	fmt.Fprintf(sb, "Tracing (total %s)\n", formatTraceDuration(tracingDuration(tracing["duration"])))
+	for _, phase := range []string{"parsing", "validation"} {
+		if timing, ok := tracing[phase].(map[string]interface{}); ok {
+			fmt.Fprintf(sb, "  %-10s %s\n", phase, formatTraceDuration(tracingDuration(timing["duration"])))
+		}
+	}
+
+	execution, _ := tracing["execution"].(map[string]interface{})
+	entries, _ := execution["resolvers"].([]interface{})
+	if len(entries) == 0 {
+		return
+	}
+
+	resolvers := make([]tracedResolver, 0, len(entries))
+	for _, entry := range entries {
+		fields, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path, _ := fields["path"].([]interface{})
+		resolver := tracedResolver{path: path, duration: tracingDuration(fields["duration"])}
+		resolver.parentType, _ = fields["parentType"].(string)
+		resolver.fieldName, _ = fields["fieldName"].(string)
+		resolver.returnType, _ = fields["returnType"].(string)
+		resolvers = append(resolvers, resolver)
+	}
+
+	// Sorting by path puts every resolver right after its parent
+	sort.SliceStable(resolvers, func(i, j int) bool {
+		return comparePaths(resolvers[i].path, resolvers[j].path) < 0
+	})
+
+	sb.WriteString("  execution\n")
+	for _, resolver := range resolvers {
+		depth := 0
+		for _, segment := range resolver.path {
+			if _, isIndex := segment.(float64); !isIndex {
+				depth++
+			}
+		}
+		fmt.Fprintf(sb, "%s%s  %s.%s: %s  %s\n",
+			strings.Repeat("  ", depth+1), resolverLabel(resolver.path),
+			resolver.parentType, resolver.fieldName, resolver.returnType,
+			formatTraceDuration(resolver.duration))
+	}
+}
+
+// resolverLabel names a resolver by the last segment of its path, prefixed with
+// the list index it belongs to, if any (e.g. "[0] title")
+func resolverLabel(path []interface{}) string {
+	if len(path) == 0 {
+		return "?"
+	}
+	label := fmt.Sprint(path[len(path)-1])
+	if len(path) > 1 {
+		if index, ok := path[len(path)-2].(float64); ok {
+			label = fmt.Sprintf("[%d] %s", int(index), label)
+		}
+	}
+	return label
+}
+
+// comparePaths orders resolver paths element by element, with list indexes in
+// numeric order and a path before the paths below it
+func comparePaths(a, b []interface{}) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		an, aIsIndex := a[i].(float64)
+		bn, bIsIndex := b[i].(float64)
+		switch {
+		case aIsIndex && bIsIndex:
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+				return 1
+			}
+		default:
+			if as, bs := fmt.Sprint(a[i]), fmt.Sprint(b[i]); as != bs {
+				return strings.Compare(as, bs)
+			}
+		}
+	}
+	return len(a) - len(b)
+}
+
+// tracingDuration converts an Apollo tracing duration in nanoseconds
+func tracingDuration(value interface{}) time.Duration {
+	if ns, ok := value.(float64); ok {
+		return time.Duration(ns)
+	}
+	return 0
+}
+
+// formatTraceDuration prints durations in milliseconds with microsecond precision
+func formatTraceDuration(d time.Duration) string {
+	return fmt.Sprintf("%.3fms", float64(d)/float64(time.Millisecond))
+}
+
+// writeExtensionValue renders an extension as a key with nested children
+func writeExtensionValue(sb *strings.Builder, key string, value interface{}, depth int) {
+	indent := strings.Repeat("  ", depth)
+	switch v := value.(type) {
+	case map[string]interface{}:
+		fmt.Fprintf(sb, "%s%s\n", indent, key)
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			writeExtensionValue(sb, k, v[k], depth+1)
+		}
+	case []interface{}:
+		fmt.Fprintf(sb, "%s%s\n", indent, key)
+		for i, item := range v {
+			writeExtensionValue(sb, fmt.Sprintf("[%d]", i), item, depth+1)
+		}
+	default:
+		fmt.Fprintf(sb, "%s%s: %v\n", indent, key, v)
+	}
+}
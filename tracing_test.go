@@ -0,0 +1,70 @@
+package gqlt
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFormatTracing(t *testing.T) {
+	var extensions map[string]interface{}
+	err := json.Unmarshal([]byte(`{
+		"tracing": {
+			"version": 1,
+			"startTime": "2025-01-01T12:00:00.000Z",
+			"endTime": "2025-01-01T12:00:00.002Z",
+			"duration": 2500000,
+			"parsing": {"startOffset": 10000, "duration": 50000},
+			"validation": {"startOffset": 60000, "duration": 20000},
+			"execution": {
+				"resolvers": [
+					{"path": ["user", "posts", 1, "title"], "parentType": "Post", "fieldName": "title", "returnType": "String!", "startOffset": 900000, "duration": 3000},
+					{"path": ["user"], "parentType": "Query", "fieldName": "user", "returnType": "User", "startOffset": 100000, "duration": 1200000},
+					{"path": ["user", "posts", 0, "title"], "parentType": "Post", "fieldName": "title", "returnType": "String!", "startOffset": 800000, "duration": 2000},
+					{"path": ["user", "posts"], "parentType": "User", "fieldName": "posts", "returnType": "[Post!]!", "startOffset": 500000, "duration": 300000}
+				]
+			}
+		},
+		"cost": {"requested": 12, "limit": 1000}
+	}`), &extensions)
+	if err != nil {
+		t.Fatalf("Failed to decode extensions: %v", err)
+	}
+
+	expected := `Tracing (total 2.500ms)
+  parsing    0.050ms
+  validation 0.020ms
+  execution
+    user  Query.user: User  1.200ms
+      posts  User.posts: [Post!]!  0.300ms
+        [0] title  Post.title: String!  0.002ms
+        [1] title  Post.title: String!  0.003ms
+cost
+  limit: 1000
+  requested: 12
+`
+	if got := FormatTracing(extensions); got != expected {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", got, expected)
+	}
+}
+
+func TestFormatTracingWithoutTracing(t *testing.T) {
+	if got := FormatTracing(nil); got != "" {
+		t.Errorf("Expected no output for no extensions, got %q", got)
+	}
+
+	extensions := map[string]interface{}{
+		"queryPlan": map[string]interface{}{
+			"kind":  "Sequence",
+			"nodes": []interface{}{map[string]interface{}{"service": "users"}},
+		},
+	}
+	expected := `queryPlan
+  kind: Sequence
+  nodes
+    [0]
+      service: users
+`
+	if got := FormatTracing(extensions); got != expected {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", got, expected)
+	}
+}
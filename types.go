@@ -10,12 +10,13 @@ type Summary struct {
 
 // TypeDescription represents a type description
 type TypeDescription struct {
-	Name        string         `json:"name"`
-	Kind        string         `json:"kind"`
-	Description string         `json:"description,omitempty"`
-	Fields      []FieldSummary `json:"fields,omitempty"`
-	InputFields []FieldSummary `json:"inputFields,omitempty"`
-	EnumValues  []EnumValue    `json:"enumValues,omitempty"`
+	Name         string         `json:"name"`
+	Kind         string         `json:"kind"`
+	Description  string         `json:"description,omitempty"`
+	Fields       []FieldSummary `json:"fields,omitempty"`
+	InputFields  []FieldSummary `json:"inputFields,omitempty"`
+	EnumValues   []EnumValue    `json:"enumValues,omitempty"`
+	Implementors []string       `json:"implementors,omitempty"`
 }
 
 // FieldDescription represents a field description
@@ -42,3 +43,102 @@ type EnumValue struct {
 	Name        string `json:"name"`
 	Description string `json:"description,omitempty"`
 }
+
+// DeprecatedField represents a deprecated field or enum value
+type DeprecatedField struct {
+	TypeName          string `json:"typeName"`
+	Name              string `json:"name"`
+	Kind              string `json:"kind"` // FIELD or ENUM_VALUE
+	DeprecationReason string `json:"deprecationReason,omitempty"`
+}
+
+// SchemaStats represents aggregate statistics about a schema
+type SchemaStats struct {
+	Objects                int                  `json:"objects"`
+	Interfaces             int                  `json:"interfaces"`
+	Unions                 int                  `json:"unions"`
+	Enums                  int                  `json:"enums"`
+	InputObjects           int                  `json:"inputObjects"`
+	Scalars                int                  `json:"scalars"`
+	AverageFieldsPerObject float64              `json:"averageFieldsPerObject"`
+	MostReferencedTypes    []TypeReferenceCount `json:"mostReferencedTypes"`
+	Deprecated             int                  `json:"deprecated"`
+}
+
+// TypeReferenceCount represents how often a type is referenced by fields, arguments and input fields
+type TypeReferenceCount struct {
+	Name       string `json:"name"`
+	References int    `json:"references"`
+}
+
+// Reference represents a field, argument or input field that uses a type, as
+// found by FindReferences
+type Reference struct {
+	TypeName string `json:"typeName"` // The type declaring the field or input field
+	Field    string `json:"field"`
+	Argument string `json:"argument,omitempty"` // Set for arguments of Field
+	Kind     string `json:"kind"`               // FIELD, ARGUMENT or INPUT_FIELD
+	Type     string `json:"type"`               // The full type, e.g. [User!]!
+}
+
+// LintFinding describes an anti-pattern found by LintQuery
+type LintFinding struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+// ValidationError describes a problem found when validating a query against a schema
+type ValidationError struct {
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Rule    string `json:"rule,omitempty"`
+}
+
+// Schema change categories reported by SchemaDiff
+const (
+	ChangeTypeAdded        = "TYPE_ADDED"
+	ChangeTypeRemoved      = "TYPE_REMOVED"
+	ChangeTypeKindChanged  = "TYPE_KIND_CHANGED"
+	ChangeFieldAdded       = "FIELD_ADDED"
+	ChangeFieldRemoved     = "FIELD_REMOVED"
+	ChangeFieldTypeChanged = "FIELD_TYPE_CHANGED"
+	ChangeEnumValueAdded   = "ENUM_VALUE_ADDED"
+	ChangeEnumValueRemoved = "ENUM_VALUE_REMOVED"
+)
+
+// SchemaChange represents a single difference between two schemas
+type SchemaChange struct {
+	Change      string `json:"change"`
+	Path        string `json:"path"`
+	Description string `json:"description"`
+	Breaking    bool   `json:"breaking"`
+}
+
+// DiffReport represents the differences between two schemas
+type DiffReport struct {
+	Changes       []SchemaChange `json:"changes"`
+	BreakingCount int            `json:"breakingCount"`
+}
+
+// HasBreakingChanges reports whether the diff contains any breaking change
+func (r *DiffReport) HasBreakingChanges() bool {
+	return r.BreakingCount > 0
+}
+
+// JSON change kinds reported by DiffJSON
+const (
+	ChangeValueAdded   = "ADDED"
+	ChangeValueRemoved = "REMOVED"
+	ChangeValueChanged = "CHANGED"
+)
+
+// Change represents a single difference between two JSON values
+type Change struct {
+	Kind string      `json:"kind"`
+	Path string      `json:"path"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
@@ -0,0 +1,246 @@
+package gqlt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// ValidateQuery parses a query and validates it against the schema. Syntax errors,
+// unknown fields, wrong argument types and missing required arguments are reported
+// as validation errors with their position in the query. An empty result means the
+// query is valid; an error is returned only if the schema itself can't be used.
+//
+// Example:
+//
+//	errs, err := analyzer.ValidateQuery(`{ user { nmae } }`)
+//	for _, e := range errs {
+//	    fmt.Printf("%d:%d %s\n", e.Line, e.Column, e.Message)
+//	}
+func (a *Analyzer) ValidateQuery(query string) ([]ValidationError, error) {
+	schema, err := a.astSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	validationErrors := []ValidationError{}
+	_, errs := gqlparser.LoadQuery(schema, query)
+	for _, e := range errs {
+		validationError := ValidationError{
+			Message: e.Message,
+			Rule:    e.Rule,
+		}
+		if len(e.Locations) > 0 {
+			validationError.Line = e.Locations[0].Line
+			validationError.Column = e.Locations[0].Column
+		}
+		validationErrors = append(validationErrors, validationError)
+	}
+
+	return validationErrors, nil
+}
+
+// astSchema builds a gqlparser schema from the introspection data so queries can be
+// validated with the reference validation rules
+func (a *Analyzer) astSchema() (*ast.Schema, error) {
+	prelude, err := parser.ParseSchema(validator.Prelude)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse prelude: %w", err)
+	}
+
+	// Built-in scalars and directives come from the prelude
+	builtIn := make(map[string]bool)
+	for _, def := range prelude.Definitions {
+		builtIn[def.Name] = true
+	}
+	for _, dir := range prelude.Directives {
+		builtIn["@"+dir.Name] = true
+	}
+
+	pos := &ast.Position{Src: &ast.Source{Name: "introspection"}}
+	doc := &ast.SchemaDocument{}
+
+	types, ok := a.schemaData["types"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid types format")
+	}
+	for _, t := range types {
+		typeObj, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := typeObj["name"].(string)
+		if name == "" || builtIn[name] || strings.HasPrefix(name, "__") {
+			continue
+		}
+		doc.Definitions = append(doc.Definitions, introspectionToDefinition(typeObj, pos))
+	}
+
+	if directives, ok := a.schemaData["directives"].([]interface{}); ok {
+		for _, d := range directives {
+			dirObj, ok := d.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := dirObj["name"].(string)
+			if name == "" || builtIn["@"+name] {
+				continue
+			}
+			directive := &ast.DirectiveDefinition{
+				Name:         name,
+				Arguments:    introspectionToArguments(dirObj["args"], pos),
+				IsRepeatable: dirObj["isRepeatable"] == true,
+				Position:     pos,
+			}
+			locations, _ := dirObj["locations"].([]interface{})
+			for _, loc := range locations {
+				if locStr, ok := loc.(string); ok {
+					directive.Locations = append(directive.Locations, ast.DirectiveLocation(locStr))
+				}
+			}
+			doc.Directives = append(doc.Directives, directive)
+		}
+	}
+
+	// Declare the root operation types explicitly, since they may not use default names
+	schemaDef := &ast.SchemaDefinition{Position: pos}
+	for key, operation := range map[string]ast.Operation{
+		"queryType":        ast.Query,
+		"mutationType":     ast.Mutation,
+		"subscriptionType": ast.Subscription,
+	} {
+		if rootType, ok := a.schemaData[key].(map[string]interface{}); ok {
+			if name, _ := rootType["name"].(string); name != "" {
+				schemaDef.OperationTypes = append(schemaDef.OperationTypes, &ast.OperationTypeDefinition{
+					Operation: operation,
+					Type:      name,
+					Position:  pos,
+				})
+			}
+		}
+	}
+	doc.Schema = append(doc.Schema, schemaDef)
+
+	prelude.Merge(doc)
+	schema, err := validator.ValidateSchemaDocument(prelude)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build schema for validation: %w", err)
+	}
+	return schema, nil
+}
+
+// introspectionToDefinition converts an introspection type into a gqlparser definition
+func introspectionToDefinition(typeObj map[string]interface{}, pos *ast.Position) *ast.Definition {
+	name, _ := typeObj["name"].(string)
+	kind, _ := typeObj["kind"].(string)
+	description, _ := typeObj["description"].(string)
+
+	def := &ast.Definition{
+		Kind:        ast.DefinitionKind(kind),
+		Name:        name,
+		Description: description,
+		Interfaces:  typeNames(typeObj["interfaces"]),
+		Types:       typeNames(typeObj["possibleTypes"]),
+		Position:    pos,
+	}
+
+	fieldsKey := "fields"
+	if def.Kind == ast.InputObject {
+		fieldsKey = "inputFields"
+	}
+	if fields, ok := typeObj[fieldsKey].([]interface{}); ok {
+		for _, f := range fields {
+			fieldObj, ok := f.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			fieldName, _ := fieldObj["name"].(string)
+			// Introspection fields are added to the query type by the validator
+			if strings.HasPrefix(fieldName, "__") {
+				continue
+			}
+			fieldType, _ := fieldObj["type"].(map[string]interface{})
+			def.Fields = append(def.Fields, &ast.FieldDefinition{
+				Name:         fieldName,
+				Arguments:    introspectionToArguments(fieldObj["args"], pos),
+				DefaultValue: parseDefaultValue(fieldObj["defaultValue"]),
+				Type:         introspectionToType(fieldType, pos),
+				Position:     pos,
+			})
+		}
+	}
+
+	if values, ok := typeObj["enumValues"].([]interface{}); ok {
+		for _, v := range values {
+			if valueObj, ok := v.(map[string]interface{}); ok {
+				valueName, _ := valueObj["name"].(string)
+				def.EnumValues = append(def.EnumValues, &ast.EnumValueDefinition{Name: valueName, Position: pos})
+			}
+		}
+	}
+
+	return def
+}
+
+// introspectionToArguments converts introspection arguments into gqlparser argument definitions
+func introspectionToArguments(args interface{}, pos *ast.Position) ast.ArgumentDefinitionList {
+	var result ast.ArgumentDefinitionList
+	argList, _ := args.([]interface{})
+	for _, arg := range argList {
+		argObj, ok := arg.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := argObj["name"].(string)
+		argType, _ := argObj["type"].(map[string]interface{})
+		result = append(result, &ast.ArgumentDefinition{
+			Name:         name,
+			DefaultValue: parseDefaultValue(argObj["defaultValue"]),
+			Type:         introspectionToType(argType, pos),
+			Position:     pos,
+		})
+	}
+	return result
+}
+
+// introspectionToType converts an introspection type reference into a gqlparser type
+func introspectionToType(typeRef map[string]interface{}, pos *ast.Position) *ast.Type {
+	kind, _ := typeRef["kind"].(string)
+	ofType, _ := typeRef["ofType"].(map[string]interface{})
+
+	switch kind {
+	case "NON_NULL":
+		t := introspectionToType(ofType, pos)
+		t.NonNull = true
+		return t
+	case "LIST":
+		return ast.ListType(introspectionToType(ofType, pos), pos)
+	default:
+		name, _ := typeRef["name"].(string)
+		return ast.NamedType(name, pos)
+	}
+}
+
+// parseDefaultValue parses an introspection default value (a GraphQL literal) into a
+// gqlparser value, returning nil if there is no default
+func parseDefaultValue(defaultValue interface{}) *ast.Value {
+	raw, ok := defaultValue.(string)
+	if !ok || raw == "" {
+		return nil
+	}
+
+	// Parse the literal as an argument value of a throwaway query
+	doc, err := parser.ParseQuery(&ast.Source{Input: "{ f(v: " + raw + ") }"})
+	if err == nil && len(doc.Operations) == 1 {
+		if field, ok := doc.Operations[0].SelectionSet[0].(*ast.Field); ok && len(field.Arguments) == 1 {
+			return field.Arguments[0].Value
+		}
+	}
+
+	// Keep the raw literal so the argument still counts as having a default
+	return &ast.Value{Raw: raw, Kind: ast.StringValue}
+}
@@ -0,0 +1,137 @@
+package gqlt
+
+import (
+	"strings"
+	"testing"
+)
+
+func newValidateTestAnalyzer(t *testing.T) *Analyzer {
+	t.Helper()
+	introspection, err := SDLToIntrospection(`
+		type Query {
+			user(id: ID!): User
+			users(first: Int = 10, role: Role): [User!]!
+		}
+
+		type Mutation {
+			createUser(input: CreateUserInput!): User
+		}
+
+		type User {
+			id: ID!
+			name: String!
+			role: Role
+		}
+
+		enum Role {
+			ADMIN
+			USER
+		}
+
+		input CreateUserInput {
+			name: String!
+			role: Role = USER
+		}
+	`)
+	if err != nil {
+		t.Fatalf("SDLToIntrospection failed: %v", err)
+	}
+	analyzer, err := NewAnalyzer(&Response{Data: introspection})
+	if err != nil {
+		t.Fatalf("NewAnalyzer failed: %v", err)
+	}
+	return analyzer
+}
+
+func TestAnalyzer_ValidateQuery(t *testing.T) {
+	analyzer := newValidateTestAnalyzer(t)
+
+	tests := []struct {
+		name     string
+		query    string
+		contains string // expected error message fragment, empty for a valid query
+		line     int
+		column   int
+	}{
+		{
+			name:  "valid query",
+			query: `query { user(id: "1") { id name role } users { id } }`,
+		},
+		{
+			name:  "valid mutation with defaulted input field",
+			query: `mutation { createUser(input: { name: "Ada" }) { id } }`,
+		},
+		{
+			name:     "unknown field",
+			query:    "query {\n  user(id: \"1\") {\n    email\n  }\n}",
+			contains: `Cannot query field "email" on type "User"`,
+			line:     3,
+			column:   5,
+		},
+		{
+			name:     "missing required argument",
+			query:    `query { user { id } }`,
+			contains: `argument "id" of type "ID!" is required`,
+			line:     1,
+			column:   9,
+		},
+		{
+			name:     "wrong argument type",
+			query:    `query { users(first: "ten") { id } }`,
+			contains: `Int cannot represent non-integer value`,
+			line:     1,
+			column:   23,
+		},
+		{
+			name:     "syntax error",
+			query:    `query { user(id: "1") { id }`,
+			contains: "Expected Name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, err := analyzer.ValidateQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ValidateQuery failed: %v", err)
+			}
+
+			if tt.contains == "" {
+				if len(errs) != 0 {
+					t.Errorf("Expected query to be valid, got %+v", errs)
+				}
+				return
+			}
+
+			if len(errs) == 0 {
+				t.Fatalf("Expected validation errors")
+			}
+			if !strings.Contains(errs[0].Message, tt.contains) {
+				t.Errorf("Expected error containing %q, got %q", tt.contains, errs[0].Message)
+			}
+			if tt.line != 0 && (errs[0].Line != tt.line || errs[0].Column != tt.column) {
+				t.Errorf("Expected error at %d:%d, got %d:%d", tt.line, tt.column, errs[0].Line, errs[0].Column)
+			}
+		})
+	}
+}
+
+func TestAnalyzer_ValidateQuery_MockServerSchema(t *testing.T) {
+	analyzer, err := LoadAnalyzerFromFile("internal/mockserver/graph/schema.graphqls")
+	if err != nil {
+		t.Fatalf("LoadAnalyzerFromFile failed: %v", err)
+	}
+
+	// Exercises interfaces, input objects and defaulted arguments
+	errs, err := analyzer.ValidateQuery(`query {
+		node(id: "User:1") { id ... on User { email } }
+		users(limit: 5) { id name }
+		todos { id }
+	}`)
+	if err != nil {
+		t.Fatalf("ValidateQuery failed: %v", err)
+	}
+	if len(errs) != 0 {
+		t.Errorf("Expected query to be valid, got %+v", errs)
+	}
+}